@@ -0,0 +1,366 @@
+// Package akscluster provides CastAiAksCluster, a component resource that
+// connects an existing Azure AKS cluster to CAST AI.
+//
+// Unlike EKS and GKE, AKS onboarding is a single phase: the Azure AD
+// application CAST AI authenticates as is created up front, so the component
+// registers the cluster with its credentials and then creates the default node
+// configuration, the default node template, optional extra node templates and
+// the optional autoscaler policies.
+//
+// The application can authenticate with a client secret or, secretless, with a
+// federated identity credential that trusts the CAST AI impersonation service
+// account (see castai.GetImpersonationServiceAccount). Azure resources are not
+// created by the component itself, so it does not depend on the pulumi-azure
+// SDK.
+//
+// Example usage:
+//
+//	cluster, err := akscluster.NewCastAiAksCluster(ctx, "my-cluster", &akscluster.CastAiAksClusterArgs{
+//		ClusterName:       "my-aks-cluster",
+//		Region:            "westeurope",
+//		SubscriptionId:    pulumi.String("00000000-0000-0000-0000-000000000000"),
+//		TenantId:          pulumi.String("00000000-0000-0000-0000-000000000000"),
+//		NodeResourceGroup: pulumi.String("MC_my-rg_my-aks-cluster_westeurope"),
+//		ClientId:          app.ClientId,
+//		ClientSecret:      password.Value,
+//		Subnets:           pulumi.StringArray{subnet.ID()},
+//	})
+package akscluster
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscalerpolicy"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// ComponentType is the type token of CastAiAksCluster.
+const ComponentType = "castai:aks:CastAiAksCluster"
+
+// DefaultNodeTemplateName is the name of the node template CAST AI uses when no
+// other template matches a workload. The component always creates it.
+const DefaultNodeTemplateName = "default-by-castai"
+
+// Values accepted by the provider for NodeConfigurationAksArgs.
+var (
+	osDiskTypes         = []string{"standard", "standard-ssd", "premium-ssd"}
+	ephemeralPlacements = []string{"cacheDisk", "resourceDisk", "nvmeDisk"}
+)
+
+// CastAiAksClusterArgs are the inputs of CastAiAksCluster.
+type CastAiAksClusterArgs struct {
+	// ClusterName is the name of the AKS cluster to connect to CAST AI.
+	ClusterName string
+	// Region is the Azure region where the cluster is located.
+	Region string
+	// SubscriptionId is the Azure subscription of the cluster.
+	SubscriptionId pulumi.StringInput
+	// TenantId is the Azure AD tenant of the subscription.
+	TenantId pulumi.StringInput
+	// NodeResourceGroup is the resource group nodes are created in, usually
+	// MC_<resource group>_<cluster>_<region>.
+	NodeResourceGroup pulumi.StringInput
+
+	// ClientId is the ID of the Azure AD application CAST AI authenticates as.
+	ClientId pulumi.StringInput
+	// ClientSecret is a password of the application. Use either ClientSecret
+	// or FederationId.
+	ClientSecret pulumi.StringPtrInput
+	// FederationId is the federation ID of a federated identity credential on
+	// the application, for secretless authentication through impersonation.
+	FederationId pulumi.StringPtrInput
+
+	// ApiToken is the CAST AI API token. When set, the component creates its
+	// own castai.Provider; otherwise the default or inherited provider is used.
+	ApiToken pulumi.StringPtrInput
+	// ApiUrl is the CAST AI API URL used with ApiToken.
+	ApiUrl pulumi.StringPtrInput
+
+	// Subnets are the subnet IDs for CAST AI provisioned nodes.
+	Subnets pulumi.StringArrayInput
+	// NodeConfiguration holds AKS specific settings of the default node
+	// configuration, such as load balancers, ephemeral OS disks and public IPs.
+	NodeConfiguration *config.NodeConfigurationAksArgs
+
+	// DeleteNodesOnDisconnect removes CAST AI nodes when the cluster is disconnected.
+	DeleteNodesOnDisconnect bool
+	// Tags are added to CAST AI provisioned nodes.
+	Tags pulumi.StringMapInput
+	// NodeTemplates are created on top of the default node configuration, keyed
+	// by template name. ClusterId and ConfigurationId are filled in when unset.
+	// An entry named DefaultNodeTemplateName replaces the default template.
+	NodeTemplates map[string]*config.NodeTemplateArgs
+	// Autoscaler, when set, applies these autoscaler policies to the cluster.
+	Autoscaler *autoscalerpolicy.Policy
+}
+
+// CastAiAksCluster connects an AKS cluster to CAST AI.
+type CastAiAksCluster struct {
+	pulumi.ResourceState
+
+	// Cluster is the registered cluster.
+	Cluster *castai.AksCluster
+
+	// ClusterId is the CAST AI cluster ID.
+	ClusterId pulumi.StringOutput
+	// ClusterToken is the token the CAST AI agent authenticates with. It is
+	// marked secret.
+	ClusterToken pulumi.StringOutput
+	// CredentialsId is the ID of the cluster credentials in CAST AI.
+	CredentialsId pulumi.StringOutput
+	// OrganizationId is the CAST AI organization owning the cluster.
+	OrganizationId pulumi.StringOutput
+	// NodeConfigurationId is the ID of the default node configuration.
+	NodeConfigurationId pulumi.StringOutput
+	// NodeTemplates are the created node templates, keyed by template name,
+	// including DefaultNodeTemplateName.
+	NodeTemplates map[string]*config.NodeTemplate
+	// Autoscaler is the autoscaler policy resource, if Autoscaler was set.
+	Autoscaler *castai.Autoscaler
+}
+
+// NewCastAiAksCluster registers a new CastAiAksCluster component.
+func NewCastAiAksCluster(ctx *pulumi.Context, name string, args *CastAiAksClusterArgs, opts ...pulumi.ResourceOption) (*CastAiAksCluster, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+
+	component := &CastAiAksCluster{}
+	if err := ctx.RegisterComponentResource(ComponentType, name, component, opts...); err != nil {
+		return nil, err
+	}
+	childOpts := []pulumi.ResourceOption{pulumi.Parent(component)}
+
+	if args.ApiToken != nil {
+		provider, err := castai.NewProvider(ctx, name+"-provider", &castai.ProviderArgs{
+			ApiToken: args.ApiToken,
+			ApiUrl:   args.ApiUrl,
+		}, childOpts...)
+		if err != nil {
+			return nil, err
+		}
+		childOpts = append(childOpts, pulumi.Provider(provider))
+	}
+
+	clusterArgs := &castai.AksClusterArgs{
+		Name:                    pulumi.String(args.ClusterName),
+		Region:                  pulumi.String(args.Region),
+		SubscriptionId:          args.SubscriptionId,
+		TenantId:                args.TenantId,
+		NodeResourceGroup:       args.NodeResourceGroup,
+		ClientId:                args.ClientId,
+		FederationId:            args.FederationId,
+		DeleteNodesOnDisconnect: pulumi.Bool(args.DeleteNodesOnDisconnect),
+	}
+	if args.ClientSecret != nil {
+		clusterArgs.ClientSecret = pulumi.ToSecret(args.ClientSecret.ToStringPtrOutput()).(pulumi.StringPtrOutput)
+	}
+	cluster, err := castai.NewAksCluster(ctx, name+"-cluster", clusterArgs, childOpts...)
+	if err != nil {
+		return nil, err
+	}
+	component.Cluster = cluster
+	component.ClusterId = cluster.ID().ToStringOutput()
+	component.ClusterToken = pulumi.ToSecret(cluster.ClusterToken).(pulumi.StringOutput)
+	component.CredentialsId = cluster.CredentialsId
+	component.OrganizationId = cluster.OrganizationId
+
+	aks := config.NodeConfigurationAksArgs{}
+	if args.NodeConfiguration != nil {
+		aks = *args.NodeConfiguration
+	}
+	nodeConfig, err := config.NewNodeConfiguration(ctx, name+"-node-config-default", &config.NodeConfigurationArgs{
+		ClusterId: component.ClusterId,
+		Name:      pulumi.String("default"),
+		Subnets:   args.Subnets,
+		Tags:      args.Tags,
+		Aks:       aks,
+	}, append(childOpts, pulumi.DependsOn([]pulumi.Resource{cluster}))...)
+	if err != nil {
+		return nil, err
+	}
+	component.NodeConfigurationId = nodeConfig.ID().ToStringOutput()
+
+	defaultConfig, err := config.NewNodeConfigurationDefault(ctx, name+"-node-config-default-ref", &config.NodeConfigurationDefaultArgs{
+		ClusterId:       component.ClusterId,
+		ConfigurationId: component.NodeConfigurationId,
+	}, childOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	component.NodeTemplates = map[string]*config.NodeTemplate{}
+	templates := []pulumi.Resource{defaultConfig}
+	templateArgsByKey := args.nodeTemplates()
+	for _, key := range sortedKeys(templateArgsByKey) {
+		templateArgs := *templateArgsByKey[key]
+		if templateArgs.ClusterId == nil {
+			templateArgs.ClusterId = component.ClusterId
+		}
+		if templateArgs.ConfigurationId == nil {
+			templateArgs.ConfigurationId = component.NodeConfigurationId
+		}
+		if templateArgs.Name == nil {
+			templateArgs.Name = pulumi.String(key)
+		}
+		if key == DefaultNodeTemplateName && templateArgs.IsDefault == nil {
+			templateArgs.IsDefault = pulumi.Bool(true)
+		}
+		template, err := config.NewNodeTemplate(ctx, name+"-template-"+key, &templateArgs,
+			append(childOpts, pulumi.DependsOn([]pulumi.Resource{defaultConfig}))...)
+		if err != nil {
+			return nil, err
+		}
+		component.NodeTemplates[key] = template
+		templates = append(templates, template)
+	}
+
+	if args.Autoscaler != nil {
+		component.Autoscaler, err = castai.NewAutoscaler(ctx, name+"-autoscaler", &castai.AutoscalerArgs{
+			ClusterId:          component.ClusterId,
+			AutoscalerSettings: args.Autoscaler.ToSettingsArgs(),
+		}, append(childOpts, pulumi.DependsOn(templates))...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ctx.RegisterResourceOutputs(component, pulumi.Map{
+		"clusterId":           component.ClusterId,
+		"clusterToken":        component.ClusterToken,
+		"credentialsId":       component.CredentialsId,
+		"organizationId":      component.OrganizationId,
+		"nodeConfigurationId": component.NodeConfigurationId,
+	}); err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+// nodeTemplates returns NodeTemplates with the default template added unless
+// the caller provided one.
+func (args *CastAiAksClusterArgs) nodeTemplates() map[string]*config.NodeTemplateArgs {
+	out := map[string]*config.NodeTemplateArgs{
+		DefaultNodeTemplateName: {
+			IsDefault:   pulumi.Bool(true),
+			IsEnabled:   pulumi.Bool(true),
+			ShouldTaint: pulumi.Bool(false),
+			Constraints: config.NodeTemplateConstraintsArgs{
+				OnDemand: pulumi.Bool(true),
+			},
+		},
+	}
+	for key, template := range args.NodeTemplates {
+		out[key] = template
+	}
+	return out
+}
+
+func (args *CastAiAksClusterArgs) validate() error {
+	if args == nil {
+		return errors.New("missing required arguments")
+	}
+	var errs []error
+	if args.ClusterName == "" {
+		errs = append(errs, errors.New("clusterName is required"))
+	}
+	if args.Region == "" {
+		errs = append(errs, errors.New("region is required"))
+	}
+	if args.SubscriptionId == nil {
+		errs = append(errs, errors.New("subscriptionId is required"))
+	}
+	if args.TenantId == nil {
+		errs = append(errs, errors.New("tenantId is required"))
+	}
+	if args.NodeResourceGroup == nil {
+		errs = append(errs, errors.New("nodeResourceGroup is required"))
+	}
+	if args.ClientId == nil {
+		errs = append(errs, errors.New("clientId is required"))
+	}
+	switch {
+	case args.ClientSecret != nil && args.FederationId != nil:
+		errs = append(errs, errors.New("clientSecret cannot be combined with federationId"))
+	case args.ClientSecret == nil && args.FederationId == nil:
+		errs = append(errs, errors.New("either clientSecret or federationId is required"))
+	}
+	if isEmpty(args.Subnets) {
+		errs = append(errs, errors.New("subnets is required"))
+	}
+	if aks := args.NodeConfiguration; aks != nil {
+		if v, ok := aks.OsDiskType.(pulumi.String); ok && !contains(osDiskTypes, string(v)) {
+			errs = append(errs, fmt.Errorf("nodeConfiguration.osDiskType must be one of %v, got %q", osDiskTypes, v))
+		}
+		if disk, ok := ephemeralOsDisk(aks.EphemeralOsDisk); ok {
+			if v, ok := disk.Placement.(pulumi.String); ok && !contains(ephemeralPlacements, string(v)) {
+				errs = append(errs, fmt.Errorf("nodeConfiguration.ephemeralOsDisk.placement must be one of %v, got %q", ephemeralPlacements, v))
+			}
+		}
+	}
+	for key, template := range args.NodeTemplates {
+		if template == nil {
+			errs = append(errs, fmt.Errorf("nodeTemplates[%q] is nil", key))
+		}
+	}
+	if args.Autoscaler != nil {
+		if err := args.Autoscaler.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isEmpty reports whether a list input is missing or a literal empty list.
+// Lists only known at deployment time are assumed to be non-empty.
+func isEmpty(in pulumi.StringArrayInput) bool {
+	if in == nil {
+		return true
+	}
+	if arr, ok := in.(pulumi.StringArray); ok {
+		return len(arr) == 0
+	}
+	return false
+}
+
+// ephemeralOsDisk returns the ephemeral OS disk of a node configuration when
+// it is a literal: NodeConfigurationAksEphemeralOsDiskArgs, a pointer to it,
+// or the result of config.NodeConfigurationAksEphemeralOsDiskPtr, whose
+// unexported type has the same fields.
+func ephemeralOsDisk(in config.NodeConfigurationAksEphemeralOsDiskPtrInput) (config.NodeConfigurationAksEphemeralOsDiskArgs, bool) {
+	var disk config.NodeConfigurationAksEphemeralOsDiskArgs
+	v := reflect.ValueOf(in)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return disk, false
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || !v.Type().ConvertibleTo(reflect.TypeOf(disk)) {
+		return disk, false
+	}
+	return v.Convert(reflect.TypeOf(disk)).Interface().(config.NodeConfigurationAksEphemeralOsDiskArgs), true
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]*config.NodeTemplateArgs) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
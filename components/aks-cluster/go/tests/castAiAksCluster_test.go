@@ -0,0 +1,313 @@
+package tests
+
+import (
+	"testing"
+
+	akscluster "github.com/castai/pulumi-castai/components/aks-cluster/go"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscalerpolicy"
+	"github.com/castai/pulumi-castai/sdk/go/castai/castaitest"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	aksClusterType        = "castai:azure:AksCluster"
+	nodeConfigurationType = "castai:config/node:NodeConfiguration"
+	nodeTemplateType      = "castai:config/node:NodeTemplate"
+
+	subnetID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/nodes"
+)
+
+func baseArgs() *akscluster.CastAiAksClusterArgs {
+	return &akscluster.CastAiAksClusterArgs{
+		ClusterName:       "my-aks-cluster",
+		Region:            "westeurope",
+		SubscriptionId:    pulumi.String("11111111-1111-1111-1111-111111111111"),
+		TenantId:          pulumi.String("22222222-2222-2222-2222-222222222222"),
+		NodeResourceGroup: pulumi.String("MC_rg_my-aks-cluster_westeurope"),
+		ClientId:          pulumi.String("33333333-3333-3333-3333-333333333333"),
+		ClientSecret:      pulumi.String("s3cr3t"),
+		Subnets:           pulumi.ToStringArray([]string{subnetID}),
+	}
+}
+
+// TestClientSecretAuth tests onboarding with an application password
+func TestClientSecretAuth(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := baseArgs()
+		args.DeleteNodesOnDisconnect = true
+		cluster, err := akscluster.NewCastAiAksCluster(ctx, "secret", args)
+		require.NoError(t, err)
+
+		assert.Len(t, castaitest.Await(t, cluster.ClusterId).Value, 36)
+		token := castaitest.Await(t, cluster.ClusterToken)
+		assert.NotEmpty(t, token.Value)
+		assert.True(t, token.Secret, "the cluster token must be secret")
+		assert.Len(t, castaitest.Await(t, cluster.NodeConfigurationId).Value, 36)
+		return nil
+	})
+
+	cluster, ok := mocks.Find(aksClusterType, "secret-cluster")
+	require.True(t, ok)
+	secret := cluster.Inputs["clientSecret"]
+	require.True(t, secret.IsSecret(), "clientSecret must be secret")
+	assert.Equal(t, "s3cr3t", secret.SecretValue().Element.StringValue())
+	assert.NotContains(t, cluster.Inputs, resource.PropertyKey("federationId"))
+	assert.Equal(t, "my-aks-cluster", cluster.Inputs["name"].StringValue())
+	assert.Equal(t, "westeurope", cluster.Inputs["region"].StringValue())
+	assert.Equal(t, "MC_rg_my-aks-cluster_westeurope", cluster.Inputs["nodeResourceGroup"].StringValue())
+	assert.True(t, cluster.Inputs["deleteNodesOnDisconnect"].BoolValue())
+
+	nodeConfig, ok := mocks.Find(nodeConfigurationType, "secret-node-config-default")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, nodeConfig.Inputs["clusterId"].StringValue())
+	assert.Equal(t, []string{subnetID}, castaitest.Strings(nodeConfig.Inputs["subnets"]))
+	assert.Contains(t, nodeConfig.Inputs, resource.PropertyKey("aks"))
+
+	ref, ok := mocks.Find("castai:config/node:NodeConfigurationDefault", "secret-node-config-default-ref")
+	require.True(t, ok)
+	assert.Equal(t, nodeConfig.ID, ref.Inputs["configurationId"].StringValue())
+
+	assert.Empty(t, mocks.RegistrationsOf("castai:autoscaling:Autoscaler"))
+}
+
+// TestFederatedIdentityAuth tests secretless onboarding with a federation ID
+func TestFederatedIdentityAuth(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := baseArgs()
+		args.ClientSecret = nil
+		args.FederationId = pulumi.String("44444444-4444-4444-4444-444444444444")
+		_, err := akscluster.NewCastAiAksCluster(ctx, "federated", args)
+		require.NoError(t, err)
+		return nil
+	})
+
+	cluster, ok := mocks.Find(aksClusterType, "federated-cluster")
+	require.True(t, ok)
+	assert.Equal(t, "44444444-4444-4444-4444-444444444444", cluster.Inputs["federationId"].StringValue())
+	assert.NotContains(t, cluster.Inputs, resource.PropertyKey("clientSecret"))
+}
+
+// TestNodeConfigurationSettings tests that AKS settings reach the default node configuration
+func TestNodeConfigurationSettings(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := baseArgs()
+		args.Tags = pulumi.StringMap{"team": pulumi.String("platform")}
+		args.NodeConfiguration = &config.NodeConfigurationAksArgs{
+			MaxPodsPerNode: pulumi.Int(60),
+			OsDiskType:     pulumi.String("premium-ssd"),
+			EphemeralOsDisk: config.NodeConfigurationAksEphemeralOsDiskArgs{
+				Placement: pulumi.String("cacheDisk"),
+			},
+			Loadbalancers: config.NodeConfigurationAksLoadbalancerArray{
+				config.NodeConfigurationAksLoadbalancerArgs{
+					Id: pulumi.String("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/kubernetes"),
+					IpBasedBackendPools: config.NodeConfigurationAksLoadbalancerIpBasedBackendPoolArray{
+						config.NodeConfigurationAksLoadbalancerIpBasedBackendPoolArgs{Name: pulumi.String("kubernetes")},
+					},
+				},
+			},
+			PublicIp: config.NodeConfigurationAksPublicIpArgs{
+				PublicIpPrefix:       pulumi.String("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPPrefixes/nodes"),
+				IdleTimeoutInMinutes: pulumi.Int(10),
+			},
+		}
+		_, err := akscluster.NewCastAiAksCluster(ctx, "settings", args)
+		require.NoError(t, err)
+		return nil
+	})
+
+	nodeConfig, ok := mocks.Find(nodeConfigurationType, "settings-node-config-default")
+	require.True(t, ok)
+	assert.Equal(t, "platform", nodeConfig.Inputs["tags"].ObjectValue()["team"].StringValue())
+
+	aks := nodeConfig.Inputs["aks"].ObjectValue()
+	assert.Equal(t, float64(60), aks["maxPodsPerNode"].NumberValue())
+	assert.Equal(t, "premium-ssd", aks["osDiskType"].StringValue())
+	assert.Equal(t, "cacheDisk", aks["ephemeralOsDisk"].ObjectValue()["placement"].StringValue())
+
+	loadbalancers := aks["loadbalancers"].ArrayValue()
+	require.Len(t, loadbalancers, 1)
+	lb := loadbalancers[0].ObjectValue()
+	assert.Contains(t, lb["id"].StringValue(), "loadBalancers/kubernetes")
+	assert.Equal(t, "kubernetes", lb["ipBasedBackendPools"].ArrayValue()[0].ObjectValue()["name"].StringValue())
+
+	publicIP := aks["publicIp"].ObjectValue()
+	assert.Contains(t, publicIP["publicIpPrefix"].StringValue(), "publicIPPrefixes/nodes")
+	assert.Equal(t, float64(10), publicIP["idleTimeoutInMinutes"].NumberValue())
+}
+
+// TestDefaultNodeTemplate tests that the default template is always created
+func TestDefaultNodeTemplate(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		cluster, err := akscluster.NewCastAiAksCluster(ctx, "default", baseArgs())
+		require.NoError(t, err)
+		assert.Len(t, cluster.NodeTemplates, 1)
+		assert.Contains(t, cluster.NodeTemplates, akscluster.DefaultNodeTemplateName)
+		return nil
+	})
+
+	nodeConfig, _ := mocks.Find(nodeConfigurationType, "default-node-config-default")
+	template, ok := mocks.Find(nodeTemplateType, "default-template-default-by-castai")
+	require.True(t, ok)
+	assert.Equal(t, "default-by-castai", template.Inputs["name"].StringValue())
+	assert.Equal(t, nodeConfig.ID, template.Inputs["configurationId"].StringValue())
+	assert.True(t, template.Inputs["isDefault"].BoolValue())
+	assert.True(t, template.Inputs["isEnabled"].BoolValue())
+	assert.True(t, template.Inputs["constraints"].ObjectValue()["onDemand"].BoolValue())
+}
+
+// TestNodeTemplatesAndAutoscaler tests extra templates, overriding the default template and the autoscaler
+func TestNodeTemplatesAndAutoscaler(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := baseArgs()
+		args.NodeTemplates = map[string]*config.NodeTemplateArgs{
+			akscluster.DefaultNodeTemplateName: {
+				Constraints: config.NodeTemplateConstraintsArgs{
+					OnDemand:         pulumi.Bool(true),
+					Spot:             pulumi.Bool(true),
+					UseSpotFallbacks: pulumi.Bool(true),
+				},
+			},
+			"spot": {
+				ShouldTaint: pulumi.Bool(true),
+				Constraints: config.NodeTemplateConstraintsArgs{
+					Spot: pulumi.Bool(true),
+				},
+			},
+		}
+		args.Autoscaler = &autoscalerpolicy.Policy{
+			Enabled: autoscalerpolicy.Bool(true),
+		}
+		cluster, err := akscluster.NewCastAiAksCluster(ctx, "tpl", args)
+		require.NoError(t, err)
+		assert.Len(t, cluster.NodeTemplates, 2)
+		assert.NotNil(t, cluster.Autoscaler)
+		return nil
+	})
+
+	defaultTemplate, ok := mocks.Find(nodeTemplateType, "tpl-template-default-by-castai")
+	require.True(t, ok)
+	assert.True(t, defaultTemplate.Inputs["isDefault"].BoolValue(), "the default template stays default when overridden")
+	assert.True(t, defaultTemplate.Inputs["constraints"].ObjectValue()["useSpotFallbacks"].BoolValue())
+
+	spot, ok := mocks.Find(nodeTemplateType, "tpl-template-spot")
+	require.True(t, ok)
+	assert.NotContains(t, spot.Inputs, resource.PropertyKey("isDefault"))
+	assert.True(t, spot.Inputs["shouldTaint"].BoolValue())
+
+	cluster, _ := mocks.Find(aksClusterType, "tpl-cluster")
+	autoscaler, ok := mocks.Find("castai:autoscaling:Autoscaler", "tpl-autoscaler")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, autoscaler.ID)
+	assert.True(t, autoscaler.Inputs["autoscalerSettings"].ObjectValue()["enabled"].BoolValue())
+}
+
+// TestApiTokenCreatesProvider tests that an explicit API token gets its own provider
+func TestApiTokenCreatesProvider(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := baseArgs()
+		args.ApiToken = pulumi.String("token")
+		_, err := akscluster.NewCastAiAksCluster(ctx, "prov", args)
+		require.NoError(t, err)
+		return nil
+	})
+
+	_, ok := mocks.Find("pulumi:providers:castai", "prov-provider")
+	require.True(t, ok)
+	cluster, _ := mocks.Find(aksClusterType, "prov-cluster")
+	assert.Contains(t, cluster.Provider, "prov-provider")
+}
+
+// TestValidation tests that invalid arguments fail before any resource is registered
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(args *akscluster.CastAiAksClusterArgs)
+		errMsg string
+	}{
+		{
+			name:   "missing subnets",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) { args.Subnets = pulumi.StringArray{} },
+			errMsg: "subnets is required",
+		},
+		{
+			name:   "missing node resource group",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) { args.NodeResourceGroup = nil },
+			errMsg: "nodeResourceGroup is required",
+		},
+		{
+			name:   "missing credentials",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) { args.ClientSecret = nil },
+			errMsg: "either clientSecret or federationId is required",
+		},
+		{
+			name:   "secret and federation",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) { args.FederationId = pulumi.String("fed") },
+			errMsg: "clientSecret cannot be combined with federationId",
+		},
+		{
+			name: "invalid os disk type",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) {
+				args.NodeConfiguration = &config.NodeConfigurationAksArgs{OsDiskType: pulumi.String("ultra")}
+			},
+			errMsg: "nodeConfiguration.osDiskType must be one of",
+		},
+		{
+			name: "invalid ephemeral disk placement",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) {
+				args.NodeConfiguration = &config.NodeConfigurationAksArgs{
+					EphemeralOsDisk: config.NodeConfigurationAksEphemeralOsDiskArgs{Placement: pulumi.String("tmpfs")},
+				}
+			},
+			errMsg: "nodeConfiguration.ephemeralOsDisk.placement must be one of",
+		},
+		{
+			name: "invalid ephemeral disk placement by pointer",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) {
+				args.NodeConfiguration = &config.NodeConfigurationAksArgs{
+					EphemeralOsDisk: &config.NodeConfigurationAksEphemeralOsDiskArgs{Placement: pulumi.String("tmpfs")},
+				}
+			},
+			errMsg: "nodeConfiguration.ephemeralOsDisk.placement must be one of",
+		},
+		{
+			name: "invalid ephemeral disk placement by ptr function",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) {
+				args.NodeConfiguration = &config.NodeConfigurationAksArgs{
+					EphemeralOsDisk: config.NodeConfigurationAksEphemeralOsDiskPtr(&config.NodeConfigurationAksEphemeralOsDiskArgs{Placement: pulumi.String("tmpfs")}),
+				}
+			},
+			errMsg: "nodeConfiguration.ephemeralOsDisk.placement must be one of",
+		},
+		{
+			name: "invalid autoscaler policy",
+			mutate: func(args *akscluster.CastAiAksClusterArgs) {
+				args.Autoscaler = &autoscalerpolicy.Policy{
+					SpotInstances: &autoscalerpolicy.SpotInstances{
+						MaxReclaimRate: autoscalerpolicy.Int(150),
+					},
+				}
+			},
+			errMsg: "invalid autoscaler policy: spotInstances.maxReclaimRate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := castaitest.NewMocks()
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				args := baseArgs()
+				tt.mutate(args)
+				_, err := akscluster.NewCastAiAksCluster(ctx, "invalid", args)
+				return err
+			}, pulumi.WithMocks("project", "stack", mocks))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+			assert.Empty(t, mocks.Registrations())
+		})
+	}
+}
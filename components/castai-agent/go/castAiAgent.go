@@ -0,0 +1,353 @@
+// Package castaiagent provides CastAiAgent, a component resource that installs
+// the CAST AI agent and controllers into a cluster with Helm.
+//
+// It is the phase-2 counterpart of the cluster components: castai-agent is
+// installed with the cluster token of any CAST AI cluster resource, and unless
+// ReadOnlyMode is set, the charts CAST AI needs to manage the cluster follow:
+// castai-cluster-controller, castai-spot-handler, castai-evictor,
+// castai-pod-pinner and castai-workload-autoscaler. Every chart is pinned to
+// the version in DefaultVersions, can be disabled, and takes extra values that
+// are merged over the ones the component sets.
+//
+// The releases are created with pulumi-kubernetes. Pass the Kubernetes
+// provider of the cluster with pulumi.Providers; children of the component
+// inherit it.
+//
+// Example usage:
+//
+//	agent, err := castaiagent.NewCastAiAgent(ctx, "castai", &castaiagent.CastAiAgentArgs{
+//		Cloud:        castaiagent.EKS,
+//		ClusterId:    cluster.ClusterId,
+//		ClusterToken: cluster.ClusterToken,
+//		Evictor:      castaiagent.ChartArgs{Disabled: true},
+//	}, pulumi.Providers(k8sProvider))
+package castaiagent
+
+import (
+	"errors"
+	"fmt"
+
+	helmv3 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/helm/v3"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// ComponentType is the type token of CastAiAgent.
+const ComponentType = "castai:index:CastAiAgent"
+
+const (
+	// Repository is the Helm repository the charts are installed from.
+	Repository = "https://castai.github.io/helm-charts"
+	// DefaultNamespace is the namespace the charts are installed into.
+	DefaultNamespace = "castai-agent"
+	// DefaultApiUrl is the CAST AI API the charts connect to.
+	DefaultApiUrl = "https://api.cast.ai"
+)
+
+// Chart names.
+const (
+	AgentChart              = "castai-agent"
+	ClusterControllerChart  = "castai-cluster-controller"
+	SpotHandlerChart        = "castai-spot-handler"
+	EvictorChart            = "castai-evictor"
+	PodPinnerChart          = "castai-pod-pinner"
+	WorkloadAutoscalerChart = "castai-workload-autoscaler"
+)
+
+// DefaultVersions are the chart versions installed when ChartArgs.Version is
+// empty. They are the versions the component is tested with and are updated
+// with its releases.
+var DefaultVersions = map[string]string{
+	AgentChart:              "0.119.0",
+	ClusterControllerChart:  "0.85.0",
+	SpotHandlerChart:        "0.29.0",
+	EvictorChart:            "0.33.0",
+	PodPinnerChart:          "1.3.0",
+	WorkloadAutoscalerChart: "0.1.117",
+}
+
+// Cloud is the cloud provider of the cluster.
+type Cloud string
+
+const (
+	EKS Cloud = "eks"
+	GKE Cloud = "gke"
+	AKS Cloud = "aks"
+)
+
+// spotProviders are the castai.provider values of castai-spot-handler, which
+// names the cloud rather than the Kubernetes service.
+var spotProviders = map[Cloud]string{
+	EKS: "aws",
+	GKE: "gcp",
+	AKS: "azure",
+}
+
+// ChartArgs customizes one chart.
+type ChartArgs struct {
+	// Disabled skips the chart.
+	Disabled bool
+	// Version overrides the version in DefaultVersions.
+	Version string
+	// Values are merged over the values the component sets. Nested pulumi.Map
+	// values are merged key by key; any other value replaces the default.
+	Values pulumi.Map
+}
+
+// CastAiAgentArgs are the inputs of CastAiAgent.
+type CastAiAgentArgs struct {
+	// Cloud is the cloud provider of the cluster.
+	Cloud Cloud
+	// ClusterId is the CAST AI cluster ID, e.g. the ClusterId output of a
+	// cluster component or the ID of a castai cluster resource.
+	ClusterId pulumi.StringInput
+	// ClusterToken is the token of the cluster the charts authenticate with.
+	// It is marked secret.
+	ClusterToken pulumi.StringInput
+
+	// ApiUrl is the CAST AI API URL (default: DefaultApiUrl).
+	ApiUrl pulumi.StringInput
+	// Namespace is the namespace of the releases (default: DefaultNamespace).
+	// It is created with the first release, which the others depend on.
+	Namespace string
+	// ReadOnlyMode only installs castai-agent, for clusters that are monitored
+	// but not managed by CAST AI.
+	ReadOnlyMode bool
+
+	// Agent customizes castai-agent. Disable it only if the agent is installed
+	// by other means.
+	Agent ChartArgs
+	// ClusterController customizes castai-cluster-controller.
+	ClusterController ChartArgs
+	// SpotHandler customizes castai-spot-handler.
+	SpotHandler ChartArgs
+	// Evictor customizes castai-evictor. It is installed with no replicas, which
+	// the autoscaler scales up once evictor is enabled in its policies.
+	Evictor ChartArgs
+	// PodPinner customizes castai-pod-pinner. It is installed with no replicas.
+	PodPinner ChartArgs
+	// WorkloadAutoscaler customizes castai-workload-autoscaler. It reads its
+	// credentials from the castai-cluster-controller release, so it needs the
+	// cluster controller to be installed, by this component or otherwise.
+	WorkloadAutoscaler ChartArgs
+}
+
+// CastAiAgent installs the CAST AI charts into a cluster.
+type CastAiAgent struct {
+	pulumi.ResourceState
+
+	// Releases are the installed releases, keyed by chart name.
+	Releases map[string]*helmv3.Release
+	// Versions are the installed chart versions, keyed by chart name.
+	Versions map[string]string
+}
+
+// chart is a release the component installs.
+type chart struct {
+	name    string
+	release string
+	args    ChartArgs
+	values  pulumi.Map
+	// dependsOn lists the charts that are installed first.
+	dependsOn []string
+}
+
+// NewCastAiAgent registers a new CastAiAgent component.
+func NewCastAiAgent(ctx *pulumi.Context, name string, args *CastAiAgentArgs, opts ...pulumi.ResourceOption) (*CastAiAgent, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+
+	component := &CastAiAgent{
+		Releases: map[string]*helmv3.Release{},
+		Versions: map[string]string{},
+	}
+	if err := ctx.RegisterComponentResource(ComponentType, name, component, opts...); err != nil {
+		return nil, err
+	}
+
+	// namespaceRelease is the release that creates the namespace. The other
+	// releases depend on it, including the ones that do not depend on its
+	// chart, so that none is installed before the namespace exists.
+	var namespaceRelease *helmv3.Release
+	for _, c := range args.charts() {
+		if c.args.Disabled {
+			continue
+		}
+		version := c.args.Version
+		if version == "" {
+			version = DefaultVersions[c.name]
+		}
+
+		var dependsOn []pulumi.Resource
+		if namespaceRelease != nil {
+			dependsOn = append(dependsOn, namespaceRelease)
+		}
+		for _, dep := range c.dependsOn {
+			if r, ok := component.Releases[dep]; ok && r != namespaceRelease {
+				dependsOn = append(dependsOn, r)
+			}
+		}
+		// The first release creates the namespace. Only castai-agent waits for
+		// its pods: the other charts have nothing to run until the agent has
+		// connected the cluster.
+		first := namespaceRelease == nil
+		release, err := helmv3.NewRelease(ctx, name+"-"+c.release, &helmv3.ReleaseArgs{
+			Name:            pulumi.String(c.release),
+			Chart:           pulumi.String(c.name),
+			Version:         pulumi.String(version),
+			RepositoryOpts:  helmv3.RepositoryOptsArgs{Repo: pulumi.String(Repository)},
+			Namespace:       pulumi.String(args.namespace()),
+			CreateNamespace: pulumi.Bool(first),
+			CleanupOnFail:   pulumi.Bool(true),
+			Timeout:         pulumi.Int(300),
+			SkipAwait:       pulumi.Bool(c.name != AgentChart),
+			Values:          mergeValues(c.values, c.args.Values),
+		}, pulumi.Parent(component), pulumi.DependsOn(dependsOn))
+		if err != nil {
+			return nil, fmt.Errorf("installing %s: %w", c.name, err)
+		}
+		if first {
+			namespaceRelease = release
+		}
+		component.Releases[c.name] = release
+		component.Versions[c.name] = version
+	}
+
+	if err := ctx.RegisterResourceOutputs(component, pulumi.Map{
+		"versions": pulumi.ToStringMap(component.Versions),
+	}); err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+// charts returns the charts to install, in order. Disabled charts are
+// filtered out by the caller, so dependencies on them are dropped.
+func (args *CastAiAgentArgs) charts() []chart {
+	apiURL := args.ApiUrl
+	if apiURL == nil {
+		apiURL = pulumi.String(DefaultApiUrl)
+	}
+	token := pulumi.ToSecret(args.ClusterToken).(pulumi.StringOutput)
+
+	charts := []chart{{
+		name:    AgentChart,
+		release: "castai-agent",
+		args:    args.Agent,
+		values: pulumi.Map{
+			"provider":        pulumi.String(string(args.Cloud)),
+			"createNamespace": pulumi.Bool(false),
+			"apiURL":          apiURL,
+			"apiKey":          token,
+		},
+	}}
+	if args.ReadOnlyMode {
+		return charts
+	}
+
+	return append(charts,
+		chart{
+			name:    ClusterControllerChart,
+			release: "cluster-controller",
+			args:    args.ClusterController,
+			values: pulumi.Map{
+				"castai": pulumi.Map{
+					"clusterID": args.ClusterId,
+					"apiURL":    apiURL,
+					"apiKey":    token,
+				},
+			},
+			dependsOn: []string{AgentChart},
+		},
+		chart{
+			name:    SpotHandlerChart,
+			release: "castai-spot-handler",
+			args:    args.SpotHandler,
+			values: pulumi.Map{
+				"castai": pulumi.Map{
+					"clusterID": args.ClusterId,
+					"apiURL":    apiURL,
+					"provider":  pulumi.String(spotProviders[args.Cloud]),
+				},
+			},
+			dependsOn: []string{AgentChart},
+		},
+		chart{
+			name:    EvictorChart,
+			release: "castai-evictor",
+			args:    args.Evictor,
+			values: pulumi.Map{
+				"replicaCount": pulumi.Int(0),
+			},
+			dependsOn: []string{AgentChart},
+		},
+		chart{
+			name:    PodPinnerChart,
+			release: "castai-pod-pinner",
+			args:    args.PodPinner,
+			values: pulumi.Map{
+				"castai": pulumi.Map{
+					"clusterID": args.ClusterId,
+					"apiURL":    apiURL,
+					"apiKey":    token,
+				},
+				"replicaCount": pulumi.Int(0),
+			},
+			dependsOn: []string{AgentChart},
+		},
+		chart{
+			name:    WorkloadAutoscalerChart,
+			release: "castai-workload-autoscaler",
+			args:    args.WorkloadAutoscaler,
+			values: pulumi.Map{
+				"castai": pulumi.Map{
+					"apiKeySecretRef": pulumi.String("castai-cluster-controller"),
+					"configMapRef":    pulumi.String("castai-cluster-controller"),
+				},
+			},
+			dependsOn: []string{AgentChart, ClusterControllerChart},
+		},
+	)
+}
+
+func (args *CastAiAgentArgs) namespace() string {
+	if args.Namespace == "" {
+		return DefaultNamespace
+	}
+	return args.Namespace
+}
+
+func (args *CastAiAgentArgs) validate() error {
+	if args == nil {
+		return errors.New("missing required arguments")
+	}
+	var errs []error
+	if _, ok := spotProviders[args.Cloud]; !ok {
+		errs = append(errs, fmt.Errorf("cloud must be one of %q, %q or %q, got %q", EKS, GKE, AKS, args.Cloud))
+	}
+	if args.ClusterId == nil {
+		errs = append(errs, errors.New("clusterId is required"))
+	}
+	if args.ClusterToken == nil {
+		errs = append(errs, errors.New("clusterToken is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// mergeValues returns a copy of defaults with overrides merged in.
+func mergeValues(defaults, overrides pulumi.Map) pulumi.Map {
+	out := make(pulumi.Map, len(defaults)+len(overrides))
+	for k, v := range defaults {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		base, ok := out[k].(pulumi.Map)
+		override, isMap := v.(pulumi.Map)
+		if ok && isMap {
+			out[k] = mergeValues(base, override)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,242 @@
+package tests
+
+import (
+	"testing"
+
+	castaiagent "github.com/castai/pulumi-castai/components/castai-agent/go"
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/castaitest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const releaseType = "kubernetes:helm.sh/v3:Release"
+
+// releases returns the registered releases keyed by chart name.
+func releases(mocks *castaitest.Mocks) map[string]castaitest.Registration {
+	out := map[string]castaitest.Registration{}
+	for _, r := range mocks.RegistrationsOf(releaseType) {
+		out[r.Inputs["chart"].StringValue()] = r
+	}
+	return out
+}
+
+// value returns a nested value of a release, unwrapping secrets.
+func value(r castaitest.Registration, path ...string) resource.PropertyValue {
+	v := r.Inputs["values"]
+	for _, key := range path {
+		for v.IsSecret() {
+			v = v.SecretValue().Element
+		}
+		v = v.ObjectValue()[resource.PropertyKey(key)]
+	}
+	return v
+}
+
+// TestFullInstall tests that all charts are installed with the outputs of a cluster resource
+func TestFullInstall(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		cluster, err := castai.NewEksCluster(ctx, "cluster", &castai.EksClusterArgs{
+			AccountId: pulumi.String("123456789012"),
+			Region:    pulumi.String("us-east-1"),
+			Name:      pulumi.String("my-eks-cluster"),
+		})
+		require.NoError(t, err)
+
+		agent, err := castaiagent.NewCastAiAgent(ctx, "castai", &castaiagent.CastAiAgentArgs{
+			Cloud:        castaiagent.EKS,
+			ClusterId:    cluster.ID().ToStringOutput(),
+			ClusterToken: cluster.ClusterToken,
+		})
+		require.NoError(t, err)
+		assert.Len(t, agent.Releases, 6)
+		assert.Equal(t, castaiagent.DefaultVersions, agent.Versions)
+		return nil
+	})
+
+	cluster, ok := mocks.Find("castai:aws:EksCluster", "cluster")
+	require.True(t, ok)
+	token := cluster.Outputs["clusterToken"].StringValue()
+
+	charts := releases(mocks)
+	require.Len(t, charts, 6)
+	for name, r := range charts {
+		assert.Equal(t, castaiagent.DefaultVersions[name], r.Inputs["version"].StringValue(), name)
+		assert.Equal(t, castaiagent.Repository, r.Inputs["repositoryOpts"].ObjectValue()["repo"].StringValue(), name)
+		assert.Equal(t, castaiagent.DefaultNamespace, r.Inputs["namespace"].StringValue(), name)
+		assert.Equal(t, name == castaiagent.AgentChart, r.Inputs["createNamespace"].BoolValue(), name)
+		if name != castaiagent.AgentChart {
+			assert.Contains(t, dependencyNames(r), charts[castaiagent.AgentChart].Name, name)
+		}
+	}
+
+	agent := charts[castaiagent.AgentChart]
+	assert.Equal(t, "eks", value(agent, "provider").StringValue())
+	assert.Equal(t, castaiagent.DefaultApiUrl, value(agent, "apiURL").StringValue())
+	assert.True(t, value(agent, "apiKey").IsSecret(), "the cluster token must be secret")
+	assert.Equal(t, token, value(agent, "apiKey").SecretValue().Element.StringValue())
+
+	controller := charts[castaiagent.ClusterControllerChart]
+	assert.Equal(t, "cluster-controller", controller.Inputs["name"].StringValue())
+	assert.Equal(t, cluster.ID, value(controller, "castai", "clusterID").StringValue())
+	assert.True(t, value(controller, "castai", "apiKey").IsSecret())
+
+	assert.Equal(t, "aws", value(charts[castaiagent.SpotHandlerChart], "castai", "provider").StringValue())
+	assert.Equal(t, float64(0), value(charts[castaiagent.EvictorChart], "replicaCount").NumberValue())
+	assert.Equal(t, "castai-cluster-controller",
+		value(charts[castaiagent.WorkloadAutoscalerChart], "castai", "apiKeySecretRef").StringValue())
+}
+
+// TestClouds tests the provider values of each cloud
+func TestClouds(t *testing.T) {
+	for _, tc := range []struct {
+		cloud castaiagent.Cloud
+		spot  string
+	}{
+		{cloud: castaiagent.EKS, spot: "aws"},
+		{cloud: castaiagent.GKE, spot: "gcp"},
+		{cloud: castaiagent.AKS, spot: "azure"},
+	} {
+		t.Run(string(tc.cloud), func(t *testing.T) {
+			mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+				_, err := castaiagent.NewCastAiAgent(ctx, "castai", &castaiagent.CastAiAgentArgs{
+					Cloud:        tc.cloud,
+					ClusterId:    pulumi.String("cluster-id"),
+					ClusterToken: pulumi.String("token"),
+				})
+				return err
+			})
+
+			charts := releases(mocks)
+			assert.Equal(t, string(tc.cloud), value(charts[castaiagent.AgentChart], "provider").StringValue())
+			assert.Equal(t, tc.spot, value(charts[castaiagent.SpotHandlerChart], "castai", "provider").StringValue())
+		})
+	}
+}
+
+// TestReadOnlyMode tests that read-only mode only installs the agent
+func TestReadOnlyMode(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		_, err := castaiagent.NewCastAiAgent(ctx, "castai", &castaiagent.CastAiAgentArgs{
+			Cloud:        castaiagent.GKE,
+			ClusterId:    pulumi.String("cluster-id"),
+			ClusterToken: pulumi.String("token"),
+			ReadOnlyMode: true,
+		})
+		return err
+	})
+
+	charts := releases(mocks)
+	assert.Len(t, charts, 1)
+	assert.Contains(t, charts, castaiagent.AgentChart)
+}
+
+// TestChartArgs tests disabling charts, pinning versions and overriding values
+func TestChartArgs(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		agent, err := castaiagent.NewCastAiAgent(ctx, "castai", &castaiagent.CastAiAgentArgs{
+			Cloud:        castaiagent.AKS,
+			ClusterId:    pulumi.String("cluster-id"),
+			ClusterToken: pulumi.String("token"),
+			ApiUrl:       pulumi.String("https://api.eu.cast.ai"),
+			Namespace:    "castai",
+			Agent:        castaiagent.ChartArgs{Version: "1.2.3"},
+			Evictor:      castaiagent.ChartArgs{Disabled: true},
+			PodPinner: castaiagent.ChartArgs{Values: pulumi.Map{
+				"replicaCount": pulumi.Int(2),
+				"castai":       pulumi.Map{"clusterID": pulumi.String("other")},
+			}},
+			WorkloadAutoscaler: castaiagent.ChartArgs{Disabled: true},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3", agent.Versions[castaiagent.AgentChart])
+		assert.NotContains(t, agent.Releases, castaiagent.EvictorChart)
+
+		_, ok := agent.Versions[castaiagent.WorkloadAutoscalerChart]
+		assert.False(t, ok)
+		return nil
+	})
+
+	charts := releases(mocks)
+	assert.Len(t, charts, 4)
+	assert.NotContains(t, charts, castaiagent.EvictorChart)
+	assert.NotContains(t, charts, castaiagent.WorkloadAutoscalerChart)
+
+	agent := charts[castaiagent.AgentChart]
+	assert.Equal(t, "1.2.3", agent.Inputs["version"].StringValue())
+	assert.Equal(t, "castai", agent.Inputs["namespace"].StringValue())
+	assert.Equal(t, "https://api.eu.cast.ai", value(agent, "apiURL").StringValue())
+
+	pinner := charts[castaiagent.PodPinnerChart]
+	assert.Equal(t, float64(2), value(pinner, "replicaCount").NumberValue())
+	assert.Equal(t, "other", value(pinner, "castai", "clusterID").StringValue())
+	assert.Equal(t, "https://api.eu.cast.ai", value(pinner, "castai", "apiURL").StringValue(),
+		"nested values not overridden are kept")
+	assert.True(t, value(pinner, "castai", "apiKey").IsSecret())
+}
+
+// TestDisabledAgent tests that the first installed chart creates the namespace
+// and that the other charts are installed after it
+func TestDisabledAgent(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		_, err := castaiagent.NewCastAiAgent(ctx, "castai", &castaiagent.CastAiAgentArgs{
+			Cloud:        castaiagent.EKS,
+			ClusterId:    pulumi.String("cluster-id"),
+			ClusterToken: pulumi.String("token"),
+			Agent:        castaiagent.ChartArgs{Disabled: true},
+		})
+		return err
+	})
+
+	charts := releases(mocks)
+	assert.Len(t, charts, 5)
+	assert.True(t, charts[castaiagent.ClusterControllerChart].Inputs["createNamespace"].BoolValue())
+	assert.False(t, charts[castaiagent.SpotHandlerChart].Inputs["createNamespace"].BoolValue())
+
+	namespace := charts[castaiagent.ClusterControllerChart]
+	for name, r := range charts {
+		if name == castaiagent.ClusterControllerChart {
+			continue
+		}
+		assert.Contains(t, dependencyNames(r), namespace.Name, name)
+	}
+}
+
+// dependencyNames returns the resource names of the dependencies of a release.
+func dependencyNames(r castaitest.Registration) []string {
+	var names []string
+	for _, urn := range r.Dependencies {
+		names = append(names, resource.URN(urn).Name())
+	}
+	return names
+}
+
+// TestValidation tests that invalid arguments fail before anything is installed
+func TestValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args *castaiagent.CastAiAgentArgs
+		err  string
+	}{
+		{name: "nil", args: nil, err: "missing required arguments"},
+		{
+			name: "cloud",
+			args: &castaiagent.CastAiAgentArgs{Cloud: "eks-anywhere", ClusterId: pulumi.String("id"), ClusterToken: pulumi.String("token")},
+			err:  `cloud must be one of "eks", "gke" or "aks", got "eks-anywhere"`,
+		},
+		{name: "cluster id", args: &castaiagent.CastAiAgentArgs{Cloud: castaiagent.EKS, ClusterToken: pulumi.String("token")}, err: "clusterId is required"},
+		{name: "cluster token", args: &castaiagent.CastAiAgentArgs{Cloud: castaiagent.EKS, ClusterId: pulumi.String("id")}, err: "clusterToken is required"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mocks := castaitest.NewMocks()
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				_, err := castaiagent.NewCastAiAgent(ctx, "castai", tc.args)
+				return err
+			}, pulumi.WithMocks("project", "stack", mocks))
+			assert.ErrorContains(t, err, tc.err)
+			assert.Empty(t, mocks.Registrations())
+		})
+	}
+}
@@ -0,0 +1,651 @@
+// Package ekscluster provides CastAiEksCluster, a component resource that
+// connects an existing Amazon EKS cluster to CAST AI.
+//
+// It is the Go counterpart of the TypeScript component in
+// components/eks-cluster/typescript and follows the same model:
+//
+//   - Phase 1 registers the cluster with CAST AI. In read-only mode this is all
+//     the component does, and the cluster can be monitored once the agent is
+//     installed with the returned cluster token.
+//   - Phase 2 (ReadOnlyMode false) looks up the CAST AI user ARN, creates or
+//     takes the IAM role CAST AI assumes, attaches it to the cluster and
+//     creates the default node configuration, optional node templates and the
+//     optional autoscaler policies.
+//
+// By default the IAM resources are created with DefaultEksIam, like the
+// EksIamResources component of the TypeScript version. Pass the ARNs of an
+// existing role and instance profile, or an Iam function, to override it.
+//
+// Example usage:
+//
+//	cluster, err := ekscluster.NewCastAiEksCluster(ctx, "my-cluster", &ekscluster.CastAiEksClusterArgs{
+//		ClusterName:    "my-eks-cluster",
+//		Region:         "us-east-1",
+//		AccountId:      pulumi.String("123456789012"),
+//		Subnets:        pulumi.ToStringArray([]string{"subnet-xxx", "subnet-yyy"}),
+//		SecurityGroups: pulumi.ToStringArray([]string{"sg-xxx"}),
+//	})
+package ekscluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscalerpolicy"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/eks"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// ComponentType is the type token of CastAiEksCluster.
+const ComponentType = "castai:eks:CastAiEksCluster"
+
+// EksIamComponentType is the type token of the component DefaultEksIam
+// registers.
+const EksIamComponentType = "castai:aws:EksIamResources"
+
+// EksIamArgs is passed to an EksIamFunc once the cluster is registered.
+type EksIamArgs struct {
+	// ClusterName is the name of the EKS cluster.
+	ClusterName string
+	// Region is the AWS region of the cluster.
+	Region string
+	// AccountId is the AWS account ID of the cluster.
+	AccountId pulumi.StringInput
+	// ClusterId is the CAST AI cluster ID, for tagging and naming.
+	ClusterId pulumi.StringOutput
+	// CastaiUserArn is the CAST AI user that must be trusted by the role.
+	CastaiUserArn pulumi.StringOutput
+}
+
+// EksIam are the IAM resources CAST AI needs to manage the cluster.
+type EksIam struct {
+	// RoleArn is the ARN of the role CAST AI assumes.
+	RoleArn pulumi.StringInput
+	// InstanceProfileArn is the instance profile attached to CAST AI nodes.
+	InstanceProfileArn pulumi.StringInput
+	// SecurityGroupId is an optional security group added to CAST AI nodes,
+	// in addition to CastAiEksClusterArgs.SecurityGroups.
+	SecurityGroupId pulumi.StringInput
+	// DependsOn lists resources that must exist before the role is attached
+	// to the cluster, such as policy attachments.
+	DependsOn []pulumi.Resource
+}
+
+// EksIamFunc creates the IAM resources for a cluster. It is called with the
+// component as parent, so resources it creates should pass opts through.
+type EksIamFunc func(ctx *pulumi.Context, name string, args *EksIamArgs, opts ...pulumi.ResourceOption) (*EksIam, error)
+
+// CastAiEksClusterArgs are the inputs of CastAiEksCluster.
+type CastAiEksClusterArgs struct {
+	// ClusterName is the name of the EKS cluster to connect to CAST AI.
+	ClusterName string
+	// Region is the AWS region where the cluster is located.
+	Region string
+	// AccountId is the AWS account ID.
+	AccountId pulumi.StringInput
+
+	// ApiToken is the CAST AI API token. When set, the component creates its
+	// own castai.Provider; otherwise the default or inherited provider is used.
+	ApiToken pulumi.StringPtrInput
+	// ApiUrl is the CAST AI API URL used with ApiToken.
+	ApiUrl pulumi.StringPtrInput
+
+	// ReadOnlyMode only registers the cluster for monitoring and skips phase 2.
+	ReadOnlyMode bool
+
+	// Subnets are the subnet IDs for CAST AI provisioned nodes. Required when
+	// ReadOnlyMode is false.
+	Subnets pulumi.StringArrayInput
+	// SecurityGroups are the security group IDs for CAST AI provisioned nodes.
+	// Required when ReadOnlyMode is false.
+	SecurityGroups pulumi.StringArrayInput
+
+	// AssumeRoleArn is an existing role for CAST AI to assume. It is set
+	// together with InstanceProfileArn and replaces the default IAM resources.
+	AssumeRoleArn pulumi.StringInput
+	// InstanceProfileArn is an existing instance profile for CAST AI nodes.
+	InstanceProfileArn pulumi.StringInput
+	// Iam creates the role and instance profile once the CAST AI user ARN is
+	// known. It defaults to DefaultEksIam unless the ARNs are set.
+	Iam EksIamFunc
+
+	// DeleteNodesOnDisconnect removes CAST AI nodes when the cluster is disconnected.
+	DeleteNodesOnDisconnect bool
+	// Tags are added to CAST AI provisioned nodes.
+	Tags pulumi.StringMapInput
+	// NodeTemplates are created on top of the default node configuration, keyed
+	// by template name. ClusterId and ConfigurationId are filled in when unset.
+	NodeTemplates map[string]*config.NodeTemplateArgs
+	// Autoscaler, when set, applies these autoscaler policies to the cluster.
+	Autoscaler *autoscalerpolicy.Policy
+}
+
+// CastAiEksCluster connects an EKS cluster to CAST AI.
+type CastAiEksCluster struct {
+	pulumi.ResourceState
+
+	// Cluster is the registered cluster.
+	Cluster *castai.EksCluster
+
+	// ClusterId is the CAST AI cluster ID.
+	ClusterId pulumi.StringOutput
+	// ClusterToken is the token the CAST AI agent authenticates with. It is
+	// marked secret.
+	ClusterToken pulumi.StringOutput
+	// CredentialsId is the ID of the cluster credentials in CAST AI.
+	CredentialsId pulumi.StringOutput
+	// OrganizationId is the CAST AI organization owning the cluster.
+	OrganizationId pulumi.StringOutput
+
+	// The following fields are only set when ReadOnlyMode is false.
+
+	// CastaiUserArn is the CAST AI user trusted by the IAM role.
+	CastaiUserArn pulumi.StringOutput
+	// CastaiRoleArn is the role CAST AI assumes.
+	CastaiRoleArn pulumi.StringOutput
+	// InstanceProfileArn is the instance profile of CAST AI nodes.
+	InstanceProfileArn pulumi.StringOutput
+	// NodeConfigurationId is the ID of the default node configuration.
+	NodeConfigurationId pulumi.StringOutput
+	// NodeTemplates are the created node templates, keyed by template name.
+	NodeTemplates map[string]*config.NodeTemplate
+	// Autoscaler is the autoscaler policy resource, if Autoscaler was set.
+	Autoscaler *castai.Autoscaler
+
+	readOnly bool
+}
+
+// NewCastAiEksCluster registers a new CastAiEksCluster component.
+func NewCastAiEksCluster(ctx *pulumi.Context, name string, args *CastAiEksClusterArgs, opts ...pulumi.ResourceOption) (*CastAiEksCluster, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+
+	component := &CastAiEksCluster{readOnly: args.ReadOnlyMode}
+	if err := ctx.RegisterComponentResource(ComponentType, name, component, opts...); err != nil {
+		return nil, err
+	}
+	childOpts := []pulumi.ResourceOption{pulumi.Parent(component)}
+
+	if args.ApiToken != nil {
+		provider, err := castai.NewProvider(ctx, name+"-provider", &castai.ProviderArgs{
+			ApiToken: args.ApiToken,
+			ApiUrl:   args.ApiUrl,
+		}, childOpts...)
+		if err != nil {
+			return nil, err
+		}
+		childOpts = append(childOpts, pulumi.Provider(provider))
+	}
+
+	if args.ReadOnlyMode {
+		cluster, err := castai.NewEksCluster(ctx, name+"-cluster", &castai.EksClusterArgs{
+			AccountId:               args.AccountId,
+			Region:                  pulumi.String(args.Region),
+			Name:                    pulumi.String(args.ClusterName),
+			DeleteNodesOnDisconnect: pulumi.Bool(args.DeleteNodesOnDisconnect),
+		}, childOpts...)
+		if err != nil {
+			return nil, err
+		}
+		component.setCluster(cluster)
+		if err := ctx.RegisterResourceOutputs(component, component.outputs()); err != nil {
+			return nil, err
+		}
+		return component, nil
+	}
+
+	// Phase 1: get the cluster ID and the CAST AI user that the IAM role trusts.
+	clusterID, err := castai.NewEksClusterId(ctx, name+"-cluster-id", &castai.EksClusterIdArgs{
+		AccountId:   args.AccountId,
+		Region:      pulumi.String(args.Region),
+		ClusterName: pulumi.String(args.ClusterName),
+	}, childOpts...)
+	if err != nil {
+		return nil, err
+	}
+	userArn, err := castai.NewEksUserArn(ctx, name+"-user-arn", &castai.EksUserArnArgs{
+		ClusterId: clusterID.ID().ToStringOutput(),
+	}, childOpts...)
+	if err != nil {
+		return nil, err
+	}
+	component.CastaiUserArn = userArn.Arn
+
+	iamFunc := args.Iam
+	if iamFunc == nil && args.AssumeRoleArn == nil {
+		iamFunc = DefaultEksIam
+	}
+	iamOut := &EksIam{
+		RoleArn:            args.AssumeRoleArn,
+		InstanceProfileArn: args.InstanceProfileArn,
+	}
+	if iamFunc != nil {
+		iamOut, err = iamFunc(ctx, name+"-iam", &EksIamArgs{
+			ClusterName:   args.ClusterName,
+			Region:        args.Region,
+			AccountId:     args.AccountId,
+			ClusterId:     clusterID.ID().ToStringOutput(),
+			CastaiUserArn: userArn.Arn,
+		}, pulumi.Parent(component))
+		if err != nil {
+			return nil, fmt.Errorf("creating IAM resources: %w", err)
+		}
+		if iamOut == nil || iamOut.RoleArn == nil || iamOut.InstanceProfileArn == nil {
+			return nil, errors.New("Iam must return a RoleArn and an InstanceProfileArn")
+		}
+	}
+	component.CastaiRoleArn = iamOut.RoleArn.ToStringOutput()
+	component.InstanceProfileArn = iamOut.InstanceProfileArn.ToStringOutput()
+
+	// Phase 2: attach the role to the cluster so CAST AI can manage it.
+	cluster, err := castai.NewEksCluster(ctx, name+"-cluster", &castai.EksClusterArgs{
+		AccountId:               args.AccountId,
+		Region:                  pulumi.String(args.Region),
+		Name:                    pulumi.String(args.ClusterName),
+		AssumeRoleArn:           component.CastaiRoleArn,
+		DeleteNodesOnDisconnect: pulumi.Bool(args.DeleteNodesOnDisconnect),
+	}, append(childOpts, pulumi.DependsOn(append([]pulumi.Resource{clusterID}, iamOut.DependsOn...)))...)
+	if err != nil {
+		return nil, err
+	}
+	component.setCluster(cluster)
+
+	securityGroups := args.SecurityGroups.ToStringArrayOutput()
+	if iamOut.SecurityGroupId != nil {
+		securityGroups = pulumi.All(iamOut.SecurityGroupId, args.SecurityGroups).ApplyT(func(all []interface{}) []string {
+			return append([]string{all[0].(string)}, all[1].([]string)...)
+		}).(pulumi.StringArrayOutput)
+	}
+
+	nodeConfig, err := config.NewNodeConfiguration(ctx, name+"-node-config-default", &config.NodeConfigurationArgs{
+		ClusterId: component.ClusterId,
+		Name:      pulumi.String("default"),
+		Subnets:   args.Subnets,
+		Tags:      args.Tags,
+		Eks: config.NodeConfigurationEksArgs{
+			InstanceProfileArn: component.InstanceProfileArn,
+			SecurityGroups:     securityGroups,
+		},
+	}, append(childOpts, pulumi.DependsOn([]pulumi.Resource{cluster}))...)
+	if err != nil {
+		return nil, err
+	}
+	component.NodeConfigurationId = nodeConfig.ID().ToStringOutput()
+
+	defaultConfig, err := config.NewNodeConfigurationDefault(ctx, name+"-node-config-default-ref", &config.NodeConfigurationDefaultArgs{
+		ClusterId:       component.ClusterId,
+		ConfigurationId: component.NodeConfigurationId,
+	}, childOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	component.NodeTemplates = map[string]*config.NodeTemplate{}
+	templates := []pulumi.Resource{defaultConfig}
+	for _, key := range sortedKeys(args.NodeTemplates) {
+		templateArgs := *args.NodeTemplates[key]
+		if templateArgs.ClusterId == nil {
+			templateArgs.ClusterId = component.ClusterId
+		}
+		if templateArgs.ConfigurationId == nil {
+			templateArgs.ConfigurationId = component.NodeConfigurationId
+		}
+		if templateArgs.Name == nil {
+			templateArgs.Name = pulumi.String(key)
+		}
+		template, err := config.NewNodeTemplate(ctx, name+"-template-"+key, &templateArgs,
+			append(childOpts, pulumi.DependsOn([]pulumi.Resource{defaultConfig}))...)
+		if err != nil {
+			return nil, err
+		}
+		component.NodeTemplates[key] = template
+		templates = append(templates, template)
+	}
+
+	if args.Autoscaler != nil {
+		component.Autoscaler, err = castai.NewAutoscaler(ctx, name+"-autoscaler", &castai.AutoscalerArgs{
+			ClusterId:          component.ClusterId,
+			AutoscalerSettings: args.Autoscaler.ToSettingsArgs(),
+		}, append(childOpts, pulumi.DependsOn(templates))...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ctx.RegisterResourceOutputs(component, component.outputs()); err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+// EksIamResources are the IAM resources DefaultEksIam creates.
+type EksIamResources struct {
+	pulumi.ResourceState
+
+	// Role is the role CAST AI assumes.
+	Role *iam.Role
+	// NodeRole is the role of CAST AI provisioned nodes.
+	NodeRole *iam.Role
+	// InstanceProfile holds NodeRole.
+	InstanceProfile *iam.InstanceProfile
+	// AccessEntry lets NodeRole join the cluster.
+	AccessEntry *eks.AccessEntry
+}
+
+// nodePolicies are the AWS managed policies attached to the node role, keyed
+// by the suffix of their attachment name.
+var nodePolicies = map[string]string{
+	"eks-worker-node": "arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy",
+	"eks-cni":         "arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy",
+	"ecr-readonly":    "arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+	"ssm-managed":     "arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore",
+	"ebs-csi":         "arn:aws:iam::aws:policy/service-role/AmazonEBSCSIDriverPolicy",
+}
+
+// DefaultEksIam creates the IAM resources CAST AI needs in an
+// EksIamResources component:
+//
+//   - a role trusting the CAST AI user, with the CAST AI policy and the
+//     AmazonEC2ReadOnlyAccess and IAMReadOnlyAccess managed policies;
+//   - a node role with the EKS worker, CNI, ECR, SSM and EBS CSI managed
+//     policies, and an instance profile holding it;
+//   - an EKS access entry for the node role. Clusters that authenticate with
+//     the aws-auth ConfigMap only need the node role mapped there, or an Iam
+//     function of their own.
+func DefaultEksIam(ctx *pulumi.Context, name string, args *EksIamArgs, opts ...pulumi.ResourceOption) (*EksIam, error) {
+	res := &EksIamResources{}
+	if err := ctx.RegisterComponentResource(EksIamComponentType, name, res, opts...); err != nil {
+		return nil, err
+	}
+	parent := pulumi.Parent(res)
+	// The first 8 characters of the cluster ID keep the names unique per
+	// cluster and within the 64 character limit of role names.
+	idPrefix := args.ClusterId.ApplyT(func(id string) string {
+		if len(id) > 8 {
+			return id[:8]
+		}
+		return id
+	}).(pulumi.StringOutput)
+	tags := pulumi.StringMap{"cast:cluster-id": args.ClusterId}
+
+	var err error
+	res.Role, err = iam.NewRole(ctx, name+"-role", &iam.RoleArgs{
+		Name:        pulumi.Sprintf("cast-eks-%s-role-%s", args.ClusterName, idPrefix),
+		Description: pulumi.Sprintf("CAST AI role for EKS cluster %s", args.ClusterName),
+		AssumeRolePolicy: args.CastaiUserArn.ApplyT(func(arn string) (string, error) {
+			return policyJSON(statement{"Effect": "Allow", "Principal": statement{"AWS": arn}, "Action": "sts:AssumeRole"})
+		}).(pulumi.StringOutput),
+		Tags: tags,
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+	castaiPolicy, err := castaiPolicyJSON(args.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := iam.NewPolicy(ctx, name+"-policy", &iam.PolicyArgs{
+		Name:        pulumi.Sprintf("castai-eks-%s", args.ClusterName),
+		Description: pulumi.Sprintf("CAST AI policy for EKS cluster %s", args.ClusterName),
+		Policy:      pulumi.String(castaiPolicy),
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependsOn []pulumi.Resource
+	attach := func(suffix string, role *iam.Role, policyArn pulumi.StringInput) error {
+		attachment, err := iam.NewRolePolicyAttachment(ctx, name+"-"+suffix, &iam.RolePolicyAttachmentArgs{
+			Role:      role.Name,
+			PolicyArn: policyArn,
+		}, parent)
+		if err != nil {
+			return err
+		}
+		dependsOn = append(dependsOn, attachment)
+		return nil
+	}
+	if err := attach("policy-attachment", res.Role, policy.Arn); err != nil {
+		return nil, err
+	}
+	if err := attach("ec2-readonly", res.Role, pulumi.String("arn:aws:iam::aws:policy/AmazonEC2ReadOnlyAccess")); err != nil {
+		return nil, err
+	}
+	if err := attach("iam-readonly", res.Role, pulumi.String("arn:aws:iam::aws:policy/IAMReadOnlyAccess")); err != nil {
+		return nil, err
+	}
+
+	nodeTrust, err := policyJSON(statement{"Effect": "Allow", "Principal": statement{"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"})
+	if err != nil {
+		return nil, err
+	}
+	res.NodeRole, err = iam.NewRole(ctx, name+"-node-role", &iam.RoleArgs{
+		Name:             pulumi.Sprintf("cast-eks-%s-node-role", args.ClusterName),
+		AssumeRolePolicy: pulumi.String(nodeTrust),
+		Tags:             tags,
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+	for _, suffix := range sortedKeys(nodePolicies) {
+		if err := attach("node-"+suffix, res.NodeRole, pulumi.String(nodePolicies[suffix])); err != nil {
+			return nil, err
+		}
+	}
+
+	res.InstanceProfile, err = iam.NewInstanceProfile(ctx, name+"-instance-profile", &iam.InstanceProfileArgs{
+		Name: pulumi.Sprintf("cast-eks-%s-profile-%s", args.ClusterName, idPrefix),
+		Role: res.NodeRole.Name,
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+	res.AccessEntry, err = eks.NewAccessEntry(ctx, name+"-access-entry", &eks.AccessEntryArgs{
+		ClusterName:  pulumi.String(args.ClusterName),
+		PrincipalArn: res.NodeRole.Arn,
+		Type:         pulumi.String("EC2_LINUX"),
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+	dependsOn = append(dependsOn, res.InstanceProfile, res.AccessEntry)
+
+	if err := ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"roleArn":            res.Role.Arn,
+		"nodeRoleArn":        res.NodeRole.Arn,
+		"instanceProfileArn": res.InstanceProfile.Arn,
+	}); err != nil {
+		return nil, err
+	}
+	return &EksIam{
+		RoleArn:            res.Role.Arn,
+		InstanceProfileArn: res.InstanceProfile.Arn,
+		DependsOn:          dependsOn,
+	}, nil
+}
+
+// statement is a JSON object of an IAM policy document.
+type statement = map[string]any
+
+// policyJSON returns an IAM policy document with the given statements.
+func policyJSON(statements ...statement) (string, error) {
+	doc, err := json.Marshal(map[string]any{"Version": "2012-10-17", "Statement": statements})
+	return string(doc), err
+}
+
+// castaiPolicyJSON returns the policy of the role CAST AI assumes. Instance
+// and autoscaling group actions are limited to resources owned by the
+// cluster.
+func castaiPolicyJSON(clusterName string) (string, error) {
+	owned := func(prefix string) statement {
+		return statement{"StringEquals": statement{prefix + ":ResourceTag/kubernetes.io/cluster/" + clusterName: "owned"}}
+	}
+	return policyJSON(
+		statement{
+			"Sid": "RunInstancesTagging", "Effect": "Allow", "Action": "ec2:CreateTags", "Resource": "*",
+			"Condition": statement{"StringEquals": statement{"ec2:CreateAction": []string{"RunInstances"}}},
+		},
+		statement{
+			"Sid": "RunInstances", "Effect": "Allow", "Action": "ec2:RunInstances",
+			"Resource": []string{
+				"arn:aws:ec2:*::image/*",
+				"arn:aws:ec2:*::snapshot/*",
+				"arn:aws:ec2:*:*:instance/*",
+				"arn:aws:ec2:*:*:key-pair/*",
+				"arn:aws:ec2:*:*:launch-template/*",
+				"arn:aws:ec2:*:*:network-interface/*",
+				"arn:aws:ec2:*:*:security-group/*",
+				"arn:aws:ec2:*:*:subnet/*",
+				"arn:aws:ec2:*:*:volume/*",
+			},
+		},
+		statement{
+			"Sid": "RunInstancesInstanceProfile", "Effect": "Allow", "Action": "iam:PassRole", "Resource": "*",
+			"Condition": statement{"StringEquals": statement{"iam:PassedToService": "ec2.amazonaws.com"}},
+		},
+		statement{
+			"Sid": "InstanceActions", "Effect": "Allow", "Resource": "*", "Condition": owned("ec2"),
+			"Action": []string{"ec2:TerminateInstances", "ec2:StartInstances", "ec2:StopInstances", "ec2:ModifyInstanceAttribute"},
+		},
+		statement{
+			"Sid": "AutoscalingGroups", "Effect": "Allow", "Resource": "*", "Condition": owned("autoscaling"),
+			"Action": []string{
+				"autoscaling:UpdateAutoScalingGroup",
+				"autoscaling:SuspendProcesses",
+				"autoscaling:ResumeProcesses",
+				"autoscaling:TerminateInstanceInAutoScalingGroup",
+			},
+		},
+		statement{"Sid": "EC2CreateTags", "Effect": "Allow", "Action": "ec2:CreateTags", "Resource": "*"},
+		statement{"Sid": "EKS", "Effect": "Allow", "Action": []string{"eks:Describe*", "eks:List*"}, "Resource": "*"},
+		statement{
+			"Sid": "EC2Read", "Effect": "Allow", "Resource": "*",
+			"Action": []string{
+				"ec2:DescribeInstances",
+				"ec2:DescribeImages",
+				"ec2:DescribeVolumes",
+				"ec2:DescribeSecurityGroups",
+				"ec2:DescribeSubnets",
+				"ec2:DescribeInstanceTypes",
+				"ec2:DescribeInstanceTypeOfferings",
+				"ec2:DescribeLaunchTemplateVersions",
+				"ec2:DescribeAvailabilityZones",
+				"ec2:DescribeSpotPriceHistory",
+				"ec2:DescribeRegions",
+			},
+		},
+		statement{
+			"Sid": "AutoscalingRead", "Effect": "Allow", "Resource": "*",
+			"Action": []string{
+				"autoscaling:DescribeAutoScalingGroups",
+				"autoscaling:DescribeLaunchConfigurations",
+				"autoscaling:DescribeScalingActivities",
+			},
+		},
+		statement{
+			"Sid": "IAMRead", "Effect": "Allow", "Resource": "*",
+			"Action": []string{"iam:GetInstanceProfile", "iam:GetRole", "iam:SimulatePrincipalPolicy"},
+		},
+	)
+}
+
+func (c *CastAiEksCluster) setCluster(cluster *castai.EksCluster) {
+	c.Cluster = cluster
+	c.ClusterId = cluster.ID().ToStringOutput()
+	c.ClusterToken = pulumi.ToSecret(cluster.ClusterToken).(pulumi.StringOutput)
+	c.CredentialsId = cluster.CredentialsId
+	c.OrganizationId = cluster.OrganizationId
+}
+
+func (c *CastAiEksCluster) outputs() pulumi.Map {
+	out := pulumi.Map{
+		"clusterId":      c.ClusterId,
+		"clusterToken":   c.ClusterToken,
+		"credentialsId":  c.CredentialsId,
+		"organizationId": c.OrganizationId,
+	}
+	if !c.readOnly {
+		out["castaiUserArn"] = c.CastaiUserArn
+		out["castaiRoleArn"] = c.CastaiRoleArn
+		out["instanceProfileArn"] = c.InstanceProfileArn
+		out["nodeConfigurationId"] = c.NodeConfigurationId
+	}
+	return out
+}
+
+func (args *CastAiEksClusterArgs) validate() error {
+	if args == nil {
+		return errors.New("missing required arguments")
+	}
+	var errs []error
+	if args.ClusterName == "" {
+		errs = append(errs, errors.New("clusterName is required"))
+	}
+	if args.Region == "" {
+		errs = append(errs, errors.New("region is required"))
+	}
+	if args.AccountId == nil {
+		errs = append(errs, errors.New("accountId is required"))
+	}
+
+	if args.ReadOnlyMode {
+		if len(args.NodeTemplates) > 0 {
+			errs = append(errs, errors.New("nodeTemplates cannot be used when readOnlyMode is true"))
+		}
+		if args.Autoscaler != nil {
+			errs = append(errs, errors.New("autoscaler cannot be used when readOnlyMode is true"))
+		}
+		return errors.Join(errs...)
+	}
+
+	if isEmpty(args.Subnets) {
+		errs = append(errs, errors.New("subnets is required when readOnlyMode is false"))
+	}
+	if isEmpty(args.SecurityGroups) {
+		errs = append(errs, errors.New("securityGroups is required when readOnlyMode is false"))
+	}
+	switch {
+	case args.Iam != nil && (args.AssumeRoleArn != nil || args.InstanceProfileArn != nil):
+		errs = append(errs, errors.New("iam cannot be combined with assumeRoleArn or instanceProfileArn"))
+	case (args.AssumeRoleArn == nil) != (args.InstanceProfileArn == nil):
+		errs = append(errs, errors.New("assumeRoleArn and instanceProfileArn must be set together"))
+	}
+	for key, template := range args.NodeTemplates {
+		if template == nil {
+			errs = append(errs, fmt.Errorf("nodeTemplates[%q] is nil", key))
+		}
+	}
+	if args.Autoscaler != nil {
+		if err := args.Autoscaler.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isEmpty reports whether a list input is missing or a literal empty list.
+// Lists only known at deployment time are assumed to be non-empty.
+func isEmpty(in pulumi.StringArrayInput) bool {
+	if in == nil {
+		return true
+	}
+	if arr, ok := in.(pulumi.StringArray); ok {
+		return len(arr) == 0
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,383 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	ekscluster "github.com/castai/pulumi-castai/components/eks-cluster/go"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscalerpolicy"
+	"github.com/castai/pulumi-castai/sdk/go/castai/castaitest"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	eksClusterType        = "castai:aws:EksCluster"
+	nodeConfigurationType = "castai:config/node:NodeConfiguration"
+	nodeTemplateType      = "castai:config/node:NodeTemplate"
+)
+
+func fullArgs() *ekscluster.CastAiEksClusterArgs {
+	return &ekscluster.CastAiEksClusterArgs{
+		ClusterName:        "my-eks-cluster",
+		Region:             "us-east-1",
+		AccountId:          pulumi.String("123456789012"),
+		Subnets:            pulumi.ToStringArray([]string{"subnet-1", "subnet-2"}),
+		SecurityGroups:     pulumi.ToStringArray([]string{"sg-1"}),
+		AssumeRoleArn:      pulumi.String("arn:aws:iam::123456789012:role/castai"),
+		InstanceProfileArn: pulumi.String("arn:aws:iam::123456789012:instance-profile/castai"),
+	}
+}
+
+// TestReadOnlyMode tests that read-only mode only registers the cluster
+func TestReadOnlyMode(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		cluster, err := ekscluster.NewCastAiEksCluster(ctx, "ro", &ekscluster.CastAiEksClusterArgs{
+			ClusterName:  "my-eks-cluster",
+			Region:       "us-east-1",
+			AccountId:    pulumi.String("123456789012"),
+			ReadOnlyMode: true,
+		})
+		require.NoError(t, err)
+
+		id := castaitest.Await(t, cluster.ClusterId)
+		assert.Len(t, id.Value, 36)
+
+		token := castaitest.Await(t, cluster.ClusterToken)
+		assert.NotEmpty(t, token.Value)
+		assert.True(t, token.Secret, "the cluster token must be secret")
+		assert.Nil(t, cluster.NodeTemplates)
+		return nil
+	})
+
+	var types []string
+	for _, r := range mocks.Registrations() {
+		types = append(types, r.Type)
+	}
+	assert.Equal(t, []string{ekscluster.ComponentType, eksClusterType}, types)
+
+	reg, _ := mocks.Find(eksClusterType, "ro-cluster")
+	assert.NotContains(t, reg.Inputs, resource.PropertyKey("assumeRoleArn"))
+	assert.Equal(t, "my-eks-cluster", reg.Inputs["name"].StringValue())
+}
+
+// TestFullOnboardingWithExistingRole tests phase 1 and phase 2 with pre-created IAM resources
+func TestFullOnboardingWithExistingRole(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.DeleteNodesOnDisconnect = true
+		args.Tags = pulumi.StringMap{"team": pulumi.String("platform")}
+		cluster, err := ekscluster.NewCastAiEksCluster(ctx, "full", args)
+		require.NoError(t, err)
+
+		assert.Equal(t, "arn:aws:iam::123456789012:role/castai", castaitest.Await(t, cluster.CastaiRoleArn).Value)
+		assert.Contains(t, castaitest.Await(t, cluster.CastaiUserArn).Value, "arn:aws:iam::")
+		assert.Len(t, castaitest.Await(t, cluster.NodeConfigurationId).Value, 36)
+		return nil
+	})
+
+	_, ok := mocks.Find("castai:aws:EksClusterId", "full-cluster-id")
+	assert.True(t, ok, "phase 1 registers the cluster id")
+	_, ok = mocks.Find("castai:aws:EksUserArn", "full-user-arn")
+	assert.True(t, ok, "phase 1 looks up the CAST AI user ARN")
+
+	cluster, ok := mocks.Find(eksClusterType, "full-cluster")
+	require.True(t, ok)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/castai", cluster.Inputs["assumeRoleArn"].StringValue())
+	assert.True(t, cluster.Inputs["deleteNodesOnDisconnect"].BoolValue())
+
+	nodeConfig, ok := mocks.Find(nodeConfigurationType, "full-node-config-default")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, nodeConfig.Inputs["clusterId"].StringValue())
+	assert.Equal(t, "default", nodeConfig.Inputs["name"].StringValue())
+	assert.Equal(t, []string{"subnet-1", "subnet-2"}, castaitest.Strings(nodeConfig.Inputs["subnets"]))
+	assert.Equal(t, "platform", nodeConfig.Inputs["tags"].ObjectValue()["team"].StringValue())
+	eks := nodeConfig.Inputs["eks"].ObjectValue()
+	assert.Equal(t, "arn:aws:iam::123456789012:instance-profile/castai", eks["instanceProfileArn"].StringValue())
+	assert.Equal(t, []string{"sg-1"}, castaitest.Strings(eks["securityGroups"]))
+
+	ref, ok := mocks.Find("castai:config/node:NodeConfigurationDefault", "full-node-config-default-ref")
+	require.True(t, ok)
+	assert.Equal(t, nodeConfig.ID, ref.Inputs["configurationId"].StringValue())
+
+	assert.Empty(t, mocks.RegistrationsOf(nodeTemplateType))
+	assert.Empty(t, mocks.RegistrationsOf("castai:autoscaling:Autoscaler"))
+}
+
+// TestIamFunc tests that the Iam function receives the CAST AI user ARN and its outputs are used
+func TestIamFunc(t *testing.T) {
+	var gotUserArn, gotClusterID string
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.AssumeRoleArn = nil
+		args.InstanceProfileArn = nil
+		args.Iam = func(ctx *pulumi.Context, name string, in *ekscluster.EksIamArgs, opts ...pulumi.ResourceOption) (*ekscluster.EksIam, error) {
+			assert.Equal(t, "my-eks-cluster", in.ClusterName)
+			gotUserArn = castaitest.Await(t, in.CastaiUserArn).Value.(string)
+			gotClusterID = castaitest.Await(t, in.ClusterId).Value.(string)
+
+			var role struct{ pulumi.CustomResourceState }
+			if err := ctx.RegisterResource("aws:iam/role:Role", name+"-role", pulumi.Map{
+				"assumeRolePolicy": in.CastaiUserArn,
+			}, &role, opts...); err != nil {
+				return nil, err
+			}
+			return &ekscluster.EksIam{
+				RoleArn:            pulumi.String("arn:aws:iam::123456789012:role/created"),
+				InstanceProfileArn: pulumi.String("arn:aws:iam::123456789012:instance-profile/created"),
+				SecurityGroupId:    pulumi.String("sg-castai"),
+				DependsOn:          []pulumi.Resource{&role},
+			}, nil
+		}
+		_, err := ekscluster.NewCastAiEksCluster(ctx, "iam", args)
+		require.NoError(t, err)
+		return nil
+	})
+
+	userArn, _ := mocks.Find("castai:aws:EksUserArn", "iam-user-arn")
+	assert.Equal(t, userArn.Outputs["arn"].StringValue(), gotUserArn)
+	clusterID, _ := mocks.Find("castai:aws:EksClusterId", "iam-cluster-id")
+	assert.Equal(t, clusterID.ID, gotClusterID)
+
+	role, ok := mocks.Find("aws:iam/role:Role", "iam-iam-role")
+	require.True(t, ok, "resources created by Iam are registered")
+	assert.Equal(t, gotUserArn, role.Inputs["assumeRolePolicy"].StringValue())
+
+	cluster, _ := mocks.Find(eksClusterType, "iam-cluster")
+	assert.Equal(t, "arn:aws:iam::123456789012:role/created", cluster.Inputs["assumeRoleArn"].StringValue())
+
+	nodeConfig, _ := mocks.Find(nodeConfigurationType, "iam-node-config-default")
+	eks := nodeConfig.Inputs["eks"].ObjectValue()
+	assert.Equal(t, []string{"sg-castai", "sg-1"}, castaitest.Strings(eks["securityGroups"]))
+}
+
+// awsMocks reports the ARN and name AWS computes for the IAM resources.
+type awsMocks struct{}
+
+func (awsMocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	outputs := args.Inputs.Copy()
+	kind := strings.ToLower(args.TypeToken[strings.LastIndex(args.TypeToken, ":")+1:])
+	if !outputs.HasValue("name") {
+		outputs["name"] = resource.NewStringProperty(args.Name)
+	}
+	outputs["arn"] = resource.NewStringProperty("arn:aws:iam::123456789012:" + kind + "/" + outputs["name"].StringValue())
+	return args.Name + "-id", outputs, nil
+}
+
+func (awsMocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return args.Args, nil
+}
+
+// TestDefaultIam tests that the IAM resources are created when neither the ARNs nor Iam are set
+func TestDefaultIam(t *testing.T) {
+	mocks := castaitest.NewMocks()
+	mocks.Fallback = awsMocks{}
+	mocks.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.AssumeRoleArn = nil
+		args.InstanceProfileArn = nil
+		_, err := ekscluster.NewCastAiEksCluster(ctx, "def", args)
+		require.NoError(t, err)
+		return nil
+	})
+
+	_, ok := mocks.Find(ekscluster.EksIamComponentType, "def-iam")
+	require.True(t, ok)
+	clusterID, _ := mocks.Find("castai:aws:EksClusterId", "def-cluster-id")
+	userArn, _ := mocks.Find("castai:aws:EksUserArn", "def-user-arn")
+
+	role, ok := mocks.Find("aws:iam/role:Role", "def-iam-role")
+	require.True(t, ok)
+	assert.Equal(t, "cast-eks-my-eks-cluster-role-"+clusterID.ID[:8], role.Inputs["name"].StringValue())
+	var trust struct {
+		Statement []struct {
+			Principal map[string]string
+			Action    string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(role.Inputs["assumeRolePolicy"].StringValue()), &trust))
+	require.Len(t, trust.Statement, 1)
+	assert.Equal(t, userArn.Outputs["arn"].StringValue(), trust.Statement[0].Principal["AWS"])
+	assert.Equal(t, "sts:AssumeRole", trust.Statement[0].Action)
+
+	policy, ok := mocks.Find("aws:iam/policy:Policy", "def-iam-policy")
+	require.True(t, ok)
+	assert.Contains(t, policy.Inputs["policy"].StringValue(), `"ec2:ResourceTag/kubernetes.io/cluster/my-eks-cluster":"owned"`)
+
+	attachments := mocks.RegistrationsOf("aws:iam/rolePolicyAttachment:RolePolicyAttachment")
+	assert.Len(t, attachments, 8, "the CAST AI policy, 2 managed policies on the role and 5 on the node role")
+
+	nodeRole, ok := mocks.Find("aws:iam/role:Role", "def-iam-node-role")
+	require.True(t, ok)
+	assert.Contains(t, nodeRole.Inputs["assumeRolePolicy"].StringValue(), "ec2.amazonaws.com")
+	profile, ok := mocks.Find("aws:iam/instanceProfile:InstanceProfile", "def-iam-instance-profile")
+	require.True(t, ok)
+	assert.Equal(t, nodeRole.Outputs["name"].StringValue(), profile.Inputs["role"].StringValue())
+	entry, ok := mocks.Find("aws:eks/accessEntry:AccessEntry", "def-iam-access-entry")
+	require.True(t, ok)
+	assert.Equal(t, nodeRole.Outputs["arn"].StringValue(), entry.Inputs["principalArn"].StringValue())
+	assert.Equal(t, "my-eks-cluster", entry.Inputs["clusterName"].StringValue())
+
+	cluster, _ := mocks.Find(eksClusterType, "def-cluster")
+	assert.Equal(t, role.Outputs["arn"].StringValue(), cluster.Inputs["assumeRoleArn"].StringValue())
+	var dependencies []string
+	for _, urn := range cluster.Dependencies {
+		dependencies = append(dependencies, resource.URN(urn).Name())
+	}
+	for _, dep := range append(attachments, profile, entry) {
+		assert.Contains(t, dependencies, dep.Name, "the role is attached once the IAM resources exist")
+	}
+	nodeConfig, _ := mocks.Find(nodeConfigurationType, "def-node-config-default")
+	assert.Equal(t, profile.Outputs["arn"].StringValue(), nodeConfig.Inputs["eks"].ObjectValue()["instanceProfileArn"].StringValue())
+}
+
+// TestNodeTemplatesAndAutoscaler tests the optional node templates and autoscaler policies
+func TestNodeTemplatesAndAutoscaler(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.NodeTemplates = map[string]*config.NodeTemplateArgs{
+			"spot": {
+				ShouldTaint: pulumi.Bool(true),
+				Constraints: config.NodeTemplateConstraintsArgs{
+					Spot: pulumi.Bool(true),
+				},
+			},
+			"on-demand": {
+				Name: pulumi.String("stable"),
+			},
+		}
+		args.Autoscaler = &autoscalerpolicy.Policy{
+			Enabled: autoscalerpolicy.Bool(true),
+			UnschedulablePods: &autoscalerpolicy.UnschedulablePods{
+				Enabled: autoscalerpolicy.Bool(true),
+			},
+		}
+		cluster, err := ekscluster.NewCastAiEksCluster(ctx, "tpl", args)
+		require.NoError(t, err)
+		assert.Len(t, cluster.NodeTemplates, 2)
+		assert.NotNil(t, cluster.Autoscaler)
+		return nil
+	})
+
+	nodeConfig, _ := mocks.Find(nodeConfigurationType, "tpl-node-config-default")
+	cluster, _ := mocks.Find(eksClusterType, "tpl-cluster")
+
+	spot, ok := mocks.Find(nodeTemplateType, "tpl-template-spot")
+	require.True(t, ok)
+	assert.Equal(t, "spot", spot.Inputs["name"].StringValue(), "template name defaults to the map key")
+	assert.Equal(t, cluster.ID, spot.Inputs["clusterId"].StringValue())
+	assert.Equal(t, nodeConfig.ID, spot.Inputs["configurationId"].StringValue())
+	assert.True(t, spot.Inputs["shouldTaint"].BoolValue())
+
+	onDemand, ok := mocks.Find(nodeTemplateType, "tpl-template-on-demand")
+	require.True(t, ok)
+	assert.Equal(t, "stable", onDemand.Inputs["name"].StringValue())
+
+	autoscaler, ok := mocks.Find("castai:autoscaling:Autoscaler", "tpl-autoscaler")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, autoscaler.ID)
+	settings := autoscaler.Inputs["autoscalerSettings"].ObjectValue()
+	assert.True(t, settings["enabled"].BoolValue())
+	assert.True(t, settings["unschedulablePods"].ObjectValue()["enabled"].BoolValue())
+}
+
+// TestApiTokenCreatesProvider tests that an explicit API token gets its own provider
+func TestApiTokenCreatesProvider(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.ApiToken = pulumi.String("token")
+		args.ApiUrl = pulumi.String("https://api.eu.cast.ai")
+		_, err := ekscluster.NewCastAiEksCluster(ctx, "prov", args)
+		require.NoError(t, err)
+		return nil
+	})
+
+	provider, ok := mocks.Find("pulumi:providers:castai", "prov-provider")
+	require.True(t, ok)
+	assert.Equal(t, "https://api.eu.cast.ai", provider.Inputs["apiUrl"].StringValue())
+
+	cluster, _ := mocks.Find(eksClusterType, "prov-cluster")
+	assert.Contains(t, cluster.Provider, "prov-provider")
+}
+
+// TestValidation tests that invalid arguments fail before any resource is registered
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(args *ekscluster.CastAiEksClusterArgs)
+		errMsg string
+	}{
+		{
+			name:   "missing subnets",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) { args.Subnets = nil },
+			errMsg: "subnets is required when readOnlyMode is false",
+		},
+		{
+			name:   "empty subnets",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) { args.Subnets = pulumi.StringArray{} },
+			errMsg: "subnets is required when readOnlyMode is false",
+		},
+		{
+			name:   "missing security groups",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) { args.SecurityGroups = nil },
+			errMsg: "securityGroups is required when readOnlyMode is false",
+		},
+		{
+			name:   "missing account id",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) { args.AccountId = nil },
+			errMsg: "accountId is required",
+		},
+		{
+			name:   "missing role",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) { args.InstanceProfileArn = nil },
+			errMsg: "assumeRoleArn and instanceProfileArn must be set together",
+		},
+		{
+			name: "role and iam function",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) {
+				args.Iam = func(*pulumi.Context, string, *ekscluster.EksIamArgs, ...pulumi.ResourceOption) (*ekscluster.EksIam, error) {
+					return nil, nil
+				}
+			},
+			errMsg: "iam cannot be combined with assumeRoleArn or instanceProfileArn",
+		},
+		{
+			name: "templates in read-only mode",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) {
+				args.ReadOnlyMode = true
+				args.NodeTemplates = map[string]*config.NodeTemplateArgs{"spot": {}}
+			},
+			errMsg: "nodeTemplates cannot be used when readOnlyMode is true",
+		},
+		{
+			name: "invalid autoscaler policy",
+			mutate: func(args *ekscluster.CastAiEksClusterArgs) {
+				args.Autoscaler = &autoscalerpolicy.Policy{
+					SpotInstances: &autoscalerpolicy.SpotInstances{
+						MaxReclaimRate: autoscalerpolicy.Int(150),
+					},
+				}
+			},
+			errMsg: "invalid autoscaler policy: spotInstances.maxReclaimRate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := castaitest.NewMocks()
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				args := fullArgs()
+				tt.mutate(args)
+				_, err := ekscluster.NewCastAiEksCluster(ctx, "invalid", args)
+				return err
+			}, pulumi.WithMocks("project", "stack", mocks))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+			assert.Empty(t, mocks.Registrations())
+		})
+	}
+}
@@ -0,0 +1,583 @@
+// Package gkecluster provides CastAiGkeCluster, a component resource that
+// connects an existing Google GKE cluster to CAST AI.
+//
+// It is the Go counterpart of the TypeScript component in
+// components/gke-cluster/typescript and follows the same model:
+//
+//   - Phase 1 registers the cluster with CAST AI. In read-only mode this is all
+//     the component does, and the cluster can be monitored once the agent is
+//     installed with the returned cluster token.
+//   - Phase 2 (ReadOnlyMode false) looks up the permissions CAST AI needs with
+//     castai.GetGkePolicies, creates or takes the service account credentials,
+//     attaches them to the cluster and creates the default node configuration,
+//     optional node templates and the optional autoscaler policies.
+//
+// By default the service account is created with DefaultGkeIam, like the
+// GkeIamResources component of the TypeScript version. Pass the JSON key of an
+// existing service account, or an Iam function, to override it.
+//
+// Example usage:
+//
+//	cluster, err := gkecluster.NewCastAiGkeCluster(ctx, "my-cluster", &gkecluster.CastAiGkeClusterArgs{
+//		ClusterName: "my-gke-cluster",
+//		Location:    "us-central1",
+//		ProjectId:   pulumi.String("my-project"),
+//		Subnets:     pulumi.ToStringArray([]string{"projects/my-project/regions/us-central1/subnetworks/default"}),
+//		NetworkTags: pulumi.ToStringArray([]string{"castai-node"}),
+//	})
+package gkecluster
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscalerpolicy"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/projects"
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// ComponentType is the type token of CastAiGkeCluster.
+const ComponentType = "castai:gke:CastAiGkeCluster"
+
+// GkeIamComponentType is the type token of the component DefaultGkeIam
+// registers. It is the one of the TypeScript GkeIamResources.
+const GkeIamComponentType = "castai:index:GkeIamResources"
+
+const (
+	// DefaultNodeDiskType is the disk type of CAST AI nodes when NodeDiskType is empty.
+	DefaultNodeDiskType = "pd-standard"
+	// DefaultNodeMaxPodsPerNode is the pod limit of CAST AI nodes when NodeMaxPodsPerNode is zero.
+	DefaultNodeMaxPodsPerNode = 110
+)
+
+// GkeIamArgs is passed to a GkeIamFunc once the cluster is registered.
+type GkeIamArgs struct {
+	// ClusterName is the name of the GKE cluster.
+	ClusterName string
+	// Location is the zone or region of the cluster.
+	Location string
+	// ProjectId is the GCP project of the cluster.
+	ProjectId pulumi.StringInput
+	// ClusterId is the CAST AI cluster ID, for labels and naming.
+	ClusterId pulumi.StringOutput
+	// CastServiceAccount is the CAST AI service account that is allowed to
+	// impersonate the cluster's service account.
+	CastServiceAccount pulumi.StringOutput
+	// Permissions are the IAM permissions CAST AI needs, as returned by
+	// castai.GetGkePolicies. They are typically granted through a custom role.
+	Permissions []string
+}
+
+// GkeIam are the credentials CAST AI uses to manage the cluster.
+type GkeIam struct {
+	// CredentialsJson is the JSON key of the service account holding Permissions.
+	CredentialsJson pulumi.StringInput
+	// ServiceAccountEmail is the email of that service account, if known.
+	ServiceAccountEmail pulumi.StringInput
+	// DependsOn lists resources that must exist before the credentials are
+	// attached to the cluster, such as role bindings.
+	DependsOn []pulumi.Resource
+}
+
+// GkeIamFunc creates the IAM resources for a cluster. It is called with the
+// component as parent, so resources it creates should pass opts through.
+type GkeIamFunc func(ctx *pulumi.Context, name string, args *GkeIamArgs, opts ...pulumi.ResourceOption) (*GkeIam, error)
+
+// CastAiGkeClusterArgs are the inputs of CastAiGkeCluster.
+type CastAiGkeClusterArgs struct {
+	// ClusterName is the name of the GKE cluster to connect to CAST AI.
+	ClusterName string
+	// Location is the zone or region where the cluster is located.
+	Location string
+	// ProjectId is the GCP project ID.
+	ProjectId pulumi.StringInput
+
+	// ApiToken is the CAST AI API token. When set, the component creates its
+	// own castai.Provider; otherwise the default or inherited provider is used.
+	ApiToken pulumi.StringPtrInput
+	// ApiUrl is the CAST AI API URL used with ApiToken.
+	ApiUrl pulumi.StringPtrInput
+
+	// ReadOnlyMode only registers the cluster for monitoring and skips phase 2.
+	ReadOnlyMode bool
+
+	// Subnets are the subnetworks for CAST AI provisioned nodes. Required when
+	// ReadOnlyMode is false.
+	Subnets pulumi.StringArrayInput
+	// NetworkTags are added to CAST AI provisioned nodes so that firewall rules
+	// apply to them. Required when ReadOnlyMode is false.
+	NetworkTags pulumi.StringArrayInput
+
+	// CredentialsJson is the JSON key of an existing service account with the
+	// permissions from castai.GetGkePolicies. It replaces the default IAM
+	// resources.
+	CredentialsJson pulumi.StringInput
+	// Iam creates the service account once the cluster ID and the required
+	// permissions are known. It defaults to DefaultGkeIam unless
+	// CredentialsJson is set.
+	Iam GkeIamFunc
+	// PolicyFeatures enables optional features when looking up the required
+	// permissions, for example "loadBalancersNetworkEndpointGroup".
+	PolicyFeatures map[string]bool
+
+	// DeleteNodesOnDisconnect removes CAST AI nodes when the cluster is disconnected.
+	DeleteNodesOnDisconnect bool
+	// Tags are added to CAST AI provisioned nodes.
+	Tags pulumi.StringMapInput
+	// NodeDiskType is the boot disk type of CAST AI nodes (default: "pd-standard").
+	NodeDiskType string
+	// NodeMaxPodsPerNode is the pod limit of CAST AI nodes (default: 110).
+	NodeMaxPodsPerNode int
+	// NodeTemplates are created on top of the default node configuration, keyed
+	// by template name. ClusterId and ConfigurationId are filled in when unset.
+	NodeTemplates map[string]*config.NodeTemplateArgs
+	// Autoscaler, when set, applies these autoscaler policies to the cluster.
+	Autoscaler *autoscalerpolicy.Policy
+}
+
+// CastAiGkeCluster connects a GKE cluster to CAST AI.
+type CastAiGkeCluster struct {
+	pulumi.ResourceState
+
+	// Cluster is the registered cluster.
+	Cluster *castai.GkeCluster
+
+	// ClusterId is the CAST AI cluster ID.
+	ClusterId pulumi.StringOutput
+	// ClusterToken is the token the CAST AI agent authenticates with. It is
+	// marked secret.
+	ClusterToken pulumi.StringOutput
+	// CredentialsId is the ID of the cluster credentials in CAST AI.
+	CredentialsId pulumi.StringOutput
+	// OrganizationId is the CAST AI organization owning the cluster.
+	OrganizationId pulumi.StringOutput
+
+	// The following fields are only set when ReadOnlyMode is false.
+
+	// CastServiceAccount is the CAST AI service account for impersonation.
+	CastServiceAccount pulumi.StringOutput
+	// ServiceAccountEmail is the service account CAST AI authenticates as. It
+	// is not known when CredentialsJson is set.
+	ServiceAccountEmail pulumi.StringOutput
+	// Permissions are the IAM permissions CAST AI needs in the project.
+	Permissions []string
+	// NodeConfigurationId is the ID of the default node configuration.
+	NodeConfigurationId pulumi.StringOutput
+	// NodeTemplates are the created node templates, keyed by template name.
+	NodeTemplates map[string]*config.NodeTemplate
+	// Autoscaler is the autoscaler policy resource, if Autoscaler was set.
+	Autoscaler *castai.Autoscaler
+
+	readOnly bool
+}
+
+// NewCastAiGkeCluster registers a new CastAiGkeCluster component.
+func NewCastAiGkeCluster(ctx *pulumi.Context, name string, args *CastAiGkeClusterArgs, opts ...pulumi.ResourceOption) (*CastAiGkeCluster, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+
+	component := &CastAiGkeCluster{readOnly: args.ReadOnlyMode}
+	if err := ctx.RegisterComponentResource(ComponentType, name, component, opts...); err != nil {
+		return nil, err
+	}
+	childOpts := []pulumi.ResourceOption{pulumi.Parent(component)}
+	invokeOpts := []pulumi.InvokeOption{pulumi.Parent(component)}
+
+	if args.ApiToken != nil {
+		provider, err := castai.NewProvider(ctx, name+"-provider", &castai.ProviderArgs{
+			ApiToken: args.ApiToken,
+			ApiUrl:   args.ApiUrl,
+		}, childOpts...)
+		if err != nil {
+			return nil, err
+		}
+		childOpts = append(childOpts, pulumi.Provider(provider))
+		invokeOpts = append(invokeOpts, pulumi.Provider(provider))
+	}
+
+	if args.ReadOnlyMode {
+		cluster, err := castai.NewGkeCluster(ctx, name+"-cluster", &castai.GkeClusterArgs{
+			ProjectId:               args.ProjectId,
+			Location:                pulumi.String(args.Location),
+			Name:                    pulumi.String(args.ClusterName),
+			DeleteNodesOnDisconnect: pulumi.Bool(args.DeleteNodesOnDisconnect),
+		}, childOpts...)
+		if err != nil {
+			return nil, err
+		}
+		component.setCluster(cluster)
+		if err := ctx.RegisterResourceOutputs(component, component.outputs()); err != nil {
+			return nil, err
+		}
+		return component, nil
+	}
+
+	// Phase 1: get the cluster ID and the CAST AI service account, and look up
+	// the permissions the cluster's service account needs.
+	clusterID, err := castai.NewGkeClusterId(ctx, name+"-cluster-id", &castai.GkeClusterIdArgs{
+		ProjectId: args.ProjectId,
+		Location:  pulumi.String(args.Location),
+		Name:      pulumi.String(args.ClusterName),
+	}, childOpts...)
+	if err != nil {
+		return nil, err
+	}
+	component.CastServiceAccount = clusterID.CastServiceAccount
+
+	policies, err := castai.GetGkePolicies(ctx, &castai.GetGkePoliciesArgs{
+		Features: args.PolicyFeatures,
+	}, invokeOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("looking up GKE policies: %w", err)
+	}
+	component.Permissions = policies.Policies
+
+	iamFunc := args.Iam
+	if iamFunc == nil && args.CredentialsJson == nil {
+		iamFunc = DefaultGkeIam
+	}
+	iam := &GkeIam{CredentialsJson: args.CredentialsJson}
+	if iamFunc != nil {
+		iam, err = iamFunc(ctx, name+"-iam", &GkeIamArgs{
+			ClusterName:        args.ClusterName,
+			Location:           args.Location,
+			ProjectId:          args.ProjectId,
+			ClusterId:          clusterID.ID().ToStringOutput(),
+			CastServiceAccount: clusterID.CastServiceAccount,
+			Permissions:        policies.Policies,
+		}, pulumi.Parent(component))
+		if err != nil {
+			return nil, fmt.Errorf("creating IAM resources: %w", err)
+		}
+		if iam == nil || iam.CredentialsJson == nil {
+			return nil, errors.New("Iam must return CredentialsJson")
+		}
+	}
+	if iam.ServiceAccountEmail != nil {
+		component.ServiceAccountEmail = iam.ServiceAccountEmail.ToStringOutput()
+	}
+
+	// Phase 2: attach the credentials to the cluster so CAST AI can manage it.
+	cluster, err := castai.NewGkeCluster(ctx, name+"-cluster", &castai.GkeClusterArgs{
+		ProjectId:               args.ProjectId,
+		Location:                pulumi.String(args.Location),
+		Name:                    pulumi.String(args.ClusterName),
+		CredentialsJson:         pulumi.ToSecret(iam.CredentialsJson).(pulumi.StringOutput),
+		DeleteNodesOnDisconnect: pulumi.Bool(args.DeleteNodesOnDisconnect),
+	}, append(childOpts, pulumi.DependsOn(append([]pulumi.Resource{clusterID}, iam.DependsOn...)))...)
+	if err != nil {
+		return nil, err
+	}
+	component.setCluster(cluster)
+
+	diskType := args.NodeDiskType
+	if diskType == "" {
+		diskType = DefaultNodeDiskType
+	}
+	maxPods := args.NodeMaxPodsPerNode
+	if maxPods == 0 {
+		maxPods = DefaultNodeMaxPodsPerNode
+	}
+	nodeConfig, err := config.NewNodeConfiguration(ctx, name+"-node-config-default", &config.NodeConfigurationArgs{
+		ClusterId: component.ClusterId,
+		Name:      pulumi.String("default"),
+		Subnets:   args.Subnets,
+		Tags:      args.Tags,
+		Gke: config.NodeConfigurationGkeArgs{
+			DiskType:       pulumi.String(diskType),
+			NetworkTags:    args.NetworkTags,
+			MaxPodsPerNode: pulumi.Int(maxPods),
+		},
+	}, append(childOpts, pulumi.DependsOn([]pulumi.Resource{cluster}))...)
+	if err != nil {
+		return nil, err
+	}
+	component.NodeConfigurationId = nodeConfig.ID().ToStringOutput()
+
+	defaultConfig, err := config.NewNodeConfigurationDefault(ctx, name+"-node-config-default-ref", &config.NodeConfigurationDefaultArgs{
+		ClusterId:       component.ClusterId,
+		ConfigurationId: component.NodeConfigurationId,
+	}, childOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	component.NodeTemplates = map[string]*config.NodeTemplate{}
+	templates := []pulumi.Resource{defaultConfig}
+	for _, key := range sortedKeys(args.NodeTemplates) {
+		templateArgs := *args.NodeTemplates[key]
+		if templateArgs.ClusterId == nil {
+			templateArgs.ClusterId = component.ClusterId
+		}
+		if templateArgs.ConfigurationId == nil {
+			templateArgs.ConfigurationId = component.NodeConfigurationId
+		}
+		if templateArgs.Name == nil {
+			templateArgs.Name = pulumi.String(key)
+		}
+		template, err := config.NewNodeTemplate(ctx, name+"-template-"+key, &templateArgs,
+			append(childOpts, pulumi.DependsOn([]pulumi.Resource{defaultConfig}))...)
+		if err != nil {
+			return nil, err
+		}
+		component.NodeTemplates[key] = template
+		templates = append(templates, template)
+	}
+
+	if args.Autoscaler != nil {
+		component.Autoscaler, err = castai.NewAutoscaler(ctx, name+"-autoscaler", &castai.AutoscalerArgs{
+			ClusterId:          component.ClusterId,
+			AutoscalerSettings: args.Autoscaler.ToSettingsArgs(),
+		}, append(childOpts, pulumi.DependsOn(templates))...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ctx.RegisterResourceOutputs(component, component.outputs()); err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+// GkeIamResources are the IAM resources DefaultGkeIam creates.
+type GkeIamResources struct {
+	pulumi.ResourceState
+
+	// ServiceAccount is the service account CAST AI authenticates as.
+	ServiceAccount *serviceaccount.Account
+	// Role is the custom role holding GkeIamArgs.Permissions.
+	Role *projects.IAMCustomRole
+	// Key is the JSON key of ServiceAccount.
+	Key *serviceaccount.Key
+}
+
+// DefaultGkeIam creates the IAM resources CAST AI needs in a GkeIamResources
+// component:
+//
+//   - a service account named after the cluster, with a short hash of its
+//     location and name so that similar names do not collide;
+//   - a custom role with the permissions from castai.GetGkePolicies;
+//   - project bindings of that role and roles/iam.serviceAccountUser to the
+//     service account;
+//   - a key of the service account, returned as CredentialsJson.
+//
+// The key is marked secret and stored in the stack state. Replace the
+// "-key" resource to rotate it.
+func DefaultGkeIam(ctx *pulumi.Context, name string, args *GkeIamArgs, opts ...pulumi.ResourceOption) (*GkeIam, error) {
+	res := &GkeIamResources{}
+	if err := ctx.RegisterComponentResource(GkeIamComponentType, name, res, opts...); err != nil {
+		return nil, err
+	}
+	parent := pulumi.Parent(res)
+
+	var err error
+	res.ServiceAccount, err = serviceaccount.NewAccount(ctx, name+"-sa", &serviceaccount.AccountArgs{
+		AccountId:   pulumi.String(serviceAccountID(args.Location, args.ClusterName)),
+		DisplayName: pulumi.Sprintf("CAST AI service account for %s", args.ClusterName),
+		Project:     args.ProjectId,
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+	res.Role, err = projects.NewIAMCustomRole(ctx, name+"-role", &projects.IAMCustomRoleArgs{
+		RoleId:      pulumi.String(customRoleID(args.Location, args.ClusterName)),
+		Title:       pulumi.Sprintf("CAST AI GKE %s Role", args.ClusterName),
+		Description: pulumi.String("Role for CAST AI to manage GKE cluster " + args.ClusterName),
+		Project:     args.ProjectId,
+		Permissions: pulumi.ToStringArray(args.Permissions),
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	member := pulumi.Sprintf("serviceAccount:%s", res.ServiceAccount.Email)
+	var dependsOn []pulumi.Resource
+	for _, b := range []struct {
+		suffix string
+		role   pulumi.StringInput
+	}{
+		{"role-binding", res.Role.Name},
+		{"sa-user-binding", pulumi.String("roles/iam.serviceAccountUser")},
+	} {
+		binding, err := projects.NewIAMMember(ctx, name+"-"+b.suffix, &projects.IAMMemberArgs{
+			Project: args.ProjectId,
+			Role:    b.role,
+			Member:  member,
+		}, parent)
+		if err != nil {
+			return nil, err
+		}
+		dependsOn = append(dependsOn, binding)
+	}
+
+	res.Key, err = serviceaccount.NewKey(ctx, name+"-key", &serviceaccount.KeyArgs{
+		ServiceAccountId: res.ServiceAccount.Name,
+	}, parent)
+	if err != nil {
+		return nil, err
+	}
+	// The private key is the base64-encoded JSON key file.
+	credentials := pulumi.ToSecret(res.Key.PrivateKey.ApplyT(func(key string) (string, error) {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		return string(decoded), err
+	})).(pulumi.StringOutput)
+
+	if err := ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"serviceAccountEmail": res.ServiceAccount.Email,
+		"serviceAccountKey":   credentials,
+	}); err != nil {
+		return nil, err
+	}
+	return &GkeIam{
+		CredentialsJson:     credentials,
+		ServiceAccountEmail: res.ServiceAccount.Email,
+		DependsOn:           dependsOn,
+	}, nil
+}
+
+var invalidIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sanitizeClusterName lowercases a cluster name and joins its alphanumeric
+// runs with sep, dropping leading digits.
+func sanitizeClusterName(clusterName, sep string) string {
+	s := strings.Trim(invalidIDChars.ReplaceAllString(strings.ToLower(clusterName), sep), sep)
+	return strings.TrimLeft(s, "0123456789"+sep)
+}
+
+// serviceAccountID returns the account ID of the service account of a
+// cluster. Account IDs are 6 to 30 lowercase letters, digits and dashes.
+func serviceAccountID(location, clusterName string) string {
+	return clusterIAMID("castai-gke", "-", 30, location, clusterName)
+}
+
+// customRoleID returns the ID of the custom role of a cluster. Role IDs are at
+// most 64 letters, digits, underscores and dots.
+func customRoleID(location, clusterName string) string {
+	return clusterIAMID("castai_gke", "_", 64, location, clusterName)
+}
+
+// clusterIAMID joins prefix, the cluster name cut to fit maxLen, and a short
+// hash of the location and name, which together identify the cluster in its
+// project. The hash keeps the IDs of clusters whose names only differ after
+// the cut, or only in their location, apart.
+func clusterIAMID(prefix, sep string, maxLen int, location, clusterName string) string {
+	sum := sha256.Sum256([]byte(location + "/" + clusterName))
+	hash := hex.EncodeToString(sum[:])[:6]
+
+	name := sanitizeClusterName(clusterName, sep)
+	if room := maxLen - len(prefix) - len(hash) - 2*len(sep); len(name) > room {
+		name = strings.TrimRight(name[:room], sep)
+	}
+	if name == "" {
+		return prefix + sep + hash
+	}
+	return prefix + sep + name + sep + hash
+}
+
+func (c *CastAiGkeCluster) setCluster(cluster *castai.GkeCluster) {
+	c.Cluster = cluster
+	c.ClusterId = cluster.ID().ToStringOutput()
+	c.ClusterToken = pulumi.ToSecret(cluster.ClusterToken).(pulumi.StringOutput)
+	c.CredentialsId = cluster.CredentialsId
+	c.OrganizationId = cluster.OrganizationId
+}
+
+func (c *CastAiGkeCluster) outputs() pulumi.Map {
+	out := pulumi.Map{
+		"clusterId":      c.ClusterId,
+		"clusterToken":   c.ClusterToken,
+		"credentialsId":  c.CredentialsId,
+		"organizationId": c.OrganizationId,
+	}
+	if !c.readOnly {
+		out["castServiceAccount"] = c.CastServiceAccount
+		out["permissions"] = pulumi.ToStringArray(c.Permissions)
+		out["nodeConfigurationId"] = c.NodeConfigurationId
+		if c.ServiceAccountEmail != (pulumi.StringOutput{}) {
+			out["serviceAccountEmail"] = c.ServiceAccountEmail
+		}
+	}
+	return out
+}
+
+func (args *CastAiGkeClusterArgs) validate() error {
+	if args == nil {
+		return errors.New("missing required arguments")
+	}
+	var errs []error
+	if args.ClusterName == "" {
+		errs = append(errs, errors.New("clusterName is required"))
+	}
+	if args.Location == "" {
+		errs = append(errs, errors.New("location is required"))
+	}
+	if args.ProjectId == nil {
+		errs = append(errs, errors.New("projectId is required"))
+	}
+
+	if args.ReadOnlyMode {
+		if len(args.NodeTemplates) > 0 {
+			errs = append(errs, errors.New("nodeTemplates cannot be used when readOnlyMode is true"))
+		}
+		if args.Autoscaler != nil {
+			errs = append(errs, errors.New("autoscaler cannot be used when readOnlyMode is true"))
+		}
+		return errors.Join(errs...)
+	}
+
+	if isEmpty(args.Subnets) {
+		errs = append(errs, errors.New("subnets is required when readOnlyMode is false"))
+	}
+	if isEmpty(args.NetworkTags) {
+		errs = append(errs, errors.New("networkTags is required when readOnlyMode is false"))
+	}
+	if args.Iam != nil && args.CredentialsJson != nil {
+		errs = append(errs, errors.New("iam cannot be combined with credentialsJson"))
+	}
+	if args.NodeMaxPodsPerNode < 0 {
+		errs = append(errs, errors.New("nodeMaxPodsPerNode must not be negative"))
+	}
+	for key, template := range args.NodeTemplates {
+		if template == nil {
+			errs = append(errs, fmt.Errorf("nodeTemplates[%q] is nil", key))
+		}
+	}
+	if args.Autoscaler != nil {
+		if err := args.Autoscaler.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isEmpty reports whether a list input is missing or a literal empty list.
+// Lists only known at deployment time are assumed to be non-empty.
+func isEmpty(in pulumi.StringArrayInput) bool {
+	if in == nil {
+		return true
+	}
+	if arr, ok := in.(pulumi.StringArray); ok {
+		return len(arr) == 0
+	}
+	return false
+}
+
+func sortedKeys(m map[string]*config.NodeTemplateArgs) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
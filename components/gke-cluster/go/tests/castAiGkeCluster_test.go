@@ -0,0 +1,389 @@
+package tests
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	gkecluster "github.com/castai/pulumi-castai/components/gke-cluster/go"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscalerpolicy"
+	"github.com/castai/pulumi-castai/sdk/go/castai/castaitest"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gkeClusterType        = "castai:gcp:GkeCluster"
+	nodeConfigurationType = "castai:config/node:NodeConfiguration"
+	nodeTemplateType      = "castai:config/node:NodeTemplate"
+)
+
+func fullArgs() *gkecluster.CastAiGkeClusterArgs {
+	return &gkecluster.CastAiGkeClusterArgs{
+		ClusterName:     "my-gke-cluster",
+		Location:        "us-central1",
+		ProjectId:       pulumi.String("my-project"),
+		Subnets:         pulumi.ToStringArray([]string{"projects/my-project/regions/us-central1/subnetworks/default"}),
+		NetworkTags:     pulumi.ToStringArray([]string{"castai-node"}),
+		CredentialsJson: pulumi.String(`{"type":"service_account"}`),
+	}
+}
+
+// TestReadOnlyMode tests that read-only mode only registers the cluster
+func TestReadOnlyMode(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		cluster, err := gkecluster.NewCastAiGkeCluster(ctx, "ro", &gkecluster.CastAiGkeClusterArgs{
+			ClusterName:  "my-gke-cluster",
+			Location:     "us-central1",
+			ProjectId:    pulumi.String("my-project"),
+			ReadOnlyMode: true,
+		})
+		require.NoError(t, err)
+
+		id := castaitest.Await(t, cluster.ClusterId)
+		assert.Len(t, id.Value, 36)
+
+		token := castaitest.Await(t, cluster.ClusterToken)
+		assert.NotEmpty(t, token.Value)
+		assert.True(t, token.Secret, "the cluster token must be secret")
+		assert.Nil(t, cluster.Permissions)
+		return nil
+	})
+
+	var types []string
+	for _, r := range mocks.Registrations() {
+		types = append(types, r.Type)
+	}
+	assert.Equal(t, []string{gkecluster.ComponentType, gkeClusterType}, types)
+	assert.Empty(t, mocks.Invocations(), "read-only mode does not look up policies")
+
+	reg, _ := mocks.Find(gkeClusterType, "ro-cluster")
+	assert.NotContains(t, reg.Inputs, resource.PropertyKey("credentialsJson"))
+	assert.Equal(t, "my-gke-cluster", reg.Inputs["name"].StringValue())
+}
+
+// TestFullOnboardingWithCredentials tests phase 1 and phase 2 with an existing service account key
+func TestFullOnboardingWithCredentials(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.DeleteNodesOnDisconnect = true
+		args.Tags = pulumi.StringMap{"team": pulumi.String("platform")}
+		cluster, err := gkecluster.NewCastAiGkeCluster(ctx, "full", args)
+		require.NoError(t, err)
+
+		assert.Equal(t, castaitest.GkePolicies, cluster.Permissions)
+		assert.Contains(t, castaitest.Await(t, cluster.CastServiceAccount).Value, "@my-project.iam.gserviceaccount.com")
+		assert.Len(t, castaitest.Await(t, cluster.NodeConfigurationId).Value, 36)
+		return nil
+	})
+
+	_, ok := mocks.Find("castai:gcp:GkeClusterId", "full-cluster-id")
+	assert.True(t, ok, "phase 1 registers the cluster id")
+	assert.Len(t, mocks.InvocationsOf("castai:gcp:getGkePolicies"), 1)
+
+	cluster, ok := mocks.Find(gkeClusterType, "full-cluster")
+	require.True(t, ok)
+	credentials := cluster.Inputs["credentialsJson"]
+	require.True(t, credentials.IsSecret(), "credentialsJson must be secret")
+	assert.Equal(t, `{"type":"service_account"}`, credentials.SecretValue().Element.StringValue())
+	assert.True(t, cluster.Inputs["deleteNodesOnDisconnect"].BoolValue())
+
+	nodeConfig, ok := mocks.Find(nodeConfigurationType, "full-node-config-default")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, nodeConfig.Inputs["clusterId"].StringValue())
+	assert.Equal(t, "default", nodeConfig.Inputs["name"].StringValue())
+	assert.Equal(t, []string{"projects/my-project/regions/us-central1/subnetworks/default"}, castaitest.Strings(nodeConfig.Inputs["subnets"]))
+	assert.Equal(t, "platform", nodeConfig.Inputs["tags"].ObjectValue()["team"].StringValue())
+	gke := nodeConfig.Inputs["gke"].ObjectValue()
+	assert.Equal(t, []string{"castai-node"}, castaitest.Strings(gke["networkTags"]))
+	assert.Equal(t, "pd-standard", gke["diskType"].StringValue())
+	assert.Equal(t, float64(110), gke["maxPodsPerNode"].NumberValue())
+
+	ref, ok := mocks.Find("castai:config/node:NodeConfigurationDefault", "full-node-config-default-ref")
+	require.True(t, ok)
+	assert.Equal(t, nodeConfig.ID, ref.Inputs["configurationId"].StringValue())
+
+	assert.Empty(t, mocks.RegistrationsOf(nodeTemplateType))
+	assert.Empty(t, mocks.RegistrationsOf("castai:autoscaling:Autoscaler"))
+}
+
+// TestIamFunc tests that the Iam function receives the policies and the CAST AI service account
+func TestIamFunc(t *testing.T) {
+	var gotPermissions []string
+	var gotCastServiceAccount, gotClusterID string
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.CredentialsJson = nil
+		args.PolicyFeatures = map[string]bool{"loadBalancersNetworkEndpointGroup": true}
+		args.Iam = func(ctx *pulumi.Context, name string, in *gkecluster.GkeIamArgs, opts ...pulumi.ResourceOption) (*gkecluster.GkeIam, error) {
+			assert.Equal(t, "my-gke-cluster", in.ClusterName)
+			assert.Equal(t, "us-central1", in.Location)
+			gotPermissions = in.Permissions
+			gotCastServiceAccount = castaitest.Await(t, in.CastServiceAccount).Value.(string)
+			gotClusterID = castaitest.Await(t, in.ClusterId).Value.(string)
+
+			var role struct{ pulumi.CustomResourceState }
+			if err := ctx.RegisterResource("gcp:projects/iAMCustomRole:IAMCustomRole", name+"-role", pulumi.Map{
+				"permissions": pulumi.ToStringArray(in.Permissions),
+			}, &role, opts...); err != nil {
+				return nil, err
+			}
+			return &gkecluster.GkeIam{
+				CredentialsJson:     pulumi.String(`{"type":"service_account","client_email":"castai@my-project.iam.gserviceaccount.com"}`),
+				ServiceAccountEmail: pulumi.String("castai@my-project.iam.gserviceaccount.com"),
+				DependsOn:           []pulumi.Resource{&role},
+			}, nil
+		}
+		cluster, err := gkecluster.NewCastAiGkeCluster(ctx, "iam", args)
+		require.NoError(t, err)
+		assert.Equal(t, "castai@my-project.iam.gserviceaccount.com", castaitest.Await(t, cluster.ServiceAccountEmail).Value)
+		return nil
+	})
+
+	assert.Contains(t, gotPermissions, "compute.networkEndpointGroups.get", "policy features are passed to getGkePolicies")
+	clusterID, _ := mocks.Find("castai:gcp:GkeClusterId", "iam-cluster-id")
+	assert.Equal(t, clusterID.ID, gotClusterID)
+	assert.Equal(t, clusterID.Outputs["castServiceAccount"].StringValue(), gotCastServiceAccount)
+
+	role, ok := mocks.Find("gcp:projects/iAMCustomRole:IAMCustomRole", "iam-iam-role")
+	require.True(t, ok, "resources created by Iam are registered")
+	assert.Equal(t, gotPermissions, castaitest.Strings(role.Inputs["permissions"]))
+
+	cluster, _ := mocks.Find(gkeClusterType, "iam-cluster")
+	assert.Contains(t, cluster.Inputs["credentialsJson"].SecretValue().Element.StringValue(), "castai@my-project")
+}
+
+// gcpMocks reports the outputs GCP computes for the IAM resources.
+type gcpMocks struct{}
+
+func (gcpMocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	outputs := args.Inputs.Copy()
+	switch args.TypeToken {
+	case "gcp:serviceaccount/account:Account":
+		email := outputs["accountId"].StringValue() + "@my-project.iam.gserviceaccount.com"
+		outputs["email"] = resource.NewStringProperty(email)
+		outputs["name"] = resource.NewStringProperty("projects/my-project/serviceAccounts/" + email)
+	case "gcp:projects/iAMCustomRole:IAMCustomRole":
+		outputs["name"] = resource.NewStringProperty("projects/my-project/roles/" + outputs["roleId"].StringValue())
+	case "gcp:serviceaccount/key:Key":
+		outputs["privateKey"] = resource.NewStringProperty(base64.StdEncoding.EncodeToString([]byte(`{"type":"service_account"}`)))
+	}
+	return args.Name + "-id", outputs, nil
+}
+
+func (gcpMocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return args.Args, nil
+}
+
+// TestDefaultIam tests that the service account is created when neither the credentials nor Iam are set
+func TestDefaultIam(t *testing.T) {
+	mocks := castaitest.NewMocks()
+	mocks.Fallback = gcpMocks{}
+	mocks.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.ClusterName = "My_GKE.Cluster-With-A-Very-Long-Name"
+		args.CredentialsJson = nil
+		cluster, err := gkecluster.NewCastAiGkeCluster(ctx, "def", args)
+		require.NoError(t, err)
+		email := castaitest.Await(t, cluster.ServiceAccountEmail)
+		assert.Equal(t, "castai-gke-my-gke-clust-cce2e1@my-project.iam.gserviceaccount.com", email.Value)
+		return nil
+	})
+
+	_, ok := mocks.Find(gkecluster.GkeIamComponentType, "def-iam")
+	require.True(t, ok)
+
+	account, ok := mocks.Find("gcp:serviceaccount/account:Account", "def-iam-sa")
+	require.True(t, ok)
+	assert.Equal(t, "castai-gke-my-gke-clust-cce2e1", account.Inputs["accountId"].StringValue())
+	assert.Equal(t, "my-project", account.Inputs["project"].StringValue())
+
+	role, ok := mocks.Find("gcp:projects/iAMCustomRole:IAMCustomRole", "def-iam-role")
+	require.True(t, ok)
+	assert.Equal(t, "castai_gke_my_gke_cluster_with_a_very_long_name_cce2e1", role.Inputs["roleId"].StringValue())
+	assert.NotEmpty(t, castaitest.Strings(role.Inputs["permissions"]), "the role holds the permissions from getGkePolicies")
+
+	bindings := mocks.RegistrationsOf("gcp:projects/iAMMember:IAMMember")
+	require.Len(t, bindings, 2)
+	var roles []string
+	for _, b := range bindings {
+		assert.Equal(t, "serviceAccount:"+account.Outputs["email"].StringValue(), b.Inputs["member"].StringValue())
+		roles = append(roles, b.Inputs["role"].StringValue())
+	}
+	assert.Equal(t, []string{role.Outputs["name"].StringValue(), "roles/iam.serviceAccountUser"}, roles)
+
+	key, ok := mocks.Find("gcp:serviceaccount/key:Key", "def-iam-key")
+	require.True(t, ok)
+	assert.Equal(t, account.Outputs["name"].StringValue(), key.Inputs["serviceAccountId"].StringValue())
+
+	cluster, _ := mocks.Find(gkeClusterType, "def-cluster")
+	credentials := cluster.Inputs["credentialsJson"]
+	assert.True(t, credentials.IsSecret())
+	assert.Equal(t, `{"type":"service_account"}`, credentials.SecretValue().Element.StringValue(), "the key is decoded")
+	var dependencies []string
+	for _, urn := range cluster.Dependencies {
+		dependencies = append(dependencies, resource.URN(urn).Name())
+	}
+	for _, b := range bindings {
+		assert.Contains(t, dependencies, b.Name, "the credentials are attached once the roles are bound")
+	}
+}
+
+// TestDefaultIamNames tests that clusters whose names share a long prefix, or
+// that only differ in location, get distinct service account and role IDs
+func TestDefaultIamNames(t *testing.T) {
+	mocks := castaitest.NewMocks()
+	mocks.Fallback = gcpMocks{}
+	clusters := []struct{ name, location string }{
+		{"production-europe-west1-primary", "europe-west1"},
+		{"production-europe-west1-secondary", "europe-west1"},
+		{"production-europe-west1-primary", "europe-west1-b"},
+		{"42", "us-central1"},
+	}
+	mocks.Run(t, func(ctx *pulumi.Context) error {
+		for i, c := range clusters {
+			args := fullArgs()
+			args.ClusterName = c.name
+			args.Location = c.location
+			args.CredentialsJson = nil
+			_, err := gkecluster.NewCastAiGkeCluster(ctx, fmt.Sprintf("c%d", i), args)
+			require.NoError(t, err)
+		}
+		return nil
+	})
+
+	accountIDs := map[string]bool{}
+	for _, r := range mocks.RegistrationsOf("gcp:serviceaccount/account:Account") {
+		id := r.Inputs["accountId"].StringValue()
+		assert.Regexp(t, `^[a-z][a-z0-9-]{4,28}[a-z0-9]$`, id)
+		accountIDs[id] = true
+	}
+	assert.Len(t, accountIDs, len(clusters))
+	assert.True(t, accountIDs["castai-gke-9b3168"], "a name without letters leaves only the hash")
+
+	roleIDs := map[string]bool{}
+	for _, r := range mocks.RegistrationsOf("gcp:projects/iAMCustomRole:IAMCustomRole") {
+		id := r.Inputs["roleId"].StringValue()
+		assert.Regexp(t, `^[a-zA-Z0-9_.]{3,64}$`, id)
+		roleIDs[id] = true
+	}
+	assert.Len(t, roleIDs, len(clusters))
+}
+
+// TestNodeTemplatesAndAutoscaler tests the optional node templates and autoscaler policies
+func TestNodeTemplatesAndAutoscaler(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.NodeDiskType = "pd-balanced"
+		args.NodeMaxPodsPerNode = 64
+		args.NodeTemplates = map[string]*config.NodeTemplateArgs{
+			"spot": {
+				ShouldTaint: pulumi.Bool(true),
+				Constraints: config.NodeTemplateConstraintsArgs{
+					Spot: pulumi.Bool(true),
+				},
+			},
+		}
+		args.Autoscaler = &autoscalerpolicy.Policy{
+			Enabled: autoscalerpolicy.Bool(true),
+		}
+		cluster, err := gkecluster.NewCastAiGkeCluster(ctx, "tpl", args)
+		require.NoError(t, err)
+		assert.Len(t, cluster.NodeTemplates, 1)
+		assert.NotNil(t, cluster.Autoscaler)
+		return nil
+	})
+
+	nodeConfig, _ := mocks.Find(nodeConfigurationType, "tpl-node-config-default")
+	gke := nodeConfig.Inputs["gke"].ObjectValue()
+	assert.Equal(t, "pd-balanced", gke["diskType"].StringValue())
+	assert.Equal(t, float64(64), gke["maxPodsPerNode"].NumberValue())
+
+	cluster, _ := mocks.Find(gkeClusterType, "tpl-cluster")
+	spot, ok := mocks.Find(nodeTemplateType, "tpl-template-spot")
+	require.True(t, ok)
+	assert.Equal(t, "spot", spot.Inputs["name"].StringValue(), "template name defaults to the map key")
+	assert.Equal(t, cluster.ID, spot.Inputs["clusterId"].StringValue())
+	assert.Equal(t, nodeConfig.ID, spot.Inputs["configurationId"].StringValue())
+
+	autoscaler, ok := mocks.Find("castai:autoscaling:Autoscaler", "tpl-autoscaler")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, autoscaler.ID)
+	assert.True(t, autoscaler.Inputs["autoscalerSettings"].ObjectValue()["enabled"].BoolValue())
+}
+
+// TestApiTokenCreatesProvider tests that an explicit API token gets its own provider
+func TestApiTokenCreatesProvider(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := fullArgs()
+		args.ApiToken = pulumi.String("token")
+		_, err := gkecluster.NewCastAiGkeCluster(ctx, "prov", args)
+		require.NoError(t, err)
+		return nil
+	})
+
+	_, ok := mocks.Find("pulumi:providers:castai", "prov-provider")
+	require.True(t, ok)
+	cluster, _ := mocks.Find(gkeClusterType, "prov-cluster")
+	assert.Contains(t, cluster.Provider, "prov-provider")
+}
+
+// TestValidation tests that invalid arguments fail before any resource is registered
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(args *gkecluster.CastAiGkeClusterArgs)
+		errMsg string
+	}{
+		{
+			name:   "missing subnets",
+			mutate: func(args *gkecluster.CastAiGkeClusterArgs) { args.Subnets = nil },
+			errMsg: "subnets is required when readOnlyMode is false",
+		},
+		{
+			name:   "empty network tags",
+			mutate: func(args *gkecluster.CastAiGkeClusterArgs) { args.NetworkTags = pulumi.StringArray{} },
+			errMsg: "networkTags is required when readOnlyMode is false",
+		},
+		{
+			name:   "missing project",
+			mutate: func(args *gkecluster.CastAiGkeClusterArgs) { args.ProjectId = nil },
+			errMsg: "projectId is required",
+		},
+		{
+			name: "credentials and iam function",
+			mutate: func(args *gkecluster.CastAiGkeClusterArgs) {
+				args.Iam = func(*pulumi.Context, string, *gkecluster.GkeIamArgs, ...pulumi.ResourceOption) (*gkecluster.GkeIam, error) {
+					return nil, nil
+				}
+			},
+			errMsg: "iam cannot be combined with credentialsJson",
+		},
+		{
+			name: "autoscaler in read-only mode",
+			mutate: func(args *gkecluster.CastAiGkeClusterArgs) {
+				args.ReadOnlyMode = true
+				args.Autoscaler = &autoscalerpolicy.Policy{}
+			},
+			errMsg: "autoscaler cannot be used when readOnlyMode is true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := castaitest.NewMocks()
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				args := fullArgs()
+				tt.mutate(args)
+				_, err := gkecluster.NewCastAiGkeCluster(ctx, "invalid", args)
+				return err
+			}, pulumi.WithMocks("project", "stack", mocks))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+			assert.Empty(t, mocks.Registrations())
+		})
+	}
+}
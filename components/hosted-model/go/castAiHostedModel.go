@@ -0,0 +1,578 @@
+// Package hostedmodel provides CastAiHostedModel, a component resource that
+// deploys a model with the CAST AI AI Optimizer.
+//
+// A hosted model takes three resources: the AiOptimizerModelRegistry holding
+// the weights of a private model, the AiOptimizerModelSpecs describing the
+// model, and the AiOptimizerHostedModel running it in a cluster. The
+// component creates the ones the model source needs and wires their IDs
+// together:
+//
+//   - HuggingFaceModel: model specs of a HuggingFace model and the hosted
+//     model.
+//   - Registry: a GCS or S3 registry, model specs of a model in it and the
+//     hosted model.
+//   - ModelSpecsId: only the hosted model, for models whose specs exist, such
+//     as the ones CAST AI manages.
+//
+// Autoscaling, hibernation and vLLM settings are checked for consistency
+// before anything is created. Once the hosted model is created, the Ready
+// output polls its status until it is running. It fails the deployment with
+// the StatusReason of the model when the model fails or does not start in
+// time.
+//
+// Example usage:
+//
+//	model, err := hostedmodel.NewCastAiHostedModel(ctx, "llama", &hostedmodel.CastAiHostedModelArgs{
+//		ClusterId:        cluster.ClusterId,
+//		Model:            pulumi.String("llama-3.1-8b-instruct"),
+//		HuggingFaceModel: pulumi.String("meta-llama/Llama-3.1-8B-Instruct"),
+//		Service:          pulumi.String("llama"),
+//		Port:             pulumi.Int(8080),
+//		VllmConfig:       &hostedmodel.VllmConfig{SecretName: "hf-token"},
+//		HorizontalAutoscaling: &hostedmodel.HorizontalAutoscaling{
+//			MinReplicas: 1, MaxReplicas: 4, TargetMetric: "gpu_cache_usage_perc", TargetValue: 0.8,
+//		},
+//	})
+package hostedmodel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// ComponentType is the type token of CastAiHostedModel.
+const ComponentType = "castai:index:CastAiHostedModel"
+
+// Statuses of a hosted model the deployment succeeds with.
+const (
+	// StatusRunning is the status of a model serving requests.
+	StatusRunning = "RUNNING"
+	// StatusHibernated is the status of a model scaled to zero by its
+	// hibernation settings. It is only accepted when Hibernation is set.
+	StatusHibernated = "HIBERNATED"
+)
+
+// failedStatuses are statuses a hosted model does not leave on its own, so
+// the wait ends with its StatusReason.
+var failedStatuses = map[string]bool{
+	"FAILED":   true,
+	"DELETING": true,
+}
+
+// Defaults of Wait.
+const (
+	DefaultWaitTimeout  = 30 * time.Minute
+	DefaultWaitInterval = 10 * time.Second
+	// maxWaitInterval caps the doubling poll interval.
+	maxWaitInterval = time.Minute
+)
+
+// Default CAST AI API the status is read from when the provider config does
+// not set castai:apiUrl.
+const defaultAPIURL = "https://api.cast.ai"
+
+// hostedModelPath is the AI Optimizer API path of a hosted model.
+const hostedModelPath = "/ai-optimizer/v1beta/organizations/%s/clusters/%s/hosted-models/%s"
+
+// Registry types of the model specs.
+const (
+	registryHuggingFace = "HUGGING_FACE"
+	registryPrivate     = "PRIVATE"
+)
+
+// RegistryArgs are the bucket a private model is stored in. Exactly one of
+// Gcs and S3 is set.
+type RegistryArgs struct {
+	// Gcs stores the model in a Google Cloud Storage bucket.
+	Gcs *castai.AiOptimizerModelRegistryGcsArgs
+	// S3 stores the model in an AWS S3 bucket.
+	S3 *castai.AiOptimizerModelRegistryS3Args
+	// Credentials are the JSON-encoded credentials of the bucket. They are
+	// marked secret.
+	Credentials pulumi.StringInput
+}
+
+// VllmConfig is the vLLM configuration of a HuggingFace model. At most one of
+// HuggingFaceToken and SecretName is set.
+type VllmConfig struct {
+	// HuggingFaceToken is the token gated models are downloaded with.
+	HuggingFaceToken pulumi.StringInput
+	// SecretName is the Kubernetes secret holding the HuggingFace token.
+	SecretName string
+}
+
+// HorizontalAutoscaling scales the replicas of the model on a metric.
+type HorizontalAutoscaling struct {
+	// MinReplicas may only be 0 with Hibernation, which brings the model back.
+	MinReplicas int
+	MaxReplicas int
+	// TargetMetric is the metric scaled on, e.g. "gpu_cache_usage_perc".
+	TargetMetric string
+	// TargetValue is the value of TargetMetric the replicas are scaled to.
+	TargetValue float64
+}
+
+// Hibernation scales the model to zero while it gets few requests.
+type Hibernation struct {
+	// HibernateCondition is when the model hibernates, e.g. after 30m with at
+	// most 0 requests.
+	HibernateCondition Condition
+	// ResumeCondition is when a hibernated model resumes.
+	ResumeCondition Condition
+}
+
+// Condition is a request count over a period.
+type Condition struct {
+	// Duration is the period, as a Go duration such as "30m".
+	Duration string
+	// RequestCount is the request threshold. 0 is not set.
+	RequestCount int
+}
+
+// StatusFunc returns the current status and status reason of a hosted model.
+type StatusFunc func(ctx context.Context, clusterID, hostedModelID string) (status, reason string, err error)
+
+// Wait configures how long Ready polls a hosted model that is not running
+// yet, such as one still pulling its image or waiting for a GPU node.
+type Wait struct {
+	// Timeout bounds the wait. It defaults to DefaultWaitTimeout.
+	Timeout time.Duration
+	// Interval is the wait before the first poll. It doubles after every
+	// poll, up to a minute, and defaults to DefaultWaitInterval.
+	Interval time.Duration
+	// StatusFunc reads the status. It defaults to APIStatus with the castai
+	// provider config of the stack.
+	StatusFunc StatusFunc
+}
+
+// CastAiHostedModelArgs are the inputs of CastAiHostedModel.
+type CastAiHostedModelArgs struct {
+	// ClusterId is the CAST AI cluster the model is deployed to.
+	ClusterId pulumi.StringPtrInput
+
+	// Model is the name of the model specs. It is required unless
+	// ModelSpecsId is set.
+	Model pulumi.StringInput
+	// Description is the description of the model specs.
+	Description pulumi.StringPtrInput
+	// Type is the model type of the specs, e.g. "chat" or "embeddings".
+	Type pulumi.StringPtrInput
+	// Routable makes the model available to the AI Optimizer router.
+	Routable pulumi.BoolPtrInput
+
+	// HuggingFaceModel deploys a HuggingFace model, e.g.
+	// "meta-llama/Llama-3.1-8B-Instruct".
+	HuggingFaceModel pulumi.StringInput
+	// Registry deploys a model from a bucket. BaseModelId is the model it
+	// was derived from.
+	Registry    *RegistryArgs
+	BaseModelId pulumi.StringInput
+	// ModelSpecsId deploys the model of existing specs.
+	ModelSpecsId pulumi.StringInput
+
+	// Service is the Kubernetes service of the model.
+	Service pulumi.StringInput
+	// Port is the port the service exposes the model on.
+	Port pulumi.IntInput
+	// NodeTemplateName is the node template of the model nodes.
+	NodeTemplateName pulumi.StringPtrInput
+	// EdgeLocationIds are the edge locations the model may run in.
+	EdgeLocationIds pulumi.StringArrayInput
+	// Fallback is the model requests are routed to while this one is down.
+	Fallback castai.AiOptimizerHostedModelFallbackPtrInput
+
+	// VllmConfig only applies to HuggingFace models.
+	VllmConfig            *VllmConfig
+	HorizontalAutoscaling *HorizontalAutoscaling
+	Hibernation           *Hibernation
+
+	// Wait tunes how Ready polls the status of the hosted model.
+	Wait *Wait
+}
+
+// CastAiHostedModel deploys a model with the AI Optimizer.
+type CastAiHostedModel struct {
+	pulumi.ResourceState
+
+	// Registry is nil unless Registry is set.
+	Registry *castai.AiOptimizerModelRegistry
+	// ModelSpecs is nil when ModelSpecsId is set.
+	ModelSpecs  *castai.AiOptimizerModelSpecs
+	HostedModel *castai.AiOptimizerHostedModel
+
+	// ModelSpecsId is the ID of the deployed model specs.
+	ModelSpecsId pulumi.StringOutput
+	// Status is the status of the hosted model.
+	Status pulumi.StringOutput
+	// Ready resolves to true once the hosted model reports a running status.
+	// It fails with the StatusReason of a model that fails or is not
+	// running before Wait.Timeout.
+	Ready pulumi.BoolOutput
+}
+
+// NewCastAiHostedModel registers a new CastAiHostedModel component.
+func NewCastAiHostedModel(ctx *pulumi.Context, name string, args *CastAiHostedModelArgs, opts ...pulumi.ResourceOption) (*CastAiHostedModel, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+
+	component := &CastAiHostedModel{}
+	if err := ctx.RegisterComponentResource(ComponentType, name, component, opts...); err != nil {
+		return nil, err
+	}
+	parent := pulumi.Parent(component)
+
+	specsID := args.ModelSpecsId
+	if specsID == nil {
+		specsArgs := &castai.AiOptimizerModelSpecsArgs{
+			Model:       args.Model,
+			Description: args.Description,
+			Type:        args.Type,
+			Routable:    args.Routable,
+		}
+		if args.Registry != nil {
+			registry, err := castai.NewAiOptimizerModelRegistry(ctx, name, args.Registry.registryArgs(), parent)
+			if err != nil {
+				return nil, fmt.Errorf("creating model registry: %w", err)
+			}
+			component.Registry = registry
+			specsArgs.RegistryType = pulumi.String(registryPrivate)
+			specsArgs.PrivateRegistry = &castai.AiOptimizerModelSpecsPrivateRegistryArgs{
+				RegistryId:  registry.ID().ToStringOutput(),
+				BaseModelId: args.BaseModelId,
+			}
+		} else {
+			specsArgs.RegistryType = pulumi.String(registryHuggingFace)
+			specsArgs.Huggingface = &castai.AiOptimizerModelSpecsHuggingfaceArgs{ModelName: args.HuggingFaceModel}
+		}
+
+		specs, err := castai.NewAiOptimizerModelSpecs(ctx, name, specsArgs, parent)
+		if err != nil {
+			return nil, fmt.Errorf("creating model specs: %w", err)
+		}
+		component.ModelSpecs = specs
+		specsID = specs.ID().ToStringOutput()
+	}
+
+	model, err := castai.NewAiOptimizerHostedModel(ctx, name, args.hostedModelArgs(specsID), parent)
+	if err != nil {
+		return nil, fmt.Errorf("creating hosted model: %w", err)
+	}
+	component.HostedModel = model
+	component.ModelSpecsId = model.ModelSpecsId
+	component.Status = model.Status
+	wait := args.wait(ctx)
+	hibernation := args.Hibernation != nil
+	component.Ready = pulumi.All(model.ClusterId, model.ID(), model.Status, model.StatusReason).ApplyTWithContext(ctx.Context(), func(goCtx context.Context, v []any) (bool, error) {
+		return true, wait.wait(goCtx, name, v[0].(string), string(v[1].(pulumi.ID)), v[2].(string), v[3].(string), hibernation)
+	}).(pulumi.BoolOutput)
+
+	if err := ctx.RegisterResourceOutputs(component, pulumi.Map{
+		"modelSpecsId": component.ModelSpecsId,
+		"status":       component.Status,
+		"ready":        component.Ready,
+	}); err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+// wait fills in the defaults of args.Wait.
+func (args *CastAiHostedModelArgs) wait(ctx *pulumi.Context) Wait {
+	var w Wait
+	if args.Wait != nil {
+		w = *args.Wait
+	}
+	if w.Timeout <= 0 {
+		w.Timeout = DefaultWaitTimeout
+	}
+	if w.Interval <= 0 {
+		w.Interval = DefaultWaitInterval
+	}
+	if w.StatusFunc == nil {
+		w.StatusFunc = APIStatus(ctx)
+	}
+	return w
+}
+
+// wait polls a hosted model that reported status and reason until it is
+// running. Failed models and the timeout end the wait with an error holding
+// the last status and reason.
+func (w Wait) wait(ctx context.Context, name, clusterID, id, status, reason string, hibernation bool) error {
+	ctx, cancel := context.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+
+	interval := w.Interval
+	for {
+		if running(status, hibernation) {
+			return nil
+		}
+		if failedStatuses[strings.ToUpper(status)] {
+			return statusError(fmt.Sprintf("hosted model %s is %s", name, status), reason)
+		}
+
+		select {
+		case <-ctx.Done():
+			return statusError(fmt.Sprintf("hosted model %s is %s, not %s after %s", name, status, StatusRunning, w.Timeout), reason)
+		case <-time.After(interval):
+		}
+		interval = min(2*interval, maxWaitInterval)
+
+		var err error
+		status, reason, err = w.StatusFunc(ctx, clusterID, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return fmt.Errorf("reading the status of hosted model %s: %w", name, err)
+		}
+	}
+}
+
+func running(status string, hibernation bool) bool {
+	return strings.EqualFold(status, StatusRunning) || (hibernation && strings.EqualFold(status, StatusHibernated))
+}
+
+func statusError(msg, reason string) error {
+	if reason == "" {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("%s: %s", msg, reason)
+}
+
+// APIStatus returns a StatusFunc that reads the status from the CAST AI API.
+// The API URL, key and organization are read from the castai:apiUrl,
+// castai:apiToken and castai:organizationId config of the stack, or the
+// CASTAI_API_URL, CASTAI_API_TOKEN and CASTAI_ORGANIZATION_ID env vars. The
+// organization defaults to the only one the key has access to.
+func APIStatus(ctx *pulumi.Context) StatusFunc {
+	setting := func(key, envVar string) string {
+		if v := config.Get(ctx, "castai:"+key); v != "" {
+			return v
+		}
+		return os.Getenv(envVar)
+	}
+	api := &statusAPI{
+		url:   strings.TrimSuffix(setting("apiUrl", "CASTAI_API_URL"), "/"),
+		token: setting("apiToken", "CASTAI_API_TOKEN"),
+		orgID: setting("organizationId", "CASTAI_ORGANIZATION_ID"),
+	}
+	if api.url == "" {
+		api.url = defaultAPIURL
+	}
+	return api.status
+}
+
+// statusAPI reads hosted model statuses from the CAST AI API.
+type statusAPI struct {
+	url, token, orgID string
+}
+
+func (api *statusAPI) status(ctx context.Context, clusterID, id string) (string, string, error) {
+	if api.orgID == "" {
+		var orgs struct {
+			Organizations []struct {
+				ID string `json:"id"`
+			} `json:"organizations"`
+		}
+		if err := api.get(ctx, "/v1/organizations", &orgs); err != nil {
+			return "", "", err
+		}
+		if len(orgs.Organizations) != 1 {
+			return "", "", fmt.Errorf("the API key has access to %d organizations, set castai:organizationId", len(orgs.Organizations))
+		}
+		api.orgID = orgs.Organizations[0].ID
+	}
+
+	var model struct {
+		Status       string `json:"status"`
+		StatusReason string `json:"statusReason"`
+	}
+	if err := api.get(ctx, fmt.Sprintf(hostedModelPath, api.orgID, clusterID, id), &model); err != nil {
+		return "", "", err
+	}
+	return model.Status, model.StatusReason, nil
+}
+
+func (api *statusAPI) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api.url+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", api.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("GET %s: decoding response: %w", path, err)
+	}
+	return nil
+}
+
+func (r *RegistryArgs) registryArgs() *castai.AiOptimizerModelRegistryArgs {
+	args := &castai.AiOptimizerModelRegistryArgs{
+		Credentials: pulumi.ToSecret(r.Credentials).(pulumi.StringOutput),
+	}
+	if r.Gcs != nil {
+		args.ProviderType = pulumi.String("GCS")
+		args.Gcs = r.Gcs
+	} else {
+		args.ProviderType = pulumi.String("S3")
+		args.S3 = r.S3
+	}
+	return args
+}
+
+func (args *CastAiHostedModelArgs) hostedModelArgs(specsID pulumi.StringInput) *castai.AiOptimizerHostedModelArgs {
+	out := &castai.AiOptimizerHostedModelArgs{
+		ClusterId:        args.ClusterId,
+		ModelSpecsId:     specsID,
+		Service:          args.Service,
+		Port:             args.Port,
+		NodeTemplateName: args.NodeTemplateName,
+		EdgeLocationIds:  args.EdgeLocationIds,
+		Fallback:         args.Fallback,
+	}
+	if c := args.VllmConfig; c != nil {
+		vllm := &castai.AiOptimizerHostedModelVllmConfigArgs{}
+		if c.HuggingFaceToken != nil {
+			vllm.HuggingFaceToken = pulumi.ToSecret(c.HuggingFaceToken).(pulumi.StringOutput)
+		}
+		if c.SecretName != "" {
+			vllm.SecretName = pulumi.String(c.SecretName)
+		}
+		out.VllmConfig = vllm
+	}
+	if a := args.HorizontalAutoscaling; a != nil {
+		out.HorizontalAutoscaling = &castai.AiOptimizerHostedModelHorizontalAutoscalingArgs{
+			Enabled:      pulumi.Bool(true),
+			MinReplicas:  pulumi.Int(a.MinReplicas),
+			MaxReplicas:  pulumi.Int(a.MaxReplicas),
+			TargetMetric: pulumi.String(a.TargetMetric),
+			TargetValue:  pulumi.Float64(a.TargetValue),
+		}
+	}
+	if h := args.Hibernation; h != nil {
+		out.Hibernation = &castai.AiOptimizerHostedModelHibernationArgs{
+			Enabled: pulumi.Bool(true),
+			HibernateCondition: castai.AiOptimizerHostedModelHibernationHibernateConditionArgs{
+				Duration:     pulumi.String(h.HibernateCondition.Duration),
+				RequestCount: pulumi.Int(h.HibernateCondition.RequestCount),
+			},
+			ResumeCondition: castai.AiOptimizerHostedModelHibernationResumeConditionArgs{
+				Duration:     pulumi.String(h.ResumeCondition.Duration),
+				RequestCount: pulumi.Int(h.ResumeCondition.RequestCount),
+			},
+		}
+	}
+	return out
+}
+
+func (args *CastAiHostedModelArgs) validate() error {
+	if args == nil {
+		return errors.New("missing required arguments")
+	}
+	var errs []error
+	fail := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	sources := 0
+	for _, set := range []bool{args.HuggingFaceModel != nil, args.Registry != nil, args.ModelSpecsId != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		fail("exactly one of huggingFaceModel, registry and modelSpecsId is required")
+	}
+	if args.ModelSpecsId == nil && args.Model == nil {
+		fail("model is required")
+	}
+	if r := args.Registry; r != nil {
+		if (r.Gcs == nil) == (r.S3 == nil) {
+			fail("registry: exactly one of gcs and s3 is required")
+		}
+		if r.Credentials == nil {
+			fail("registry: credentials are required")
+		}
+		if args.BaseModelId == nil {
+			fail("baseModelId is required with registry")
+		}
+	} else if args.BaseModelId != nil {
+		fail("baseModelId only applies with registry")
+	}
+	if args.Service == nil {
+		fail("service is required")
+	}
+	if args.Port == nil {
+		fail("port is required")
+	}
+
+	if c := args.VllmConfig; c != nil {
+		if args.HuggingFaceModel == nil {
+			fail("vllmConfig only applies to HuggingFace models")
+		}
+		if c.HuggingFaceToken != nil && c.SecretName != "" {
+			fail("vllmConfig: huggingFaceToken and secretName are mutually exclusive")
+		}
+	}
+
+	if a := args.HorizontalAutoscaling; a != nil {
+		if a.MinReplicas < 0 {
+			fail("horizontalAutoscaling: minReplicas must not be negative, got %d", a.MinReplicas)
+		}
+		if a.MaxReplicas < 1 || a.MaxReplicas < a.MinReplicas {
+			fail("horizontalAutoscaling: maxReplicas must be at least 1 and minReplicas %d, got %d", a.MinReplicas, a.MaxReplicas)
+		}
+		if a.MinReplicas == 0 && args.Hibernation == nil {
+			fail("horizontalAutoscaling: minReplicas 0 requires hibernation to resume the model")
+		}
+		if a.TargetMetric == "" {
+			fail("horizontalAutoscaling: targetMetric is required")
+		}
+		if a.TargetValue <= 0 {
+			fail("horizontalAutoscaling: targetValue must be positive, got %g", a.TargetValue)
+		}
+	}
+
+	if h := args.Hibernation; h != nil {
+		for _, c := range []struct {
+			name string
+			Condition
+		}{{"hibernateCondition", h.HibernateCondition}, {"resumeCondition", h.ResumeCondition}} {
+			if d, err := time.ParseDuration(c.Duration); err != nil || d <= 0 {
+				fail("hibernation: %s.duration must be a positive duration such as \"30m\", got %q", c.name, c.Duration)
+			}
+			if c.RequestCount < 0 {
+				fail("hibernation: %s.requestCount must not be negative, got %d", c.name, c.RequestCount)
+			}
+		}
+		// A model hibernating at a request count it resumes at would
+		// hibernate and resume in turn.
+		hibernate, resume := h.HibernateCondition.RequestCount, h.ResumeCondition.RequestCount
+		if hibernate > 0 && resume > 0 && hibernate >= resume {
+			fail("hibernation: hibernateCondition.requestCount %d must be less than resumeCondition.requestCount %d", hibernate, resume)
+		}
+	}
+	return errors.Join(errs...)
+}
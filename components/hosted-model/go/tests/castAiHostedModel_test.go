@@ -0,0 +1,341 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hostedmodel "github.com/castai/pulumi-castai/components/hosted-model/go"
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/castaitest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	registryType    = "castai:index/aiOptimizer:AiOptimizerModelRegistry"
+	specsType       = "castai:index/aiOptimizer:AiOptimizerModelSpecs"
+	hostedModelType = "castai:index/aiOptimizer:AiOptimizerHostedModel"
+)
+
+// statusMocks reports a status and reason for every hosted model.
+type statusMocks struct {
+	*castaitest.Mocks
+	status, reason string
+}
+
+func (m *statusMocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	id, outputs, err := m.Mocks.NewResource(args)
+	if err == nil && args.TypeToken == hostedModelType {
+		outputs["status"] = resource.NewStringProperty(m.status)
+		outputs["statusReason"] = resource.NewStringProperty(m.reason)
+	}
+	return id, outputs, err
+}
+
+func huggingFaceArgs() *hostedmodel.CastAiHostedModelArgs {
+	return &hostedmodel.CastAiHostedModelArgs{
+		ClusterId:        pulumi.String("11111111-1111-1111-1111-111111111111"),
+		Model:            pulumi.String("llama-3.1-8b-instruct"),
+		HuggingFaceModel: pulumi.String("meta-llama/Llama-3.1-8B-Instruct"),
+		Service:          pulumi.String("llama"),
+		Port:             pulumi.Int(8080),
+	}
+}
+
+// TestHuggingFaceModel tests that a HuggingFace model gets specs and a hosted model with its settings
+func TestHuggingFaceModel(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		args := huggingFaceArgs()
+		args.VllmConfig = &hostedmodel.VllmConfig{HuggingFaceToken: pulumi.String("hf_token")}
+		args.HorizontalAutoscaling = &hostedmodel.HorizontalAutoscaling{
+			MinReplicas: 0, MaxReplicas: 4, TargetMetric: "gpu_cache_usage_perc", TargetValue: 0.8,
+		}
+		args.Hibernation = &hostedmodel.Hibernation{
+			HibernateCondition: hostedmodel.Condition{Duration: "30m"},
+			ResumeCondition:    hostedmodel.Condition{Duration: "1m", RequestCount: 1},
+		}
+		model, err := hostedmodel.NewCastAiHostedModel(ctx, "llama", args)
+		require.NoError(t, err)
+		assert.Nil(t, model.Registry)
+		require.NotNil(t, model.ModelSpecs)
+
+		assert.Equal(t, true, castaitest.Await(t, model.Ready).Value)
+		assert.Equal(t, hostedmodel.StatusRunning, castaitest.Await(t, model.Status).Value)
+		specsID := castaitest.Await(t, model.ModelSpecs.ID().ToStringOutput()).Value
+		assert.Equal(t, specsID, castaitest.Await(t, model.ModelSpecsId).Value)
+		return nil
+	})
+
+	assert.Empty(t, mocks.RegistrationsOf(registryType))
+	specs, ok := mocks.Find(specsType, "llama")
+	require.True(t, ok)
+	assert.Equal(t, "HUGGING_FACE", specs.Inputs["registryType"].StringValue())
+	assert.Equal(t, "meta-llama/Llama-3.1-8B-Instruct", specs.Inputs["huggingface"].ObjectValue()["modelName"].StringValue())
+
+	model, ok := mocks.Find(hostedModelType, "llama")
+	require.True(t, ok)
+	assert.Equal(t, specs.ID, model.Inputs["modelSpecsId"].StringValue())
+	assert.True(t, model.Inputs["vllmConfig"].ObjectValue()["huggingFaceToken"].IsSecret())
+	autoscaling := model.Inputs["horizontalAutoscaling"].ObjectValue()
+	assert.True(t, autoscaling["enabled"].BoolValue())
+	assert.Equal(t, 4.0, autoscaling["maxReplicas"].NumberValue())
+	hibernation := model.Inputs["hibernation"].ObjectValue()
+	assert.Equal(t, "30m", hibernation["hibernateCondition"].ObjectValue()["duration"].StringValue())
+	assert.Equal(t, 1.0, hibernation["resumeCondition"].ObjectValue()["requestCount"].NumberValue())
+}
+
+// TestPrivateModel tests that a private model gets a registry its specs refer to
+func TestPrivateModel(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		model, err := hostedmodel.NewCastAiHostedModel(ctx, "tuned", &hostedmodel.CastAiHostedModelArgs{
+			Model: pulumi.String("tuned-llama"),
+			Registry: &hostedmodel.RegistryArgs{
+				Gcs:         &castai.AiOptimizerModelRegistryGcsArgs{Bucket: pulumi.String("models"), Prefix: pulumi.String("tuned")},
+				Credentials: pulumi.String(`{"type": "service_account"}`),
+			},
+			BaseModelId: pulumi.String("meta-llama/Llama-3.1-8B-Instruct"),
+			Service:     pulumi.String("tuned"),
+			Port:        pulumi.Int(8080),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, model.Registry)
+		return nil
+	})
+
+	registry, ok := mocks.Find(registryType, "tuned")
+	require.True(t, ok)
+	assert.Equal(t, "GCS", registry.Inputs["providerType"].StringValue())
+	assert.Equal(t, "models", registry.Inputs["gcs"].ObjectValue()["bucket"].StringValue())
+	assert.True(t, registry.Inputs["credentials"].IsSecret())
+
+	specs, ok := mocks.Find(specsType, "tuned")
+	require.True(t, ok)
+	assert.Equal(t, "PRIVATE", specs.Inputs["registryType"].StringValue())
+	private := specs.Inputs["privateRegistry"].ObjectValue()
+	assert.Equal(t, registry.ID, private["registryId"].StringValue())
+	assert.Equal(t, "meta-llama/Llama-3.1-8B-Instruct", private["baseModelId"].StringValue())
+}
+
+// TestPredefinedModel tests that existing specs are deployed without creating specs
+func TestPredefinedModel(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		model, err := hostedmodel.NewCastAiHostedModel(ctx, "qwen", &hostedmodel.CastAiHostedModelArgs{
+			ModelSpecsId: pulumi.String("predefined-qwen"),
+			Service:      pulumi.String("qwen"),
+			Port:         pulumi.Int(8000),
+		})
+		require.NoError(t, err)
+		assert.Nil(t, model.ModelSpecs)
+		return nil
+	})
+
+	assert.Empty(t, mocks.RegistrationsOf(specsType))
+	model, ok := mocks.Find(hostedModelType, "qwen")
+	require.True(t, ok)
+	assert.Equal(t, "predefined-qwen", model.Inputs["modelSpecsId"].StringValue())
+}
+
+// TestStatus tests that a model that is not running fails the deployment with its status reason
+func TestStatus(t *testing.T) {
+	for _, tc := range []struct {
+		name, status, reason string
+		hibernation          bool
+		err                  string
+	}{
+		{name: "running", status: "RUNNING"},
+		{name: "hibernated", status: "HIBERNATED", hibernation: true},
+		{name: "hibernated without hibernation", status: "HIBERNATED", err: "hosted model llama is HIBERNATED, not RUNNING after 10ms"},
+		{name: "failed", status: "FAILED", reason: "insufficient GPU capacity", err: "hosted model llama is FAILED: insufficient GPU capacity"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mocks := &statusMocks{Mocks: castaitest.NewMocks(), status: tc.status, reason: tc.reason}
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				args := huggingFaceArgs()
+				if tc.hibernation {
+					args.Hibernation = &hostedmodel.Hibernation{
+						HibernateCondition: hostedmodel.Condition{Duration: "30m"},
+						ResumeCondition:    hostedmodel.Condition{Duration: "1m", RequestCount: 1},
+					}
+				}
+				args.Wait = &hostedmodel.Wait{
+					Timeout:  10 * time.Millisecond,
+					Interval: time.Millisecond,
+					StatusFunc: func(context.Context, string, string) (string, string, error) {
+						return tc.status, tc.reason, nil
+					},
+				}
+				_, err := hostedmodel.NewCastAiHostedModel(ctx, "llama", args)
+				return err
+			}, pulumi.WithMocks("project", "stack", mocks))
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+// TestStatusPolling tests that a model that is still deploying is polled until it runs or fails
+func TestStatusPolling(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		statuses []string
+		reason   string
+		err      string
+	}{
+		{name: "starts", statuses: []string{"DEPLOYING", "DEPLOYING", "RUNNING"}},
+		{name: "fails", statuses: []string{"DEPLOYING", "FAILED"}, reason: "image pull failed", err: "hosted model llama is FAILED: image pull failed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mocks := &statusMocks{Mocks: castaitest.NewMocks(), status: "PENDING"}
+			var polled []string
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				args := huggingFaceArgs()
+				args.Wait = &hostedmodel.Wait{
+					Interval: time.Millisecond,
+					StatusFunc: func(_ context.Context, clusterID, id string) (string, string, error) {
+						polled = append(polled, clusterID+"/"+id)
+						return tc.statuses[len(polled)-1], tc.reason, nil
+					},
+				}
+				model, err := hostedmodel.NewCastAiHostedModel(ctx, "llama", args)
+				require.NoError(t, err)
+				if tc.err == "" {
+					assert.Equal(t, true, castaitest.Await(t, model.Ready).Value)
+				}
+				return nil
+			}, pulumi.WithMocks("project", "stack", mocks))
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+
+			model, ok := mocks.Find(hostedModelType, "llama")
+			require.True(t, ok)
+			require.Len(t, polled, len(tc.statuses))
+			assert.Equal(t, "11111111-1111-1111-1111-111111111111/"+model.ID, polled[0])
+		})
+	}
+}
+
+// TestStatusFromAPI tests that the status is polled from the CAST AI API by default
+func TestStatusFromAPI(t *testing.T) {
+	var paths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.Header.Get("X-API-Key") != "api-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/v1/organizations" {
+			json.NewEncoder(w).Encode(map[string]any{"organizations": []any{map[string]any{"id": "org-1"}}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"status": "RUNNING"})
+	}))
+	defer api.Close()
+	t.Setenv("CASTAI_API_URL", api.URL)
+	t.Setenv("CASTAI_API_TOKEN", "api-key")
+
+	mocks := &statusMocks{Mocks: castaitest.NewMocks(), status: "DEPLOYING"}
+	require.NoError(t, pulumi.RunErr(func(ctx *pulumi.Context) error {
+		args := huggingFaceArgs()
+		args.Wait = &hostedmodel.Wait{Interval: time.Millisecond}
+		_, err := hostedmodel.NewCastAiHostedModel(ctx, "llama", args)
+		return err
+	}, pulumi.WithMocks("project", "stack", mocks)))
+
+	model, ok := mocks.Find(hostedModelType, "llama")
+	require.True(t, ok)
+	assert.Equal(t, []string{
+		"/v1/organizations",
+		"/ai-optimizer/v1beta/organizations/org-1/clusters/11111111-1111-1111-1111-111111111111/hosted-models/" + model.ID,
+	}, paths)
+}
+
+// TestValidation tests that inconsistent arguments fail before anything is created
+func TestValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(args *hostedmodel.CastAiHostedModelArgs)
+		err    string
+	}{
+		{
+			name:   "two sources",
+			modify: func(args *hostedmodel.CastAiHostedModelArgs) { args.ModelSpecsId = pulumi.String("id") },
+			err:    "exactly one of huggingFaceModel, registry and modelSpecsId is required",
+		},
+		{
+			name: "registry",
+			modify: func(args *hostedmodel.CastAiHostedModelArgs) {
+				args.HuggingFaceModel = nil
+				args.Registry = &hostedmodel.RegistryArgs{}
+			},
+			err: "registry: exactly one of gcs and s3 is required\nregistry: credentials are required\nbaseModelId is required with registry",
+		},
+		{
+			name: "vllm config of a private model",
+			modify: func(args *hostedmodel.CastAiHostedModelArgs) {
+				args.HuggingFaceModel = nil
+				args.ModelSpecsId = pulumi.String("id")
+				args.VllmConfig = &hostedmodel.VllmConfig{SecretName: "hf-token"}
+			},
+			err: "vllmConfig only applies to HuggingFace models",
+		},
+		{
+			name: "vllm token and secret",
+			modify: func(args *hostedmodel.CastAiHostedModelArgs) {
+				args.VllmConfig = &hostedmodel.VllmConfig{HuggingFaceToken: pulumi.String("hf"), SecretName: "hf-token"}
+			},
+			err: "vllmConfig: huggingFaceToken and secretName are mutually exclusive",
+		},
+		{
+			name: "autoscaling",
+			modify: func(args *hostedmodel.CastAiHostedModelArgs) {
+				args.HorizontalAutoscaling = &hostedmodel.HorizontalAutoscaling{MinReplicas: 0, MaxReplicas: 0}
+			},
+			err: "horizontalAutoscaling: maxReplicas must be at least 1 and minReplicas 0, got 0\n" +
+				"horizontalAutoscaling: minReplicas 0 requires hibernation to resume the model\n" +
+				"horizontalAutoscaling: targetMetric is required\n" +
+				"horizontalAutoscaling: targetValue must be positive, got 0",
+		},
+		{
+			name: "hibernation",
+			modify: func(args *hostedmodel.CastAiHostedModelArgs) {
+				args.Hibernation = &hostedmodel.Hibernation{
+					HibernateCondition: hostedmodel.Condition{Duration: "half an hour", RequestCount: 10},
+					ResumeCondition:    hostedmodel.Condition{Duration: "1m", RequestCount: 5},
+				}
+			},
+			err: "hibernation: hibernateCondition.duration must be a positive duration such as \"30m\", got \"half an hour\"\n" +
+				"hibernation: hibernateCondition.requestCount 10 must be less than resumeCondition.requestCount 5",
+		},
+		{
+			name: "required",
+			modify: func(args *hostedmodel.CastAiHostedModelArgs) {
+				args.Model, args.Service, args.Port = nil, nil, nil
+			},
+			err: "model is required\nservice is required\nport is required",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			args := huggingFaceArgs()
+			tc.modify(args)
+			mocks := castaitest.NewMocks()
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				_, err := hostedmodel.NewCastAiHostedModel(ctx, "llama", args)
+				return err
+			}, pulumi.WithMocks("project", "stack", mocks))
+			assert.ErrorContains(t, err, tc.err)
+			assert.Empty(t, mocks.Registrations())
+		})
+	}
+}
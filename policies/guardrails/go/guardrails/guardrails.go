@@ -0,0 +1,338 @@
+// Package guardrails implements castai-guardrails, a CrossGuard policy pack
+// that checks CAST AI resources before `pulumi up` sends them to the API.
+//
+// The policies match resources by the type tokens provider/resources.go maps
+// the terraform-provider-castai resources to, so they apply equally to
+// resources declared directly and to those created by the cluster components.
+// Every policy can be disabled or tuned in the policy pack configuration:
+//
+//	{
+//		"autoscaler-cluster-limits": {"maxCores": 512},
+//		"no-aggressive-eviction-in-production": {"productionStacks": ["prod-*"]},
+//		"node-template-cpu-bounds": "advisory"
+//	}
+//
+// Values that are unknown during a preview are not checked; they are checked
+// again when the update runs.
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/policyx"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai/schedule"
+)
+
+// PackName is the name of the policy pack.
+const PackName = "castai-guardrails"
+
+// Version is the version of the policy pack.
+var Version = semver.MustParse("0.1.0")
+
+// Type tokens of the resources checked by the pack.
+const (
+	AutoscalerType            = "castai:autoscaling:Autoscaler"
+	NodeTemplateType          = "castai:config/node:NodeTemplate"
+	WorkloadScalingPolicyType = "castai:workload:WorkloadScalingPolicy"
+	EksClusterType            = "castai:aws:EksCluster"
+	GkeClusterType            = "castai:gcp:GkeCluster"
+	AksClusterType            = "castai:azure:AksCluster"
+	HibernationScheduleType   = schedule.HibernationScheduleType
+	RebalancingScheduleType   = schedule.RebalancingScheduleType
+)
+
+// Names of the policies in the pack.
+const (
+	AutoscalerClusterLimits               = "autoscaler-cluster-limits"
+	NoAggressiveEvictionInProduction      = "no-aggressive-eviction-in-production"
+	NodeTemplateCPUBounds                 = "node-template-cpu-bounds"
+	NoImmediateApplyOnManagedInProduction = "no-immediate-apply-on-managed-workloads-in-production"
+	KeepNodesOnDisconnect                 = "keep-nodes-on-disconnect"
+	ScheduleCronExpressions               = "schedule-cron-expressions"
+)
+
+// DefaultProductionStacks are the stack name patterns, in path.Match syntax,
+// that production-only policies apply to when `productionStacks` is not
+// configured.
+var DefaultProductionStacks = []string{"prod", "production", "prod-*", "*-prod"}
+
+// CheckedProperties lists the input properties each resource type is checked
+// on, as paths into the resource inputs. Autoscaler settings are also read
+// from the same paths in `autoscalerPoliciesJson`.
+var CheckedProperties = map[string][][]string{
+	AutoscalerType: {
+		{"autoscalerSettings", "clusterLimits", "enabled"},
+		{"autoscalerSettings", "clusterLimits", "cpu", "maxCores"},
+		{"autoscalerSettings", "nodeDownscaler", "evictor", "aggressiveMode"},
+		{"autoscalerPoliciesJson"},
+	},
+	NodeTemplateType: {
+		{"constraints", "minCpu"},
+		{"constraints", "maxCpu"},
+	},
+	WorkloadScalingPolicyType: {
+		{"applyType"},
+		{"managementOption"},
+	},
+	EksClusterType: {{"deleteNodesOnDisconnect"}},
+	GkeClusterType: {{"deleteNodesOnDisconnect"}},
+	AksClusterType: {{"deleteNodesOnDisconnect"}},
+	HibernationScheduleType: {
+		{"enabled"},
+		{"pauseConfig", "enabled"},
+		{"pauseConfig", "schedule", "cronExpression"},
+		{"resumeConfig", "enabled"},
+		{"resumeConfig", "schedule", "cronExpression"},
+	},
+	RebalancingScheduleType: {{"schedule", "cron"}},
+}
+
+// NewPolicyPack returns the policy pack for the given stack.
+func NewPolicyPack(stack string) (policyx.PolicyPack, error) {
+	return policyx.NewPolicyPack(PackName, Version, policyx.EnforcementLevelMandatory, Policies(stack))
+}
+
+// Policies returns the policies of the pack for the given stack. The stack
+// name decides whether production-only policies apply.
+func Policies(stack string) []policyx.Policy {
+	return []policyx.Policy{
+		autoscalerClusterLimits(),
+		noAggressiveEvictionInProduction(stack),
+		nodeTemplateCPUBounds(),
+		noImmediateApplyOnManagedInProduction(stack),
+		keepNodesOnDisconnect(),
+		scheduleCronExpressions(),
+	}
+}
+
+// productionStacksSchema returns the config schema of production-only
+// policies. Schemas are sent to the engine as protobuf structs, which only
+// hold untyped lists.
+func productionStacksSchema() map[string]any {
+	defaults := make([]any, len(DefaultProductionStacks))
+	for i, pattern := range DefaultProductionStacks {
+		defaults[i] = pattern
+	}
+	return map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string"},
+		"default":     defaults,
+		"description": "Stack name patterns (path.Match syntax) the policy applies to.",
+	}
+}
+
+func autoscalerClusterLimits() policyx.Policy {
+	return policyx.NewResourceValidationPolicy(AutoscalerClusterLimits, policyx.ResourceValidationPolicyArgs{
+		Description:      "Autoscalers must enable cluster limits with a maximum number of CPU cores.",
+		EnforcementLevel: policyx.EnforcementLevelMandatory,
+		ConfigSchema: &policyx.ConfigSchema{
+			Properties: map[string]map[string]any{
+				"maxCores": {
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Largest allowed clusterLimits.cpu.maxCores. Unset allows any limit.",
+				},
+			},
+		},
+		ValidateResource: func(_ context.Context, args policyx.ResourceValidationArgs) error {
+			if args.Resource.Type != AutoscalerType {
+				return nil
+			}
+			settings, ok, err := autoscalerSettings(args.Resource.Properties)
+			if err != nil {
+				args.Manager.ReportViolation(err.Error(), "")
+				return nil
+			}
+			if !ok {
+				return nil
+			}
+
+			enabled := lookup(settings, "clusterLimits", "enabled")
+			maxCores := lookup(settings, "clusterLimits", "cpu", "maxCores")
+			if enabled.IsComputed() || maxCores.IsComputed() {
+				return nil
+			}
+			if !enabled.IsBool() || !enabled.AsBool() {
+				args.Manager.ReportViolation("clusterLimits.enabled must be true.", "")
+			}
+			if !maxCores.IsNumber() || maxCores.AsNumber() <= 0 {
+				args.Manager.ReportViolation("clusterLimits.cpu.maxCores must be set.", "")
+			} else if limit, ok := intConfig(args.Config, "maxCores"); ok && maxCores.AsNumber() > float64(limit) {
+				args.Manager.ReportViolation(fmt.Sprintf(
+					"clusterLimits.cpu.maxCores is %v, the largest allowed value is %d.", maxCores.AsNumber(), limit), "")
+			}
+			return nil
+		},
+	})
+}
+
+func noAggressiveEvictionInProduction(stack string) policyx.Policy {
+	return policyx.NewResourceValidationPolicy(NoAggressiveEvictionInProduction, policyx.ResourceValidationPolicyArgs{
+		Description:      "The evictor must not run in aggressive mode in production stacks.",
+		EnforcementLevel: policyx.EnforcementLevelMandatory,
+		ConfigSchema: &policyx.ConfigSchema{
+			Properties: map[string]map[string]any{"productionStacks": productionStacksSchema()},
+		},
+		ValidateResource: func(_ context.Context, args policyx.ResourceValidationArgs) error {
+			if args.Resource.Type != AutoscalerType || !isProduction(stack, args.Config) {
+				return nil
+			}
+			settings, ok, err := autoscalerSettings(args.Resource.Properties)
+			if err != nil || !ok {
+				// Malformed JSON is reported by autoscaler-cluster-limits.
+				return nil
+			}
+			if aggressive := lookup(settings, "nodeDownscaler", "evictor", "aggressiveMode"); aggressive.IsBool() && aggressive.AsBool() {
+				args.Manager.ReportViolation(fmt.Sprintf(
+					"nodeDownscaler.evictor.aggressiveMode is true in production stack %q.", stack), "")
+			}
+			return nil
+		},
+	})
+}
+
+func nodeTemplateCPUBounds() policyx.Policy {
+	return policyx.NewResourceValidationPolicy(NodeTemplateCPUBounds, policyx.ResourceValidationPolicyArgs{
+		Description:      "Node templates must bound the CPU cores of the nodes they create.",
+		EnforcementLevel: policyx.EnforcementLevelMandatory,
+		ConfigSchema: &policyx.ConfigSchema{
+			Properties: map[string]map[string]any{
+				"maxCpu": {
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Largest allowed constraints.maxCpu. Unset allows any bound.",
+				},
+			},
+		},
+		ValidateResource: func(_ context.Context, args policyx.ResourceValidationArgs) error {
+			if args.Resource.Type != NodeTemplateType {
+				return nil
+			}
+			props := args.Resource.Properties
+			minCPU := lookup(props, "constraints", "minCpu")
+			maxCPU := lookup(props, "constraints", "maxCpu")
+			if minCPU.IsComputed() || maxCPU.IsComputed() {
+				return nil
+			}
+			if !maxCPU.IsNumber() || maxCPU.AsNumber() <= 0 {
+				args.Manager.ReportViolation("constraints.maxCpu must be set.", "")
+				return nil
+			}
+			if minCPU.IsNumber() && minCPU.AsNumber() > maxCPU.AsNumber() {
+				args.Manager.ReportViolation(fmt.Sprintf(
+					"constraints.minCpu (%v) is larger than constraints.maxCpu (%v).", minCPU.AsNumber(), maxCPU.AsNumber()), "")
+			}
+			if limit, ok := intConfig(args.Config, "maxCpu"); ok && maxCPU.AsNumber() > float64(limit) {
+				args.Manager.ReportViolation(fmt.Sprintf(
+					"constraints.maxCpu is %v, the largest allowed value is %d.", maxCPU.AsNumber(), limit), "")
+			}
+			return nil
+		},
+	})
+}
+
+func noImmediateApplyOnManagedInProduction(stack string) policyx.Policy {
+	return policyx.NewResourceValidationPolicy(NoImmediateApplyOnManagedInProduction, policyx.ResourceValidationPolicyArgs{
+		Description:      "Workload scaling policies must not restart managed workloads immediately in production stacks.",
+		EnforcementLevel: policyx.EnforcementLevelMandatory,
+		ConfigSchema: &policyx.ConfigSchema{
+			Properties: map[string]map[string]any{"productionStacks": productionStacksSchema()},
+		},
+		ValidateResource: func(_ context.Context, args policyx.ResourceValidationArgs) error {
+			if args.Resource.Type != WorkloadScalingPolicyType || !isProduction(stack, args.Config) {
+				return nil
+			}
+			props := args.Resource.Properties
+			if stringValue(props.Get("applyType")) == "IMMEDIATE" && stringValue(props.Get("managementOption")) == "MANAGED" {
+				args.Manager.ReportViolation(fmt.Sprintf(
+					"applyType is IMMEDIATE for MANAGED workloads in production stack %q; use DEFERRED.", stack), "")
+			}
+			return nil
+		},
+	})
+}
+
+func keepNodesOnDisconnect() policyx.Policy {
+	return policyx.NewResourceValidationPolicy(KeepNodesOnDisconnect, policyx.ResourceValidationPolicyArgs{
+		Description:      "Disconnecting a cluster from CAST AI must not delete its nodes.",
+		EnforcementLevel: policyx.EnforcementLevelMandatory,
+		ValidateResource: func(_ context.Context, args policyx.ResourceValidationArgs) error {
+			switch args.Resource.Type {
+			case EksClusterType, GkeClusterType, AksClusterType:
+			default:
+				return nil
+			}
+			if v := args.Resource.Properties.Get("deleteNodesOnDisconnect"); v.IsBool() && v.AsBool() {
+				args.Manager.ReportViolation("deleteNodesOnDisconnect must be false.", "")
+			}
+			return nil
+		},
+	})
+}
+
+func scheduleCronExpressions() policyx.Policy {
+	return policyx.NewResourceValidationPolicy(ScheduleCronExpressions, policyx.ResourceValidationPolicyArgs{
+		Description:      "Hibernation and rebalancing schedules must be valid cron expressions that fire when intended.",
+		EnforcementLevel: policyx.EnforcementLevelMandatory,
+		ValidateResource: func(_ context.Context, args policyx.ResourceValidationArgs) error {
+			props := args.Resource.Properties
+			plan := &schedule.Plan{}
+			switch args.Resource.Type {
+			case HibernationScheduleType:
+				if v := props.Get("enabled"); v.IsBool() && !v.AsBool() {
+					return nil
+				}
+				pause, ok := scheduleCron(props, "pauseConfig")
+				if !ok {
+					return nil
+				}
+				resume, ok := scheduleCron(props, "resumeConfig")
+				if !ok {
+					return nil
+				}
+				plan.Hibernations = []schedule.Hibernation{{Name: stringValue(props.Get("name")), Pause: pause, Resume: resume}}
+			case RebalancingScheduleType:
+				cron := lookup(props, "schedule", "cron")
+				if !cron.IsString() {
+					return nil
+				}
+				plan.Rebalancings = []schedule.Rebalancing{{Name: stringValue(props.Get("name")), Cron: cron.AsString()}}
+			default:
+				return nil
+			}
+			// Overlaps between schedules need the whole stack and are left to
+			// schedule.LoadStackExport.
+			for _, f := range schedule.Preview(plan, time.Now(), 0).Findings {
+				args.Manager.ReportViolation(fmt.Sprintf("%s: %s.", f.Kind, f.Message), "")
+			}
+			return nil
+		},
+	})
+}
+
+// scheduleCron returns the cron expression of a hibernation pause or resume
+// config, or "" if the config is disabled. ok is false if it is unknown.
+func scheduleCron(props property.Map, config string) (cron string, ok bool) {
+	enabled := lookup(props, config, "enabled")
+	expr := lookup(props, config, "schedule", "cronExpression")
+	switch {
+	case enabled.IsComputed() || expr.IsComputed():
+		return "", false
+	case enabled.IsBool() && !enabled.AsBool():
+		return "", true
+	}
+	return stringValue(expr), true
+}
+
+// stringValue returns v if it is a known string, and "" otherwise.
+func stringValue(v property.Value) string {
+	if v.IsString() {
+		return v.AsString()
+	}
+	return ""
+}
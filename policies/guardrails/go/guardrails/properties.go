@@ -0,0 +1,95 @@
+package guardrails
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+// lookup returns the value at a path of nested maps. It returns a null value
+// if the path does not exist, and a computed value if a map on the way is
+// unknown.
+func lookup(m property.Map, keys ...string) property.Value {
+	v := property.New(m)
+	for _, key := range keys {
+		switch {
+		case v.IsComputed():
+			return v
+		case !v.IsMap():
+			return property.Value{}
+		}
+		v = v.AsMap().Get(key)
+	}
+	return v
+}
+
+// autoscalerSettings returns the autoscaler policies of an Autoscaler, from
+// `autoscalerSettings` or, if that is not set, from the legacy
+// `autoscalerPoliciesJson`. Both use the same field names. ok is false if the
+// settings are unknown.
+func autoscalerSettings(props property.Map) (settings property.Map, ok bool, err error) {
+	if v := props.Get("autoscalerSettings"); !v.IsNull() {
+		if v.IsComputed() {
+			return property.Map{}, false, nil
+		}
+		if v.IsMap() {
+			return v.AsMap(), true, nil
+		}
+	}
+
+	v := props.Get("autoscalerPoliciesJson")
+	switch {
+	case v.IsComputed():
+		return property.Map{}, false, nil
+	case !v.IsString() || v.AsString() == "":
+		return property.Map{}, true, nil
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(v.AsString()), &decoded); err != nil {
+		return property.Map{}, false, fmt.Errorf("autoscalerPoliciesJson is not a JSON object: %w", err)
+	}
+	return resource.FromResourcePropertyMap(resource.NewPropertyMapFromMap(decoded)), true, nil
+}
+
+// intConfig returns an integer property of a policy config. Config decoded
+// from JSON holds numbers as float64.
+func intConfig(config map[string]any, key string) (int, bool) {
+	switch v := config[key].(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	case json.Number:
+		i, err := v.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
+
+// isProduction reports whether stack matches the `productionStacks` patterns
+// of a policy config, or DefaultProductionStacks if they are not configured.
+func isProduction(stack string, config map[string]any) bool {
+	patterns := DefaultProductionStacks
+	if configured, ok := config["productionStacks"]; ok {
+		patterns = nil
+		switch v := configured.(type) {
+		case []string:
+			patterns = v
+		case []any:
+			for _, p := range v {
+				if s, ok := p.(string); ok {
+					patterns = append(patterns, s)
+				}
+			}
+		}
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, stack); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+// Command castai-guardrails runs the castai-guardrails policy pack. See the
+// guardrails package for the policies.
+//
+//	pulumi preview --policy-pack policies/guardrails/go
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/policyx"
+
+	"github.com/castai/pulumi-castai/policies/guardrails/go/guardrails"
+)
+
+func main() {
+	err := policyx.Main(func(ctx *pulumi.Context) (policyx.PolicyPack, error) {
+		return guardrails.NewPolicyPack(ctx.Stack())
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
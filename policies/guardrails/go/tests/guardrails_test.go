@@ -0,0 +1,329 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/policyx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/castai/pulumi-castai/policies/guardrails/go/guardrails"
+)
+
+// schemaPath is the provider schema, relative to this directory.
+var schemaPath = filepath.Join("..", "..", "..", "..", "provider", "sdk", "schema", "schema.json")
+
+// violations records the messages a policy reports.
+type violations []string
+
+func (v *violations) ReportViolation(message, _ string) {
+	*v = append(*v, message)
+}
+
+// validate runs one policy of the pack for the given stack against a resource
+// with the given inputs and returns the reported violations.
+func validate(t *testing.T, stack, policy, typ string, inputs map[string]any, config map[string]any) []string {
+	t.Helper()
+	props := resource.FromResourcePropertyMap(resource.NewPropertyMapFromMap(inputs))
+
+	var found policyx.ResourceValidationPolicy
+	for _, p := range guardrails.Policies(stack) {
+		if p.Name() == policy {
+			found = p.(policyx.ResourceValidationPolicy)
+		}
+	}
+	require.NotNil(t, found, "policy %s not found", policy)
+
+	var reported violations
+	err := found.Validate(context.Background(), policyx.ResourceValidationArgs{
+		Manager: &reported,
+		Resource: policyx.AnalyzerResource{
+			Type:       typ,
+			Properties: props,
+			URN:        "urn:pulumi:" + stack + "::project::" + typ + "::test",
+			Name:       "test",
+		},
+		Config: config,
+	})
+	require.NoError(t, err)
+	return reported
+}
+
+func limitedAutoscaler() map[string]any {
+	return map[string]any{
+		"clusterId": "cluster-1",
+		"autoscalerSettings": map[string]any{
+			"enabled": true,
+			"clusterLimits": map[string]any{
+				"enabled": true,
+				"cpu":     map[string]any{"minCores": 2, "maxCores": 200},
+			},
+			"nodeDownscaler": map[string]any{
+				"evictor": map[string]any{"enabled": true, "aggressiveMode": true},
+			},
+		},
+	}
+}
+
+// TestPolicyPack tests that the pack is valid and its config schemas can be sent to the engine
+func TestPolicyPack(t *testing.T) {
+	pack, err := guardrails.NewPolicyPack("dev")
+	require.NoError(t, err)
+	assert.Equal(t, guardrails.PackName, pack.Name())
+
+	names := map[string]bool{}
+	for _, p := range pack.Policies() {
+		assert.False(t, names[p.Name()], "duplicate policy %s", p.Name())
+		names[p.Name()] = true
+		assert.NotEmpty(t, p.Description(), p.Name())
+		assert.Equal(t, policyx.EnforcementLevelMandatory, p.EnforcementLevel(), p.Name())
+		if schema := p.ConfigSchema(); schema != nil {
+			m := map[string]any{}
+			for k, v := range schema.Properties {
+				m[k] = v
+			}
+			_, err := structpb.NewStruct(m)
+			assert.NoError(t, err, "config schema of %s", p.Name())
+		}
+	}
+	assert.Len(t, names, 6)
+}
+
+// TestCheckedPropertiesExistInSchema tests that the policies read properties that the
+// provider schema defines for their resource types
+func TestCheckedPropertiesExistInSchema(t *testing.T) {
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		t.Skipf("provider schema not found at %s", schemaPath)
+	}
+	var schema struct {
+		Resources map[string]struct {
+			InputProperties map[string]json.RawMessage `json:"inputProperties"`
+		} `json:"resources"`
+		Types map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"types"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	for typ, paths := range guardrails.CheckedProperties {
+		res, ok := schema.Resources[typ]
+		require.True(t, ok, "%s is not a resource of the provider", typ)
+		for _, path := range paths {
+			props := res.InputProperties
+			for i, key := range path {
+				raw, ok := props[key]
+				require.True(t, ok, "%s has no input %s", typ, strings.Join(path[:i+1], "."))
+				var prop struct {
+					Ref string `json:"$ref"`
+				}
+				require.NoError(t, json.Unmarshal(raw, &prop))
+				if i < len(path)-1 {
+					require.NotEmpty(t, prop.Ref, "%s.%s is not an object", typ, strings.Join(path[:i+1], "."))
+					props = schema.Types[strings.TrimPrefix(prop.Ref, "#/types/")].Properties
+				}
+			}
+		}
+	}
+}
+
+// TestAutoscalerClusterLimits tests the cluster limits policy on typed settings
+func TestAutoscalerClusterLimits(t *testing.T) {
+	assert.Empty(t, validate(t, "dev", guardrails.AutoscalerClusterLimits, guardrails.AutoscalerType, limitedAutoscaler(), nil))
+
+	unlimited := limitedAutoscaler()
+	unlimited["autoscalerSettings"].(map[string]any)["clusterLimits"] = map[string]any{"enabled": false}
+	assert.Equal(t, []string{
+		"clusterLimits.enabled must be true.",
+		"clusterLimits.cpu.maxCores must be set.",
+	}, validate(t, "dev", guardrails.AutoscalerClusterLimits, guardrails.AutoscalerType, unlimited, nil))
+
+	assert.Len(t, validate(t, "dev", guardrails.AutoscalerClusterLimits, guardrails.AutoscalerType,
+		map[string]any{"clusterId": "cluster-1"}, nil), 2, "autoscalers without settings are unlimited")
+}
+
+// TestAutoscalerClusterLimitsMaxCores tests the configurable upper bound of maxCores
+func TestAutoscalerClusterLimitsMaxCores(t *testing.T) {
+	config := map[string]any{"maxCores": float64(100)}
+
+	got := validate(t, "dev", guardrails.AutoscalerClusterLimits, guardrails.AutoscalerType, limitedAutoscaler(), config)
+
+	assert.Equal(t, []string{"clusterLimits.cpu.maxCores is 200, the largest allowed value is 100."}, got)
+}
+
+// TestAutoscalerClusterLimitsFromJSON tests that legacy autoscalerPoliciesJson is checked too
+func TestAutoscalerClusterLimitsFromJSON(t *testing.T) {
+	limited := `{"enabled":true,"clusterLimits":{"enabled":true,"cpu":{"maxCores":64}}}`
+	assert.Empty(t, validate(t, "dev", guardrails.AutoscalerClusterLimits, guardrails.AutoscalerType,
+		map[string]any{"autoscalerPoliciesJson": limited}, nil))
+
+	unlimited := `{"enabled":true,"clusterLimits":{"enabled":false}}`
+	assert.Len(t, validate(t, "dev", guardrails.AutoscalerClusterLimits, guardrails.AutoscalerType,
+		map[string]any{"autoscalerPoliciesJson": unlimited}, nil), 2)
+
+	got := validate(t, "dev", guardrails.AutoscalerClusterLimits, guardrails.AutoscalerType,
+		map[string]any{"autoscalerPoliciesJson": "{"}, nil)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "autoscalerPoliciesJson is not a JSON object")
+}
+
+// TestAutoscalerClusterLimitsUnknown tests that settings unknown during a preview are not checked
+func TestAutoscalerClusterLimitsUnknown(t *testing.T) {
+	props := property.NewMap(map[string]property.Value{
+		"autoscalerSettings": property.New(property.Computed),
+	})
+
+	var reported violations
+	for _, p := range guardrails.Policies("prod") {
+		err := p.(policyx.ResourceValidationPolicy).Validate(context.Background(), policyx.ResourceValidationArgs{
+			Manager:  &reported,
+			Resource: policyx.AnalyzerResource{Type: guardrails.AutoscalerType, Properties: props},
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Empty(t, reported)
+}
+
+// TestNoAggressiveEvictionInProduction tests that aggressive eviction is only rejected in production stacks
+func TestNoAggressiveEvictionInProduction(t *testing.T) {
+	assert.Empty(t, validate(t, "dev", guardrails.NoAggressiveEvictionInProduction, guardrails.AutoscalerType, limitedAutoscaler(), nil))
+
+	for _, stack := range []string{"prod", "production", "prod-eu", "payments-prod"} {
+		got := validate(t, stack, guardrails.NoAggressiveEvictionInProduction, guardrails.AutoscalerType, limitedAutoscaler(), nil)
+		assert.Len(t, got, 1, stack)
+	}
+
+	calm := limitedAutoscaler()
+	calm["autoscalerSettings"].(map[string]any)["nodeDownscaler"] = map[string]any{
+		"evictor": map[string]any{"enabled": true, "aggressiveMode": false},
+	}
+	assert.Empty(t, validate(t, "prod", guardrails.NoAggressiveEvictionInProduction, guardrails.AutoscalerType, calm, nil))
+
+	json := `{"nodeDownscaler":{"evictor":{"aggressiveMode":true}}}`
+	assert.Len(t, validate(t, "prod", guardrails.NoAggressiveEvictionInProduction, guardrails.AutoscalerType,
+		map[string]any{"autoscalerPoliciesJson": json}, nil), 1)
+}
+
+// TestProductionStacksConfig tests that the production stack patterns can be configured
+func TestProductionStacksConfig(t *testing.T) {
+	config := map[string]any{"productionStacks": []any{"live-*"}}
+
+	assert.Len(t, validate(t, "live-us", guardrails.NoAggressiveEvictionInProduction, guardrails.AutoscalerType, limitedAutoscaler(), config), 1)
+	assert.Empty(t, validate(t, "prod", guardrails.NoAggressiveEvictionInProduction, guardrails.AutoscalerType, limitedAutoscaler(), config),
+		"configured patterns replace the defaults")
+}
+
+// TestNodeTemplateCPUBounds tests that node templates must set maxCpu
+func TestNodeTemplateCPUBounds(t *testing.T) {
+	bounded := map[string]any{
+		"name":        "default-by-castai",
+		"constraints": map[string]any{"minCpu": 2, "maxCpu": 32},
+	}
+	assert.Empty(t, validate(t, "dev", guardrails.NodeTemplateCPUBounds, guardrails.NodeTemplateType, bounded, nil))
+
+	for name, inputs := range map[string]map[string]any{
+		"no constraints": {"name": "gpu"},
+		"no maxCpu":      {"name": "gpu", "constraints": map[string]any{"minCpu": 8}},
+	} {
+		got := validate(t, "dev", guardrails.NodeTemplateCPUBounds, guardrails.NodeTemplateType, inputs, nil)
+		assert.Equal(t, []string{"constraints.maxCpu must be set."}, got, name)
+	}
+
+	inverted := map[string]any{"constraints": map[string]any{"minCpu": 64, "maxCpu": 32}}
+	assert.Equal(t, []string{"constraints.minCpu (64) is larger than constraints.maxCpu (32)."},
+		validate(t, "dev", guardrails.NodeTemplateCPUBounds, guardrails.NodeTemplateType, inverted, nil))
+
+	assert.Equal(t, []string{"constraints.maxCpu is 32, the largest allowed value is 16."},
+		validate(t, "dev", guardrails.NodeTemplateCPUBounds, guardrails.NodeTemplateType, bounded, map[string]any{"maxCpu": float64(16)}))
+}
+
+// TestNoImmediateApplyOnManagedInProduction tests the workload scaling policy rule
+func TestNoImmediateApplyOnManagedInProduction(t *testing.T) {
+	policy := func(applyType, managementOption string) map[string]any {
+		return map[string]any{
+			"clusterId":        "cluster-1",
+			"name":             "default",
+			"applyType":        applyType,
+			"managementOption": managementOption,
+		}
+	}
+
+	assert.Len(t, validate(t, "prod", guardrails.NoImmediateApplyOnManagedInProduction, guardrails.WorkloadScalingPolicyType,
+		policy("IMMEDIATE", "MANAGED"), nil), 1)
+	assert.Empty(t, validate(t, "prod", guardrails.NoImmediateApplyOnManagedInProduction, guardrails.WorkloadScalingPolicyType,
+		policy("DEFERRED", "MANAGED"), nil))
+	assert.Empty(t, validate(t, "prod", guardrails.NoImmediateApplyOnManagedInProduction, guardrails.WorkloadScalingPolicyType,
+		policy("IMMEDIATE", "READ_ONLY"), nil))
+	assert.Empty(t, validate(t, "dev", guardrails.NoImmediateApplyOnManagedInProduction, guardrails.WorkloadScalingPolicyType,
+		policy("IMMEDIATE", "MANAGED"), nil))
+}
+
+// TestKeepNodesOnDisconnect tests that no cluster type may delete nodes on disconnect
+func TestKeepNodesOnDisconnect(t *testing.T) {
+	for _, typ := range []string{guardrails.EksClusterType, guardrails.GkeClusterType, guardrails.AksClusterType} {
+		assert.Len(t, validate(t, "dev", guardrails.KeepNodesOnDisconnect, typ,
+			map[string]any{"name": "c", "deleteNodesOnDisconnect": true}, nil), 1, typ)
+		assert.Empty(t, validate(t, "dev", guardrails.KeepNodesOnDisconnect, typ,
+			map[string]any{"name": "c", "deleteNodesOnDisconnect": false}, nil), typ)
+		assert.Empty(t, validate(t, "dev", guardrails.KeepNodesOnDisconnect, typ,
+			map[string]any{"name": "c"}, nil), typ)
+	}
+}
+
+// TestScheduleCronExpressions tests the hibernation and rebalancing schedule checks
+func TestScheduleCronExpressions(t *testing.T) {
+	hibernation := func(pause, resume string, resumeEnabled bool) map[string]any {
+		return map[string]any{
+			"name":           "weekends",
+			"enabled":        true,
+			"organizationId": "org-1",
+			"pauseConfig":    map[string]any{"enabled": true, "schedule": map[string]any{"cronExpression": pause}},
+			"resumeConfig":   map[string]any{"enabled": resumeEnabled, "schedule": map[string]any{"cronExpression": resume}},
+		}
+	}
+
+	assert.Empty(t, validate(t, "dev", guardrails.ScheduleCronExpressions, guardrails.HibernationScheduleType,
+		hibernation("CRON_TZ=America/New_York 0 20 * * 5", "CRON_TZ=America/New_York 0 7 * * 1", true), nil))
+
+	got := validate(t, "dev", guardrails.ScheduleCronExpressions, guardrails.HibernationScheduleType,
+		hibernation("0 20 * * 5", "0 7 * * 1", false), nil)
+	require.Len(t, got, 1)
+	assert.True(t, strings.HasPrefix(got[0], "no-resume: the resume config is disabled"), got[0])
+
+	got = validate(t, "dev", guardrails.ScheduleCronExpressions, guardrails.HibernationScheduleType,
+		hibernation("0 20 * * 5", "0 7 * * 1-5", true), nil)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "resume-before-pause: resume fires at")
+
+	got = validate(t, "dev", guardrails.ScheduleCronExpressions, guardrails.HibernationScheduleType,
+		hibernation("0 20 * * 5", "CRON_TZ=Europe/Berlin 30 2 * * 0", true), nil)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "dst-gap: resume cron")
+
+	disabled := hibernation("0 20 * * 5", "0 7 * * 1", false)
+	disabled["enabled"] = false
+	assert.Empty(t, validate(t, "dev", guardrails.ScheduleCronExpressions, guardrails.HibernationScheduleType, disabled, nil))
+
+	assert.Empty(t, validate(t, "dev", guardrails.ScheduleCronExpressions, guardrails.RebalancingScheduleType,
+		map[string]any{"name": "nightly", "schedule": map[string]any{"cron": "0 3 * * *"}}, nil))
+	assert.Equal(t, []string{`invalid: cron "0 3 * *": expected 5 fields, got 4.`},
+		validate(t, "dev", guardrails.ScheduleCronExpressions, guardrails.RebalancingScheduleType,
+			map[string]any{"name": "nightly", "schedule": map[string]any{"cron": "0 3 * *"}}, nil))
+}
+
+// TestPoliciesIgnoreOtherTypes tests that every policy only checks its own resource types
+func TestPoliciesIgnoreOtherTypes(t *testing.T) {
+	for _, p := range guardrails.Policies("prod") {
+		got := validate(t, "prod", p.Name(), "castai:index:PodMutation", map[string]any{"clusterId": "cluster-1"}, nil)
+		assert.Empty(t, got, p.Name())
+	}
+}
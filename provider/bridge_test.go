@@ -0,0 +1,132 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package castai
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/castai/pulumi-castai/provider/pkg/fakeapi"
+	"github.com/castai/pulumi-castai/provider/pkg/version"
+)
+
+// bridgedProvider serves Provider() the way the plugin binary does and
+// configures it against the given fake API.
+func bridgedProvider(t *testing.T, s *fakeapi.Server) pulumirpc.ResourceProviderServer {
+	t.Helper()
+	schemaBytes, err := os.ReadFile("cmd/pulumi-resource-castai/schema.json")
+	require.NoError(t, err)
+
+	info := Provider()
+	server := tfbridge.NewProvider(context.Background(), nil, "castai", version.Version, info.P, info, schemaBytes)
+	_, err = server.Configure(context.Background(), &pulumirpc.ConfigureRequest{
+		Args: marshal(t, resource.PropertyMap{
+			"apiUrl":       resource.NewStringProperty(s.URL),
+			"apiToken":     resource.NewStringProperty(s.Token()),
+			"retryBackoff": resource.NewStringProperty("1ms"),
+		}),
+		AcceptSecrets: true,
+	})
+	require.NoError(t, err)
+	return server
+}
+
+// marshal encodes a property map for the provider RPCs.
+func marshal(t *testing.T, props resource.PropertyMap) *structpb.Struct {
+	t.Helper()
+	s, err := plugin.MarshalProperties(props, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	require.NoError(t, err)
+	return s
+}
+
+// unmarshal decodes the properties returned by the provider RPCs.
+func unmarshal(t *testing.T, s *structpb.Struct) resource.PropertyMap {
+	t.Helper()
+	props, err := plugin.UnmarshalProperties(s, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	require.NoError(t, err)
+	return props
+}
+
+// TestBridgedNodeConfigurationLifecycle tests a create, read, update and
+// delete of a node configuration through the bridged provider and the
+// upstream API client
+func TestBridgedNodeConfigurationLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := fakeapi.Start()
+	defer s.Close()
+	s.Put("/v1/kubernetes/external-clusters/cluster-1", fakeapi.Object{"id": "cluster-1", "name": "test"})
+	server := bridgedProvider(t, s)
+
+	urn := string(resource.NewURN("test", "test", "", "castai:config/node:NodeConfiguration", "custom"))
+	inputs := func(diskCPURatio float64) resource.PropertyMap {
+		return resource.PropertyMap{
+			"clusterId":    resource.NewStringProperty("cluster-1"),
+			"name":         resource.NewStringProperty("custom"),
+			"diskCpuRatio": resource.NewNumberProperty(diskCPURatio),
+			"subnets": resource.NewArrayProperty([]resource.PropertyValue{
+				resource.NewStringProperty("subnet-1"),
+			}),
+		}
+	}
+
+	checked, err := server.Check(ctx, &pulumirpc.CheckRequest{Urn: urn, News: marshal(t, inputs(25))})
+	require.NoError(t, err)
+	require.Empty(t, checked.GetFailures())
+
+	created, err := server.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: checked.GetInputs()})
+	require.NoError(t, err)
+	id := created.GetId()
+	require.NotEmpty(t, id)
+	path := "/v1/kubernetes/clusters/cluster-1/node-configurations/" + id
+	stored, ok := s.Get(path)
+	require.True(t, ok, "the configuration should be created in the API")
+	assert.EqualValues(t, 25, stored["diskCpuRatio"])
+
+	read, err := server.Read(ctx, &pulumirpc.ReadRequest{Id: id, Urn: urn, Properties: created.GetProperties()})
+	require.NoError(t, err)
+	assert.Equal(t, id, read.GetId())
+	state := unmarshal(t, read.GetProperties())
+	assert.Equal(t, "custom", state["name"].StringValue())
+	assert.Equal(t, 25.0, state["diskCpuRatio"].NumberValue())
+
+	checked, err = server.Check(ctx, &pulumirpc.CheckRequest{Urn: urn, Olds: read.GetInputs(), News: marshal(t, inputs(30))})
+	require.NoError(t, err)
+	require.Empty(t, checked.GetFailures())
+	diff, err := server.Diff(ctx, &pulumirpc.DiffRequest{Id: id, Urn: urn, Olds: read.GetProperties(), News: checked.GetInputs()})
+	require.NoError(t, err)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Empty(t, diff.GetReplaces(), "changing the disk to CPU ratio should update in place")
+
+	updated, err := server.Update(ctx, &pulumirpc.UpdateRequest{Id: id, Urn: urn, Olds: read.GetProperties(), News: checked.GetInputs()})
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, unmarshal(t, updated.GetProperties())["diskCpuRatio"].NumberValue())
+	stored, ok = s.Get(path)
+	require.True(t, ok)
+	assert.EqualValues(t, 30, stored["diskCpuRatio"])
+
+	_, err = server.Delete(ctx, &pulumirpc.DeleteRequest{Id: id, Urn: urn, Properties: updated.GetProperties()})
+	require.NoError(t, err)
+	_, ok = s.Get(path)
+	assert.False(t, ok, "the configuration should be deleted from the API")
+}
@@ -0,0 +1,193 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package castai
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/castai/pulumi-castai/provider/pkg/readiness"
+)
+
+// clusterReadinessResource is the TF name of the ClusterReadiness resource,
+// which only exists in this provider.
+const clusterReadinessResource = "castai_cluster_readiness"
+
+// clusterReadinessDocs are the TF-style docs tfgen generates the resource
+// docs from, as the upstream docs do not cover it.
+//
+//go:embed docs/castai_cluster_readiness.md
+var clusterReadinessDocs []byte
+
+// readinessAPI is the CAST AI API the ClusterReadiness resource polls. It is
+// filled in when the provider is configured.
+type readinessAPI struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// withClusterReadiness adds the castai_cluster_readiness resource to the
+// provider.
+//
+// The resource needs the API URL and key, which the upstream provider keeps
+// in its unexported meta. They are therefore read from the provider config
+// while it is configured. It must be wrapped by withHTTPSettings: its client
+// is then built while the setting-aware transport replaces
+// http.DefaultTransport, so polling shares that transport and is retried and
+// throttled like every other API call.
+func withClusterReadiness(p *schema.Provider) *schema.Provider {
+	api := &readinessAPI{}
+	p.ResourcesMap[clusterReadinessResource] = resourceClusterReadiness(api)
+
+	configure := p.ConfigureContextFunc
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		var meta interface{}
+		if configure != nil {
+			var diags diag.Diagnostics
+			if meta, diags = configure(ctx, d); diags.HasError() {
+				return meta, diags
+			}
+		}
+		api.url, _ = d.Get("api_url").(string)
+		api.token, _ = d.Get("api_token").(string)
+		// The transport of the HTTP settings, which is only in place for
+		// the duration of configure.
+		api.client = &http.Client{Transport: http.DefaultTransport}
+		return meta, nil
+	}
+	return p
+}
+
+func resourceClusterReadiness(api *readinessAPI) *schema.Resource {
+	conditions := make([]string, len(readiness.Conditions))
+	for i, c := range readiness.Conditions {
+		conditions[i] = string(c)
+	}
+
+	return &schema.Resource{
+		Description: "Waits until the CAST AI agent of a registered cluster has connected. " +
+			"Resources that configure the cluster, such as node configurations and autoscaler policies, " +
+			"should depend on it so they are not created before the agent is running.",
+		CreateContext: api.waitContext,
+		ReadContext:   api.readContext,
+		UpdateContext: api.waitContext,
+		DeleteContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics { return nil },
+		// The wait is bounded by the timeout field rather than the SDK's
+		// 20 minute default.
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(24 * time.Hour),
+			Update: schema.DefaultTimeout(24 * time.Hour),
+		},
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "CAST AI cluster ID to wait for.",
+			},
+			"wait_for": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(readiness.Connected),
+				ValidateFunc: validation.StringInSlice(conditions, false),
+				Description: "Condition to wait for: \"connected\" once the agent reports online, " +
+					"\"ready\" once CAST AI has also finished onboarding the cluster.",
+			},
+			"timeout": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "15m",
+				ValidateDiagFunc: validateDuration,
+				Description:      "Maximum time to wait, e.g. \"10m\". \"0\" waits until the update is cancelled.",
+			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "10s",
+				ValidateDiagFunc: validateDuration,
+				Description:      "Wait between two status checks, e.g. \"30s\".",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Cluster status, e.g. \"connecting\" or \"ready\".",
+			},
+			"agent_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Agent status, e.g. \"waiting-connection\" or \"online\".",
+			},
+		},
+	}
+}
+
+// waitContext waits for the cluster on create and update. Neither a failed
+// create nor the inputs of a failed update are stored, so the next update
+// waits again.
+func (api *readinessAPI) waitContext(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	cfg := api.config()
+	cfg.Condition = readiness.Condition(d.Get("wait_for").(string))
+	// Both durations are checked by validateDuration.
+	cfg.Timeout, _ = time.ParseDuration(d.Get("timeout").(string))
+	cfg.Interval, _ = time.ParseDuration(d.Get("poll_interval").(string))
+
+	clusterID := d.Get("cluster_id").(string)
+	status, err := readiness.Wait(ctx, cfg, clusterID)
+	if err != nil {
+		d.Partial(true)
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterID)
+	return setReadinessStatus(d, status)
+}
+
+// readContext refreshes the status without waiting. The status fields are
+// computed, so a cluster that has since disconnected only changes their
+// values in the state: it is neither an error nor a diff, and the next update
+// does not wait again unless an input changes or the resource is replaced.
+func (api *readinessAPI) readContext(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	status, err := readiness.Get(ctx, api.config(), d.Id())
+	if errors.Is(err, readiness.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return setReadinessStatus(d, status)
+}
+
+func (api *readinessAPI) config() readiness.Config {
+	return readiness.Config{APIURL: api.url, APIToken: api.token, HTTPClient: api.client}
+}
+
+func setReadinessStatus(d *schema.ResourceData, status readiness.Status) diag.Diagnostics {
+	if err := d.Set("status", status.Status); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("agent_status", status.AgentStatus); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
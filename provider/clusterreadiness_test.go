@@ -0,0 +1,147 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package castai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/castai/pulumi-castai/provider/pkg/fakeapi"
+)
+
+// readinessProvider returns a provider with the castai_cluster_readiness
+// resource, configured against s with the given extra settings.
+func readinessProvider(t *testing.T, s *fakeapi.Server, settings ...string) (*schema.Provider, *schema.Resource) {
+	t.Helper()
+	upstream := fakeUpstream(t)
+	upstream.Schema["api_token"] = &schema.Schema{Type: schema.TypeString, Optional: true}
+	upstream.ResourcesMap = map[string]*schema.Resource{}
+	p := withHTTPSettings(withClusterReadiness(upstream))
+	config := map[string]interface{}{
+		"api_url":   s.URL,
+		"api_token": s.Token(),
+	}
+	for i := 0; i+1 < len(settings); i += 2 {
+		config[settings[i]] = settings[i+1]
+	}
+	configure(t, p, config)
+	return p, p.ResourcesMap[clusterReadinessResource]
+}
+
+func seedCluster(s *fakeapi.Server, id, status, agentStatus string) {
+	s.Put("/v1/kubernetes/external-clusters/"+id, fakeapi.Object{"id": id, "status": status, "agentStatus": agentStatus})
+}
+
+// TestClusterReadinessSchema tests that the resource is added to the provider
+func TestClusterReadinessSchema(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	p, res := readinessProvider(t, s)
+
+	require.NotNil(t, res)
+	assert.NoError(t, p.InternalValidate())
+	assert.True(t, res.Schema["cluster_id"].ForceNew)
+	assert.Equal(t, "connected", res.Schema["wait_for"].Default)
+	assert.True(t, res.Schema["status"].Computed)
+}
+
+// TestClusterReadinessCreate tests that create waits for the agent and stores its status
+func TestClusterReadinessCreate(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	_, res := readinessProvider(t, s)
+	seedCluster(s, "cluster-1", "connecting", "online")
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"cluster_id": "cluster-1"})
+	diags := res.CreateContext(context.Background(), d, nil)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, "cluster-1", d.Id())
+	assert.Equal(t, "connecting", d.Get("status"))
+	assert.Equal(t, "online", d.Get("agent_status"))
+	// The fake API rejects requests without the configured API key.
+	assert.Len(t, s.Requests(), 1)
+}
+
+// TestClusterReadinessTimeout tests that a timed out wait fails the create
+func TestClusterReadinessTimeout(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	_, res := readinessProvider(t, s)
+	seedCluster(s, "cluster-1", "connecting", "online")
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"cluster_id":    "cluster-1",
+		"wait_for":      "ready",
+		"timeout":       "50ms",
+		"poll_interval": "10ms",
+	})
+	diags := res.CreateContext(context.Background(), d, nil)
+
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "cluster cluster-1 is not ready")
+	assert.Empty(t, d.Id(), "a failed wait should not be stored")
+}
+
+// TestClusterReadinessRead tests that read refreshes the status and drops deleted clusters
+func TestClusterReadinessRead(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	_, res := readinessProvider(t, s)
+	seedCluster(s, "cluster-1", "ready", "disconnected")
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"cluster_id": "cluster-1"})
+	d.SetId("cluster-1")
+	diags := res.ReadContext(context.Background(), d, nil)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "disconnected", d.Get("agent_status"), "read should not wait")
+
+	d.SetId("deleted")
+	diags = res.ReadContext(context.Background(), d, nil)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
+
+// TestClusterReadinessHTTPSettings tests that polling goes through the
+// transport of the HTTP settings, which retries throttled requests
+func TestClusterReadinessHTTPSettings(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	_, res := readinessProvider(t, s, retryBackoffField, "1ms")
+	seedCluster(s, "cluster-1", "ready", "online")
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"cluster_id": "cluster-1"})
+	d.SetId("cluster-1")
+	s.FailNext(1, http.StatusTooManyRequests)
+	diags := res.ReadContext(context.Background(), d, nil)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "online", d.Get("agent_status"))
+	assert.Len(t, s.Requests(), 2, "the throttled request should be retried")
+
+	// Without retries the throttled request fails the read.
+	s = fakeapi.Start()
+	defer s.Close()
+	_, res = readinessProvider(t, s, maxRetriesField, "0")
+	seedCluster(s, "cluster-1", "ready", "online")
+	s.FailNext(1, http.StatusTooManyRequests)
+	diags = res.ReadContext(context.Background(), d, nil)
+	assert.True(t, diags.HasError())
+	assert.Len(t, s.Requests(), 1)
+}
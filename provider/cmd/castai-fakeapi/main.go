@@ -0,0 +1,34 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// castai-fakeapi serves the in-memory CAST AI API stand-in so Pulumi programs
+// can run against it by setting CASTAI_API_URL and CASTAI_API_TOKEN.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/castai/pulumi-castai/provider/pkg/fakeapi"
+)
+
+func main() {
+	addr := flag.String("listen", "127.0.0.1:8089", "address to listen on")
+	token := flag.String("token", fakeapi.DefaultToken, "API key accepted in the X-API-Key header")
+	flag.Parse()
+
+	log.Printf("CAST AI fake API listening on http://%s (organization %s)", *addr, fakeapi.DefaultOrganizationID)
+	log.Fatal(http.ListenAndServe(*addr, fakeapi.NewAPI(fakeapi.WithToken(*token))))
+}
@@ -0,0 +1,82 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// castai-import-gen walks a CAST AI organization and writes the JSON file
+// `pulumi import --file` reads to adopt its clusters, node templates, scaling
+// policies and other objects into a Pulumi stack.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/castai/pulumi-castai/provider/pkg/importgen"
+	"github.com/castai/pulumi-castai/provider/pkg/transport"
+)
+
+func main() {
+	apiURL := flag.String("api-url", envOr("CASTAI_API_URL", "https://api.cast.ai"), "CAST AI API URL")
+	orgID := flag.String("organization-id", os.Getenv("CASTAI_ORGANIZATION_ID"), "organization to walk, required if the API key has access to several")
+	out := flag.String("out", "-", "file to write the import file to, - for stdout")
+	flag.Parse()
+
+	token := os.Getenv("CASTAI_API_TOKEN")
+	if token == "" {
+		log.Fatal("CASTAI_API_TOKEN must be set")
+	}
+
+	result, err := importgen.Generate(context.Background(), importgen.Config{
+		APIURL:         *apiURL,
+		APIToken:       token,
+		OrganizationID: *orgID,
+		HTTPClient: &http.Client{Transport: transport.New(http.DefaultTransport, transport.Config{
+			MaxRetries: 3,
+			Backoff:    time.Second,
+			Timeout:    time.Minute,
+		})},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, w := range result.Warnings {
+		log.Printf("warning: %s", w)
+	}
+
+	data, err := json.MarshalIndent(result.File, "", "    ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+	if *out == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*out, data, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d resources to import", len(result.File.Resources))
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
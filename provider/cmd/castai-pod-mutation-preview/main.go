@@ -0,0 +1,146 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// castai-pod-mutation-preview validates a PodMutation and shows the pods it
+// turns a sample pod into.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/castai/pulumi-castai/provider/pkg/podmutation"
+)
+
+func main() {
+	mutation := flag.String("mutation", "", "YAML or JSON file with the PodMutation inputs, - for stdin")
+	stack := flag.String("stack-export", "", "output of `pulumi stack export` to read the mutation from, - for stdin")
+	name := flag.String("name", "", "mutation to read from -stack-export, required if the stack manages several")
+	asJSON := flag.Bool("json", false, "write the variants as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: castai-pod-mutation-preview (-mutation FILE | -stack-export FILE) [flags] [MANIFEST]\n\n"+
+			"MANIFEST is a Pod, or a workload with a pod template, in YAML or JSON. Without it\n"+
+			"the mutation is only validated.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+
+	var (
+		m   *podmutation.Mutation
+		err error
+	)
+	switch {
+	case (*mutation == "") == (*stack == ""), flag.NArg() > 1:
+		flag.Usage()
+		os.Exit(2)
+	case *mutation != "":
+		err = read(*mutation, func(r io.Reader) (err error) {
+			m, err = podmutation.LoadMutation(r)
+			return err
+		})
+	default:
+		err = read(*stack, func(r io.Reader) (err error) {
+			m, err = podmutation.LoadStackExport(r, *name)
+			return err
+		})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := m.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if flag.NArg() == 0 {
+		return
+	}
+
+	var pod *corev1.Pod
+	if err := read(flag.Arg(0), func(r io.Reader) (err error) {
+		pod, err = podmutation.LoadPod(r)
+		return err
+	}); err != nil {
+		log.Fatal(err)
+	}
+	variants, err := podmutation.Preview(m, pod)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(variants)
+	} else {
+		err = write(os.Stdout, variants)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// read opens path, or stdin for -, and passes it to load.
+func read(path string, load func(io.Reader) error) error {
+	if path == "-" {
+		return load(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// write prints each variant as a YAML document headed by its name and share.
+func write(w io.Writer, variants []podmutation.Variant) error {
+	for i, v := range variants {
+		data, err := json.Marshal(v.Pod)
+		if err != nil {
+			return err
+		}
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		name := v.Name
+		if name == "" {
+			name = "all pods"
+		}
+		fmt.Fprintf(w, "# %s (%g%%)\n", name, v.Share)
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
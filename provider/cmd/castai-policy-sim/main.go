@@ -0,0 +1,151 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// castai-policy-sim reports which WorkloadScalingPolicy each workload of a
+// set of Kubernetes manifests would be assigned, and flags policies that are
+// unreachable or shadowed by a higher-ordered policy.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/castai/pulumi-castai/provider/pkg/policysim"
+)
+
+func main() {
+	policies := flag.String("policies", "", "YAML or JSON file with the policies and their order, - for stdin")
+	stack := flag.String("stack-export", "", "output of `pulumi stack export` to read the policies from, - for stdin")
+	clusterID := flag.String("cluster-id", "", "cluster to read from -stack-export, required if the stack manages several")
+	asJSON := flag.Bool("json", false, "write the report as JSON")
+	strict := flag.Bool("strict", false, "exit with status 1 if any policy is flagged")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: castai-policy-sim (-policies FILE | -stack-export FILE) [flags] MANIFEST...\n\n"+
+			"MANIFEST is a YAML or JSON file, or a directory searched for them.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+
+	var (
+		spec *policysim.Spec
+		err  error
+	)
+	switch {
+	case (*policies == "") == (*stack == ""):
+		flag.Usage()
+		os.Exit(2)
+	case *policies != "":
+		err = read(*policies, func(r io.Reader) (err error) {
+			spec, err = policysim.LoadSpec(r)
+			return err
+		})
+	default:
+		err = read(*stack, func(r io.Reader) (err error) {
+			spec, err = policysim.LoadStackExport(r, *clusterID)
+			return err
+		})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cluster := &policysim.Cluster{}
+	for _, path := range flag.Args() {
+		if err := loadManifests(cluster, path); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	report := policysim.Simulate(spec, cluster)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(report)
+	} else {
+		err = write(os.Stdout, report)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *strict && len(report.Findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+// read opens path, or stdin for -, and passes it to load.
+func read(path string, load func(io.Reader) error) error {
+	if path == "-" {
+		return load(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// loadManifests reads a manifest file, or every manifest file of a directory.
+func loadManifests(cluster *policysim.Cluster, path string) error {
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if p != path {
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+			default:
+				return nil
+			}
+		}
+		return read(p, func(r io.Reader) error { return policysim.LoadManifests(cluster, r) })
+	})
+}
+
+func write(w io.Writer, report *policysim.Report) error {
+	fmt.Fprintf(w, "Policy order: %s\n\n", strings.Join(report.Order, ", "))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "WORKLOAD\tPOLICY\tALSO MATCHES")
+	for _, a := range report.Assignments {
+		policy := a.Policy
+		if policy == "" {
+			policy = "(cluster default)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", a.Workload, policy, strings.Join(a.Shadowed, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(w)
+		for _, f := range report.Findings {
+			fmt.Fprintln(w, f)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package castai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/convert"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	codegenconvert "github.com/pulumi/pulumi/pkg/v3/codegen/convert"
+	gogen "github.com/pulumi/pulumi/pkg/v3/codegen/go"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// convertTestsDir holds one directory per `pulumi convert --from terraform`
+// golden test: a main.tf and the main.go it converts to. It is part of the Go
+// SDK test module, so building that module compiles the converted programs.
+var convertTestsDir = filepath.Join("..", "tests", "sdk", "go", "convert")
+
+// providerSchemaPath is the schema the provider binary embeds and serves.
+var providerSchemaPath = filepath.Join("cmd", "pulumi-resource-castai", "schema.json")
+
+// TestConvertMapping tests that the mapping served to `pulumi convert` covers
+// every TF resource and data source with the tokens and property names of the
+// Pulumi schema
+func TestConvertMapping(t *testing.T) {
+	prov := Provider()
+	mapped := unmarshalMapping(t, convertMapping(t, prov))
+	spec := loadSchemaSpec(t)
+	ignored := ignoredMappings(prov)
+
+	for _, name := range upstreamNames(prov.P.ResourcesMap()) {
+		if ignored[name] {
+			continue
+		}
+		info, ok := mapped.Resources[name]
+		if !assert.True(t, ok, "resource %s is missing from the mapping", name) {
+			continue
+		}
+		res, ok := spec.Resources[string(info.Tok)]
+		if !assert.True(t, ok, "resource %s maps to %s, which is not in schema.json", name, info.Tok) {
+			continue
+		}
+		props := map[string]schema.PropertySpec{}
+		for k, v := range res.InputProperties {
+			props[k] = v
+		}
+		for k, v := range res.Properties {
+			props[k] = v
+		}
+		tfRes, _ := mapped.P.ResourcesMap().GetOk(name)
+		assertMappedNames(t, spec, name, tfRes.Schema(), info.Fields, props)
+	}
+
+	for _, name := range upstreamNames(prov.P.DataSourcesMap()) {
+		if ignored[name] {
+			continue
+		}
+		info, ok := mapped.DataSources[name]
+		if !assert.True(t, ok, "data source %s is missing from the mapping", name) {
+			continue
+		}
+		fn, ok := spec.Functions[string(info.Tok)]
+		if !assert.True(t, ok, "data source %s maps to %s, which is not in schema.json", name, info.Tok) {
+			continue
+		}
+		props := map[string]schema.PropertySpec{}
+		if fn.Inputs != nil {
+			for k, v := range fn.Inputs.Properties {
+				props[k] = v
+			}
+		}
+		if fn.ReturnType != nil && fn.ReturnType.ObjectTypeSpec != nil {
+			for k, v := range fn.ReturnType.ObjectTypeSpec.Properties {
+				props[k] = v
+			}
+		}
+		tfDS, _ := mapped.P.DataSourcesMap().GetOk(name)
+		assertMappedNames(t, spec, "data."+name, tfDS.Schema(), info.Fields, props)
+	}
+}
+
+// TestConvert tests that the HCL samples in convertTestsDir convert to the Go
+// programs next to them. Run with PULUMI_ACCEPT=1 to update the programs.
+func TestConvert(t *testing.T) {
+	entries, err := os.ReadDir(convertTestsDir)
+	if os.IsNotExist(err) {
+		t.Skipf("convert tests not found at %s", convertTestsDir)
+	}
+	require.NoError(t, err)
+
+	mapper := &providerMapper{mapping: convertMapping(t, Provider())}
+	loader := &schemaLoader{spec: loadSchemaSpec(t)}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(convertTestsDir, entry.Name())
+		t.Run(entry.Name(), func(t *testing.T) {
+			_, program, err := convert.Eject(dir, loader, mapper)
+			require.NoError(t, err)
+
+			files, diags, err := gogen.GenerateProgram(program)
+			require.NoError(t, err)
+			require.False(t, diags.HasErrors(), "%v", diags)
+			generated, ok := files["main.go"]
+			require.True(t, ok, "no main.go generated")
+
+			golden := filepath.Join(dir, "main.go")
+			if cmdutil.IsTruthy(os.Getenv("PULUMI_ACCEPT")) {
+				require.NoError(t, os.WriteFile(golden, generated, 0o600))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(generated))
+		})
+	}
+}
+
+// convertMapping returns the mapping the provider serves for the "terraform"
+// key of GetMapping.
+func convertMapping(t *testing.T, prov tfbridge.ProviderInfo) []byte {
+	t.Helper()
+	data, err := json.Marshal(tfbridge.MarshalProviderInfo(&prov))
+	require.NoError(t, err)
+	return data
+}
+
+// unmarshalMapping decodes a mapping the way the converter does.
+func unmarshalMapping(t *testing.T, data []byte) *tfbridge.ProviderInfo {
+	t.Helper()
+	var info tfbridge.MarshallableProviderInfo
+	require.NoError(t, json.Unmarshal(data, &info))
+	return info.Unmarshal()
+}
+
+func loadSchemaSpec(t *testing.T) *schema.PackageSpec {
+	t.Helper()
+	data, err := os.ReadFile(providerSchemaPath)
+	require.NoError(t, err)
+	var spec schema.PackageSpec
+	require.NoError(t, json.Unmarshal(data, &spec))
+	return &spec
+}
+
+// assertMappedNames asserts that the Pulumi name the converter derives for
+// every TF field, including fields of nested blocks, is a property of the
+// corresponding Pulumi resource, function or type.
+func assertMappedNames(t *testing.T, spec *schema.PackageSpec, path string, tfSchema shim.SchemaMap,
+	infos map[string]*tfbridge.SchemaInfo, props map[string]schema.PropertySpec,
+) {
+	t.Helper()
+	tfSchema.Range(func(key string, field shim.Schema) bool {
+		name := tfbridge.TerraformToPulumiNameV2(key, tfSchema, infos)
+		prop, ok := props[name]
+		if !assert.True(t, ok, "%s.%s converts to %s, which is not in schema.json", path, key, name) {
+			return true
+		}
+		block, ok := field.Elem().(shim.Resource)
+		if !ok {
+			return true
+		}
+		ref := prop.Ref
+		if ref == "" && prop.Items != nil {
+			ref = prop.Items.Ref
+		}
+		typ, ok := spec.Types[strings.TrimPrefix(ref, "#/types/")]
+		if !assert.True(t, ok, "%s.%s refers to unknown type %q", path, key, ref) {
+			return true
+		}
+		var nested map[string]*tfbridge.SchemaInfo
+		if info := infos[key]; info != nil {
+			nested = info.Fields
+			if nested == nil && info.Elem != nil {
+				nested = info.Elem.Fields
+			}
+		}
+		assertMappedNames(t, spec, path+"."+key, block.Schema(), nested, typ.Properties)
+		return true
+	})
+}
+
+// providerMapper serves the provider's mapping like the provider plugin's
+// GetMapping does.
+type providerMapper struct {
+	mapping []byte
+}
+
+func (m *providerMapper) GetMapping(_ context.Context, provider string, _ *codegenconvert.MapperPackageHint) ([]byte, error) {
+	if provider != "castai" {
+		return nil, nil
+	}
+	return m.mapping, nil
+}
+
+// schemaLoader serves the provider schema like the provider plugin's
+// GetSchema does.
+type schemaLoader struct {
+	spec *schema.PackageSpec
+
+	once sync.Once
+	pkg  *schema.Package
+	err  error
+}
+
+func (l *schemaLoader) LoadPackage(pkg string, _ *semver.Version) (*schema.Package, error) {
+	if pkg != "castai" {
+		return nil, fmt.Errorf("unknown package %q", pkg)
+	}
+	l.once.Do(func() {
+		var diags hcl.Diagnostics
+		l.pkg, diags, l.err = schema.BindSpec(*l.spec, l, schema.ValidationOptions{AllowDanglingReferences: true})
+		if l.err == nil && diags.HasErrors() {
+			l.err = diags
+		}
+	})
+	return l.pkg, l.err
+}
+
+func (l *schemaLoader) LoadPackageV2(_ context.Context, pkg *schema.PackageDescriptor) (*schema.Package, error) {
+	return l.LoadPackage(pkg.Name, pkg.Version)
+}
+
+func (l *schemaLoader) LoadPackageReference(pkg string, version *semver.Version) (schema.PackageReference, error) {
+	p, err := l.LoadPackage(pkg, version)
+	if err != nil {
+		return nil, err
+	}
+	return p.Reference(), nil
+}
+
+func (l *schemaLoader) LoadPackageReferenceV2(
+	ctx context.Context, pkg *schema.PackageDescriptor,
+) (schema.PackageReference, error) {
+	return l.LoadPackageReference(pkg.Name, pkg.Version)
+}
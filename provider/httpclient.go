@@ -0,0 +1,165 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package castai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+
+	"github.com/castai/pulumi-castai/provider/pkg/transport"
+)
+
+// Provider config fields that tune the HTTP client of the CAST AI API.
+const (
+	maxRetriesField            = "max_retries"
+	retryBackoffField          = "retry_backoff"
+	requestTimeoutField        = "request_timeout"
+	maxConcurrentRequestsField = "max_concurrent_requests"
+)
+
+// httpSettingsEnvVars are the env vars read when the HTTP settings are not set.
+var httpSettingsEnvVars = map[string]string{
+	maxRetriesField:            "CASTAI_MAX_RETRIES",
+	retryBackoffField:          "CASTAI_RETRY_BACKOFF",
+	requestTimeoutField:        "CASTAI_REQUEST_TIMEOUT",
+	maxConcurrentRequestsField: "CASTAI_MAX_CONCURRENT_REQUESTS",
+}
+
+// httpSettingsDefaults are the values used when neither the config nor the
+// env vars set an HTTP setting.
+var httpSettingsDefaults = map[string]interface{}{
+	maxRetriesField:            3,
+	retryBackoffField:          "1s",
+	requestTimeoutField:        "1m",
+	maxConcurrentRequestsField: 0,
+}
+
+// defaultTransportMu serializes the configure calls that swap
+// http.DefaultTransport.
+var defaultTransportMu sync.Mutex
+
+// withHTTPSettings adds the HTTP settings to the provider schema and applies
+// them while the provider is configured.
+//
+// The upstream provider does not expose its HTTP client; it wraps
+// http.DefaultTransport when it builds the client in its configure function.
+// The setting-aware transport therefore replaces http.DefaultTransport only
+// for the duration of that call, and the original is restored before
+// configure returns, so nothing else in the process sees the swap.
+func withHTTPSettings(p *schema.Provider) *schema.Provider {
+	p.Schema[maxRetriesField] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		DefaultFunc:  schema.EnvDefaultFunc(httpSettingsEnvVars[maxRetriesField], httpSettingsDefaults[maxRetriesField]),
+		ValidateFunc: validation.IntAtLeast(0),
+		Description:  "Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.",
+	}
+	p.Schema[retryBackoffField] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		DefaultFunc:      schema.EnvDefaultFunc(httpSettingsEnvVars[retryBackoffField], httpSettingsDefaults[retryBackoffField]),
+		ValidateDiagFunc: validateDuration,
+		Description:      "Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.",
+	}
+	p.Schema[requestTimeoutField] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		DefaultFunc:      schema.EnvDefaultFunc(httpSettingsEnvVars[requestTimeoutField], httpSettingsDefaults[requestTimeoutField]),
+		ValidateDiagFunc: validateDuration,
+		Description:      "Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.",
+	}
+	p.Schema[maxConcurrentRequestsField] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		DefaultFunc:  schema.EnvDefaultFunc(httpSettingsEnvVars[maxConcurrentRequestsField], httpSettingsDefaults[maxConcurrentRequestsField]),
+		ValidateFunc: validation.IntAtLeast(0),
+		Description:  "Maximum number of CAST AI API requests in flight. `0` means no limit.",
+	}
+
+	configure := p.ConfigureContextFunc
+	if configure == nil && p.ConfigureFunc != nil {
+		configureFunc := p.ConfigureFunc
+		configure = func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			meta, err := configureFunc(d)
+			return meta, diag.FromErr(err)
+		}
+	}
+	p.ConfigureFunc = nil
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		cfg, err := httpSettings(d)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		if configure == nil {
+			return nil, nil
+		}
+		defaultTransportMu.Lock()
+		defer defaultTransportMu.Unlock()
+		base := http.DefaultTransport
+		http.DefaultTransport = transport.New(base, cfg)
+		defer func() { http.DefaultTransport = base }()
+		return configure(ctx, d)
+	}
+	return p
+}
+
+// httpSettingConfig returns the Pulumi config mapping of an HTTP setting.
+func httpSettingConfig(field string) *tfbridge.SchemaInfo {
+	return &tfbridge.SchemaInfo{
+		Default: &tfbridge.DefaultInfo{
+			Value:   httpSettingsDefaults[field],
+			EnvVars: []string{httpSettingsEnvVars[field]},
+		},
+	}
+}
+
+// httpSettings reads the HTTP settings from the provider configuration.
+func httpSettings(d *schema.ResourceData) (transport.Config, error) {
+	backoff, err := time.ParseDuration(d.Get(retryBackoffField).(string))
+	if err != nil {
+		return transport.Config{}, fmt.Errorf("%s: %w", retryBackoffField, err)
+	}
+	timeout, err := time.ParseDuration(d.Get(requestTimeoutField).(string))
+	if err != nil {
+		return transport.Config{}, fmt.Errorf("%s: %w", requestTimeoutField, err)
+	}
+	return transport.Config{
+		MaxRetries:    d.Get(maxRetriesField).(int),
+		Backoff:       backoff,
+		Timeout:       timeout,
+		MaxConcurrent: d.Get(maxConcurrentRequestsField).(int),
+	}, nil
+}
+
+// validateDuration accepts non-negative Go durations such as 500ms or 1m.
+func validateDuration(v interface{}, _ cty.Path) diag.Diagnostics {
+	d, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return diag.Errorf("invalid duration %q, use e.g. 500ms, 2s or 1m", v)
+	}
+	if d < 0 {
+		return diag.Errorf("duration %q must not be negative", v)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package castai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/castai/terraform-provider-castai/castai"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/castai/pulumi-castai/provider/pkg/fakeapi"
+	"github.com/castai/pulumi-castai/provider/pkg/transport"
+	"github.com/castai/pulumi-castai/provider/pkg/version"
+)
+
+// fakeUpstream returns a provider that, like terraform-provider-castai,
+// builds its API client on top of http.DefaultTransport when configured.
+func fakeUpstream(t *testing.T) *schema.Provider {
+	defaultTransport := http.DefaultTransport
+	t.Cleanup(func() {
+		assert.Same(t, defaultTransport, http.DefaultTransport, "configure must restore http.DefaultTransport")
+	})
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"api_url": {Type: schema.TypeString, Optional: true},
+		},
+		ConfigureContextFunc: func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return &http.Client{Transport: http.DefaultTransport}, nil
+		},
+	}
+}
+
+// configure configures p with the given raw config and returns its meta.
+func configure(t *testing.T, p *schema.Provider, config map[string]interface{}) *http.Client {
+	t.Helper()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(config))
+	require.False(t, diags.HasError(), "%v", diags)
+	client, ok := p.Meta().(*http.Client)
+	require.True(t, ok)
+	return client
+}
+
+// TestHTTPSettingsSchema tests that the HTTP settings are added to the provider schema
+func TestHTTPSettingsSchema(t *testing.T) {
+	p := withHTTPSettings(fakeUpstream(t))
+
+	for _, field := range []string{maxRetriesField, retryBackoffField, requestTimeoutField, maxConcurrentRequestsField} {
+		sch, ok := p.Schema[field]
+		require.True(t, ok, field)
+		assert.True(t, sch.Optional, field)
+		assert.NotEmpty(t, sch.Description, field)
+	}
+	assert.NoError(t, p.InternalValidate())
+}
+
+// TestHTTPSettingsDefaults tests the transport settings of an unconfigured provider
+func TestHTTPSettingsDefaults(t *testing.T) {
+	client := configure(t, withHTTPSettings(fakeUpstream(t)), map[string]interface{}{})
+
+	tr, ok := client.Transport.(*transport.Transport)
+	require.True(t, ok, "the upstream client should use the provider transport")
+	assert.Equal(t, transport.Config{
+		MaxRetries: 3,
+		Backoff:    time.Second,
+		Timeout:    time.Minute,
+	}, tr.Config())
+}
+
+// TestHTTPSettingsFromConfig tests that configured values reach the transport
+func TestHTTPSettingsFromConfig(t *testing.T) {
+	client := configure(t, withHTTPSettings(fakeUpstream(t)), map[string]interface{}{
+		maxRetriesField:            5,
+		retryBackoffField:          "250ms",
+		requestTimeoutField:        "10s",
+		maxConcurrentRequestsField: 4,
+	})
+
+	tr := client.Transport.(*transport.Transport)
+	assert.Equal(t, transport.Config{
+		MaxRetries:    5,
+		Backoff:       250 * time.Millisecond,
+		Timeout:       10 * time.Second,
+		MaxConcurrent: 4,
+	}, tr.Config())
+}
+
+// TestHTTPSettingsFromEnv tests that the env vars are read when the config is not set
+func TestHTTPSettingsFromEnv(t *testing.T) {
+	t.Setenv("CASTAI_MAX_RETRIES", "0")
+	t.Setenv("CASTAI_RETRY_BACKOFF", "2s")
+	t.Setenv("CASTAI_REQUEST_TIMEOUT", "0")
+	t.Setenv("CASTAI_MAX_CONCURRENT_REQUESTS", "8")
+
+	client := configure(t, withHTTPSettings(fakeUpstream(t)), map[string]interface{}{})
+
+	tr := client.Transport.(*transport.Transport)
+	assert.Equal(t, transport.Config{
+		Backoff:       2 * time.Second,
+		MaxConcurrent: 8,
+	}, tr.Config())
+}
+
+// TestHTTPSettingsInvalidDuration tests that a malformed duration fails configuration
+func TestHTTPSettingsInvalidDuration(t *testing.T) {
+	p := withHTTPSettings(fakeUpstream(t))
+
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		retryBackoffField: "soon",
+	}))
+
+	assert.True(t, diags.HasError())
+	assert.True(t, validateDuration("-1s", nil).HasError(), "negative durations are rejected")
+}
+
+// TestHTTPSettingsRetryAgainstFakeAPI tests that the configured client rides out
+// throttling and outages of the API
+func TestHTTPSettingsRetryAgainstFakeAPI(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	client := configure(t, withHTTPSettings(fakeUpstream(t)), map[string]interface{}{
+		"api_url":         s.URL,
+		retryBackoffField: "1ms",
+	})
+
+	s.FailNext(1, http.StatusServiceUnavailable)
+	s.FailNext(1, http.StatusBadGateway)
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/v1/organizations", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", s.Token())
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, s.Requests(), 3)
+}
+
+// TestHTTPSettingsRetryUpstream tests that the client the real upstream provider
+// builds in its configure function retries throttled requests
+func TestHTTPSettingsRetryUpstream(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	defaultTransport := http.DefaultTransport
+
+	p := withHTTPSettings(castai.Provider(version.Version))
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"api_url":         s.URL,
+		"api_token":       s.Token(),
+		retryBackoffField: "1ms",
+	}))
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Same(t, defaultTransport, http.DefaultTransport, "configure must restore http.DefaultTransport")
+
+	res := p.ResourcesMap["castai_eks_cluster"]
+	d := res.Data(nil)
+	d.SetId("missing")
+	s.FailNext(2, http.StatusTooManyRequests)
+	diags = res.ReadContext(context.Background(), d, p.Meta())
+	require.False(t, diags.HasError(), "%v", diags)
+
+	var reads int
+	for _, r := range s.Requests() {
+		if r.Method == http.MethodGet && r.Path == "/v1/kubernetes/external-clusters/missing" {
+			reads++
+		}
+	}
+	assert.Equal(t, 3, reads, "two throttled attempts and the final 404")
+	assert.Empty(t, d.Id(), "a cluster the API does not know is removed from state")
+}
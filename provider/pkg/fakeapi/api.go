@@ -0,0 +1,373 @@
+// Package fakeapi implements an in-memory stand-in for the CAST AI REST API.
+//
+// It serves the endpoints the bridged terraform-provider-castai uses for
+// clusters, node configurations, node templates, autoscaler policies,
+// rebalancing, workload scaling policies and organization/IAM objects, so the
+// provider can be configured with `api_url` pointing at a local listener and
+// exercised through full create/read/update/delete cycles without network
+// access or a real CAST AI account.
+//
+// Responses echo the stored request body together with the fields the API
+// assigns on the server side (ids, tokens, statuses and timestamps). Payloads
+// are not validated against the OpenAPI specification.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultToken is the API key accepted when no token is configured.
+	DefaultToken = "fake-castai-api-token"
+	// DefaultOrganizationID is the id of the organization every API starts with.
+	DefaultOrganizationID = "00000000-0000-4000-8000-000000000001"
+	// DefaultOrganizationName is the name of the default organization.
+	DefaultOrganizationName = "fake-organization"
+
+	apiKeyHeader = "X-API-Key"
+)
+
+// Object is a JSON object stored by the fake API.
+type Object = map[string]interface{}
+
+// Request is a request received by the fake API.
+type Request struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// Option configures an API.
+type Option func(*API)
+
+// WithToken sets the API key that requests must present in the X-API-Key header.
+func WithToken(token string) Option {
+	return func(a *API) {
+		a.token = token
+	}
+}
+
+// WithClock overrides the time source used for createdAt/updatedAt fields.
+func WithClock(now func() time.Time) Option {
+	return func(a *API) {
+		a.now = now
+	}
+}
+
+// API is an http.Handler that keeps CAST AI objects in memory.
+type API struct {
+	token string
+	now   func() time.Time
+
+	mu       sync.Mutex
+	seq      int
+	tables   map[string]*table
+	requests []Request
+	failures []int
+}
+
+// table holds the objects of one collection in insertion order.
+type table struct {
+	order []string
+	items map[string]Object
+}
+
+// NewAPI returns an API seeded with the default organization.
+func NewAPI(opts ...Option) *API {
+	a := &API{
+		token:  DefaultToken,
+		now:    time.Now,
+		tables: map[string]*table{},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.put(organizationsPath, DefaultOrganizationID, Object{
+		"id":        DefaultOrganizationID,
+		"name":      DefaultOrganizationName,
+		"createdAt": a.timestamp(),
+	})
+	return a
+}
+
+// Server is an API listening on a local address.
+type Server struct {
+	*API
+	// URL is the base URL to use as the provider's `api_url`.
+	URL string
+
+	srv *httptest.Server
+}
+
+// Start serves a new API on a local loopback listener.
+func Start(opts ...Option) *Server {
+	api := NewAPI(opts...)
+	srv := httptest.NewServer(api)
+	return &Server{API: api, URL: srv.URL, srv: srv}
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Token returns the API key the server accepts.
+func (a *API) Token() string {
+	return a.token
+}
+
+// Get returns a copy of the object stored at the given item path, for example
+// "/v1/kubernetes/external-clusters/<id>".
+func (a *API) Get(path string) (Object, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	base, id := splitItemPath(path)
+	obj, ok := a.get(base, id)
+	if !ok {
+		return nil, false
+	}
+	return clone(obj), true
+}
+
+// List returns copies of all objects stored under a collection path, in the
+// order they were created.
+func (a *API) List(path string) []Object {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.list(strings.TrimSuffix(path, "/"))
+}
+
+// Put stores obj at the given item path, replacing any existing object. It is
+// meant for seeding state that the provider only reads.
+func (a *API) Put(path string, obj Object) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	base, id := splitItemPath(path)
+	a.put(base, id, clone(obj))
+}
+
+// Requests returns the requests received so far.
+func (a *API) Requests() []Request {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	out := make([]Request, len(a.requests))
+	copy(out, a.requests)
+	return out
+}
+
+// FailNext makes the next count requests fail with the given status code
+// before they are routed, to exercise client retries. 429 responses ask the
+// client to retry after one second.
+func (a *API) FailNext(count, status int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		a.failures = append(a.failures, status)
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading request body: %v", err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.requests = append(a.requests, Request{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+
+	if len(a.failures) > 0 {
+		status := a.failures[0]
+		a.failures = a.failures[1:]
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeError(w, status, "injected failure")
+		return
+	}
+
+	if r.Header.Get(apiKeyHeader) != a.token {
+		writeError(w, http.StatusUnauthorized, "invalid or missing %s header", apiKeyHeader)
+		return
+	}
+
+	var payload Object
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			writeError(w, http.StatusBadRequest, "request body is not a JSON object: %v", err)
+			return
+		}
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	for _, rt := range a.routes() {
+		params, ok := match(rt.pattern, path)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			continue
+		}
+		status, resp := rt.handle(&call{path: path, params: params, query: r.URL.Query(), body: payload})
+		writeJSON(w, status, resp)
+		return
+	}
+
+	for _, rt := range a.routes() {
+		if _, ok := match(rt.pattern, path); ok {
+			writeError(w, http.StatusMethodNotAllowed, "method %s is not supported on %s", r.Method, path)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "no route for %s %s", r.Method, path)
+}
+
+func (a *API) nextID() string {
+	a.seq++
+	return fmt.Sprintf("00000000-0000-4000-9000-%012d", a.seq)
+}
+
+func (a *API) timestamp() string {
+	return a.now().UTC().Format(time.RFC3339)
+}
+
+func (a *API) get(base, id string) (Object, bool) {
+	t, ok := a.tables[base]
+	if !ok {
+		return nil, false
+	}
+	obj, ok := t.items[id]
+	return obj, ok
+}
+
+func (a *API) put(base, id string, obj Object) {
+	t, ok := a.tables[base]
+	if !ok {
+		t = &table{items: map[string]Object{}}
+		a.tables[base] = t
+	}
+	if _, exists := t.items[id]; !exists {
+		t.order = append(t.order, id)
+	}
+	t.items[id] = obj
+}
+
+func (a *API) delete(base, id string) bool {
+	t, ok := a.tables[base]
+	if !ok {
+		return false
+	}
+	if _, ok := t.items[id]; !ok {
+		return false
+	}
+	delete(t.items, id)
+	for i, v := range t.order {
+		if v == id {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (a *API) list(base string) []Object {
+	t, ok := a.tables[base]
+	if !ok {
+		return []Object{}
+	}
+	out := make([]Object, 0, len(t.order))
+	for _, id := range t.order {
+		out = append(out, clone(t.items[id]))
+	}
+	return out
+}
+
+// match reports whether path matches pattern, where pattern segments wrapped
+// in braces capture the corresponding path segment.
+func match(pattern, path string) (map[string]string, bool) {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	xs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(ps) != len(xs) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, p := range ps {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			if xs[i] == "" {
+				return nil, false
+			}
+			params[p[1:len(p)-1]] = xs[i]
+			continue
+		}
+		if p != xs[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func splitItemPath(path string) (string, string) {
+	path = strings.TrimSuffix(path, "/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return "", path
+	}
+	return path[:i], path[i+1:]
+}
+
+// clone deep-copies a JSON object so callers never share state with the store.
+func clone(obj Object) Object {
+	if obj == nil {
+		return nil
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		panic(fmt.Sprintf("fakeapi: object is not JSON serializable: %v", err))
+	}
+	var out Object
+	if err := json.Unmarshal(b, &out); err != nil {
+		panic(fmt.Sprintf("fakeapi: %v", err))
+	}
+	return out
+}
+
+// merge applies patch on top of obj, recursing into nested objects.
+func merge(obj, patch Object) {
+	for k, v := range patch {
+		if nested, ok := v.(map[string]interface{}); ok {
+			if cur, ok := obj[k].(map[string]interface{}); ok {
+				merge(cur, nested)
+				continue
+			}
+		}
+		obj[k] = v
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body == nil {
+		body = Object{}
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, Object{"message": fmt.Sprintf(format, args...)})
+}
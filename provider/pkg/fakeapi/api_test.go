@@ -0,0 +1,315 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedClock() time.Time {
+	return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+}
+
+// do sends a JSON request to the server and decodes the JSON response.
+func do(t *testing.T, s *Server, method, path string, body interface{}) (int, Object) {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", s.Token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var out Object
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return resp.StatusCode, out
+}
+
+func registerEKS(t *testing.T, s *Server) string {
+	t.Helper()
+	status, cluster := do(t, s, http.MethodPost, "/v1/kubernetes/external-clusters", Object{
+		"name": "test-eks",
+		"eks":  Object{"accountId": "123456789012", "region": "us-west-2", "clusterName": "test-eks"},
+	})
+	require.Equal(t, http.StatusOK, status)
+	return cluster["id"].(string)
+}
+
+// TestUnauthorized tests that requests without the API key are rejected
+func TestUnauthorized(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/v1/organizations")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+// TestExternalClusterLifecycle tests register, token, update, disconnect and delete of a cluster
+func TestExternalClusterLifecycle(t *testing.T) {
+	s := Start(WithClock(fixedClock))
+	defer s.Close()
+
+	id := registerEKS(t, s)
+
+	status, cluster := do(t, s, http.MethodGet, "/v1/kubernetes/external-clusters/"+id, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "test-eks", cluster["name"])
+	assert.Equal(t, DefaultOrganizationID, cluster["organizationId"])
+	assert.Equal(t, "eks", cluster["providerType"])
+	assert.Equal(t, "ready", cluster["status"])
+	assert.NotEmpty(t, cluster["credentialsId"])
+	assert.Equal(t, "2025-01-02T03:04:05Z", cluster["createdAt"])
+
+	status, token := do(t, s, http.MethodPost, "/v1/kubernetes/external-clusters/"+id+"/token", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.NotEmpty(t, token["token"])
+
+	status, cluster = do(t, s, http.MethodPut, "/v1/kubernetes/external-clusters/"+id, Object{
+		"eks": Object{"assumeRoleArn": "arn:aws:iam::123456789012:role/castai"},
+	})
+	require.Equal(t, http.StatusOK, status)
+	eks := cluster["eks"].(map[string]interface{})
+	assert.Equal(t, "arn:aws:iam::123456789012:role/castai", eks["assumeRoleArn"])
+	assert.Equal(t, "us-west-2", eks["region"], "updates must merge into the stored cluster")
+
+	status, cluster = do(t, s, http.MethodPost, "/v1/kubernetes/external-clusters/"+id+"/disconnect", Object{"deleteProvisionedNodes": false})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "disconnected", cluster["agentStatus"])
+
+	status, _ = do(t, s, http.MethodDelete, "/v1/kubernetes/external-clusters/"+id, nil)
+	require.Equal(t, http.StatusOK, status)
+
+	status, _ = do(t, s, http.MethodGet, "/v1/kubernetes/external-clusters/"+id, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+// TestClusterOnboardingDefaults tests the objects seeded when a cluster is registered
+func TestClusterOnboardingDefaults(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	id := registerEKS(t, s)
+
+	configs := s.List("/v1/kubernetes/clusters/" + id + "/node-configurations")
+	require.Len(t, configs, 1)
+	assert.Equal(t, true, configs[0]["default"])
+
+	status, templates := do(t, s, http.MethodGet, "/v1/kubernetes/clusters/"+id+"/node-templates", nil)
+	require.Equal(t, http.StatusOK, status)
+	items := templates["items"].([]interface{})
+	require.Len(t, items, 1)
+	template := items[0].(map[string]interface{})["template"].(map[string]interface{})
+	assert.Equal(t, "default-by-castai", template["name"])
+	assert.Equal(t, true, template["isDefault"])
+
+	status, policies := do(t, s, http.MethodGet, "/v1/kubernetes/clusters/"+id+"/policies", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, policies["enabled"])
+}
+
+// TestNodeConfigurationCRUD tests the node configuration collection and set-default route
+func TestNodeConfigurationCRUD(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	id := registerEKS(t, s)
+	base := "/v1/kubernetes/clusters/" + id + "/node-configurations"
+
+	status, created := do(t, s, http.MethodPost, base, Object{
+		"name":         "custom",
+		"diskCpuRatio": 25,
+		"subnets":      []string{"subnet-1", "subnet-2"},
+	})
+	require.Equal(t, http.StatusOK, status)
+	configID := created["id"].(string)
+	assert.Equal(t, false, created["default"])
+	assert.EqualValues(t, 1, created["version"])
+
+	status, updated := do(t, s, http.MethodPut, base+"/"+configID, Object{"diskCpuRatio": 30})
+	require.Equal(t, http.StatusOK, status)
+	assert.EqualValues(t, 30, updated["diskCpuRatio"])
+	assert.EqualValues(t, 2, updated["version"])
+	assert.Equal(t, "custom", updated["name"])
+
+	status, _ = do(t, s, http.MethodPost, base+"/"+configID+"/default", nil)
+	require.Equal(t, http.StatusOK, status)
+	for _, cfg := range s.List(base) {
+		assert.Equal(t, cfg["id"] == configID, cfg["default"], "only %s should be default", configID)
+	}
+
+	status, _ = do(t, s, http.MethodDelete, base+"/"+configID, nil)
+	require.Equal(t, http.StatusOK, status)
+	_, ok := s.Get(base + "/" + configID)
+	assert.False(t, ok)
+}
+
+// TestNodeTemplatesAreKeyedByName tests that node templates use their name as identifier
+func TestNodeTemplatesAreKeyedByName(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	id := registerEKS(t, s)
+	base := "/v1/kubernetes/clusters/" + id + "/node-templates"
+
+	status, _ := do(t, s, http.MethodPost, base, Object{"configurationId": "cfg"})
+	assert.Equal(t, http.StatusBadRequest, status, "name is required")
+
+	status, created := do(t, s, http.MethodPost, base, Object{"name": "spot-pool", "shouldTaint": true})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, created["isDefault"])
+
+	status, _ = do(t, s, http.MethodPost, base, Object{"name": "spot-pool"})
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, updated := do(t, s, http.MethodPut, base+"/spot-pool", Object{"shouldTaint": false})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, updated["shouldTaint"])
+	assert.Equal(t, "spot-pool", updated["name"])
+
+	status, _ = do(t, s, http.MethodDelete, base+"/spot-pool", nil)
+	require.Equal(t, http.StatusOK, status)
+}
+
+// TestAutoscalerPoliciesUpsert tests that policy updates merge into the defaults
+func TestAutoscalerPoliciesUpsert(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	id := registerEKS(t, s)
+
+	status, policies := do(t, s, http.MethodPut, "/v1/kubernetes/clusters/"+id+"/policies", Object{
+		"enabled":       true,
+		"clusterLimits": Object{"enabled": true, "cpu": Object{"maxCores": 100}},
+	})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, policies["enabled"])
+	cpu := policies["clusterLimits"].(map[string]interface{})["cpu"].(map[string]interface{})
+	assert.EqualValues(t, 100, cpu["maxCores"])
+	assert.EqualValues(t, 1, cpu["minCores"], "unspecified fields keep their previous value")
+
+	status, _ = do(t, s, http.MethodGet, "/v1/kubernetes/clusters/unknown/policies", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+// TestWorkloadScalingPolicies tests the workload scaling policy collection
+func TestWorkloadScalingPolicies(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	id := registerEKS(t, s)
+	base := "/v1/workload-autoscaling/clusters/" + id + "/policies"
+
+	status, created := do(t, s, http.MethodPost, base, Object{"name": "prod", "applyType": "IMMEDIATE"})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, id, created["clusterId"])
+	assert.Equal(t, false, created["isDefault"])
+
+	status, list := do(t, s, http.MethodGet, base, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, list["items"], 1)
+}
+
+// TestRebalancingSchedules tests the organization-wide rebalancing schedule collection
+func TestRebalancingSchedules(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	status, created := do(t, s, http.MethodPost, "/v1/rebalancing-schedules", Object{
+		"name":     "nightly",
+		"schedule": Object{"cron": "0 3 * * *"},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	status, list := do(t, s, http.MethodGet, "/v1/rebalancing-schedules", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, list["schedules"], 1)
+
+	status, _ = do(t, s, http.MethodPatch, "/v1/rebalancing-schedules/"+created["id"].(string), Object{})
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+}
+
+// TestOrganizationAndServiceAccounts tests organization lookups and service account keys
+func TestOrganizationAndServiceAccounts(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	status, orgs := do(t, s, http.MethodGet, "/v1/organizations", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, orgs["organizations"], 1)
+
+	base := "/v1/organizations/" + DefaultOrganizationID + "/service-accounts"
+	status, sa := do(t, s, http.MethodPost, base, Object{"name": "ci"})
+	require.Equal(t, http.StatusOK, status)
+	saID := sa["id"].(string)
+
+	keys := base + "/" + saID + "/keys"
+	status, key := do(t, s, http.MethodPost, keys, Object{"name": "ci-key"})
+	require.Equal(t, http.StatusOK, status)
+	assert.NotEmpty(t, key["token"], "the key token is returned on creation")
+
+	status, key = do(t, s, http.MethodGet, keys+"/"+key["id"].(string), nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, key, "token", "the key token must not be readable afterwards")
+
+	status, group := do(t, s, http.MethodPost, "/v1/organizations/"+DefaultOrganizationID+"/groups", Object{"name": "admins"})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, DefaultOrganizationID, group["organizationId"])
+}
+
+// TestRequestsAreRecorded tests that received requests can be inspected
+func TestRequestsAreRecorded(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	registerEKS(t, s)
+
+	reqs := s.Requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, http.MethodPost, reqs[0].Method)
+	assert.Equal(t, "/v1/kubernetes/external-clusters", reqs[0].Path)
+	assert.Contains(t, reqs[0].Body, "123456789012")
+}
+
+// TestFailNext tests that injected failures are served before routing and then cleared
+func TestFailNext(t *testing.T) {
+	s := Start()
+	defer s.Close()
+
+	s.FailNext(1, http.StatusTooManyRequests)
+	s.FailNext(1, http.StatusServiceUnavailable)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/v1/organizations", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", s.Token())
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	status, _ := do(t, s, http.MethodGet, "/v1/organizations", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	status, _ = do(t, s, http.MethodGet, "/v1/organizations", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, s.Requests(), 3)
+}
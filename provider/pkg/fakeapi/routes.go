@@ -0,0 +1,411 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	organizationsPath    = "/v1/organizations"
+	externalClustersPath = "/v1/kubernetes/external-clusters"
+	clustersPath         = "/v1/kubernetes/clusters"
+)
+
+type route struct {
+	method  string
+	pattern string
+	handle  func(c *call) (int, interface{})
+}
+
+type call struct {
+	path   string
+	params map[string]string
+	query  url.Values
+	body   Object
+}
+
+// collection describes a REST collection with the usual list/create/get/update/delete routes.
+type collection struct {
+	pattern string
+	// listKey wraps list responses, e.g. {"items": [...]}.
+	listKey string
+	// itemKey, when set, additionally wraps every listed object, e.g. {"template": {...}}.
+	itemKey string
+	// idField is the field that identifies an object. Objects keyed by "id"
+	// get a server-assigned id; any other field must be supplied by the client.
+	idField string
+	// updateMethod is the HTTP method used for updates.
+	updateMethod string
+	// writeOnly lists fields returned once on create and never stored.
+	writeOnly []string
+	// onCreate fills server-assigned fields before the object is stored.
+	onCreate func(c *call, obj Object)
+}
+
+func (a *API) routes() []route {
+	collections := []collection{
+		{
+			pattern:      externalClustersPath,
+			listKey:      "items",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+			onCreate:     a.onboardCluster,
+		},
+		{
+			pattern:      clustersPath + "/{clusterId}/node-configurations",
+			listKey:      "items",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+			onCreate: func(c *call, obj Object) {
+				obj["clusterId"] = c.params["clusterId"]
+				setDefault(obj, "default", false)
+				obj["version"] = 1
+			},
+		},
+		{
+			pattern:      clustersPath + "/{clusterId}/node-templates",
+			listKey:      "items",
+			itemKey:      "template",
+			idField:      "name",
+			updateMethod: http.MethodPut,
+			onCreate: func(c *call, obj Object) {
+				setDefault(obj, "isDefault", false)
+				setDefault(obj, "isEnabled", true)
+			},
+		},
+		{
+			pattern:      clustersPath + "/{clusterId}/rebalancing-jobs",
+			listKey:      "jobs",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+			onCreate: func(c *call, obj Object) {
+				obj["clusterId"] = c.params["clusterId"]
+				setDefault(obj, "enabled", true)
+			},
+		},
+		{
+			pattern:      "/v1/rebalancing-schedules",
+			listKey:      "schedules",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+		},
+		{
+			pattern:      "/v1/workload-autoscaling/clusters/{clusterId}/policies",
+			listKey:      "items",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+			onCreate: func(c *call, obj Object) {
+				obj["clusterId"] = c.params["clusterId"]
+				setDefault(obj, "isDefault", false)
+				setDefault(obj, "isReadonly", false)
+			},
+		},
+		{
+			pattern:      organizationsPath + "/{organizationId}/users",
+			listKey:      "users",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+		},
+		{
+			pattern:      organizationsPath + "/{organizationId}/service-accounts",
+			listKey:      "serviceAccounts",
+			idField:      "id",
+			updateMethod: http.MethodPatch,
+			onCreate: func(c *call, obj Object) {
+				obj["organizationId"] = c.params["organizationId"]
+			},
+		},
+		{
+			pattern:      organizationsPath + "/{organizationId}/service-accounts/{serviceAccountId}/keys",
+			listKey:      "keys",
+			idField:      "id",
+			updateMethod: http.MethodPatch,
+			writeOnly:    []string{"token"},
+			onCreate: func(c *call, obj Object) {
+				obj["serviceAccountId"] = c.params["serviceAccountId"]
+				obj["token"] = fmt.Sprintf("castai-sak-%s", obj["id"])
+				obj["prefix"] = "castai-sak"
+				setDefault(obj, "active", true)
+			},
+		},
+		{
+			pattern:      organizationsPath + "/{organizationId}/groups",
+			listKey:      "groups",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+			onCreate: func(c *call, obj Object) {
+				obj["organizationId"] = c.params["organizationId"]
+			},
+		},
+		{
+			pattern:      organizationsPath + "/{organizationId}/role-bindings",
+			listKey:      "roleBindings",
+			idField:      "id",
+			updateMethod: http.MethodPut,
+			onCreate: func(c *call, obj Object) {
+				obj["organizationId"] = c.params["organizationId"]
+			},
+		},
+	}
+
+	routes := []route{
+		{http.MethodPost, externalClustersPath + "/{clusterId}/token", a.createClusterToken},
+		{http.MethodPost, externalClustersPath + "/{clusterId}/disconnect", a.disconnectCluster},
+		{http.MethodPost, externalClustersPath + "/{clusterId}/reconcile", a.requireCluster(func(c *call) (int, interface{}) {
+			return http.StatusOK, Object{}
+		})},
+		{http.MethodGet, externalClustersPath + "/{clusterId}/assume-role-user", a.requireCluster(func(c *call) (int, interface{}) {
+			return http.StatusOK, Object{"arn": "arn:aws:iam::000000000000:user/cast-crossrole-" + c.params["clusterId"]}
+		})},
+		{http.MethodGet, externalClustersPath + "/{clusterId}/credentials-script", a.requireCluster(func(c *call) (int, interface{}) {
+			return http.StatusOK, Object{"script": "#!/bin/bash\necho 'fake CAST AI credentials script'\n"}
+		})},
+		{http.MethodGet, clustersPath + "/{clusterId}/policies", a.getPolicies},
+		{http.MethodPut, clustersPath + "/{clusterId}/policies", a.upsertPolicies},
+		{http.MethodPost, clustersPath + "/{clusterId}/node-configurations/{id}/default", a.setDefaultNodeConfiguration},
+		{http.MethodGet, organizationsPath, func(c *call) (int, interface{}) {
+			return http.StatusOK, Object{"organizations": a.list(organizationsPath)}
+		}},
+		{http.MethodGet, organizationsPath + "/{organizationId}", func(c *call) (int, interface{}) {
+			return a.getItem(organizationsPath, c.params["organizationId"])
+		}},
+		{http.MethodGet, "/v1/me", func(c *call) (int, interface{}) {
+			return http.StatusOK, Object{
+				"id":            "00000000-0000-4000-8000-0000000000ff",
+				"email":         "fake-user@cast.ai",
+				"name":          "Fake User",
+				"organizations": a.list(organizationsPath),
+			}
+		}},
+	}
+	for _, col := range collections {
+		routes = append(routes, col.routes(a)...)
+	}
+	return routes
+}
+
+func (col collection) routes(a *API) []route {
+	item := col.pattern + "/{item}"
+	return []route{
+		{http.MethodGet, col.pattern, func(c *call) (int, interface{}) {
+			items := a.list(c.path)
+			out := make([]interface{}, 0, len(items))
+			for _, obj := range items {
+				if col.itemKey != "" {
+					out = append(out, Object{col.itemKey: obj})
+					continue
+				}
+				out = append(out, obj)
+			}
+			return http.StatusOK, Object{col.listKey: out}
+		}},
+		{http.MethodPost, col.pattern, func(c *call) (int, interface{}) {
+			obj := c.body
+			if obj == nil {
+				obj = Object{}
+			}
+			id, _ := obj[col.idField].(string)
+			switch {
+			case col.idField == "id":
+				id = a.nextID()
+				obj["id"] = id
+			case id == "":
+				return http.StatusBadRequest, Object{"message": fmt.Sprintf("%s is required", col.idField)}
+			}
+			if _, exists := a.get(c.path, id); exists {
+				return http.StatusConflict, Object{"message": fmt.Sprintf("%s %q already exists", col.idField, id)}
+			}
+			obj["createdAt"] = a.timestamp()
+			obj["updatedAt"] = obj["createdAt"]
+			if col.onCreate != nil {
+				col.onCreate(c, obj)
+			}
+			resp := clone(obj)
+			for _, f := range col.writeOnly {
+				delete(obj, f)
+			}
+			a.put(c.path, id, obj)
+			return http.StatusOK, resp
+		}},
+		{http.MethodGet, item, func(c *call) (int, interface{}) {
+			return a.getItem(splitItemPath(c.path))
+		}},
+		{col.updateMethod, item, func(c *call) (int, interface{}) {
+			base, id := splitItemPath(c.path)
+			obj, ok := a.get(base, id)
+			if !ok {
+				return notFound(c.path)
+			}
+			merge(obj, c.body)
+			obj[col.idField] = id
+			obj["updatedAt"] = a.timestamp()
+			if v, ok := obj["version"].(int); ok {
+				obj["version"] = v + 1
+			} else if v, ok := obj["version"].(float64); ok {
+				obj["version"] = v + 1
+			}
+			return http.StatusOK, clone(obj)
+		}},
+		{http.MethodDelete, item, func(c *call) (int, interface{}) {
+			base, id := splitItemPath(c.path)
+			if !a.delete(base, id) {
+				return notFound(c.path)
+			}
+			return http.StatusOK, Object{}
+		}},
+	}
+}
+
+// onboardCluster fills the fields CAST AI assigns to a newly registered
+// cluster and seeds the defaults created during onboarding: a default node
+// configuration, the "default-by-castai" node template and the autoscaler policies.
+func (a *API) onboardCluster(c *call, obj Object) {
+	id := obj["id"].(string)
+	setDefault(obj, "organizationId", DefaultOrganizationID)
+	obj["credentialsId"] = a.nextID()
+	obj["status"] = "ready"
+	obj["agentStatus"] = "online"
+	for _, provider := range []string{"eks", "gke", "aks"} {
+		if _, ok := obj[provider]; ok {
+			obj["providerType"] = provider
+		}
+	}
+
+	nodeConfigs := clustersPath + "/" + id + "/node-configurations"
+	defaultConfigID := a.nextID()
+	a.put(nodeConfigs, defaultConfigID, Object{
+		"id":        defaultConfigID,
+		"clusterId": id,
+		"name":      "default",
+		"default":   true,
+		"version":   1,
+		"createdAt": obj["createdAt"],
+		"updatedAt": obj["createdAt"],
+	})
+	a.put(clustersPath+"/"+id+"/node-templates", "default-by-castai", Object{
+		"name":                   "default-by-castai",
+		"isDefault":              true,
+		"isEnabled":              true,
+		"configurationId":        defaultConfigID,
+		"shouldTaint":            false,
+		"constraints":            Object{"spot": false, "onDemand": true},
+		"customInstancesEnabled": false,
+	})
+	a.put(clustersPath+"/"+id, "policies", defaultPolicies())
+}
+
+func defaultPolicies() Object {
+	return Object{
+		"enabled":                             false,
+		"isScopedMode":                        false,
+		"nodeTemplatesPartialMatchingEnabled": false,
+		"unschedulablePods": Object{
+			"enabled":   false,
+			"podPinner": Object{"enabled": true},
+		},
+		"clusterLimits": Object{
+			"enabled": false,
+			"cpu":     Object{"minCores": 1, "maxCores": 20},
+		},
+		"nodeDownscaler": Object{
+			"enabled":    false,
+			"emptyNodes": Object{"enabled": false, "delaySeconds": 0},
+			"evictor": Object{
+				"enabled":                false,
+				"dryRun":                 false,
+				"aggressiveMode":         false,
+				"scopedMode":             false,
+				"cycleInterval":          "1m",
+				"nodeGracePeriodMinutes": 5,
+			},
+		},
+		"spotInstances": Object{
+			"enabled":     false,
+			"spotBackups": Object{"enabled": false, "spotBackupRestoreRateSeconds": 1800},
+		},
+	}
+}
+
+func (a *API) requireCluster(next func(c *call) (int, interface{})) func(c *call) (int, interface{}) {
+	return func(c *call) (int, interface{}) {
+		if _, ok := a.get(externalClustersPath, c.params["clusterId"]); !ok {
+			return notFound(externalClustersPath + "/" + c.params["clusterId"])
+		}
+		return next(c)
+	}
+}
+
+func (a *API) createClusterToken(c *call) (int, interface{}) {
+	cluster, ok := a.get(externalClustersPath, c.params["clusterId"])
+	if !ok {
+		return notFound(externalClustersPath + "/" + c.params["clusterId"])
+	}
+	token := fmt.Sprintf("castai-cluster-token-%s", a.nextID())
+	cluster["clusterToken"] = token
+	return http.StatusOK, Object{"token": token}
+}
+
+func (a *API) disconnectCluster(c *call) (int, interface{}) {
+	cluster, ok := a.get(externalClustersPath, c.params["clusterId"])
+	if !ok {
+		return notFound(externalClustersPath + "/" + c.params["clusterId"])
+	}
+	cluster["status"] = "disconnected"
+	cluster["agentStatus"] = "disconnected"
+	cluster["updatedAt"] = a.timestamp()
+	return http.StatusOK, clone(cluster)
+}
+
+func (a *API) getPolicies(c *call) (int, interface{}) {
+	id := c.params["clusterId"]
+	if _, ok := a.get(externalClustersPath, id); !ok {
+		return notFound(externalClustersPath + "/" + id)
+	}
+	return a.getItem(clustersPath+"/"+id, "policies")
+}
+
+func (a *API) upsertPolicies(c *call) (int, interface{}) {
+	id := c.params["clusterId"]
+	if _, ok := a.get(externalClustersPath, id); !ok {
+		return notFound(externalClustersPath + "/" + id)
+	}
+	policies, ok := a.get(clustersPath+"/"+id, "policies")
+	if !ok {
+		policies = defaultPolicies()
+		a.put(clustersPath+"/"+id, "policies", policies)
+	}
+	merge(policies, c.body)
+	return http.StatusOK, clone(policies)
+}
+
+func (a *API) setDefaultNodeConfiguration(c *call) (int, interface{}) {
+	base := clustersPath + "/" + c.params["clusterId"] + "/node-configurations"
+	target, ok := a.get(base, c.params["id"])
+	if !ok {
+		return notFound(base + "/" + c.params["id"])
+	}
+	for _, obj := range a.tables[base].items {
+		obj["default"] = false
+	}
+	target["default"] = true
+	return http.StatusOK, clone(target)
+}
+
+func (a *API) getItem(base, id string) (int, interface{}) {
+	obj, ok := a.get(base, id)
+	if !ok {
+		return notFound(base + "/" + id)
+	}
+	return http.StatusOK, clone(obj)
+}
+
+func notFound(path string) (int, interface{}) {
+	return http.StatusNotFound, Object{"message": fmt.Sprintf("%s not found", path)}
+}
+
+func setDefault(obj Object, key string, value interface{}) {
+	if _, ok := obj[key]; !ok {
+		obj[key] = value
+	}
+}
@@ -0,0 +1,87 @@
+package importgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// object is a JSON object returned by the API.
+type object map[string]any
+
+// str returns the string field key, or "" if it is not a string.
+func (o object) str(key string) string {
+	s, _ := o[key].(string)
+	return s
+}
+
+// obj returns the object field key, or nil if it is not an object.
+func (o object) obj(key string) object {
+	m, _ := o[key].(map[string]any)
+	return m
+}
+
+// client reads from the CAST AI API.
+type client struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+func newClient(cfg Config) *client {
+	c := &client{
+		url:   strings.TrimSuffix(cfg.APIURL, "/"),
+		token: cfg.APIToken,
+		http:  cfg.HTTPClient,
+	}
+	if c.http == nil {
+		c.http = http.DefaultClient
+	}
+	return c
+}
+
+// get decodes the response to GET path into out. It reports false, and no
+// error, if the API responds 404 Not Found.
+func (c *client) get(ctx context.Context, path string, out any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("X-API-Key", c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("GET %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("GET %s: decoding response: %w", path, err)
+	}
+	return true, nil
+}
+
+// list returns the objects of the collection at path, wrapped in the
+// response under key. A collection that is not found is empty.
+func (c *client) list(ctx context.Context, path, key string) ([]object, error) {
+	var resp map[string]json.RawMessage
+	if found, err := c.get(ctx, path, &resp); err != nil || !found || resp[key] == nil {
+		return nil, err
+	}
+	var items []object
+	if err := json.Unmarshal(resp[key], &items); err != nil {
+		return nil, fmt.Errorf("GET %s: decoding %s: %w", path, key, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,317 @@
+// Package importgen generates `pulumi import --file` input for the objects of
+// an existing CAST AI organization.
+//
+// Generate walks the organization through the CAST AI API: the organization
+// members, groups, service accounts, role bindings and rebalancing schedules,
+// then every connected cluster with its autoscaler policies, node
+// configurations, node templates, workload scaling policies and rebalancing
+// jobs. Cluster-scoped resources are emitted as children of their cluster, so
+// `pulumi import` generates them with the `parent` resource option.
+//
+// Import IDs use the formats the bridged terraform-provider-castai importers
+// accept. Objects the provider cannot import, such as service account keys
+// whose token is only returned on create, are not emitted.
+package importgen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Type tokens of the resources Generate emits.
+const (
+	EksClusterType            = "castai:aws:EksCluster"
+	GkeClusterType            = "castai:gcp:GkeCluster"
+	AksClusterType            = "castai:azure:AksCluster"
+	AutoscalerType            = "castai:autoscaling:Autoscaler"
+	NodeConfigurationType     = "castai:config/node:NodeConfiguration"
+	NodeTemplateType          = "castai:config/node:NodeTemplate"
+	WorkloadScalingPolicyType = "castai:workload:WorkloadScalingPolicy"
+	RebalancingScheduleType   = "castai:rebalancing:RebalancingSchedule"
+	RebalancingJobType        = "castai:rebalancing:RebalancingJob"
+	OrganizationMembersType   = "castai:organization:OrganizationMembers"
+	OrganizationGroupType     = "castai:organization:OrganizationGroup"
+	ServiceAccountType        = "castai:organization:ServiceAccount"
+	RoleBindingsType          = "castai:iam:RoleBindings"
+)
+
+// clusterTypes maps the providerType of a cluster to its resource type.
+var clusterTypes = map[string]string{
+	"eks": EksClusterType,
+	"gke": GkeClusterType,
+	"aks": AksClusterType,
+}
+
+// Config configures Generate.
+type Config struct {
+	// APIURL is the base URL of the CAST AI API.
+	APIURL string
+	// APIToken is the API key sent in the X-API-Key header.
+	APIToken string
+	// OrganizationID selects the organization to walk. If it is empty, the
+	// API key must have access to exactly one organization.
+	OrganizationID string
+	// HTTPClient sends the API requests. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// File is the JSON document read by `pulumi import --file`.
+type File struct {
+	Resources []Resource `json:"resources"`
+}
+
+// Resource is one resource of an import file.
+type Resource struct {
+	// Type is the Pulumi type token of the resource.
+	Type string `json:"type"`
+	// Name is the unique name of the resource in the file. It is used as the
+	// variable name in the generated code and by other resources' Parent.
+	Name string `json:"name"`
+	// ID is the import ID passed to the provider.
+	ID string `json:"id"`
+	// Parent is the Name of the parent resource, if any.
+	Parent string `json:"parent,omitempty"`
+	// LogicalName is the Pulumi resource name, if it differs from Name.
+	LogicalName string `json:"logicalName,omitempty"`
+}
+
+// Result is the output of Generate.
+type Result struct {
+	File File
+	// Warnings describe objects that were found but not emitted.
+	Warnings []string
+}
+
+// Generate walks the organization selected by cfg and returns the import file
+// for every object it supports.
+func Generate(ctx context.Context, cfg Config) (*Result, error) {
+	g := &generator{
+		api:   newClient(cfg),
+		names: map[string]bool{},
+	}
+	orgID, err := g.organization(ctx, cfg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.walkOrganization(ctx, orgID); err != nil {
+		return nil, err
+	}
+	schedules, err := g.walkRebalancingSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.walkClusters(ctx, schedules); err != nil {
+		return nil, err
+	}
+	return &Result{File: File{Resources: g.resources}, Warnings: g.warnings}, nil
+}
+
+type generator struct {
+	api       *client
+	resources []Resource
+	warnings  []string
+	names     map[string]bool
+}
+
+// organization returns the id of the organization to walk.
+func (g *generator) organization(ctx context.Context, id string) (string, error) {
+	if id != "" {
+		return id, nil
+	}
+	var resp struct {
+		Organizations []object `json:"organizations"`
+	}
+	if _, err := g.api.get(ctx, "/v1/organizations", &resp); err != nil {
+		return "", err
+	}
+	switch len(resp.Organizations) {
+	case 0:
+		return "", fmt.Errorf("the API key has no access to any organization")
+	case 1:
+		return resp.Organizations[0].str("id"), nil
+	}
+	return "", fmt.Errorf("the API key has access to %d organizations, select one with the organization id", len(resp.Organizations))
+}
+
+func (g *generator) walkOrganization(ctx context.Context, orgID string) error {
+	var org object
+	found, err := g.api.get(ctx, "/v1/organizations/"+orgID, &org)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("organization %s not found", orgID)
+	}
+	orgName := org.str("name")
+	if orgName == "" {
+		orgName = "organization"
+	}
+	g.add(Resource{Type: OrganizationMembersType, ID: orgID}, orgName, "members")
+
+	base := "/v1/organizations/" + orgID
+	groups, err := g.api.list(ctx, base+"/groups", "groups")
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		g.add(Resource{Type: OrganizationGroupType, ID: orgID + "/" + group.str("id")}, group.str("name"))
+	}
+	accounts, err := g.api.list(ctx, base+"/service-accounts", "serviceAccounts")
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		g.add(Resource{Type: ServiceAccountType, ID: orgID + "/" + account.str("id")}, account.str("name"))
+	}
+	bindings, err := g.api.list(ctx, base+"/role-bindings", "roleBindings")
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		g.add(Resource{Type: RoleBindingsType, ID: orgID + "/" + binding.str("id")}, binding.str("name"))
+	}
+	return nil
+}
+
+// walkRebalancingSchedules emits the rebalancing schedules and returns their
+// names by id, which rebalancing job import IDs refer to.
+func (g *generator) walkRebalancingSchedules(ctx context.Context) (map[string]string, error) {
+	schedules, err := g.api.list(ctx, "/v1/rebalancing-schedules", "schedules")
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, schedule := range schedules {
+		names[schedule.str("id")] = schedule.str("name")
+		g.add(Resource{Type: RebalancingScheduleType, ID: schedule.str("id")}, schedule.str("name"))
+	}
+	return names, nil
+}
+
+func (g *generator) walkClusters(ctx context.Context, schedules map[string]string) error {
+	clusters, err := g.api.list(ctx, "/v1/kubernetes/external-clusters", "items")
+	if err != nil {
+		return err
+	}
+	for _, cluster := range clusters {
+		id, name := cluster.str("id"), cluster.str("name")
+		typ, ok := clusterTypes[cluster.str("providerType")]
+		if !ok {
+			g.warn("cluster %s (%s): provider type %q is not supported", name, id, cluster.str("providerType"))
+			continue
+		}
+		parent := g.add(Resource{Type: typ, ID: id}, name)
+		if err := g.walkCluster(ctx, id, parent, schedules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkCluster emits the cluster-scoped resources of a cluster as children of
+// the cluster resource named parent. Their logical names are prefixed with
+// the cluster's, since Pulumi does not scope names by parent.
+func (g *generator) walkCluster(ctx context.Context, clusterID, parent string, schedules map[string]string) error {
+	base := "/v1/kubernetes/clusters/" + clusterID
+
+	found, err := g.api.get(ctx, base+"/policies", &object{})
+	if err != nil {
+		return err
+	}
+	if found {
+		g.add(Resource{Type: AutoscalerType, ID: clusterID, Parent: parent}, parent, "autoscaler")
+	}
+
+	configs, err := g.api.list(ctx, base+"/node-configurations", "items")
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		g.add(Resource{Type: NodeConfigurationType, ID: clusterID + "/" + config.str("id"), Parent: parent}, parent, config.str("name"))
+	}
+
+	templates, err := g.api.list(ctx, base+"/node-templates", "items")
+	if err != nil {
+		return err
+	}
+	for _, item := range templates {
+		template := item.obj("template")
+		g.add(Resource{Type: NodeTemplateType, ID: clusterID + "/" + template.str("name"), Parent: parent}, parent, template.str("name"))
+	}
+
+	policies, err := g.api.list(ctx, "/v1/workload-autoscaling/clusters/"+clusterID+"/policies", "items")
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		g.add(Resource{Type: WorkloadScalingPolicyType, ID: clusterID + "/" + policy.str("id"), Parent: parent}, parent, policy.str("name"))
+	}
+
+	jobs, err := g.api.list(ctx, base+"/rebalancing-jobs", "jobs")
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		schedule, ok := schedules[job.str("rebalancingScheduleId")]
+		if !ok {
+			g.warn("rebalancing job %s of cluster %s: schedule %s not found", job.str("id"), clusterID, job.str("rebalancingScheduleId"))
+			continue
+		}
+		g.add(Resource{Type: RebalancingJobType, ID: clusterID + "/" + schedule, Parent: parent}, parent, schedule)
+	}
+	return nil
+}
+
+// add appends r under a logical name made of parts, made unique within the
+// file, and returns its Name.
+func (g *generator) add(r Resource, parts ...string) string {
+	logical := logicalName(parts...)
+	if logical == "" {
+		logical = strings.ToLower(r.Type[strings.LastIndex(r.Type, ":")+1:])
+	}
+	unique := logical
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", logical, i)
+	}
+	g.names[unique] = true
+
+	r.Name = identifier(unique)
+	if r.Name != unique {
+		r.LogicalName = unique
+	}
+	g.resources = append(g.resources, r)
+	return r.Name
+}
+
+func (g *generator) warn(format string, args ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+var (
+	nonName       = regexp.MustCompile(`[^a-z0-9]+`)
+	nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// logicalName joins parts into a lowercase, dash-separated resource name.
+func logicalName(parts ...string) string {
+	var words []string
+	for _, part := range parts {
+		if word := strings.Trim(nonName.ReplaceAllString(strings.ToLower(part), "-"), "-"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, "-")
+}
+
+// identifier turns a logical name into a name usable as a variable in every
+// language `pulumi import` generates code for.
+func identifier(name string) string {
+	id := nonIdentifier.ReplaceAllString(name, "_")
+	if id != "" && id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+	return id
+}
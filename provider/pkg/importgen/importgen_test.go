@@ -0,0 +1,177 @@
+package importgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/castai/pulumi-castai/provider/pkg/fakeapi"
+)
+
+// post creates an object in the fake API and returns it.
+func post(t *testing.T, s *fakeapi.Server, path string, body fakeapi.Object) fakeapi.Object {
+	t.Helper()
+
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, s.URL+path, bytes.NewReader(b))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", s.Token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out fakeapi.Object
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func generate(t *testing.T, s *fakeapi.Server, orgID string) *Result {
+	t.Helper()
+
+	result, err := Generate(context.Background(), Config{
+		APIURL:         s.URL,
+		APIToken:       s.Token(),
+		OrganizationID: orgID,
+	})
+	require.NoError(t, err)
+	return result
+}
+
+// TestGenerate tests that every object of an organization is emitted with its
+// import ID, parent and logical name
+func TestGenerate(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+
+	org := "/v1/organizations/" + fakeapi.DefaultOrganizationID
+	group := post(t, s, org+"/groups", fakeapi.Object{"name": "Platform Team"})
+	account := post(t, s, org+"/service-accounts", fakeapi.Object{"name": "ci"})
+	binding := post(t, s, org+"/role-bindings", fakeapi.Object{"name": "platform-admins"})
+	schedule := post(t, s, "/v1/rebalancing-schedules", fakeapi.Object{"name": "nightly"})
+
+	eks := post(t, s, "/v1/kubernetes/external-clusters", fakeapi.Object{
+		"name": "prod-eks",
+		"eks":  fakeapi.Object{"accountId": "123456789012", "region": "us-west-2", "clusterName": "prod-eks"},
+	})["id"].(string)
+	gke := post(t, s, "/v1/kubernetes/external-clusters", fakeapi.Object{
+		"name": "staging_gke",
+		"gke":  fakeapi.Object{"projectId": "acme", "location": "us-central1", "clusterName": "staging"},
+	})["id"].(string)
+
+	cluster := "/v1/kubernetes/clusters/" + eks
+	config := post(t, s, cluster+"/node-configurations", fakeapi.Object{"name": "gpu"})
+	post(t, s, cluster+"/node-templates", fakeapi.Object{"name": "spot-workers"})
+	policy := post(t, s, "/v1/workload-autoscaling/clusters/"+eks+"/policies", fakeapi.Object{"name": "resiliency"})
+	post(t, s, cluster+"/rebalancing-jobs", fakeapi.Object{"rebalancingScheduleId": schedule["id"]})
+
+	defaultConfig := s.List(cluster + "/node-configurations")[0]["id"].(string)
+	gkeConfig := s.List("/v1/kubernetes/clusters/" + gke + "/node-configurations")[0]["id"].(string)
+
+	result := generate(t, s, "")
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, []Resource{
+		{Type: OrganizationMembersType, Name: "fake_organization_members", ID: fakeapi.DefaultOrganizationID, LogicalName: "fake-organization-members"},
+		{Type: OrganizationGroupType, Name: "platform_team", ID: fakeapi.DefaultOrganizationID + "/" + group["id"].(string), LogicalName: "platform-team"},
+		{Type: ServiceAccountType, Name: "ci", ID: fakeapi.DefaultOrganizationID + "/" + account["id"].(string)},
+		{Type: RoleBindingsType, Name: "platform_admins", ID: fakeapi.DefaultOrganizationID + "/" + binding["id"].(string), LogicalName: "platform-admins"},
+		{Type: RebalancingScheduleType, Name: "nightly", ID: schedule["id"].(string)},
+
+		{Type: EksClusterType, Name: "prod_eks", ID: eks, LogicalName: "prod-eks"},
+		{Type: AutoscalerType, Name: "prod_eks_autoscaler", ID: eks, Parent: "prod_eks", LogicalName: "prod-eks-autoscaler"},
+		{Type: NodeConfigurationType, Name: "prod_eks_default", ID: eks + "/" + defaultConfig, Parent: "prod_eks", LogicalName: "prod-eks-default"},
+		{Type: NodeConfigurationType, Name: "prod_eks_gpu", ID: eks + "/" + config["id"].(string), Parent: "prod_eks", LogicalName: "prod-eks-gpu"},
+		{Type: NodeTemplateType, Name: "prod_eks_default_by_castai", ID: eks + "/default-by-castai", Parent: "prod_eks", LogicalName: "prod-eks-default-by-castai"},
+		{Type: NodeTemplateType, Name: "prod_eks_spot_workers", ID: eks + "/spot-workers", Parent: "prod_eks", LogicalName: "prod-eks-spot-workers"},
+		{Type: WorkloadScalingPolicyType, Name: "prod_eks_resiliency", ID: eks + "/" + policy["id"].(string), Parent: "prod_eks", LogicalName: "prod-eks-resiliency"},
+		{Type: RebalancingJobType, Name: "prod_eks_nightly", ID: eks + "/nightly", Parent: "prod_eks", LogicalName: "prod-eks-nightly"},
+
+		{Type: GkeClusterType, Name: "staging_gke", ID: gke, LogicalName: "staging-gke"},
+		{Type: AutoscalerType, Name: "staging_gke_autoscaler", ID: gke, Parent: "staging_gke", LogicalName: "staging-gke-autoscaler"},
+		{Type: NodeConfigurationType, Name: "staging_gke_default", ID: gke + "/" + gkeConfig, Parent: "staging_gke", LogicalName: "staging-gke-default"},
+		{Type: NodeTemplateType, Name: "staging_gke_default_by_castai", ID: gke + "/default-by-castai", Parent: "staging_gke", LogicalName: "staging-gke-default-by-castai"},
+	}, result.File.Resources)
+
+	// The file round-trips through the JSON `pulumi import --file` reads.
+	b, err := json.Marshal(result.File)
+	require.NoError(t, err)
+	var decoded struct {
+		Resources []map[string]string `json:"resources"`
+	}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, map[string]string{"type": ServiceAccountType, "name": "ci", "id": fakeapi.DefaultOrganizationID + "/" + account["id"].(string)}, decoded.Resources[2])
+	assert.Equal(t, "prod_eks", decoded.Resources[6]["parent"])
+}
+
+// TestGenerateUniqueNames tests that objects with the same logical name get
+// distinct names
+func TestGenerateUniqueNames(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+
+	for range 2 {
+		post(t, s, "/v1/rebalancing-schedules", fakeapi.Object{"name": "nightly"})
+	}
+	post(t, s, "/v1/rebalancing-schedules", fakeapi.Object{"name": "1 hour"})
+
+	var names []string
+	for _, r := range generate(t, s, "").File.Resources {
+		if r.Type == RebalancingScheduleType {
+			names = append(names, r.Name)
+		}
+	}
+	assert.Equal(t, []string{"nightly", "nightly_2", "_1_hour"}, names)
+}
+
+// TestGenerateWarnings tests that objects that cannot be imported are reported
+// instead of emitted
+func TestGenerateWarnings(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+
+	cluster := post(t, s, "/v1/kubernetes/external-clusters", fakeapi.Object{"name": "on-prem"})["id"].(string)
+	eks := post(t, s, "/v1/kubernetes/external-clusters", fakeapi.Object{"name": "eks", "eks": fakeapi.Object{}})["id"].(string)
+	post(t, s, "/v1/kubernetes/clusters/"+eks+"/rebalancing-jobs", fakeapi.Object{"rebalancingScheduleId": "deleted"})
+
+	result := generate(t, s, "")
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], cluster)
+	assert.Contains(t, result.Warnings[1], "schedule deleted not found")
+	for _, r := range result.File.Resources {
+		assert.NotEqual(t, cluster, r.ID)
+		assert.NotEqual(t, RebalancingJobType, r.Type)
+	}
+}
+
+// TestGenerateOrganization tests how the organization to walk is selected
+func TestGenerateOrganization(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+
+	_, err := Generate(context.Background(), Config{APIURL: s.URL, APIToken: s.Token(), OrganizationID: "missing"})
+	assert.ErrorContains(t, err, "organization missing not found")
+
+	s.Put("/v1/organizations/00000000-0000-4000-8000-000000000002", fakeapi.Object{"id": "00000000-0000-4000-8000-000000000002", "name": "other"})
+	_, err = Generate(context.Background(), Config{APIURL: s.URL, APIToken: s.Token()})
+	assert.ErrorContains(t, err, "access to 2 organizations")
+
+	result := generate(t, s, "00000000-0000-4000-8000-000000000002")
+	assert.Equal(t, "other_members", result.File.Resources[0].Name)
+}
+
+// TestGenerateAPIError tests that API errors abort the walk
+func TestGenerateAPIError(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+
+	_, err := Generate(context.Background(), Config{APIURL: s.URL, APIToken: "wrong"})
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,166 @@
+// Package instancecatalog checks the constraints of node templates against an
+// embedded catalog of the instance families of each cloud.
+//
+// The catalog is a snapshot, identified by its version, of instance types
+// CAST AI can provision. It is used to catch constraints that would silently
+// match nothing: misspelled instance families, families of another cloud,
+// and CPU, memory, architecture and GPU constraints no instance type
+// satisfies. Unless the catalog of a cloud is complete, only families of
+// another cloud fail; the other findings are warnings, since the family or
+// instance type may just be missing from the catalog.
+package instancecatalog
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Cloud is a cloud with a catalog.
+type Cloud string
+
+const (
+	AWS Cloud = "aws"
+	GCP Cloud = "gcp"
+)
+
+// Clouds are the clouds with a catalog.
+var Clouds = []Cloud{AWS, GCP}
+
+// String returns the name of the cloud as used in messages.
+func (c Cloud) String() string {
+	switch c {
+	case AWS:
+		return "AWS"
+	case GCP:
+		return "GCP"
+	}
+	return string(c)
+}
+
+// Catalog is the instance catalog of a cloud.
+type Catalog struct {
+	Cloud Cloud `json:"cloud"`
+	// Version identifies the snapshot, e.g. "2026.10".
+	Version string `json:"version"`
+	// Complete is set when the catalog has every family and instance type of
+	// the cloud, so that unknown families and constraints no instance type
+	// satisfies are errors rather than warnings.
+	Complete bool     `json:"complete,omitempty"`
+	Families []Family `json:"families"`
+}
+
+// Family is an instance family, the value of the instanceFamilies
+// constraints.
+type Family struct {
+	Name string `json:"name"`
+	// Architecture is "amd64" or "arm64".
+	Architecture string `json:"architecture"`
+	// CPUManufacturer is one of the cpuManufacturers values: AMD, AMPERE,
+	// AWS or INTEL.
+	CPUManufacturer string `json:"cpuManufacturer"`
+	// GPUManufacturer and GPUName are set for families with GPUs.
+	GPUManufacturer string         `json:"gpuManufacturer,omitempty"`
+	GPUName         string         `json:"gpuName,omitempty"`
+	Types           []InstanceType `json:"types"`
+}
+
+// InstanceType is an instance type of a family.
+type InstanceType struct {
+	Name string `json:"name"`
+	CPU  int    `json:"cpu"`
+	// Memory is in MiB, like the minMemory and maxMemory constraints.
+	Memory    int  `json:"memory"`
+	GPUs      int  `json:"gpus,omitempty"`
+	Burstable bool `json:"burstable,omitempty"`
+}
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+var catalogs = map[Cloud]*Catalog{}
+
+func init() {
+	for _, cloud := range Clouds {
+		data, err := catalogFiles.ReadFile("catalogs/" + string(cloud) + ".json")
+		if err != nil {
+			panic(err)
+		}
+		var c Catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("decoding the %s instance catalog: %v", cloud, err))
+		}
+		sort.Slice(c.Families, func(i, j int) bool { return c.Families[i].Name < c.Families[j].Name })
+		catalogs[cloud] = &c
+	}
+}
+
+// Get returns the catalog of a cloud, or nil if there is none.
+func Get(cloud Cloud) *Catalog {
+	return catalogs[cloud]
+}
+
+// Family returns the family with a name, or nil.
+func (c *Catalog) Family(name string) *Family {
+	i := sort.Search(len(c.Families), func(i int) bool { return c.Families[i].Name >= name })
+	if i < len(c.Families) && c.Families[i].Name == name {
+		return &c.Families[i]
+	}
+	return nil
+}
+
+// suggest returns the family name is most likely a typo of: one that differs
+// by a single edit or swap of adjacent characters, without changing the
+// generation digits. A name with other digits, such as m9g next to m7g, is
+// more likely a family newer than the catalog.
+func (c *Catalog) suggest(name string) (string, bool) {
+	best := ""
+	for _, f := range c.Families {
+		if digits(f.Name) != digits(name) || distance(name, f.Name) > 1 {
+			continue
+		}
+		// Prefer a swap, e.g. m5da to m5ad rather than m5a.
+		if best == "" || (len(f.Name) == len(name) && len(best) != len(name)) {
+			best = f.Name
+		}
+	}
+	return best, best != ""
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// distance is the optimal string alignment distance of a and b: the number
+// of insertions, deletions, substitutions and swaps of adjacent characters
+// turning one into the other.
+func distance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
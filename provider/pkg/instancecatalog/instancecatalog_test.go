@@ -0,0 +1,192 @@
+package instancecatalog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func constraints(t *testing.T, doc string) *Constraints {
+	t.Helper()
+	var inputs map[string]any
+	require.NoError(t, json.Unmarshal([]byte(doc), &inputs))
+	c, err := ParseConstraints(inputs)
+	require.NoError(t, err)
+	return c
+}
+
+// complete marks the catalog of a cloud complete for the rest of the test.
+func complete(t *testing.T, cloud Cloud) {
+	t.Helper()
+	c := Get(cloud)
+	c.Complete = true
+	t.Cleanup(func() { c.Complete = false })
+}
+
+func messages(findings []Finding) (errs, warnings []string) {
+	for _, f := range findings {
+		if f.Warning {
+			warnings = append(warnings, f.String())
+		} else {
+			errs = append(errs, f.String())
+		}
+	}
+	return errs, warnings
+}
+
+// TestCatalogs tests that the embedded catalogs are loaded and well formed
+func TestCatalogs(t *testing.T) {
+	for _, cloud := range Clouds {
+		c := Get(cloud)
+		require.NotNil(t, c, cloud)
+		assert.Equal(t, cloud, c.Cloud)
+		assert.NotEmpty(t, c.Version)
+		for _, f := range c.Families {
+			assert.Contains(t, []string{"amd64", "arm64"}, f.Architecture, f.Name)
+			assert.Contains(t, []string{"AMD", "AMPERE", "AWS", "INTEL"}, f.CPUManufacturer, f.Name)
+			assert.NotEmpty(t, f.Types, f.Name)
+			for _, typ := range f.Types {
+				assert.Positive(t, typ.CPU, typ.Name)
+				assert.Positive(t, typ.Memory, typ.Name)
+				assert.Equal(t, typ.GPUs > 0, f.GPUName != "", typ.Name)
+			}
+		}
+	}
+	assert.NotNil(t, Get(AWS).Family("m5"))
+	assert.Nil(t, Get(AWS).Family("n2"))
+	assert.NotNil(t, Get(GCP).Family("n2"))
+}
+
+// TestValidateValid tests that satisfiable constraints have no findings
+func TestValidateValid(t *testing.T) {
+	for _, doc := range []string{
+		`{}`,
+		`{"minCpu": 4, "maxCpu": 16, "instanceFamilies": {"includes": ["m5", "c6g"]}, "architectures": ["amd64", "arm64"]}`,
+		`{"isGpuOnly": true, "gpu": {"manufacturers": ["NVIDIA"], "includeNames": ["nvidia-l4"]}, "gcp": {}}`,
+		`{"burstableInstances": "enabled", "maxCpu": 2, "aws": {}}`,
+	} {
+		assert.Empty(t, Validate(constraints(t, doc)), doc)
+	}
+}
+
+// TestValidateFamilies tests the findings on misspelled, foreign and unknown
+// instance families
+func TestValidateFamilies(t *testing.T) {
+	doc := `{
+		"aws": {"enableSpot": true},
+		"instanceFamilies": {"includes": ["m5da", "n2"], "excludes": ["x9z"]},
+		"customPriorities": [{"instanceFamilies": ["c6i", "c6ii"]}]
+	}`
+	errs, warnings := messages(Validate(constraints(t, doc)))
+	assert.Equal(t, []string{
+		"constraints.instanceFamilies.includes[1]: n2 is a GCP instance family, but the template is for AWS",
+	}, errs)
+	assert.Equal(t, []string{
+		"constraints.instanceFamilies.includes[0]: unknown AWS instance family m5da, did you mean m5ad?",
+		"constraints.instanceFamilies.excludes[0]: x9z is not in the AWS instance catalog 2026.10",
+		"constraints.customPriorities[0].instanceFamilies[1]: unknown AWS instance family c6ii, did you mean c6i?",
+	}, warnings)
+
+	// Without an aws or gcp block, the cloud is the one of the known families.
+	_, warnings = messages(Validate(constraints(t, `{"instanceFamilies": {"includes": ["n2", "n2dd"]}}`)))
+	assert.Equal(t, []string{
+		"constraints.instanceFamilies.includes[1]: unknown GCP instance family n2dd, did you mean n2d?",
+	}, warnings)
+
+	// A complete catalog fails the suggestions.
+	complete(t, AWS)
+	errs, warnings = messages(Validate(constraints(t, doc)))
+	assert.Equal(t, []string{
+		"constraints.instanceFamilies.includes[0]: unknown AWS instance family m5da, did you mean m5ad?",
+		"constraints.instanceFamilies.includes[1]: n2 is a GCP instance family, but the template is for AWS",
+		"constraints.customPriorities[0].instanceFamilies[1]: unknown AWS instance family c6ii, did you mean c6i?",
+	}, errs)
+	assert.Equal(t, []string{
+		"constraints.instanceFamilies.excludes[0]: x9z is not in the AWS instance catalog 2026.10",
+	}, warnings)
+
+	// Families unknown to every catalog, e.g. Azure ones, only warn.
+	errs, warnings = messages(Validate(constraints(t, `{"instanceFamilies": {"includes": ["Standard_D4s_v5"]}}`)))
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"constraints.instanceFamilies.includes[0]: Standard_D4s_v5 is not in the instance catalogs"}, warnings)
+}
+
+// TestValidateMissingFamilies tests that families and instance types missing
+// from the catalogs do not fail valid constraints
+func TestValidateMissingFamilies(t *testing.T) {
+	for _, doc := range []string{
+		`{"aws": {}, "instanceFamilies": {"includes": ["r5d", "c5n", "m6id", "c6gn", "r6a", "c5d", "m5n", "r7iz"]}, "architectures": ["amd64", "arm64"]}`,
+		`{"gcp": {}, "instanceFamilies": {"includes": ["c3d", "n4d", "a3", "c4"]}}`,
+		`{"aws": {}, "minCpu": 256}`,
+		`{"gcp": {}, "minMemory": 2097152}`,
+	} {
+		errs, warnings := messages(Validate(constraints(t, doc)))
+		assert.Empty(t, errs, doc)
+		assert.NotEmpty(t, warnings, doc)
+	}
+}
+
+// TestValidateConflicts tests the findings on constraints that contradict
+// each other
+func TestValidateConflicts(t *testing.T) {
+	errs, warnings := messages(Validate(constraints(t, `{
+		"minCpu": 16, "maxCpu": 8,
+		"minMemory": 2048, "maxMemory": 1024,
+		"isGpuOnly": true,
+		"gpu": {"minCount": 2, "maxCount": 0, "includeNames": ["T4"], "excludeNames": ["T4"]},
+		"instanceFamilies": {"includes": ["m5"], "excludes": ["m5"]},
+		"architectures": ["amd64"], "architecturePriorities": ["arm64"],
+		"spot": false, "onDemand": false
+	}`)))
+	assert.Empty(t, warnings)
+	assert.Equal(t, []string{
+		"constraints.minCpu: 16 is greater than maxCpu 8",
+		"constraints.minMemory: 2048 is greater than maxMemory 1024",
+		"constraints.gpu.minCount: 2 is greater than gpu.maxCount 0",
+		"constraints.gpu.maxCount: is 0 while isGpuOnly is set",
+		"constraints.gpu.includeNames[0]: T4 is also excluded",
+		"constraints.instanceFamilies.includes[0]: m5 is also excluded",
+		"constraints.architecturePriorities[0]: arm64 is not one of the architectures amd64",
+		"constraints.spot: spot and onDemand are both false, so no instance can be created",
+	}, errs)
+}
+
+// TestValidateUnsatisfiable tests that the constraint leaving no instance type
+// is reported
+func TestValidateUnsatisfiable(t *testing.T) {
+	for _, tc := range []struct {
+		doc, path string
+		cloud     Cloud
+	}{
+		{`{"aws": {}, "architectures": ["arm64"], "cpuManufacturers": ["INTEL"]}`, "cpuManufacturers", AWS},
+		{`{"aws": {}, "instanceFamilies": {"includes": ["c6g"]}}`, "architectures", AWS},
+		{`{"aws": {}, "instanceFamilies": {"includes": ["t3"]}}`, "burstableInstances", AWS},
+		{`{"gcp": {}, "isGpuOnly": true, "gpu": {"includeNames": ["nvidia-l4"], "minCount": 16}}`, "gpu", GCP},
+		{`{"gcp": {}, "instanceFamilies": {"includes": ["e2"]}, "minCpu": 64}`, "minCpu", GCP},
+		{`{"aws": {}, "instanceFamilies": {"includes": ["r5"]}, "minCpu": 2, "maxMemory": 8192}`, "maxMemory", AWS},
+	} {
+		msg := "constraints." + tc.path + ": no instance type of the " + tc.cloud.String() +
+			" instance catalog 2026.10 satisfies the constraints"
+		errs, warnings := messages(Validate(constraints(t, tc.doc)))
+		assert.Empty(t, errs, tc.doc)
+		assert.Equal(t, []string{msg}, warnings, tc.doc)
+
+		// A complete catalog fails them.
+		t.Run(tc.doc, func(t *testing.T) {
+			complete(t, tc.cloud)
+			errs, _ := messages(Validate(constraints(t, tc.doc)))
+			assert.Equal(t, []string{msg}, errs)
+		})
+	}
+
+	// An unknown included family or GPU may be newer than the catalog.
+	errs, warnings := messages(Validate(constraints(t, `{"aws": {}, "instanceFamilies": {"includes": ["m9g"]}, "minCpu": 1024}`)))
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"constraints.instanceFamilies.includes[0]: m9g is not in the AWS instance catalog 2026.10"}, warnings)
+
+	errs, warnings = messages(Validate(constraints(t, `{"gcp": {}, "isGpuOnly": true, "gpu": {"includeNames": ["nvidia-tesla-t4"]}}`)))
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"constraints.gpu.includeNames[0]: nvidia-tesla-t4 is not a GPU of the GCP instance catalog 2026.10"}, warnings)
+}
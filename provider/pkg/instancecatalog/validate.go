@@ -0,0 +1,328 @@
+package instancecatalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Constraints are the instance constraints of a node template. The JSON names
+// are the ones of the `constraints` input.
+type Constraints struct {
+	Architectures          []string          `json:"architectures,omitempty"`
+	ArchitecturePriorities []string          `json:"architecturePriorities,omitempty"`
+	CPUManufacturers       []string          `json:"cpuManufacturers,omitempty"`
+	MinCPU                 *int              `json:"minCpu,omitempty"`
+	MaxCPU                 *int              `json:"maxCpu,omitempty"`
+	MinMemory              *int              `json:"minMemory,omitempty"`
+	MaxMemory              *int              `json:"maxMemory,omitempty"`
+	IsGPUOnly              *bool             `json:"isGpuOnly,omitempty"`
+	GPU                    *GPU              `json:"gpu,omitempty"`
+	InstanceFamilies       *InstanceFamilies `json:"instanceFamilies,omitempty"`
+	CustomPriorities       []CustomPriority  `json:"customPriorities,omitempty"`
+	// BurstableInstances is "enabled" to include burstable instances, which
+	// are excluded otherwise.
+	BurstableInstances *string `json:"burstableInstances,omitempty"`
+	Spot               *bool   `json:"spot,omitempty"`
+	OnDemand           *bool   `json:"onDemand,omitempty"`
+	// AWS and GCP are only checked for presence, which tells the cloud of
+	// the template.
+	AWS json.RawMessage `json:"aws,omitempty"`
+	GCP json.RawMessage `json:"gcp,omitempty"`
+}
+
+// GPU are the GPU constraints.
+type GPU struct {
+	Manufacturers []string `json:"manufacturers,omitempty"`
+	IncludeNames  []string `json:"includeNames,omitempty"`
+	ExcludeNames  []string `json:"excludeNames,omitempty"`
+	MinCount      *int     `json:"minCount,omitempty"`
+	MaxCount      *int     `json:"maxCount,omitempty"`
+}
+
+// InstanceFamilies are the families to include or exclude.
+type InstanceFamilies struct {
+	Includes []string `json:"includes,omitempty"`
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+// CustomPriority is a tier of preferred families.
+type CustomPriority struct {
+	InstanceFamilies []string `json:"instanceFamilies,omitempty"`
+}
+
+// ParseConstraints reads constraints from the plain value of the
+// `constraints` input. Unknown inputs are ignored.
+func ParseConstraints(inputs map[string]any) (*Constraints, error) {
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, err
+	}
+	var c Constraints
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("decoding node template constraints: %w", err)
+	}
+	return &c, nil
+}
+
+// Finding is a problem with the constraints of a node template.
+type Finding struct {
+	// Path is the input the finding is about, relative to `constraints`,
+	// e.g. "instanceFamilies.includes[1]".
+	Path string
+	Msg  string
+	// Warning is set for findings that a catalog older than the API can
+	// cause, such as a family newer than the catalog. Other findings are
+	// errors.
+	Warning bool
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("constraints.%s: %s", f.Path, f.Msg)
+}
+
+// familyRef is a family named by the constraints.
+type familyRef struct {
+	path string
+	name string
+}
+
+func (c *Constraints) families() []familyRef {
+	var refs []familyRef
+	if f := c.InstanceFamilies; f != nil {
+		for i, name := range f.Includes {
+			refs = append(refs, familyRef{fmt.Sprintf("instanceFamilies.includes[%d]", i), name})
+		}
+		for i, name := range f.Excludes {
+			refs = append(refs, familyRef{fmt.Sprintf("instanceFamilies.excludes[%d]", i), name})
+		}
+	}
+	for i, p := range c.CustomPriorities {
+		for j, name := range p.InstanceFamilies {
+			refs = append(refs, familyRef{fmt.Sprintf("customPriorities[%d].instanceFamilies[%d]", i, j), name})
+		}
+	}
+	return refs
+}
+
+// Validate checks the constraints on their own and against the catalog of
+// their cloud. The cloud is the one of the aws or gcp block, or else of the
+// families the constraints name; without either, only the catalog-free
+// checks run.
+func Validate(c *Constraints) []Finding {
+	var findings []Finding
+	fail := func(path, format string, args ...any) {
+		findings = append(findings, Finding{Path: path, Msg: fmt.Sprintf(format, args...)})
+	}
+	warn := func(path, format string, args ...any) {
+		findings = append(findings, Finding{Path: path, Msg: fmt.Sprintf(format, args...), Warning: true})
+	}
+
+	ordered := func(minPath string, lo *int, maxPath string, hi *int) {
+		if lo != nil && hi != nil && *lo > *hi {
+			fail(minPath, "%d is greater than %s %d", *lo, maxPath, *hi)
+		}
+	}
+	ordered("minCpu", c.MinCPU, "maxCpu", c.MaxCPU)
+	ordered("minMemory", c.MinMemory, "maxMemory", c.MaxMemory)
+	if g := c.GPU; g != nil {
+		ordered("gpu.minCount", g.MinCount, "gpu.maxCount", g.MaxCount)
+		if c.IsGPUOnly != nil && *c.IsGPUOnly && g.MaxCount != nil && *g.MaxCount == 0 {
+			fail("gpu.maxCount", "is 0 while isGpuOnly is set")
+		}
+		for i, name := range g.IncludeNames {
+			if slices.Contains(g.ExcludeNames, name) {
+				fail(fmt.Sprintf("gpu.includeNames[%d]", i), "%s is also excluded", name)
+			}
+		}
+	}
+	if f := c.InstanceFamilies; f != nil {
+		for i, name := range f.Includes {
+			if slices.Contains(f.Excludes, name) {
+				fail(fmt.Sprintf("instanceFamilies.includes[%d]", i), "%s is also excluded", name)
+			}
+		}
+	}
+	if len(c.Architectures) > 0 {
+		for i, arch := range c.ArchitecturePriorities {
+			if !slices.Contains(c.Architectures, arch) {
+				fail(fmt.Sprintf("architecturePriorities[%d]", i), "%s is not one of the architectures %s", arch, strings.Join(c.Architectures, ", "))
+			}
+		}
+	}
+	if c.Spot != nil && !*c.Spot && c.OnDemand != nil && !*c.OnDemand {
+		fail("spot", "spot and onDemand are both false, so no instance can be created")
+	}
+
+	cloud, ok := c.cloud(fail)
+	if !ok {
+		for _, ref := range c.families() {
+			if owner := cloudOf(ref.name); owner == "" {
+				warn(ref.path, "%s is not in the instance catalogs", ref.name)
+			}
+		}
+		return findings
+	}
+
+	catalog := Get(cloud)
+	// A partial catalog may lack the family or instance type the constraints
+	// are meant for, so it can only warn about them.
+	report := warn
+	if catalog.Complete {
+		report = fail
+	}
+	known := true
+	for _, ref := range c.families() {
+		if catalog.Family(ref.name) != nil {
+			continue
+		}
+		known = known && !strings.HasPrefix(ref.path, "instanceFamilies.includes")
+		if owner := cloudOf(ref.name); owner != "" {
+			fail(ref.path, "%s is a %s instance family, but the template is for %s", ref.name, owner, cloud)
+		} else if s, ok := catalog.suggest(ref.name); ok {
+			report(ref.path, "unknown %s instance family %s, did you mean %s?", cloud, ref.name, s)
+		} else {
+			warn(ref.path, "%s is not in the %s instance catalog %s", ref.name, cloud, catalog.Version)
+		}
+	}
+	if g := c.GPU; g != nil {
+		for i, name := range g.IncludeNames {
+			if !catalog.hasGPU(name) {
+				known = false
+				warn(fmt.Sprintf("gpu.includeNames[%d]", i), "%s is not a GPU of the %s instance catalog %s", name, cloud, catalog.Version)
+			}
+		}
+	}
+	// An unknown included family or GPU may be newer than the catalog, so
+	// the catalog cannot tell whether anything matches. Constraints with
+	// findings match nothing anyway or are already reported.
+	if known && len(findings) == 0 {
+		if path, ok := c.unsatisfiable(catalog); ok {
+			report(path, "no instance type of the %s instance catalog %s satisfies the constraints", cloud, catalog.Version)
+		}
+	}
+	return findings
+}
+
+// cloud returns the cloud of the constraints, reporting blocks and families
+// of different clouds.
+func (c *Constraints) cloud(fail func(path, format string, args ...any)) (Cloud, bool) {
+	var cloud Cloud
+	switch {
+	case len(c.AWS) > 0 && len(c.GCP) > 0 && string(c.AWS) != "null" && string(c.GCP) != "null":
+		fail("gcp", "is set together with aws")
+		return "", false
+	case len(c.AWS) > 0 && string(c.AWS) != "null":
+		cloud = AWS
+	case len(c.GCP) > 0 && string(c.GCP) != "null":
+		cloud = GCP
+	}
+	if cloud == "" {
+		for _, ref := range c.families() {
+			if owner := cloudOf(ref.name); owner != "" {
+				cloud = owner
+				break
+			}
+		}
+	}
+	return cloud, cloud != ""
+}
+
+// cloudOf returns the cloud whose catalog has a family, or "".
+func cloudOf(family string) Cloud {
+	for _, cloud := range Clouds {
+		if Get(cloud).Family(family) != nil {
+			return cloud
+		}
+	}
+	return ""
+}
+
+// candidate is an instance type with its family.
+type candidate struct {
+	family *Family
+	typ    InstanceType
+}
+
+// unsatisfiable applies the constraints to the catalog one by one and returns
+// the path of the constraint that leaves no instance type.
+func (c *Constraints) unsatisfiable(catalog *Catalog) (string, bool) {
+	var candidates []candidate
+	for i := range catalog.Families {
+		f := &catalog.Families[i]
+		for _, t := range f.Types {
+			candidates = append(candidates, candidate{f, t})
+		}
+	}
+
+	architectures := c.Architectures
+	if len(architectures) == 0 {
+		architectures = []string{"amd64"}
+	}
+	burstable := c.BurstableInstances != nil && *c.BurstableInstances == "enabled"
+	gpuOnly := c.IsGPUOnly != nil && *c.IsGPUOnly
+	g := c.GPU
+	if g == nil {
+		g = &GPU{}
+	}
+
+	filters := []struct {
+		path  string
+		keeps func(candidate) bool
+	}{
+		{"instanceFamilies", func(x candidate) bool {
+			f := c.InstanceFamilies
+			return f == nil || (len(f.Includes) == 0 || slices.Contains(f.Includes, x.family.Name)) && !slices.Contains(f.Excludes, x.family.Name)
+		}},
+		{"architectures", func(x candidate) bool { return slices.Contains(architectures, x.family.Architecture) }},
+		{"cpuManufacturers", func(x candidate) bool {
+			return len(c.CPUManufacturers) == 0 || slices.Contains(c.CPUManufacturers, x.family.CPUManufacturer)
+		}},
+		{"burstableInstances", func(x candidate) bool { return burstable || !x.typ.Burstable }},
+		{"isGpuOnly", func(x candidate) bool { return !gpuOnly || x.typ.GPUs > 0 }},
+		{"gpu", func(x candidate) bool {
+			if x.typ.GPUs == 0 {
+				return true
+			}
+			return (len(g.Manufacturers) == 0 || slices.Contains(g.Manufacturers, x.family.GPUManufacturer)) &&
+				(len(g.IncludeNames) == 0 || namesGPU(g.IncludeNames, x.family.GPUName)) &&
+				!namesGPU(g.ExcludeNames, x.family.GPUName) &&
+				(g.MinCount == nil || x.typ.GPUs >= *g.MinCount) &&
+				(g.MaxCount == nil || x.typ.GPUs <= *g.MaxCount)
+		}},
+		{"minCpu", func(x candidate) bool { return c.MinCPU == nil || x.typ.CPU >= *c.MinCPU }},
+		{"maxCpu", func(x candidate) bool { return c.MaxCPU == nil || x.typ.CPU <= *c.MaxCPU }},
+		{"minMemory", func(x candidate) bool { return c.MinMemory == nil || x.typ.Memory >= *c.MinMemory }},
+		{"maxMemory", func(x candidate) bool { return c.MaxMemory == nil || x.typ.Memory <= *c.MaxMemory }},
+	}
+	for _, filter := range filters {
+		candidates = slices.DeleteFunc(candidates, func(x candidate) bool { return !filter.keeps(x) })
+		if len(candidates) == 0 {
+			return filter.path, true
+		}
+	}
+	return "", false
+}
+
+// hasGPU reports whether a family of the catalog has the GPU name refers to.
+func (c *Catalog) hasGPU(name string) bool {
+	for _, f := range c.Families {
+		if f.GPUName != "" && namesGPU([]string{name}, f.GPUName) {
+			return true
+		}
+	}
+	return false
+}
+
+// namesGPU reports whether one of names refers to the GPU of a catalog
+// family. The API names GPUs both by model, e.g. "T4", and by accelerator
+// type, e.g. "nvidia-tesla-t4", so a name matches if it contains the model.
+func namesGPU(names []string, model string) bool {
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(model)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package podmutation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodMutationType is the type token of the resources read from a stack export.
+const PodMutationType = "castai:index:PodMutation"
+
+// SpotMode is the spot mode of a SpotConfig or the spot type of a
+// distribution group.
+type SpotMode string
+
+const (
+	// OptionalSpot lets pods run on spot and on-demand nodes.
+	OptionalSpot SpotMode = "OPTIONAL_SPOT"
+	// UseOnlySpot schedules pods on spot nodes only.
+	UseOnlySpot SpotMode = "USE_ONLY_SPOT"
+	// PreferredSpot prefers spot nodes and falls back to on-demand nodes.
+	PreferredSpot SpotMode = "PREFERRED_SPOT"
+)
+
+// Mutation is the part of a PodMutation that changes pods. The JSON names are
+// the ones of the resource inputs.
+type Mutation struct {
+	Name string `json:"name"`
+	Configuration
+	// SpotConfig applies a spot mode to DistributionPercentage percent of
+	// the pods.
+	SpotConfig *SpotConfig `json:"spotConfig,omitempty"`
+	// DistributionGroups split the pods into groups, each with its own
+	// configuration applied on top of the mutation's.
+	DistributionGroups []DistributionGroup `json:"distributionGroups,omitempty"`
+}
+
+// Configuration are the changes a mutation or a distribution group applies.
+type Configuration struct {
+	Labels       map[string]string   `json:"labels,omitempty"`
+	Annotations  map[string]string   `json:"annotations,omitempty"`
+	NodeSelector *NodeSelector       `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	Affinity     *Affinity           `json:"affinity,omitempty"`
+	// Patch is a JSON patch (RFC 6902) applied to the pod after the other
+	// changes.
+	Patch string `json:"patch,omitempty"`
+}
+
+// NodeSelector adds and removes node selector entries.
+type NodeSelector struct {
+	Add map[string]string `json:"add,omitempty"`
+	// Remove deletes the keys, whatever their value.
+	Remove map[string]string `json:"remove,omitempty"`
+}
+
+// Affinity is the affinity a configuration adds to pods.
+type Affinity struct {
+	NodeAffinity *NodeAffinity `json:"nodeAffinity,omitempty"`
+}
+
+// NodeAffinity holds preferred node affinity terms. The input name is the
+// plural of the Kubernetes field.
+type NodeAffinity struct {
+	Preferred []corev1.PreferredSchedulingTerm `json:"preferredDuringSchedulingIgnoredDuringExecutions,omitempty"`
+}
+
+// SpotConfig is the spot configuration of a mutation.
+type SpotConfig struct {
+	SpotMode SpotMode `json:"spotMode"`
+	// DistributionPercentage is the percentage of pods the spot mode applies
+	// to. Unset applies it to every pod.
+	DistributionPercentage *int `json:"distributionPercentage,omitempty"`
+}
+
+// DistributionGroup receives Percentage percent of the pods.
+type DistributionGroup struct {
+	Name          string             `json:"name"`
+	Percentage    int                `json:"percentage"`
+	Configuration GroupConfiguration `json:"configuration"`
+}
+
+// GroupConfiguration is the configuration of a distribution group.
+type GroupConfiguration struct {
+	Configuration
+	SpotType SpotMode `json:"spotType,omitempty"`
+}
+
+// Validate reports every reason the mutation is invalid: patches that are
+// not JSON patches of the Pod schema, invalid spot modes and tolerations, and
+// distribution groups whose percentages do not add up to 100.
+func (m *Mutation) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	m.Configuration.validate("", fail)
+	if s := m.SpotConfig; s != nil {
+		if !s.SpotMode.valid() {
+			fail("spotConfig.spotMode: unknown spot mode %q", s.SpotMode)
+		}
+		if p := s.DistributionPercentage; p != nil && (*p < 0 || *p > 100) {
+			fail("spotConfig.distributionPercentage: %d is not between 0 and 100", *p)
+		}
+	}
+
+	if len(m.DistributionGroups) > 0 {
+		total := 0
+		names := map[string]bool{}
+		for i, g := range m.DistributionGroups {
+			prefix := fmt.Sprintf("distributionGroups[%d]", i)
+			switch {
+			case g.Name == "":
+				fail("%s.name: must be set", prefix)
+			case names[g.Name]:
+				fail("%s.name: %q is used by another group", prefix, g.Name)
+			}
+			names[g.Name] = true
+			if g.Percentage < 0 || g.Percentage > 100 {
+				fail("%s.percentage: %d is not between 0 and 100", prefix, g.Percentage)
+			}
+			total += g.Percentage
+			if t := g.Configuration.SpotType; t != "" && !t.valid() {
+				fail("%s.configuration.spotType: unknown spot mode %q", prefix, t)
+			}
+			g.Configuration.validate(prefix+".configuration.", fail)
+		}
+		if total != 100 {
+			fail("distributionGroups: percentages add up to %d, not 100", total)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Configuration) validate(prefix string, fail func(string, ...any)) {
+	for i, t := range c.Tolerations {
+		switch t.Operator {
+		case "", corev1.TolerationOpEqual, corev1.TolerationOpExists:
+		default:
+			fail("%stolerations[%d].operator: unknown operator %q", prefix, i, t.Operator)
+		}
+		switch t.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			fail("%stolerations[%d].effect: unknown effect %q", prefix, i, t.Effect)
+		}
+		if t.Operator == corev1.TolerationOpExists && t.Value != "" {
+			fail("%stolerations[%d].value: must be empty for operator Exists", prefix, i)
+		}
+	}
+	if c.Patch != "" {
+		if _, err := parsePatch(c.Patch); err != nil {
+			fail("%spatch: %w", prefix, err)
+		}
+	}
+}
+
+func (s SpotMode) valid() bool {
+	switch s {
+	case OptionalSpot, UseOnlySpot, PreferredSpot:
+		return true
+	}
+	return false
+}
+
+// LoadMutation reads a Mutation from YAML or JSON, using the resource input
+// names.
+func LoadMutation(r io.Reader) (*Mutation, error) {
+	var m Mutation
+	if err := decode(r, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// stackExport is the part of `pulumi stack export` LoadStackExport reads.
+type stackExport struct {
+	Deployment struct {
+		Resources []struct {
+			URN    string          `json:"urn"`
+			Type   string          `json:"type"`
+			Inputs json.RawMessage `json:"inputs"`
+		} `json:"resources"`
+	} `json:"deployment"`
+}
+
+// LoadStackExport reads a pod mutation from the output of `pulumi stack
+// export`. If the stack manages several, name selects one of them.
+func LoadStackExport(r io.Reader, name string) (*Mutation, error) {
+	var export stackExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("reading stack export: %w", err)
+	}
+
+	var found []*Mutation
+	var names []string
+	for _, res := range export.Deployment.Resources {
+		if res.Type != PodMutationType {
+			continue
+		}
+		var m Mutation
+		if err := json.Unmarshal(res.Inputs, &m); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", res.URN, err)
+		}
+		if name != "" && m.Name == name {
+			return &m, nil
+		}
+		found = append(found, &m)
+		names = append(names, m.Name)
+	}
+
+	switch {
+	case name != "":
+		return nil, fmt.Errorf("the stack has no pod mutation named %s", name)
+	case len(found) == 0:
+		return nil, fmt.Errorf("the stack has no %s resources", PodMutationType)
+	case len(found) > 1:
+		return nil, fmt.Errorf("the stack has several pod mutations, select one of %s", strings.Join(names, ", "))
+	}
+	return found[0], nil
+}
+
+// decode reads a YAML or JSON document into v through its JSON tags.
+func decode(r io.Reader, v any) error {
+	var doc any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,166 @@
+package podmutation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// operation is a JSON patch operation.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+var podType = reflect.TypeOf(corev1.Pod{})
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// parsePatch checks that patch is a JSON patch whose paths exist in the Pod
+// schema and whose values have the type of the field they are written to.
+func parsePatch(patch string) ([]operation, error) {
+	var ops []operation
+	if err := json.Unmarshal([]byte(patch), &ops); err != nil {
+		return nil, fmt.Errorf("not a JSON array of patch operations: %w", err)
+	}
+
+	var errs []error
+	for i, op := range ops {
+		if err := op.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("operation %d (%s): %w", i, op.Op, err))
+		}
+	}
+	return ops, errors.Join(errs...)
+}
+
+func (op operation) validate() error {
+	switch op.Op {
+	case "add", "replace", "test", "remove", "move", "copy":
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+	if op.Path == nil {
+		return errors.New("path must be set")
+	}
+	typ, err := resolve(*op.Path, op.Op == "add")
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "move", "copy":
+		if op.From == nil {
+			return errors.New("from must be set")
+		}
+		from, err := resolve(*op.From, false)
+		if err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+		if from != typ {
+			return fmt.Errorf("from %s is a %s, path %s is a %s", *op.From, typeName(from), *op.Path, typeName(typ))
+		}
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return errors.New("value must be set")
+		}
+		if err := decodeStrict(op.Value, reflect.New(typ).Interface()); err != nil {
+			return fmt.Errorf("value for %s: %w", *op.Path, err)
+		}
+	}
+	return nil
+}
+
+// resolve returns the type of the Pod field a JSON pointer refers to. If
+// appending is set, the last token of an array may be `-`.
+func resolve(pointer string, appending bool) (reflect.Type, error) {
+	if pointer == "" {
+		return podType, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("path %q does not start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	typ := podType
+	for i, token := range tokens {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		at := "/" + strings.Join(tokens[:i], "/")
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		switch {
+		case reflect.PointerTo(typ).Implements(jsonUnmarshaler) && typ.Kind() == reflect.Struct:
+			// Quantities, times and IntOrString are scalars in JSON.
+			return nil, fmt.Errorf("path %s: %s is a %s", pointer, at, typeName(typ))
+		case typ.Kind() == reflect.Struct:
+			field, ok := jsonField(typ, token)
+			if !ok {
+				return nil, fmt.Errorf("path %s: %s has no field %q", pointer, at, token)
+			}
+			typ = field
+		case typ.Kind() == reflect.Slice:
+			last := i == len(tokens)-1
+			if _, err := strconv.ParseUint(token, 10, 0); err != nil && !(token == "-" && appending && last) {
+				return nil, fmt.Errorf("path %s: %q is not an index of %s", pointer, token, at)
+			}
+			typ = typ.Elem()
+		case typ.Kind() == reflect.Map:
+			typ = typ.Elem()
+		default:
+			return nil, fmt.Errorf("path %s: %s is a %s", pointer, at, typeName(typ))
+		}
+	}
+	return typ, nil
+}
+
+// jsonField returns the type of the field of a struct with the given JSON
+// name, looking into inlined structs such as TypeMeta.
+func jsonField(typ reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case tag == name:
+			return f.Type, true
+		case tag == "" && f.Anonymous:
+			if t, ok := jsonField(f.Type, name); ok {
+				return t, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// typeName describes a type in JSON terms.
+func typeName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch {
+	case typ.Kind() == reflect.Struct && reflect.PointerTo(typ).Implements(jsonUnmarshaler):
+		return typ.Name()
+	case typ.Kind() == reflect.Struct || typ.Kind() == reflect.Map:
+		return "object"
+	case typ.Kind() == reflect.Slice:
+		return "array"
+	case typ.Kind() == reflect.Bool:
+		return "boolean"
+	case typ.Kind() == reflect.String:
+		return "string"
+	}
+	return "number"
+}
+
+// decodeStrict decodes JSON into v, rejecting fields v does not have.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,200 @@
+package podmutation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      nodeSelector:
+        legacy: "true"
+      containers:
+        - name: api
+          image: nginx
+          resources:
+            requests:
+              cpu: 100m
+`
+
+func pod(t *testing.T) *corev1.Pod {
+	t.Helper()
+	p, err := LoadPod(strings.NewReader(deployment))
+	require.NoError(t, err)
+	return p
+}
+
+func mutation(t *testing.T, doc string) *Mutation {
+	t.Helper()
+	m, err := LoadMutation(strings.NewReader(doc))
+	require.NoError(t, err)
+	return m
+}
+
+// TestLoadPod tests that the pod template of a workload is used as the sample pod
+func TestLoadPod(t *testing.T) {
+	p := pod(t)
+	assert.Equal(t, "Pod", p.Kind)
+	assert.Equal(t, map[string]string{"app": "api"}, p.Labels)
+	assert.Equal(t, "100m", p.Spec.Containers[0].Resources.Requests.Cpu().String())
+
+	_, err := LoadPod(strings.NewReader("kind: ConfigMap\ndata: {}\n"))
+	assert.ErrorContains(t, err, "ConfigMap is neither a Pod nor a workload")
+
+	_, err = LoadPod(strings.NewReader("kind: Pod\nspec: {containerz: []}\n"))
+	assert.ErrorContains(t, err, `unknown field "containerz"`)
+}
+
+// TestValidatePatch tests that patch paths and values are checked against the Pod schema
+func TestValidatePatch(t *testing.T) {
+	valid := `[
+		{"op": "add", "path": "/metadata/labels/team", "value": "payments"},
+		{"op": "add", "path": "/metadata/annotations/app.kubernetes.io~1name", "value": "api"},
+		{"op": "add", "path": "/spec/tolerations/-", "value": {"key": "dedicated", "operator": "Exists"}},
+		{"op": "replace", "path": "/spec/containers/0/resources/requests/cpu", "value": "250m"},
+		{"op": "remove", "path": "/spec/nodeSelector/legacy"},
+		{"op": "copy", "from": "/metadata/labels/app", "path": "/metadata/labels/service"}
+	]`
+	require.NoError(t, (&Mutation{Configuration: Configuration{Patch: valid}}).Validate())
+
+	for patch, want := range map[string]string{
+		`{"op": "add"}`:                      "not a JSON array of patch operations",
+		`[{"op": "merge", "path": "/spec"}]`: `unknown op "merge"`,
+		`[{"op": "add", "path": "/spec/tolerationz/-", "value": {}}]`:                        `/spec has no field "tolerationz"`,
+		`[{"op": "add", "path": "/spec/tolerations/first", "value": {}}]`:                    `"first" is not an index of /spec/tolerations`,
+		`[{"op": "replace", "path": "/spec/tolerations/-", "value": {}}]`:                    `"-" is not an index of /spec/tolerations`,
+		`[{"op": "add", "path": "/spec/priority/x", "value": 1}]`:                            "/spec/priority is a number",
+		`[{"op": "add", "path": "/spec/tolerations/-", "value": {"k": "v"}}]`:                `unknown field "k"`,
+		`[{"op": "add", "path": "/spec/priority", "value": "high"}]`:                         "cannot unmarshal string",
+		`[{"op": "add", "path": "/spec/containers/0/resources/requests/cpu/x", "value": 1}]`: "is a Quantity",
+		`[{"op": "move", "from": "/spec/priority", "path": "/metadata/name"}]`:               "from /spec/priority is a number, path /metadata/name is a string",
+		`[{"op": "add", "path": "/metadata/labels/team"}]`:                                   "value must be set",
+	} {
+		err := (&Mutation{Configuration: Configuration{Patch: patch}}).Validate()
+		assert.ErrorContains(t, err, want, patch)
+	}
+}
+
+// TestValidate tests the checks of the other mutation fields
+func TestValidate(t *testing.T) {
+	m := mutation(t, `
+name: split
+spotConfig: {spotMode: SOMETIMES_SPOT, distributionPercentage: 120}
+tolerations:
+  - {key: dedicated, operator: Exists, value: "yes", effect: NoSchedule}
+distributionGroups:
+  - name: a
+    percentage: 60
+    configuration:
+      spotType: USE_ONLY_SPOT
+      patch: '[{"op": "add", "path": "/spec/bogus", "value": 1}]'
+  - name: a
+    percentage: 30
+`)
+	err := m.Validate()
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"tolerations[0].value: must be empty for operator Exists",
+		`spotConfig.spotMode: unknown spot mode "SOMETIMES_SPOT"`,
+		"spotConfig.distributionPercentage: 120 is not between 0 and 100",
+		`distributionGroups[0].configuration.patch: operation 0 (add): path /spec/bogus: /spec has no field "bogus"`,
+		`distributionGroups[1].name: "a" is used by another group`,
+		"distributionGroups: percentages add up to 90, not 100",
+	}, strings.Split(err.Error(), "\n"))
+}
+
+// TestPreview tests the pods produced for the spot split and the distribution groups
+func TestPreview(t *testing.T) {
+	m := mutation(t, `
+name: api
+labels: {mutated: "true"}
+nodeSelector:
+  add: {pool: general}
+  remove: {legacy: ""}
+spotConfig: {spotMode: PREFERRED_SPOT, distributionPercentage: 75}
+distributionGroups:
+  - name: big
+    percentage: 20
+    configuration:
+      patch: '[{"op": "replace", "path": "/spec/containers/0/resources/requests/cpu", "value": "1"}]'
+  - name: small
+    percentage: 80
+    configuration:
+      spotType: USE_ONLY_SPOT
+      tolerations: [{key: batch, operator: Exists}]
+`)
+	require.NoError(t, m.Validate())
+	variants, err := Preview(m, pod(t))
+	require.NoError(t, err)
+
+	var names []string
+	var shares []float64
+	for _, v := range variants {
+		names = append(names, v.Name)
+		shares = append(shares, v.Share)
+	}
+	assert.Equal(t, []string{"spot/big", "spot/small", "on-demand/big", "on-demand/small"}, names)
+	assert.Equal(t, []float64{15, 60, 5, 20}, shares)
+
+	spotBig := variants[0].Pod
+	assert.Equal(t, map[string]string{"app": "api", "mutated": "true"}, spotBig.Labels)
+	assert.Equal(t, map[string]string{"pool": "general"}, spotBig.Spec.NodeSelector)
+	assert.Equal(t, "1", spotBig.Spec.Containers[0].Resources.Requests.Cpu().String())
+	assert.Equal(t, SpotLabel, spotBig.Spec.Tolerations[0].Key)
+	require.NotNil(t, spotBig.Spec.Affinity)
+	assert.Equal(t, SpotLabel, spotBig.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].Preference.MatchExpressions[0].Key)
+
+	spotSmall := variants[1].Pod
+	assert.Equal(t, "true", spotSmall.Spec.NodeSelector[SpotLabel])
+	assert.Len(t, spotSmall.Spec.Tolerations, 2, "the spot toleration is not repeated")
+	assert.Equal(t, "100m", spotSmall.Spec.Containers[0].Resources.Requests.Cpu().String())
+
+	onDemandBig := variants[2].Pod
+	assert.Empty(t, onDemandBig.Spec.Tolerations)
+	assert.Nil(t, onDemandBig.Spec.Affinity)
+}
+
+// TestPreviewPatchFailure tests that a patch that cannot be applied to the sample pod is reported
+func TestPreviewPatchFailure(t *testing.T) {
+	m := &Mutation{Configuration: Configuration{Patch: `[{"op": "remove", "path": "/spec/tolerations/0"}]`}}
+	require.NoError(t, m.Validate())
+
+	_, err := Preview(m, pod(t))
+	assert.ErrorContains(t, err, "patch:")
+}
+
+// TestLoadStackExport tests that a pod mutation is read from a stack export
+func TestLoadStackExport(t *testing.T) {
+	export := `{
+  "deployment": {
+    "resources": [
+      {"urn": "urn:pulumi:dev::p::castai:index:PodMutation::a", "type": "castai:index:PodMutation",
+       "inputs": {"clusterId": "c1", "name": "a", "labels": {"x": "y"}}},
+      {"urn": "urn:pulumi:dev::p::castai:index:PodMutation::b", "type": "castai:index:PodMutation",
+       "inputs": {"clusterId": "c1", "name": "b", "distributionGroups": [{"name": "all", "percentage": 100, "configuration": {"spotType": "OPTIONAL_SPOT"}}]}}
+    ]
+  }
+}`
+	_, err := LoadStackExport(strings.NewReader(export), "")
+	assert.ErrorContains(t, err, "select one of a, b")
+
+	m, err := LoadStackExport(strings.NewReader(export), "b")
+	require.NoError(t, err)
+	assert.Equal(t, OptionalSpot, m.DistributionGroups[0].Configuration.SpotType)
+
+	_, err = LoadStackExport(strings.NewReader(export), "c")
+	assert.ErrorContains(t, err, "no pod mutation named c")
+}
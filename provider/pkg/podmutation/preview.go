@@ -0,0 +1,235 @@
+package podmutation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SpotLabel is the node label and taint key of CAST AI spot nodes.
+const SpotLabel = "scheduling.cast.ai/spot"
+
+// Variant is one of the pods a mutation turns a pod into. Pods are split into
+// variants by the spot distribution percentage and by distribution groups.
+type Variant struct {
+	// Name describes the variant, e.g. "spot" or the distribution group.
+	Name string `json:"name"`
+	// Share is the percentage of pods that become this variant.
+	Share float64     `json:"share"`
+	Pod   *corev1.Pod `json:"pod"`
+}
+
+// Preview returns the pods that m turns pod into. The mutation's own
+// configuration applies to every pod, then its spot config to its share of
+// the pods, then the configuration and spot type of each distribution group.
+// Patches run after the other changes of their configuration. The mutation
+// must be valid.
+func Preview(m *Mutation, pod *corev1.Pod) ([]Variant, error) {
+	base := pod.DeepCopy()
+	if err := m.Configuration.apply(base); err != nil {
+		return nil, err
+	}
+	variants := []Variant{{Share: 100, Pod: base}}
+
+	if s := m.SpotConfig; s != nil {
+		percentage := 100
+		if s.DistributionPercentage != nil {
+			percentage = *s.DistributionPercentage
+		}
+		variants, _ = split(variants, func(v Variant) ([]Variant, error) {
+			spot := v.Pod.DeepCopy()
+			applySpot(spot, s.SpotMode)
+			return []Variant{
+				{Name: "spot", Share: float64(percentage), Pod: spot},
+				{Name: "on-demand", Share: float64(100 - percentage), Pod: v.Pod},
+			}, nil
+		})
+	}
+
+	if len(m.DistributionGroups) > 0 {
+		return split(variants, func(v Variant) ([]Variant, error) {
+			var out []Variant
+			for _, g := range m.DistributionGroups {
+				p := v.Pod.DeepCopy()
+				if err := g.Configuration.apply(p); err != nil {
+					return nil, fmt.Errorf("distribution group %s: %w", g.Name, err)
+				}
+				if g.Configuration.SpotType != "" {
+					applySpot(p, g.Configuration.SpotType)
+				}
+				out = append(out, Variant{Name: g.Name, Share: float64(g.Percentage), Pod: p})
+			}
+			return out, nil
+		})
+	}
+	return variants, nil
+}
+
+// split replaces each variant by the ones f returns, joining their names and
+// multiplying their shares. Variants with no share are dropped.
+func split(variants []Variant, f func(Variant) ([]Variant, error)) ([]Variant, error) {
+	var out []Variant
+	for _, v := range variants {
+		parts, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parts {
+			if p.Share == 0 {
+				continue
+			}
+			if v.Name != "" {
+				p.Name = v.Name + "/" + p.Name
+			}
+			p.Share = v.Share * p.Share / 100
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// apply makes the changes of a configuration to pod.
+func (c *Configuration) apply(pod *corev1.Pod) error {
+	for k, v := range c.Labels {
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		pod.Labels[k] = v
+	}
+	for k, v := range c.Annotations {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[k] = v
+	}
+	if ns := c.NodeSelector; ns != nil {
+		for k, v := range ns.Add {
+			if pod.Spec.NodeSelector == nil {
+				pod.Spec.NodeSelector = map[string]string{}
+			}
+			pod.Spec.NodeSelector[k] = v
+		}
+		for k := range ns.Remove {
+			delete(pod.Spec.NodeSelector, k)
+		}
+	}
+	for _, t := range c.Tolerations {
+		addToleration(pod, t)
+	}
+	if a := c.Affinity; a != nil && a.NodeAffinity != nil {
+		for _, term := range a.NodeAffinity.Preferred {
+			addPreferred(pod, term)
+		}
+	}
+	if c.Patch == "" {
+		return nil
+	}
+
+	patch, err := jsonpatch.DecodePatch([]byte(c.Patch))
+	if err != nil {
+		return fmt.Errorf("patch: %w", err)
+	}
+	doc, err := json.Marshal(pod)
+	if err != nil {
+		return err
+	}
+	if doc, err = patch.Apply(doc); err != nil {
+		return fmt.Errorf("patch: %w", err)
+	}
+	var patched corev1.Pod
+	if err := decodeStrict(doc, &patched); err != nil {
+		return fmt.Errorf("patch: the patched pod is not a valid Pod: %w", err)
+	}
+	*pod = patched
+	return nil
+}
+
+// applySpot adds the scheduling constraints of a spot mode: every mode
+// tolerates spot nodes, USE_ONLY_SPOT selects them and PREFERRED_SPOT prefers
+// them.
+func applySpot(pod *corev1.Pod, mode SpotMode) {
+	addToleration(pod, corev1.Toleration{Key: SpotLabel, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule})
+	switch mode {
+	case UseOnlySpot:
+		if pod.Spec.NodeSelector == nil {
+			pod.Spec.NodeSelector = map[string]string{}
+		}
+		pod.Spec.NodeSelector[SpotLabel] = "true"
+	case PreferredSpot:
+		addPreferred(pod, corev1.PreferredSchedulingTerm{
+			Weight: 100,
+			Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: SpotLabel, Operator: corev1.NodeSelectorOpExists},
+			}},
+		})
+	}
+}
+
+// addToleration adds a toleration the pod does not have yet.
+func addToleration(pod *corev1.Pod, t corev1.Toleration) {
+	for _, existing := range pod.Spec.Tolerations {
+		if existing.MatchToleration(&t) && equalSeconds(existing.TolerationSeconds, t.TolerationSeconds) {
+			return
+		}
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, t)
+}
+
+func equalSeconds(a, b *int64) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func addPreferred(pod *corev1.Pod, term corev1.PreferredSchedulingTerm) {
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	na := pod.Spec.Affinity.NodeAffinity
+	na.PreferredDuringSchedulingIgnoredDuringExecution = append(na.PreferredDuringSchedulingIgnoredDuringExecution, term)
+}
+
+// LoadPod reads a sample pod from a YAML or JSON manifest. The manifest may
+// also be a workload, such as a Deployment or CronJob, whose pod template is
+// used.
+func LoadPod(r io.Reader) (*corev1.Pod, error) {
+	var m struct {
+		Kind     string          `json:"kind"`
+		Metadata json.RawMessage `json:"metadata"`
+		Spec     struct {
+			Template    json.RawMessage `json:"template"`
+			JobTemplate struct {
+				Spec struct {
+					Template json.RawMessage `json:"template"`
+				} `json:"spec"`
+			} `json:"jobTemplate"`
+		} `json:"spec"`
+	}
+	var raw json.RawMessage
+	if err := decode(r, &raw); err != nil {
+		return nil, fmt.Errorf("reading pod: %w", err)
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("reading pod: %w", err)
+	}
+
+	var pod corev1.Pod
+	switch {
+	case m.Kind == "Pod":
+	case m.Spec.Template != nil:
+		raw = m.Spec.Template
+	case m.Spec.JobTemplate.Spec.Template != nil:
+		raw = m.Spec.JobTemplate.Spec.Template
+	default:
+		return nil, fmt.Errorf("reading pod: %s is neither a Pod nor a workload with a pod template", m.Kind)
+	}
+	if err := decodeStrict(raw, &pod); err != nil {
+		return nil, fmt.Errorf("reading pod: %w", err)
+	}
+	pod.APIVersion, pod.Kind = "v1", "Pod"
+	return &pod, nil
+}
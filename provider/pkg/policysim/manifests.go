@@ -0,0 +1,144 @@
+package policysim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workloadKinds are the kinds the workload autoscaler assigns policies to.
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+	"Job":         true,
+	"CronJob":     true,
+	"Rollout":     true,
+}
+
+// namespaceNameLabel is set by Kubernetes on every namespace.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// Workload is a workload read from the manifests.
+type Workload struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Namespace  string            `json:"namespace"`
+	Name       string            `json:"name"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// String returns the workload as namespace/Kind/name.
+func (w Workload) String() string {
+	return w.Namespace + "/" + w.Kind + "/" + w.Name
+}
+
+// group returns the API group of the workload, empty for the core group.
+func (w Workload) group() string {
+	if i := strings.LastIndex(w.APIVersion, "/"); i >= 0 {
+		return w.APIVersion[:i]
+	}
+	return ""
+}
+
+func (w Workload) version() string {
+	return w.APIVersion[strings.LastIndex(w.APIVersion, "/")+1:]
+}
+
+// Cluster are the workloads and namespaces to simulate the policies on.
+type Cluster struct {
+	Workloads []Workload
+	// Namespaces are the namespace labels, keyed by name. Namespaces of
+	// workloads without a Namespace manifest only have the
+	// kubernetes.io/metadata.name label.
+	Namespaces map[string]map[string]string
+}
+
+// namespaceLabels returns the labels of a namespace.
+func (c *Cluster) namespaceLabels(name string) map[string]string {
+	if labels, ok := c.Namespaces[name]; ok {
+		return labels
+	}
+	return map[string]string{namespaceNameLabel: name}
+}
+
+// manifest is the part of a Kubernetes object LoadManifests reads.
+type manifest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string            `json:"name"`
+		Namespace string            `json:"namespace"`
+		Labels    map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Items []json.RawMessage `json:"items"`
+}
+
+// LoadManifests reads the namespaces and workloads of multi-document YAML or
+// JSON manifests, such as the output of `kubectl get -o yaml` or `helm
+// template`. Other objects are ignored.
+func LoadManifests(c *Cluster, r io.Reader) error {
+	if c.Namespaces == nil {
+		c.Namespaces = map[string]map[string]string{}
+	}
+	dec := yaml.NewDecoder(r)
+	for {
+		var doc any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading manifests: %w", err)
+		}
+		if doc == nil {
+			continue
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("reading manifests: %w", err)
+		}
+		if err := c.add(data); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Cluster) add(data json.RawMessage) error {
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("reading manifests: %w", err)
+	}
+	switch {
+	case strings.HasSuffix(m.Kind, "List"):
+		for _, item := range m.Items {
+			if err := c.add(item); err != nil {
+				return err
+			}
+		}
+	case m.Kind == "Namespace" && m.APIVersion == "v1":
+		labels := map[string]string{namespaceNameLabel: m.Metadata.Name}
+		for k, v := range m.Metadata.Labels {
+			labels[k] = v
+		}
+		c.Namespaces[m.Metadata.Name] = labels
+	case workloadKinds[m.Kind]:
+		ns := m.Metadata.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		c.Workloads = append(c.Workloads, Workload{
+			APIVersion: m.APIVersion,
+			Kind:       m.Kind,
+			Namespace:  ns,
+			Name:       m.Metadata.Name,
+			Labels:     m.Metadata.Labels,
+		})
+	}
+	return nil
+}
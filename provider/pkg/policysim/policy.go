@@ -0,0 +1,259 @@
+package policysim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Type tokens of the resources read from a stack export.
+const (
+	WorkloadScalingPolicyType      = "castai:workload:WorkloadScalingPolicy"
+	WorkloadScalingPolicyOrderType = "castai:workload:WorkloadScalingPolicyOrder"
+)
+
+// Spec are the scaling policies of a cluster and their order.
+type Spec struct {
+	// Policies are the scaling policies. Policies missing from Order are
+	// evaluated after the ordered ones, in this order.
+	Policies []Policy `json:"policies"`
+	// Order lists policy IDs or names, highest priority first, as set by
+	// WorkloadScalingPolicyOrder.
+	Order []string `json:"order,omitempty"`
+}
+
+// Policy is the part of a WorkloadScalingPolicy that decides which workloads
+// it is assigned to. The JSON names are the ones of the resource inputs.
+type Policy struct {
+	// ID is the policy ID. It is only needed if Order refers to IDs.
+	ID              string           `json:"id,omitempty"`
+	Name            string           `json:"name"`
+	AssignmentRules []AssignmentRule `json:"assignmentRules,omitempty"`
+}
+
+// AssignmentRule holds the rules of a policy. A workload matches the policy if
+// it matches any of its rules.
+type AssignmentRule struct {
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule matches workloads by namespace and workload metadata. A workload
+// matches the rule if it matches every condition that is set.
+type Rule struct {
+	Namespace *NamespaceCondition `json:"namespace,omitempty"`
+	Workload  *WorkloadCondition  `json:"workload,omitempty"`
+}
+
+// NamespaceCondition matches the namespace of a workload.
+type NamespaceCondition struct {
+	// Names matches namespaces by name, if set.
+	Names []string `json:"names,omitempty"`
+	// LabelsExpressions must all hold for the namespace labels.
+	LabelsExpressions []LabelExpression `json:"labelsExpressions,omitempty"`
+}
+
+// WorkloadCondition matches the kind and labels of a workload.
+type WorkloadCondition struct {
+	// Gvks matches workloads of any of these kinds, in the format
+	// kind[.version][.group], e.g. Deployment or Deployment.v1.apps.
+	Gvks []string `json:"gvks,omitempty"`
+	// LabelsExpressions must all hold for the workload labels.
+	LabelsExpressions []LabelExpression `json:"labelsExpressions,omitempty"`
+}
+
+// Operator is the operator of a LabelExpression.
+type Operator string
+
+const (
+	In           Operator = "In"
+	NotIn        Operator = "NotIn"
+	Exists       Operator = "Exists"
+	DoesNotExist Operator = "DoesNotExist"
+	// Regex holds if the label value matches any of the values, which are
+	// unanchored regular expressions.
+	Regex Operator = "Regex"
+	// Contains holds if the label value contains any of the values.
+	Contains Operator = "Contains"
+)
+
+// LabelExpression is a label selector requirement. Regex and Contains
+// expressions without a Key hold if any label value satisfies them.
+type LabelExpression struct {
+	Key      string   `json:"key,omitempty"`
+	Operator Operator `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// String returns the expression as key Operator (values), e.g. app In (api, web).
+func (e LabelExpression) String() string {
+	s := strings.TrimSpace(e.Key + " " + string(e.Operator))
+	if len(e.Values) > 0 {
+		s += " (" + strings.Join(e.Values, ", ") + ")"
+	}
+	return s
+}
+
+// validate reports why the expression can never be evaluated.
+func (e LabelExpression) validate() error {
+	switch e.Operator {
+	case In, NotIn, Exists, DoesNotExist:
+		if e.Key == "" {
+			return fmt.Errorf("%s expression needs a key", e.Operator)
+		}
+	case Regex:
+		for _, v := range e.Values {
+			if _, err := regexp.Compile(v); err != nil {
+				return fmt.Errorf("invalid regular expression %q: %w", v, err)
+			}
+		}
+	case Contains:
+	default:
+		return fmt.Errorf("unknown operator %q", e.Operator)
+	}
+	switch e.Operator {
+	case In, Regex, Contains:
+		if len(e.Values) == 0 {
+			return fmt.Errorf("%s expression needs values", e.Operator)
+		}
+	}
+	return nil
+}
+
+// matches reports whether labels satisfy the expression. The expression must
+// be valid.
+func (e LabelExpression) matches(labels map[string]string) bool {
+	if e.Key == "" {
+		for _, v := range labels {
+			if e.matchesValue(v) {
+				return true
+			}
+		}
+		return false
+	}
+	v, ok := labels[e.Key]
+	switch e.Operator {
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	case NotIn:
+		return !ok || !contains(e.Values, v)
+	}
+	return ok && e.matchesValue(v)
+}
+
+func (e LabelExpression) matchesValue(v string) bool {
+	for _, want := range e.Values {
+		switch e.Operator {
+		case In:
+			if v == want {
+				return true
+			}
+		case Regex:
+			if regexp.MustCompile(want).MatchString(v) {
+				return true
+			}
+		case Contains:
+			if strings.Contains(v, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadSpec reads a Spec from YAML or JSON.
+func LoadSpec(r io.Reader) (*Spec, error) {
+	var spec Spec
+	if err := decode(r, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// stackExport is the part of `pulumi stack export` LoadStackExport reads.
+type stackExport struct {
+	Deployment struct {
+		Resources []struct {
+			URN    string          `json:"urn"`
+			Type   string          `json:"type"`
+			ID     string          `json:"id"`
+			Inputs json.RawMessage `json:"inputs"`
+		} `json:"resources"`
+	} `json:"deployment"`
+}
+
+// LoadStackExport reads the scaling policies and their order from the output
+// of `pulumi stack export`. If the stack manages several clusters, clusterID
+// selects one of them.
+func LoadStackExport(r io.Reader, clusterID string) (*Spec, error) {
+	var export stackExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("reading stack export: %w", err)
+	}
+
+	type clusterInput struct {
+		ClusterID any `json:"clusterId"`
+	}
+	specs := map[string]*Spec{}
+	var clusters []string
+	specOf := func(in clusterInput) *Spec {
+		// Secret or unknown cluster IDs are not strings and are grouped
+		// under the empty ID.
+		id, _ := in.ClusterID.(string)
+		if specs[id] == nil {
+			specs[id] = &Spec{}
+			clusters = append(clusters, id)
+		}
+		return specs[id]
+	}
+
+	for _, res := range export.Deployment.Resources {
+		switch res.Type {
+		case WorkloadScalingPolicyType:
+			var in struct {
+				clusterInput
+				Policy
+			}
+			if err := json.Unmarshal(res.Inputs, &in); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", res.URN, err)
+			}
+			in.Policy.ID = res.ID
+			spec := specOf(in.clusterInput)
+			spec.Policies = append(spec.Policies, in.Policy)
+		case WorkloadScalingPolicyOrderType:
+			var in struct {
+				clusterInput
+				PolicyIDs []string `json:"policyIds"`
+			}
+			if err := json.Unmarshal(res.Inputs, &in); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", res.URN, err)
+			}
+			specOf(in.clusterInput).Order = in.PolicyIDs
+		}
+	}
+
+	switch {
+	case clusterID != "":
+		if spec, ok := specs[clusterID]; ok {
+			return spec, nil
+		}
+		return nil, fmt.Errorf("the stack has no scaling policies for cluster %s", clusterID)
+	case len(clusters) == 0:
+		return nil, fmt.Errorf("the stack has no %s resources", WorkloadScalingPolicyType)
+	case len(clusters) > 1:
+		return nil, fmt.Errorf("the stack has scaling policies for several clusters, select one of %s", strings.Join(clusters, ", "))
+	}
+	return specs[clusters[0]], nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
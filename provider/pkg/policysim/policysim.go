@@ -0,0 +1,417 @@
+// Package policysim simulates which WorkloadScalingPolicy the CAST AI
+// workload autoscaler assigns to each workload, without deploying anything.
+//
+// The assignment rules of a policy match workloads by namespace name and
+// labels, workload kind and workload labels. Policies are evaluated in the
+// order set by WorkloadScalingPolicyOrder and the first matching policy wins,
+// so a broad rule high in the order hides the policies below it. Simulate
+// evaluates the rules against the workloads of a set of Kubernetes manifests,
+// reports the policy of every workload, and flags policies that can never be
+// assigned: those without valid rules, those whose rules are repeated by a
+// higher-ordered policy, and those whose workloads all go to other policies.
+package policysim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FindingKind classifies a Finding.
+type FindingKind string
+
+const (
+	// Unreachable policies have no rule that can match any workload.
+	Unreachable FindingKind = "unreachable"
+	// Shadowed policies only match workloads, or only have rules, that a
+	// higher-ordered policy takes first.
+	Shadowed FindingKind = "shadowed"
+	// Unmatched policies match none of the simulated workloads.
+	Unmatched FindingKind = "unmatched"
+	// Unordered policies are missing from the policy order, or the order
+	// refers to a policy that does not exist.
+	Unordered FindingKind = "unordered"
+	// InvalidRule rules can never match, e.g. because of an unknown operator.
+	InvalidRule FindingKind = "invalid-rule"
+)
+
+// Finding is a problem with a policy.
+type Finding struct {
+	Kind    FindingKind `json:"kind"`
+	Policy  string      `json:"policy"`
+	Message string      `json:"message"`
+	// By lists the higher-ordered policies of a Shadowed finding.
+	By []string `json:"by,omitempty"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: policy %s %s", f.Kind, f.Policy, f.Message)
+}
+
+// Assignment is the policy a workload gets.
+type Assignment struct {
+	Workload Workload `json:"workload"`
+	// Policy is the name of the assigned policy, empty if no policy matches
+	// and the workload keeps the cluster default.
+	Policy string `json:"policy,omitempty"`
+	// Shadowed lists the lower-ordered policies that match the workload too.
+	Shadowed []string `json:"shadowed,omitempty"`
+}
+
+// Report is the result of Simulate.
+type Report struct {
+	// Order are the policy names in the order they are evaluated.
+	Order       []string     `json:"order"`
+	Assignments []Assignment `json:"assignments"`
+	Findings    []Finding    `json:"findings,omitempty"`
+}
+
+// Simulate assigns the policies of spec to the workloads of cluster.
+func Simulate(spec *Spec, cluster *Cluster) *Report {
+	report := &Report{}
+	policies := order(spec, report)
+	for _, p := range policies {
+		report.Order = append(report.Order, p.Name)
+	}
+
+	// Static checks, independent of the workloads.
+	rules := make([][]Rule, len(policies))
+	for i, p := range policies {
+		for j, rule := range p.rules() {
+			if err := rule.validate(); err != nil {
+				report.add(Finding{Kind: InvalidRule, Policy: p.Name, Message: fmt.Sprintf("rule %d %s", j+1, err)})
+				continue
+			}
+			rules[i] = append(rules[i], rule)
+		}
+	}
+	unreachable := map[int]bool{}
+	for i, p := range policies {
+		switch {
+		case len(p.rules()) == 0:
+			unreachable[i] = true
+			report.add(Finding{Kind: Unreachable, Policy: p.Name, Message: "has no assignment rules, so no workload is assigned to it"})
+		case len(rules[i]) == 0:
+			unreachable[i] = true
+			report.add(Finding{Kind: Unreachable, Policy: p.Name, Message: "has no valid assignment rules"})
+		default:
+			if by := staticShadows(policies[:i], rules[:i], rules[i]); by != nil {
+				unreachable[i] = true
+				report.add(Finding{Kind: Shadowed, Policy: p.Name, By: by,
+					Message: "only has rules that a higher-ordered policy matches first: " + strings.Join(by, ", ")})
+			}
+		}
+	}
+
+	// Assignment of the simulated workloads.
+	matched := make([]int, len(policies))
+	won := make([]int, len(policies))
+	winners := make([]map[string]bool, len(policies))
+	for _, w := range cluster.Workloads {
+		a := Assignment{Workload: w}
+		winner := -1
+		nsLabels := cluster.namespaceLabels(w.Namespace)
+		for i, p := range policies {
+			if !matchesAny(rules[i], w, nsLabels) {
+				continue
+			}
+			matched[i]++
+			if winner < 0 {
+				winner = i
+				a.Policy = p.Name
+				won[i]++
+				continue
+			}
+			a.Shadowed = append(a.Shadowed, p.Name)
+			if winners[i] == nil {
+				winners[i] = map[string]bool{}
+			}
+			winners[i][policies[winner].Name] = true
+		}
+		report.Assignments = append(report.Assignments, a)
+	}
+
+	if len(cluster.Workloads) == 0 {
+		return report
+	}
+	for i, p := range policies {
+		switch {
+		case unreachable[i]:
+		case matched[i] == 0:
+			report.add(Finding{Kind: Unmatched, Policy: p.Name, Message: "matches none of the workloads"})
+		case won[i] == 0:
+			by := keys(winners[i], report.Order)
+			report.add(Finding{Kind: Shadowed, Policy: p.Name, By: by,
+				Message: fmt.Sprintf("matches %d workloads, all assigned to higher-ordered policies: %s", matched[i], strings.Join(by, ", "))})
+		}
+	}
+	return report
+}
+
+// order returns the policies in evaluation order: the ordered ones first,
+// then the rest in spec order.
+func order(spec *Spec, report *Report) []Policy {
+	var ordered []Policy
+	used := map[int]bool{}
+	for _, ref := range spec.Order {
+		i := spec.find(ref)
+		if i < 0 {
+			report.add(Finding{Kind: Unordered, Policy: ref, Message: "is in the policy order but does not exist"})
+			continue
+		}
+		if !used[i] {
+			used[i] = true
+			ordered = append(ordered, spec.Policies[i])
+		}
+	}
+	for i, p := range spec.Policies {
+		if used[i] {
+			continue
+		}
+		if len(spec.Order) > 0 {
+			report.add(Finding{Kind: Unordered, Policy: p.Name, Message: "is missing from the policy order and is evaluated after the ordered policies"})
+		}
+		ordered = append(ordered, p)
+	}
+	return ordered
+}
+
+// find returns the index of the policy with the given ID or name.
+func (s *Spec) find(ref string) int {
+	for i, p := range s.Policies {
+		if p.ID != "" && p.ID == ref {
+			return i
+		}
+	}
+	for i, p := range s.Policies {
+		if p.Name == ref {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p Policy) rules() []Rule {
+	var rules []Rule
+	for _, ar := range p.AssignmentRules {
+		rules = append(rules, ar.Rules...)
+	}
+	return rules
+}
+
+// validate reports why a rule can never match.
+func (r Rule) validate() error {
+	if r.Namespace != nil {
+		if err := validateExpressions(r.Namespace.LabelsExpressions); err != nil {
+			return fmt.Errorf("namespace: %w", err)
+		}
+	}
+	if r.Workload != nil {
+		if err := validateExpressions(r.Workload.LabelsExpressions); err != nil {
+			return fmt.Errorf("workload: %w", err)
+		}
+		for _, gvk := range r.Workload.Gvks {
+			if _, _, _, err := parseGVK(gvk); err != nil {
+				return fmt.Errorf("workload: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateExpressions reports invalid expressions and expressions that
+// contradict each other, as label selector requirements are combined with AND.
+func validateExpressions(exprs []LabelExpression) error {
+	for _, e := range exprs {
+		if err := e.validate(); err != nil {
+			return err
+		}
+	}
+	for i, a := range exprs {
+		for _, b := range exprs[i+1:] {
+			if a.Key == "" || a.Key != b.Key {
+				continue
+			}
+			if contradicts(a, b) || contradicts(b, a) {
+				return fmt.Errorf("%s and %s never hold together", a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// contradicts reports whether two expressions on the same key exclude each
+// other.
+func contradicts(a, b LabelExpression) bool {
+	switch {
+	case a.Operator == DoesNotExist:
+		return b.Operator != NotIn && b.Operator != DoesNotExist
+	case a.Operator == In && b.Operator == In:
+		for _, v := range a.Values {
+			if contains(b.Values, v) {
+				return false
+			}
+		}
+		return true
+	case a.Operator == In && b.Operator == NotIn:
+		for _, v := range a.Values {
+			if !contains(b.Values, v) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// staticShadows returns the higher-ordered policies that take every rule of a
+// policy first: a rule is taken by an identical rule, or by a rule without
+// conditions that matches every workload. It returns nil if any rule is not
+// taken.
+func staticShadows(higher []Policy, higherRules [][]Rule, rules []Rule) []string {
+	var by []string
+	seen := map[string]bool{}
+	for _, rule := range rules {
+		taken := false
+		for i, hr := range higherRules {
+			for _, h := range hr {
+				if h.matchesAll() || reflect.DeepEqual(normalize(h), normalize(rule)) {
+					taken = true
+					if !seen[higher[i].Name] {
+						seen[higher[i].Name] = true
+						by = append(by, higher[i].Name)
+					}
+					break
+				}
+			}
+			if taken {
+				break
+			}
+		}
+		if !taken {
+			return nil
+		}
+	}
+	return by
+}
+
+// matchesAll reports whether a rule has no conditions.
+func (r Rule) matchesAll() bool {
+	return (r.Namespace == nil || len(r.Namespace.Names)+len(r.Namespace.LabelsExpressions) == 0) &&
+		(r.Workload == nil || len(r.Workload.Gvks)+len(r.Workload.LabelsExpressions) == 0)
+}
+
+// normalize drops empty conditions so equal rules compare equal.
+func normalize(r Rule) Rule {
+	if r.Namespace != nil && len(r.Namespace.Names)+len(r.Namespace.LabelsExpressions) == 0 {
+		r.Namespace = nil
+	}
+	if r.Workload != nil && len(r.Workload.Gvks)+len(r.Workload.LabelsExpressions) == 0 {
+		r.Workload = nil
+	}
+	return r
+}
+
+func matchesAny(rules []Rule, w Workload, nsLabels map[string]string) bool {
+	for _, r := range rules {
+		if r.matches(w, nsLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether a valid rule matches a workload.
+func (r Rule) matches(w Workload, nsLabels map[string]string) bool {
+	if ns := r.Namespace; ns != nil {
+		if len(ns.Names) > 0 && !contains(ns.Names, w.Namespace) {
+			return false
+		}
+		if !matchesAll(ns.LabelsExpressions, nsLabels) {
+			return false
+		}
+	}
+	if wl := r.Workload; wl != nil {
+		if len(wl.Gvks) > 0 && !matchesGVK(wl.Gvks, w) {
+			return false
+		}
+		if !matchesAll(wl.LabelsExpressions, w.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAll(exprs []LabelExpression, labels map[string]string) bool {
+	for _, e := range exprs {
+		if !e.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesGVK(gvks []string, w Workload) bool {
+	for _, gvk := range gvks {
+		kind, version, group, _ := parseGVK(gvk)
+		if !strings.EqualFold(kind, w.Kind) {
+			continue
+		}
+		if version != "" && version != w.version() {
+			continue
+		}
+		if group != "" && group != w.group() {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+var versionPattern = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+// parseGVK splits kind[.version][.group]. The second part is a version if it
+// looks like one, so groups with dots such as argoproj.io are kept whole.
+func parseGVK(gvk string) (kind, version, group string, err error) {
+	kind, rest, _ := strings.Cut(gvk, ".")
+	if kind == "" {
+		return "", "", "", fmt.Errorf("invalid gvk %q, want kind[.version][.group]", gvk)
+	}
+	if v, g, _ := strings.Cut(rest, "."); versionPattern.MatchString(v) {
+		return kind, v, g, nil
+	}
+	return kind, "", rest, nil
+}
+
+func (r *Report) add(f Finding) {
+	r.Findings = append(r.Findings, f)
+}
+
+// keys returns the keys of m in the given order.
+func keys(m map[string]bool, order []string) []string {
+	var out []string
+	for _, k := range order {
+		if m[k] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// decode reads YAML or JSON into v through its JSON tags.
+func decode(r io.Reader, v any) error {
+	var doc any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,298 @@
+package policysim
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const manifests = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: payments
+  labels:
+    tier: critical
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: payments
+  labels:
+    app: api
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: ledger
+  namespace: payments
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: batch/v1
+    kind: CronJob
+    metadata:
+      name: report
+      namespace: batch
+      labels:
+        team: data-eng
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: ignored
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: web
+  labels:
+    app: web
+`
+
+func cluster(t *testing.T) *Cluster {
+	t.Helper()
+	c := &Cluster{}
+	require.NoError(t, LoadManifests(c, strings.NewReader(manifests)))
+	return c
+}
+
+func spec(t *testing.T, doc string) *Spec {
+	t.Helper()
+	s, err := LoadSpec(strings.NewReader(doc))
+	require.NoError(t, err)
+	return s
+}
+
+func assignments(r *Report) map[string]string {
+	out := map[string]string{}
+	for _, a := range r.Assignments {
+		out[a.Workload.String()] = a.Policy
+	}
+	return out
+}
+
+func findings(r *Report) []string {
+	var out []string
+	for _, f := range r.Findings {
+		out = append(out, string(f.Kind)+" "+f.Policy)
+	}
+	return out
+}
+
+// TestLoadManifests tests that namespaces and workloads are read and other objects ignored
+func TestLoadManifests(t *testing.T) {
+	c := cluster(t)
+
+	var names []string
+	for _, w := range c.Workloads {
+		names = append(names, w.String())
+	}
+	assert.Equal(t, []string{"payments/Deployment/api", "payments/StatefulSet/ledger", "batch/CronJob/report", "default/Rollout/web"}, names)
+	assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": "payments", "tier": "critical"}, c.namespaceLabels("payments"))
+	assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": "batch"}, c.namespaceLabels("batch"))
+}
+
+// TestSimulate tests that the first matching policy in the order wins
+func TestSimulate(t *testing.T) {
+	s := spec(t, `
+order: [critical-id, jobs, default-web]
+policies:
+  - name: default-web
+    assignmentRules:
+      - rules:
+          - workload:
+              labelsExpressions:
+                - {key: app, operator: Exists}
+  - id: critical-id
+    name: critical
+    assignmentRules:
+      - rules:
+          - namespace:
+              labelsExpressions:
+                - {key: tier, operator: In, values: [critical]}
+            workload:
+              gvks: [Deployment.apps, StatefulSet.v1.apps]
+  - name: jobs
+    assignmentRules:
+      - rules:
+          - workload:
+              gvks: [CronJob]
+          - workload:
+              labelsExpressions:
+                - {operator: Regex, values: ["^data-"]}
+`)
+	r := Simulate(s, cluster(t))
+
+	assert.Equal(t, []string{"critical", "jobs", "default-web"}, r.Order)
+	assert.Equal(t, map[string]string{
+		"payments/Deployment/api":     "critical",
+		"payments/StatefulSet/ledger": "critical",
+		"batch/CronJob/report":        "jobs",
+		"default/Rollout/web":         "default-web",
+	}, assignments(r))
+	assert.Equal(t, []string{"default-web"}, r.Assignments[0].Shadowed, "api also matches default-web")
+	assert.Empty(t, r.Findings)
+}
+
+// TestSimulateShadowed tests that a policy whose workloads all go to higher-ordered policies is flagged
+func TestSimulateShadowed(t *testing.T) {
+	s := spec(t, `
+order: [payments, api]
+policies:
+  - name: payments
+    assignmentRules:
+      - rules:
+          - namespace: {names: [payments]}
+  - name: api
+    assignmentRules:
+      - rules:
+          - workload:
+              labelsExpressions:
+                - {key: app, operator: In, values: [api]}
+`)
+	r := Simulate(s, cluster(t))
+
+	require.Len(t, r.Findings, 1)
+	assert.Equal(t, Finding{
+		Kind:    Shadowed,
+		Policy:  "api",
+		By:      []string{"payments"},
+		Message: "matches 1 workloads, all assigned to higher-ordered policies: payments",
+	}, r.Findings[0])
+}
+
+// TestSimulateStatic tests the findings that do not depend on the workloads
+func TestSimulateStatic(t *testing.T) {
+	s := spec(t, `
+order: [everything, copy, missing]
+policies:
+  - name: everything
+    assignmentRules:
+      - rules:
+          - namespace: {}
+  - name: copy
+    assignmentRules:
+      - rules:
+          - namespace: {names: [payments]}
+  - name: manual
+  - name: broken
+    assignmentRules:
+      - rules:
+          - workload:
+              labelsExpressions:
+                - {key: app, operator: Exists}
+                - {key: app, operator: DoesNotExist}
+          - workload:
+              labelsExpressions:
+                - {operator: Regex, values: ["("]}
+          - workload:
+              gvks: [.apps]
+`)
+	r := Simulate(s, &Cluster{})
+
+	assert.Equal(t, []string{
+		"unordered missing",
+		"unordered manual",
+		"unordered broken",
+		"invalid-rule broken",
+		"invalid-rule broken",
+		"invalid-rule broken",
+		"shadowed copy",
+		"unreachable manual",
+		"unreachable broken",
+	}, findings(r))
+	assert.Contains(t, r.Findings[3].Message, "rule 1 workload: app Exists and app DoesNotExist never hold together")
+	assert.Contains(t, r.Findings[4].Message, "invalid regular expression")
+	assert.Equal(t, []string{"everything"}, r.Findings[6].By)
+}
+
+// TestSimulateUnmatched tests that policies matching no workload are flagged
+func TestSimulateUnmatched(t *testing.T) {
+	s := spec(t, `
+policies:
+  - name: gpu
+    assignmentRules:
+      - rules:
+          - workload:
+              labelsExpressions:
+                - {key: accelerator, operator: Contains, values: [nvidia]}
+`)
+	r := Simulate(s, cluster(t))
+
+	assert.Equal(t, []string{"unmatched gpu"}, findings(r))
+	for _, a := range r.Assignments {
+		assert.Empty(t, a.Policy, "%s keeps the cluster default", a.Workload)
+	}
+}
+
+// TestLabelExpression tests the label operators
+func TestLabelExpression(t *testing.T) {
+	labels := map[string]string{"app": "api", "team": "data-eng"}
+	for _, tc := range []struct {
+		expr LabelExpression
+		want bool
+	}{
+		{expr: LabelExpression{Key: "app", Operator: In, Values: []string{"web", "api"}}, want: true},
+		{expr: LabelExpression{Key: "app", Operator: NotIn, Values: []string{"api"}}, want: false},
+		{expr: LabelExpression{Key: "tier", Operator: NotIn, Values: []string{"api"}}, want: true},
+		{expr: LabelExpression{Key: "app", Operator: Exists}, want: true},
+		{expr: LabelExpression{Key: "tier", Operator: DoesNotExist}, want: true},
+		{expr: LabelExpression{Key: "team", Operator: Regex, Values: []string{"^data"}}, want: true},
+		{expr: LabelExpression{Operator: Contains, Values: []string{"eng"}}, want: true},
+		{expr: LabelExpression{Operator: Contains, Values: []string{"ops"}}, want: false},
+	} {
+		require.NoError(t, tc.expr.validate())
+		assert.Equal(t, tc.want, tc.expr.matches(labels), "%+v", tc.expr)
+	}
+}
+
+// TestParseGVK tests the kind[.version][.group] formats
+func TestParseGVK(t *testing.T) {
+	for gvk, want := range map[string][3]string{
+		"Deployment":                   {"Deployment", "", ""},
+		"Deployment.apps":              {"Deployment", "", "apps"},
+		"Deployment.v1.apps":           {"Deployment", "v1", "apps"},
+		"Rollout.argoproj.io":          {"Rollout", "", "argoproj.io"},
+		"Rollout.v1alpha1.argoproj.io": {"Rollout", "v1alpha1", "argoproj.io"},
+	} {
+		kind, version, group, err := parseGVK(gvk)
+		require.NoError(t, err)
+		assert.Equal(t, want, [3]string{kind, version, group}, gvk)
+	}
+}
+
+// TestLoadStackExport tests that policies and their order are read from a stack export
+func TestLoadStackExport(t *testing.T) {
+	export := `{
+  "version": 3,
+  "deployment": {
+    "resources": [
+      {"urn": "urn:pulumi:dev::p::castai:workload:WorkloadScalingPolicy::a", "type": "castai:workload:WorkloadScalingPolicy", "id": "id-a",
+       "inputs": {"clusterId": "c1", "name": "a", "assignmentRules": [{"rules": [{"namespace": {"names": ["x"]}}]}]}},
+      {"urn": "urn:pulumi:dev::p::castai:workload:WorkloadScalingPolicy::b", "type": "castai:workload:WorkloadScalingPolicy", "id": "id-b",
+       "inputs": {"clusterId": "c1", "name": "b"}},
+      {"urn": "urn:pulumi:dev::p::castai:workload:WorkloadScalingPolicyOrder::o", "type": "castai:workload:WorkloadScalingPolicyOrder", "id": "c1",
+       "inputs": {"clusterId": "c1", "policyIds": ["id-b", "id-a"]}},
+      {"urn": "urn:pulumi:dev::p::castai:workload:WorkloadScalingPolicy::c", "type": "castai:workload:WorkloadScalingPolicy", "id": "id-c",
+       "inputs": {"clusterId": "c2", "name": "c"}}
+    ]
+  }
+}`
+	_, err := LoadStackExport(strings.NewReader(export), "")
+	assert.ErrorContains(t, err, "select one of c1, c2")
+
+	s, err := LoadStackExport(strings.NewReader(export), "c1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id-b", "id-a"}, s.Order)
+	require.Len(t, s.Policies, 2)
+	assert.Equal(t, "id-a", s.Policies[0].ID)
+	assert.Equal(t, []string{"x"}, s.Policies[0].AssignmentRules[0].Rules[0].Namespace.Names)
+	assert.Equal(t, []string{"b", "a"}, Simulate(s, &Cluster{}).Order)
+
+	_, err = LoadStackExport(strings.NewReader(export), "c3")
+	assert.ErrorContains(t, err, "no scaling policies for cluster c3")
+}
@@ -0,0 +1,199 @@
+// Package readiness waits until the CAST AI agent of a cluster has connected.
+//
+// Registering a cluster returns the token the castai-agent Helm chart is
+// installed with, but the agent only connects once that release is running.
+// Until then, node configurations, autoscaler policies and other cluster
+// settings race the agent. Wait polls the cluster until it reaches a
+// Condition, fails early on statuses it can no longer recover from, and
+// reports the last status it saw when it times out.
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultInterval is the wait between two polls when Config.Interval is not set.
+const DefaultInterval = 10 * time.Second
+
+// Condition is the state Wait waits for.
+type Condition string
+
+const (
+	// Connected is reached once the agent reports online.
+	Connected Condition = "connected"
+	// Ready is reached once the agent is online and CAST AI has finished
+	// onboarding the cluster.
+	Ready Condition = "ready"
+)
+
+// Conditions lists the supported conditions.
+var Conditions = []Condition{Connected, Ready}
+
+// Reached reports whether a cluster with status s satisfies c.
+func (c Condition) Reached(s Status) bool {
+	switch c {
+	case Connected:
+		return s.AgentStatus == "online"
+	case Ready:
+		return s.AgentStatus == "online" && s.Status == "ready"
+	}
+	return false
+}
+
+// failedStatuses are cluster statuses a cluster does not leave on its own.
+var failedStatuses = map[string]bool{
+	"failed":   true,
+	"deleting": true,
+	"deleted":  true,
+	"archived": true,
+}
+
+// ErrNotFound is returned for clusters the API does not know.
+var ErrNotFound = errors.New("cluster not found")
+
+// Config configures Get and Wait.
+type Config struct {
+	// APIURL is the base URL of the CAST AI API.
+	APIURL string
+	// APIToken is the API key sent in the X-API-Key header.
+	APIToken string
+	// HTTPClient sends the API requests. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Condition is the state to wait for. It defaults to Connected.
+	Condition Condition
+	// Timeout bounds the whole wait. Zero means no limit other than the
+	// context's.
+	Timeout time.Duration
+	// Interval is the wait between two polls. It defaults to DefaultInterval.
+	Interval time.Duration
+}
+
+// Status is the state of a cluster as reported by the API.
+type Status struct {
+	// Status is the status of the cluster, e.g. connecting or ready.
+	Status string `json:"status"`
+	// AgentStatus is the status of the agent, e.g. waiting-connection or online.
+	AgentStatus string `json:"agentStatus"`
+}
+
+// TimeoutError is returned when a cluster does not reach the condition in time.
+type TimeoutError struct {
+	ClusterID string
+	Condition Condition
+	Waited    time.Duration
+	// Last is the last status read, if any.
+	Last Status
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("cluster %s is not %s after %s (status %q, agent status %q); "+
+		"check that the castai-agent Helm release is installed with the cluster token and can reach the CAST AI API",
+		e.ClusterID, e.Condition, e.Waited, e.Last.Status, e.Last.AgentStatus)
+}
+
+// Get returns the current status of a cluster.
+func Get(ctx context.Context, cfg Config, clusterID string) (Status, error) {
+	path := "/v1/kubernetes/external-clusters/" + clusterID
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(cfg.APIURL, "/")+path, nil)
+	if err != nil {
+		return Status{}, err
+	}
+	req.Header.Set("X-API-Key", cfg.APIToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Status{}, fmt.Errorf("GET %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Status{}, fmt.Errorf("cluster %s: %w", clusterID, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Status{}, fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	var s Status
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return Status{}, fmt.Errorf("GET %s: decoding response: %w", path, err)
+	}
+	return s, nil
+}
+
+// Wait polls a cluster until it reaches cfg.Condition and returns its status.
+// API errors, unknown clusters and failed or deleted clusters end the wait
+// with an error; running out of time returns a *TimeoutError.
+func Wait(ctx context.Context, cfg Config, clusterID string) (Status, error) {
+	cond := cfg.Condition
+	if cond == "" {
+		cond = Connected
+	}
+	if !cond.valid() {
+		return Status{}, fmt.Errorf("unknown condition %q", cond)
+	}
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var last Status
+	timeout := func() error {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &TimeoutError{ClusterID: clusterID, Condition: cond, Waited: time.Since(start).Round(time.Second), Last: last}
+		}
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return last, timeout()
+		case <-timer.C:
+		}
+
+		s, err := Get(ctx, cfg, clusterID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, timeout()
+			}
+			return last, err
+		}
+		last = s
+		if cond.Reached(s) {
+			return s, nil
+		}
+		if failedStatuses[s.Status] {
+			return s, fmt.Errorf("cluster %s is %s (agent status %q) and will not become %s", clusterID, s.Status, s.AgentStatus, cond)
+		}
+		timer.Reset(interval)
+	}
+}
+
+func (c Condition) valid() bool {
+	for _, known := range Conditions {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/castai/pulumi-castai/provider/pkg/fakeapi"
+)
+
+const clusterID = "11111111-1111-4111-8111-111111111111"
+
+// seed stores a cluster with the given statuses in the fake API.
+func seed(s *fakeapi.Server, status, agentStatus string) {
+	s.Put("/v1/kubernetes/external-clusters/"+clusterID, fakeapi.Object{
+		"id":          clusterID,
+		"name":        "prod",
+		"status":      status,
+		"agentStatus": agentStatus,
+	})
+}
+
+func config(s *fakeapi.Server) Config {
+	return Config{APIURL: s.URL, APIToken: s.Token(), Interval: time.Millisecond}
+}
+
+// scripted serves the given statuses in turn, repeating the last one.
+type scripted struct {
+	mu       sync.Mutex
+	statuses []Status
+	calls    int
+}
+
+func (s *scripted) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := min(s.calls, len(s.statuses)-1)
+	s.calls++
+	_ = json.NewEncoder(w).Encode(s.statuses[i])
+}
+
+// TestWaitConnected tests that a connected cluster is returned after one poll
+func TestWaitConnected(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	seed(s, "ready", "online")
+
+	status, err := Wait(context.Background(), config(s), clusterID)
+	require.NoError(t, err)
+	assert.Equal(t, Status{Status: "ready", AgentStatus: "online"}, status)
+	require.Len(t, s.Requests(), 1)
+	assert.Equal(t, "/v1/kubernetes/external-clusters/"+clusterID, s.Requests()[0].Path)
+}
+
+// TestWaitPolls tests that the cluster is polled until it reaches the condition
+func TestWaitPolls(t *testing.T) {
+	statuses := []Status{
+		{Status: "connecting", AgentStatus: "waiting-connection"},
+		{Status: "connecting", AgentStatus: "online"},
+		{Status: "ready", AgentStatus: "online"},
+	}
+	for _, tc := range []struct {
+		cond  Condition
+		calls int
+	}{
+		{cond: "", calls: 2},
+		{cond: Connected, calls: 2},
+		{cond: Ready, calls: 3},
+	} {
+		t.Run(string(tc.cond), func(t *testing.T) {
+			stub := &scripted{statuses: statuses}
+			srv := httptest.NewServer(stub)
+			defer srv.Close()
+
+			status, err := Wait(context.Background(), Config{APIURL: srv.URL, Condition: tc.cond, Interval: time.Millisecond}, clusterID)
+			require.NoError(t, err)
+			assert.Equal(t, statuses[tc.calls-1], status)
+			assert.Equal(t, tc.calls, stub.calls)
+		})
+	}
+}
+
+// TestWaitTimeout tests that the last status is reported when the wait times out
+func TestWaitTimeout(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	seed(s, "connecting", "waiting-connection")
+
+	cfg := config(s)
+	cfg.Timeout = 50 * time.Millisecond
+	_, err := Wait(context.Background(), cfg, clusterID)
+
+	var timeout *TimeoutError
+	require.ErrorAs(t, err, &timeout)
+	assert.Equal(t, Status{Status: "connecting", AgentStatus: "waiting-connection"}, timeout.Last)
+	assert.Equal(t, Connected, timeout.Condition)
+	assert.Contains(t, err.Error(), "cluster "+clusterID+" is not connected")
+	assert.Contains(t, err.Error(), `agent status "waiting-connection"`)
+	assert.Greater(t, len(s.Requests()), 1)
+}
+
+// TestWaitCanceled tests that canceling the context is not reported as a timeout
+func TestWaitCanceled(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	seed(s, "connecting", "waiting-connection")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Wait(ctx, config(s), clusterID)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// TestWaitErrors tests the errors that end the wait early
+func TestWaitErrors(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+
+	_, err := Wait(context.Background(), config(s), clusterID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	seed(s, "failed", "offline")
+	_, err = Wait(context.Background(), config(s), clusterID)
+	assert.ErrorContains(t, err, "cluster "+clusterID+" is failed")
+
+	cfg := config(s)
+	cfg.APIToken = "wrong"
+	_, err = Wait(context.Background(), cfg, clusterID)
+	assert.ErrorContains(t, err, "401")
+
+	cfg = config(s)
+	cfg.Condition = "online"
+	_, err = Wait(context.Background(), cfg, clusterID)
+	assert.ErrorContains(t, err, `unknown condition "online"`)
+}
+
+// TestGet tests that Get reads the status without waiting
+func TestGet(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	seed(s, "connecting", "waiting-connection")
+
+	status, err := Get(context.Background(), config(s), clusterID)
+	require.NoError(t, err)
+	assert.Equal(t, Status{Status: "connecting", AgentStatus: "waiting-connection"}, status)
+}
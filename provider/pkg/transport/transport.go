@@ -0,0 +1,212 @@
+// Package transport implements the http.RoundTripper that applies the
+// provider's retry, timeout and concurrency settings to CAST AI API calls.
+//
+// Requests are retried after 429 Too Many Requests and 502, 503 and 504
+// responses, which the API returns before doing any work. 500 responses and
+// network errors are only retried for idempotent methods, so a create is
+// never sent twice after the API may already have applied it.
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxBackoff caps the wait between two attempts, including waits requested by
+// a Retry-After header.
+const MaxBackoff = 30 * time.Second
+
+// Config tunes a Transport.
+type Config struct {
+	// MaxRetries is the number of times a failed request is retried. Zero
+	// disables retries.
+	MaxRetries int
+	// Backoff is the wait before the first retry. It doubles with every
+	// further retry, up to MaxBackoff.
+	Backoff time.Duration
+	// Timeout bounds every attempt, including reading the response body.
+	// Zero means no timeout.
+	Timeout time.Duration
+	// MaxConcurrent is the maximum number of requests in flight. Zero means
+	// no limit.
+	MaxConcurrent int
+}
+
+// Transport retries, times out and throttles requests sent through a base
+// http.RoundTripper.
+type Transport struct {
+	base  http.RoundTripper
+	cfg   Config
+	slots chan struct{}
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New returns a Transport sending requests through base.
+func New(base http.RoundTripper, cfg Config) *Transport {
+	t := &Transport{base: base, cfg: cfg, sleep: sleep}
+	if cfg.MaxConcurrent > 0 {
+		t.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	return t
+}
+
+// Config returns the settings of the transport.
+func (t *Transport) Config() Config {
+	return t.cfg
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.send(req, body)
+		if attempt >= t.cfg.MaxRetries || req.Context().Err() != nil || !retryable(req.Method, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send makes one attempt. The concurrency slot and the attempt timeout are
+// held until the response body is closed.
+func (t *Transport) send(req *http.Request, body []byte) (*http.Response, error) {
+	ctx := req.Context()
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var cancel context.CancelFunc = func() {}
+	if t.cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.cfg.Timeout)
+	}
+	done := func() {
+		cancel()
+		if t.slots != nil {
+			<-t.slots
+		}
+	}
+
+	out := req.Clone(ctx)
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: done}
+	return resp, nil
+}
+
+// backoff returns how long to wait before retrying after the given attempt.
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := t.cfg.Backoff
+	for i := 0; i < attempt && wait < MaxBackoff; i++ {
+		wait *= 2
+	}
+	if resp != nil {
+		if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok && after > wait {
+			wait = after
+		}
+	}
+	return min(wait, MaxBackoff)
+}
+
+// retryable reports whether an attempt that ended with resp or err may be
+// repeated.
+func retryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return idempotent(method)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusInternalServerError:
+		return idempotent(method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+// readBody reads the request body so it can be sent again on retries.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseOnClose runs release once, when the body is first closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
@@ -0,0 +1,185 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/castai/pulumi-castai/provider/pkg/fakeapi"
+)
+
+// newClient returns a client using a Transport whose waits are recorded
+// instead of slept.
+func newClient(cfg Config) (*http.Client, *[]time.Duration) {
+	t := New(http.DefaultTransport, cfg)
+	var waits []time.Duration
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return &http.Client{Transport: t}, &waits
+}
+
+// send sends an authenticated request to the fake API and returns the status code.
+func send(t *testing.T, c *http.Client, s *fakeapi.Server, method, path, body string) int {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", s.Token())
+
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode
+}
+
+// TestRetriesTooManyRequests tests that 429 responses are retried after their Retry-After
+func TestRetriesTooManyRequests(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	c, waits := newClient(Config{MaxRetries: 3, Backoff: 10 * time.Millisecond})
+
+	s.FailNext(2, http.StatusTooManyRequests)
+	status := send(t, c, s, http.MethodGet, "/v1/organizations", "")
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, s.Requests(), 3)
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, *waits)
+}
+
+// TestRetriesServerErrors tests exponential backoff on 5xx responses
+func TestRetriesServerErrors(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	c, waits := newClient(Config{MaxRetries: 3, Backoff: 10 * time.Millisecond})
+
+	s.FailNext(1, http.StatusBadGateway)
+	s.FailNext(1, http.StatusServiceUnavailable)
+	s.FailNext(1, http.StatusInternalServerError)
+	status := send(t, c, s, http.MethodGet, "/v1/organizations", "")
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, s.Requests(), 4)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond}, *waits)
+}
+
+// TestGivesUpAfterMaxRetries tests that the last failed response is returned
+func TestGivesUpAfterMaxRetries(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	c, _ := newClient(Config{MaxRetries: 2})
+
+	s.FailNext(5, http.StatusServiceUnavailable)
+	status := send(t, c, s, http.MethodGet, "/v1/organizations", "")
+
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Len(t, s.Requests(), 3)
+}
+
+// TestRetriedRequestsKeepTheirBody tests that a retried create sends the same body again
+func TestRetriedRequestsKeepTheirBody(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	c, _ := newClient(Config{MaxRetries: 1})
+
+	body := `{"name":"test-eks","eks":{"accountId":"123456789012","region":"us-west-2","clusterName":"test-eks"}}`
+	s.FailNext(1, http.StatusTooManyRequests)
+	status := send(t, c, s, http.MethodPost, "/v1/kubernetes/external-clusters", body)
+
+	assert.Equal(t, http.StatusOK, status)
+	reqs := s.Requests()
+	require.Len(t, reqs, 2)
+	assert.Equal(t, body, reqs[0].Body)
+	assert.Equal(t, body, reqs[1].Body)
+	assert.Len(t, s.List("/v1/kubernetes/external-clusters"), 1)
+}
+
+// TestCreatesAreNotRetriedOnInternalServerError tests that non-idempotent requests
+// are not repeated when the API may have applied them
+func TestCreatesAreNotRetriedOnInternalServerError(t *testing.T) {
+	s := fakeapi.Start()
+	defer s.Close()
+	c, _ := newClient(Config{MaxRetries: 3})
+
+	s.FailNext(1, http.StatusInternalServerError)
+	status := send(t, c, s, http.MethodPost, "/v1/kubernetes/external-clusters", `{"name":"test-eks"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Len(t, s.Requests(), 1)
+}
+
+// TestTimeout tests that every attempt is bounded by the timeout
+func TestTimeout(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	c, _ := newClient(Config{MaxRetries: 1, Timeout: 50 * time.Millisecond})
+
+	_, err := c.Get(srv.URL)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, calls)
+}
+
+// TestMaxConcurrent tests that no more than MaxConcurrent requests are in flight
+func TestMaxConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, peak int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	c, _ := newClient(Config{MaxConcurrent: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(srv.URL)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, peak)
+}
+
+// TestBackoff tests that waits double per attempt and are capped
+func TestBackoff(t *testing.T) {
+	tr := New(http.DefaultTransport, Config{Backoff: time.Second})
+
+	assert.Equal(t, time.Second, tr.backoff(0, nil))
+	assert.Equal(t, 4*time.Second, tr.backoff(2, nil))
+	assert.Equal(t, MaxBackoff, tr.backoff(10, nil))
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	assert.Equal(t, MaxBackoff, tr.backoff(0, resp), "Retry-After is capped")
+}
@@ -15,15 +15,27 @@
 package castai
 
 import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"unicode"
 
 	"github.com/castai/terraform-provider-castai/castai"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	tfbridgelog "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/log"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 
+	"github.com/castai/pulumi-castai/provider/pkg/instancecatalog"
 	"github.com/castai/pulumi-castai/provider/pkg/version"
 )
 
@@ -47,9 +59,28 @@ const (
 	aiOptimizerMod  = "index/aiOptimizer"
 )
 
+// providerIDDefaults maps the TF fields that resources default from the
+// provider configuration to the Pulumi config key holding the default.
+var providerIDDefaults = map[string]string{
+	"organization_id": "organizationId",
+	"cluster_id":      "defaultClusterId",
+}
+
+// providerIDEnvVars are the env vars read when the config keys in
+// providerIDDefaults are not set.
+var providerIDEnvVars = map[string]string{
+	"organizationId":   "CASTAI_ORGANIZATION_ID",
+	"defaultClusterId": "CASTAI_DEFAULT_CLUSTER_ID",
+}
+
+// bridgeMetadata is the bridge metadata tfgen writes next to schema.json.
+//
+//go:embed cmd/pulumi-resource-castai/bridge-metadata.json
+var bridgeMetadata []byte
+
 // Provider returns additional overlaid schema and metadata associated with the provider.
 func Provider() tfbridge.ProviderInfo {
-	p := shimv2.NewProvider(castai.Provider(version.Version))
+	p := shimv2.NewProvider(withHTTPSettings(withClusterReadiness(castai.Provider(version.Version))))
 
 	// Create a Pulumi provider mapping
 	prov := tfbridge.ProviderInfo{
@@ -66,6 +97,10 @@ func Provider() tfbridge.ProviderInfo {
 		Repository:        "https://github.com/castai/pulumi-castai",
 		GitHubOrg:         "castai",
 		Version:           version.Version,
+		MetadataInfo:      tfbridge.NewProviderMetadata(bridgeMetadata),
+		// The sources of terraform-provider-castai at the version in go.mod,
+		// checked out by `make upstream`, from which tfgen reads the docs.
+		UpstreamRepoPath: "./upstream",
 		Config: map[string]*tfbridge.SchemaInfo{
 			// Add any required configuration here
 			"api_token": {
@@ -80,19 +115,61 @@ func Provider() tfbridge.ProviderInfo {
 					EnvVars: []string{"CASTAI_API_URL"},
 				},
 			},
+			"organization_id": {
+				Default: &tfbridge.DefaultInfo{
+					EnvVars: []string{providerIDEnvVars["organizationId"]},
+				},
+			},
+			maxRetriesField:            httpSettingConfig(maxRetriesField),
+			retryBackoffField:          httpSettingConfig(retryBackoffField),
+			requestTimeoutField:        httpSettingConfig(requestTimeoutField),
+			maxConcurrentRequestsField: httpSettingConfig(maxConcurrentRequestsField),
 		},
+		ExtraConfig: map[string]*tfbridge.ConfigInfo{
+			"defaultClusterId": {
+				Info: &tfbridge.SchemaInfo{
+					Default: &tfbridge.DefaultInfo{
+						EnvVars: []string{providerIDEnvVars["defaultClusterId"]},
+					},
+				},
+				Schema: (&shimschema.Schema{
+					Type:        shim.TypeString,
+					Optional:    true,
+					Description: "Default CAST AI cluster ID for resources that do not set one.",
+				}).Shim(),
+			},
+		},
+		PreConfigureCallback: providerIDsFromEnv,
 		Resources: map[string]*tfbridge.ResourceInfo{
 			// Core Resources
-			"castai_eks_cluster":    {Tok: awsResource(awsMod, "EksCluster")},
-			"castai_gke_cluster":    {Tok: gcpResource(gcpMod, "GkeCluster")},
-			"castai_aks_cluster":    {Tok: azureResource(azureMod, "AksCluster")},
+			"castai_eks_cluster": {
+				Tok:    awsResource(awsMod, "EksCluster"),
+				Fields: secretFields("cluster_token"),
+			},
+			"castai_gke_cluster": {
+				Tok:    gcpResource(gcpMod, "GkeCluster"),
+				Fields: secretFields("cluster_token", "credentials_json"),
+			},
+			"castai_aks_cluster": {
+				Tok:    azureResource(azureMod, "AksCluster"),
+				Fields: secretFields("cluster_token", "client_secret"),
+			},
 			// NOTE: castai_cluster, castai_credentials, castai_cluster_token don't exist in TF provider v7.73.0
 
 			// Cluster ID resources (register existing clusters with CAST AI)
 			"castai_eks_clusterid":  {Tok: awsResource(awsMod, "EksClusterId")},
-			"castai_gke_cluster_id": {Tok: gcpResource(gcpMod, "GkeClusterId")},
+			"castai_gke_cluster_id": {
+				Tok:    gcpResource(gcpMod, "GkeClusterId"),
+				Fields: secretFields("cluster_token"),
+			},
 			"castai_eks_user_arn":   {Tok: awsResource(awsMod, "EksUserArn")}, // Deprecated but still exists in v7.73.0
 
+			// Waits for the agent of a registered cluster; not part of the TF provider
+			"castai_cluster_readiness": {
+				Tok:  castaiResource(mainMod, "ClusterReadiness"),
+				Docs: &tfbridge.DocInfo{Markdown: clusterReadinessDocs},
+			},
+
 			// Autoscaling resources
 			"castai_autoscaler": {
 				Tok: castaiResource(autoscalingMod, "Autoscaler"),
@@ -107,7 +184,10 @@ func Provider() tfbridge.ProviderInfo {
 			// Node Configuration resources
 			"castai_node_configuration":         {Tok: castaiResource(nodeConfigMod, "NodeConfiguration")},
 			"castai_node_configuration_default": {Tok: castaiResource(nodeConfigMod, "NodeConfigurationDefault")},
-			"castai_node_template":              {Tok: castaiResource(nodeConfigMod, "NodeTemplate")},
+			"castai_node_template": {
+				Tok:              castaiResource(nodeConfigMod, "NodeTemplate"),
+				PreCheckCallback: checkNodeTemplateConstraints,
+			},
 
 			// Workload Management resources
 			"castai_workload_scaling_policy":             {Tok: castaiResource(workloadMod, "WorkloadScalingPolicy")},
@@ -124,8 +204,19 @@ func Provider() tfbridge.ProviderInfo {
 			"castai_organization_members":     {Tok: castaiResource(organizationMod, "OrganizationMembers")},
 			"castai_organization_group":       {Tok: castaiResource(organizationMod, "OrganizationGroup")},
 			"castai_service_account":          {Tok: castaiResource(organizationMod, "ServiceAccount")},
-			"castai_service_account_key":      {Tok: castaiResource(organizationMod, "ServiceAccountKey")},
-			"castai_sso_connection":           {Tok: castaiResource(organizationMod, "SSOConnection")},
+			"castai_service_account_key": {
+				Tok:    castaiResource(organizationMod, "ServiceAccountKey"),
+				Fields: secretFields("token"),
+			},
+			"castai_sso_connection": {
+				Tok: castaiResource(organizationMod, "SSOConnection"),
+				Fields: map[string]*tfbridge.SchemaInfo{
+					"sync_auth_token": secretField(),
+					"aad":             {Elem: &tfbridge.SchemaInfo{Fields: secretFields("client_secret")}},
+					"oidc":            {Elem: &tfbridge.SchemaInfo{Fields: secretFields("client_secret")}},
+					"okta":            {Elem: &tfbridge.SchemaInfo{Fields: secretFields("client_secret")}},
+				},
+			},
 			"castai_role_bindings":            {Tok: castaiResource(iamMod, "RoleBindings")},
 			"castai_enterprise_group":         {Tok: castaiResource(organizationMod, "EnterpriseGroup")},
 			"castai_enterprise_role_binding":  {Tok: castaiResource(iamMod, "EnterpriseRoleBinding")},
@@ -145,9 +236,21 @@ func Provider() tfbridge.ProviderInfo {
 			"castai_cache_rule":          {Tok: castaiResource(cacheMod, "CacheRule")},
 
 			// AI Optimizer resources
-			"castai_ai_optimizer_model_registry": {Tok: castaiResource(aiOptimizerMod, "AiOptimizerModelRegistry")},
+			"castai_ai_optimizer_model_registry": {
+				Tok:    castaiResource(aiOptimizerMod, "AiOptimizerModelRegistry"),
+				Fields: secretFields("credentials"),
+			},
 			"castai_ai_optimizer_model_specs":    {Tok: castaiResource(aiOptimizerMod, "AiOptimizerModelSpecs")},
-			"castai_ai_optimizer_hosted_model":   {Tok: castaiResource(aiOptimizerMod, "AiOptimizerHostedModel")},
+			"castai_ai_optimizer_hosted_model": {
+				Tok: castaiResource(aiOptimizerMod, "AiOptimizerHostedModel"),
+				Fields: map[string]*tfbridge.SchemaInfo{
+					"vllm_config": {Elem: &tfbridge.SchemaInfo{Fields: secretFields("hugging_face_token")}},
+				},
+			},
+		},
+		// TF resources and data sources that are intentionally not bridged yet.
+		IgnoreMappings: []string{
+			"castai_edge_location",
 		},
 		DataSources: map[string]*tfbridge.DataSourceInfo{
 			// AWS Data Sources
@@ -193,6 +296,12 @@ func Provider() tfbridge.ProviderInfo {
 				"go",
 				mainPkg,
 			),
+			// The config/node module generates the `config` package, whose
+			// name Go programs from `pulumi convert` and `pulumi import` also
+			// use for the Pulumi config package.
+			PackageImportAliases: map[string]string{
+				"github.com/castai/pulumi-castai/sdk/go/castai/config": "castaiconfig",
+			},
 			GenerateResourceContainerTypes: true,
 		},
 		CSharp: &tfbridge.CSharpInfo{
@@ -209,10 +318,231 @@ func Provider() tfbridge.ProviderInfo {
 	// Add specific transformers here if needed for particular resources
 
 	prov.SetAutonaming(255, "-")
+	injectProviderIDDefaults(&prov)
+	injectJSONTransforms(&prov)
 
 	return prov
 }
 
+// providerIDFields are the TF fields, by resource, that default to the
+// provider configuration. They only cover IDs that always mean the
+// organization or cluster the provider manages: the organization_id of the
+// enterprise resources names an enterprise or one of its child
+// organizations, so they are left out.
+var providerIDFields = map[string][]string{
+	"castai_ai_optimizer_hosted_model":           {"cluster_id"},
+	"castai_autoscaler":                          {"cluster_id"},
+	"castai_cluster_readiness":                   {"cluster_id"},
+	"castai_commitments":                         {"organization_id"},
+	"castai_eks_user_arn":                        {"cluster_id"},
+	"castai_evictor_advanced_config":             {"cluster_id"},
+	"castai_hibernation_schedule":                {"organization_id"},
+	"castai_node_configuration":                  {"cluster_id"},
+	"castai_node_configuration_default":          {"cluster_id"},
+	"castai_node_template":                       {"cluster_id"},
+	"castai_organization_group":                  {"organization_id"},
+	"castai_organization_members":                {"organization_id"},
+	"castai_pod_mutation":                        {"organization_id", "cluster_id"},
+	"castai_rebalancing_job":                     {"cluster_id"},
+	"castai_reservations":                        {"organization_id"},
+	"castai_role_bindings":                       {"organization_id"},
+	"castai_service_account":                     {"organization_id"},
+	"castai_service_account_key":                 {"organization_id"},
+	"castai_workload_custom_metrics_data_source": {"cluster_id"},
+	"castai_workload_scaling_policy":             {"cluster_id"},
+	"castai_workload_scaling_policy_order":       {"cluster_id"},
+}
+
+// injectProviderIDDefaults defaults providerIDFields to the provider
+// configuration.
+func injectProviderIDDefaults(prov *tfbridge.ProviderInfo) {
+	for name, fields := range providerIDFields {
+		res, ok := prov.Resources[name]
+		if !ok {
+			continue
+		}
+		tfRes, ok := prov.P.ResourcesMap().GetOk(name)
+		if !ok {
+			continue
+		}
+		for _, field := range fields {
+			sch, ok := tfRes.Schema().GetOk(field)
+			if !ok || !(sch.Required() || sch.Optional()) {
+				continue
+			}
+			if res.Fields == nil {
+				res.Fields = map[string]*tfbridge.SchemaInfo{}
+			}
+			info := res.Fields[field]
+			if info == nil {
+				info = &tfbridge.SchemaInfo{}
+				res.Fields[field] = info
+			}
+			if info.Default != nil {
+				continue
+			}
+			info.Default = &tfbridge.DefaultInfo{Config: providerIDDefaults[field]}
+			// Without this, the default would make optional fields required outputs.
+			if sch.Optional() && !sch.Computed() {
+				info.MarkAsOptional = tfbridge.BoolRef(true)
+			}
+		}
+	}
+}
+
+// jsonValuedFields are the TF fields, by resource, that hold JSON text. The
+// API returns them reformatted, e.g. with its own key order, so they are
+// compared in canonical form.
+var jsonValuedFields = map[string][]string{
+	"castai_autoscaler":         {"autoscaler_policies_json"},
+	"castai_commitments":        {"gcp_cuds_json"},
+	"castai_node_configuration": {"kubelet_config", "docker_config"},
+	"castai_pod_mutation":       {"patch"},
+}
+
+// injectJSONTransforms rewrites the inputs and outputs of jsonValuedFields to
+// canonical JSON, so that JSON with equal meaning does not show up as a
+// change.
+func injectJSONTransforms(prov *tfbridge.ProviderInfo) {
+	for name, fields := range jsonValuedFields {
+		res, ok := prov.Resources[name]
+		if !ok {
+			continue
+		}
+		tfRes, ok := prov.P.ResourcesMap().GetOk(name)
+		if !ok {
+			continue
+		}
+		if res.Fields == nil {
+			res.Fields = map[string]*tfbridge.SchemaInfo{}
+		}
+		keys := make([]resource.PropertyKey, 0, len(fields))
+		for _, field := range fields {
+			info := res.Fields[field]
+			if info == nil {
+				info = &tfbridge.SchemaInfo{}
+				res.Fields[field] = info
+			}
+			info.Transform = canonicalJSONValue
+			keys = append(keys, resource.PropertyKey(tfbridge.TerraformToPulumiNameV2(field, tfRes.Schema(), res.Fields)))
+		}
+		res.TransformOutputs = canonicalJSONOutputs(keys...)
+	}
+}
+
+// checkNodeTemplateConstraints checks the constraints of a node template
+// against the instance catalog, failing contradicting constraints and
+// families of another cloud. Findings the catalog may be too old or partial
+// to judge, such as unknown families, are only warnings.
+func checkNodeTemplateConstraints(ctx context.Context, config, _ resource.PropertyMap) (resource.PropertyMap, error) {
+	v, ok := config["constraints"]
+	for ok && v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	if !ok || !v.IsObject() || v.ContainsUnknowns() {
+		return config, nil
+	}
+	c, err := instancecatalog.ParseConstraints(v.ObjectValue().Mappable())
+	if err != nil {
+		// Type errors are reported by the regular checks.
+		return config, nil
+	}
+	var errs []error
+	for _, f := range instancecatalog.Validate(c) {
+		if f.Warning {
+			// The bridge equips the context with a logger; a bare one, as in
+			// tests, drops the warnings.
+			if logger := tfbridgelog.TryGetLogger(ctx); logger != nil {
+				logger.Warn(f.String())
+			}
+			continue
+		}
+		errs = append(errs, errors.New(f.String()))
+	}
+	return config, errors.Join(errs...)
+}
+
+// providerIDsFromEnv fills unset ID config keys from their env vars. Field
+// defaults read the provider config as given, without its env var defaults.
+func providerIDsFromEnv(vars resource.PropertyMap, _ shim.ResourceConfig) error {
+	for key, envVar := range providerIDEnvVars {
+		if v, ok := vars[resource.PropertyKey(key)]; ok && !v.IsNull() {
+			continue
+		}
+		if value := os.Getenv(envVar); value != "" {
+			vars[resource.PropertyKey(key)] = resource.NewStringProperty(value)
+		}
+	}
+	return nil
+}
+
+// secretField marks a field as a Pulumi secret, regardless of whether the
+// upstream schema flags it as Sensitive.
+func secretField() *tfbridge.SchemaInfo {
+	return &tfbridge.SchemaInfo{Secret: tfbridge.BoolRef(true)}
+}
+
+// secretFields returns field overrides marking each of the named fields as secret.
+func secretFields(names ...string) map[string]*tfbridge.SchemaInfo {
+	fields := make(map[string]*tfbridge.SchemaInfo, len(names))
+	for _, name := range names {
+		fields[name] = secretField()
+	}
+	return fields
+}
+
+// canonicalJSONOutputs rewrites the named outputs to canonical JSON, so that
+// values read back from the API in another format compare equal to the
+// transformed inputs.
+func canonicalJSONOutputs(keys ...resource.PropertyKey) tfbridge.PropertyTransform {
+	return func(_ context.Context, outputs resource.PropertyMap) (resource.PropertyMap, error) {
+		for _, key := range keys {
+			if v, ok := outputs[key]; ok {
+				outputs[key], _ = canonicalJSONValue(v)
+			}
+		}
+		return outputs, nil
+	}
+}
+
+// canonicalJSONValue reformats a string holding JSON as compact JSON with
+// object keys in lexical order. Numbers keep their text. Other values,
+// including unknowns and invalid JSON, are returned unchanged; the upstream
+// validation reports invalid JSON.
+func canonicalJSONValue(v resource.PropertyValue) (resource.PropertyValue, error) {
+	if v.IsSecret() {
+		inner, err := canonicalJSONValue(v.SecretValue().Element)
+		return resource.MakeSecret(inner), err
+	}
+	if !v.IsString() {
+		return v, nil
+	}
+	s, err := canonicalJSON(v.StringValue())
+	if err != nil {
+		return v, nil
+	}
+	return resource.NewStringProperty(s), nil
+}
+
+func canonicalJSON(s string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+	if dec.More() {
+		return "", errors.New("unexpected data after the top-level value")
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
 // castaiResource creates a Pulumi token for a CAST AI resource from its module and name
 func castaiResource(mod string, name string) tokens.Type {
 	return tokens.Type(makeMemberToken(mod, name))
@@ -15,8 +15,13 @@
 package castai
 
 import (
+	"context"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -205,6 +210,9 @@ func TestProviderMetadata(t *testing.T) {
 	assert.Contains(t, prov.Keywords, "pulumi")
 	assert.Contains(t, prov.Keywords, "castai")
 	assert.Contains(t, prov.Keywords, "kubernetes")
+	require.NotNil(t, prov.MetadataInfo)
+	assert.Equal(t, "bridge-metadata.json", prov.MetadataInfo.Path)
+	assert.Equal(t, "./upstream", prov.UpstreamRepoPath)
 }
 
 // TestProviderConfig tests provider configuration schema
@@ -225,6 +233,99 @@ func TestProviderConfig(t *testing.T) {
 	assert.NotNil(t, apiURL.Default)
 	assert.Equal(t, "https://api.cast.ai", apiURL.Default.Value)
 	assert.Contains(t, apiURL.Default.EnvVars, "CASTAI_API_URL")
+
+	// Test organization ID configuration
+	orgID, ok := prov.Config["organization_id"]
+	require.True(t, ok, "organization_id configuration must exist")
+	require.NotNil(t, orgID.Default)
+	assert.Contains(t, orgID.Default.EnvVars, "CASTAI_ORGANIZATION_ID")
+
+	// Test default cluster ID configuration, which only exists in Pulumi
+	clusterID, ok := prov.ExtraConfig["defaultClusterId"]
+	require.True(t, ok, "defaultClusterId configuration must exist")
+	require.NotNil(t, clusterID.Info.Default)
+	assert.Contains(t, clusterID.Info.Default.EnvVars, "CASTAI_DEFAULT_CLUSTER_ID")
+	assert.True(t, clusterID.Schema.Optional(), "defaultClusterId should be optional")
+
+	// Test HTTP client settings, which are added to the upstream schema
+	for field, envVar := range httpSettingsEnvVars {
+		setting, ok := prov.Config[field]
+		require.True(t, ok, "%s configuration must exist", field)
+		require.NotNil(t, setting.Default)
+		assert.Equal(t, httpSettingsDefaults[field], setting.Default.Value)
+		assert.Contains(t, setting.Default.EnvVars, envVar)
+		_, ok = prov.P.Schema().GetOk(field)
+		assert.True(t, ok, "%s must be in the provider schema", field)
+	}
+}
+
+// TestProviderIDDefaults tests that the organization_id and cluster_id fields
+// of providerIDFields default to the provider configuration, and no others
+func TestProviderIDDefaults(t *testing.T) {
+	prov := Provider()
+
+	for _, name := range sortedKeys(prov.Resources) {
+		tfRes, ok := prov.P.ResourcesMap().GetOk(name)
+		if !ok {
+			continue
+		}
+		for field, configKey := range providerIDDefaults {
+			sch, ok := tfRes.Schema().GetOk(field)
+			if !ok {
+				continue
+			}
+			info := prov.Resources[name].Fields[field]
+			if !slices.Contains(providerIDFields[name], field) {
+				assert.True(t, info == nil || info.Default == nil || info.Default.Config == "",
+					"%s.%s should not default to the provider configuration", name, field)
+				continue
+			}
+			require.True(t, sch.Required() || sch.Optional(), "%s.%s must be settable", name, field)
+			require.NotNil(t, info, "%s.%s must have a field override", name, field)
+			require.NotNil(t, info.Default, "%s.%s must have a default", name, field)
+			assert.Equal(t, configKey, info.Default.Config, "%s.%s should default to the %s config", name, field, configKey)
+			if sch.Optional() && !sch.Computed() {
+				assert.Equal(t, tfbridge.BoolRef(true), info.MarkAsOptional,
+					"%s.%s should stay optional in outputs", name, field)
+			}
+		}
+	}
+	for name, fields := range providerIDFields {
+		tfRes, ok := prov.P.ResourcesMap().GetOk(name)
+		require.True(t, ok, "%s must be a resource", name)
+		for _, field := range fields {
+			_, ok := tfRes.Schema().GetOk(field)
+			assert.True(t, ok, "%s must have a %s field", name, field)
+		}
+	}
+
+	// The organization of enterprise resources is not the provider's own.
+	for _, name := range []string{"castai_enterprise_group", "castai_enterprise_role_binding", "castai_enterprise_service_account"} {
+		assert.NotContains(t, providerIDFields, name)
+	}
+
+	// Existing overrides are kept
+	assert.Equal(t, "clusterId", prov.Resources["castai_autoscaler"].Fields["cluster_id"].Name)
+}
+
+// TestProviderIDsFromEnv tests that unset ID config keys are read from their env vars
+func TestProviderIDsFromEnv(t *testing.T) {
+	t.Setenv("CASTAI_ORGANIZATION_ID", "env-org")
+	t.Setenv("CASTAI_DEFAULT_CLUSTER_ID", "env-cluster")
+
+	vars := resource.PropertyMap{
+		"organizationId": resource.NewStringProperty("config-org"),
+	}
+	require.NoError(t, providerIDsFromEnv(vars, nil))
+
+	assert.Equal(t, "config-org", vars["organizationId"].StringValue(), "explicit config wins over the env var")
+	assert.Equal(t, "env-cluster", vars["defaultClusterId"].StringValue())
+
+	t.Setenv("CASTAI_DEFAULT_CLUSTER_ID", "")
+	vars = resource.PropertyMap{}
+	require.NoError(t, providerIDsFromEnv(vars, nil))
+	_, ok := vars["defaultClusterId"]
+	assert.False(t, ok, "empty env vars should not set a default")
 }
 
 // TestProviderResources tests that all expected resources are mapped
@@ -261,6 +362,7 @@ func TestProviderResources(t *testing.T) {
 		"castai_allocation_group":      "castai:index:AllocationGroup",
 		"castai_commitments":           "castai:index:Commitments",
 		"castai_reservations":          "castai:index:Reservations",
+		"castai_cluster_readiness":     "castai:index:ClusterReadiness",
 		"castai_security_runtime_rule": "castai:index:SecurityRuntimeRule",
 		"castai_pod_mutation":          "castai:index:PodMutation",
 
@@ -303,7 +405,7 @@ func TestProviderResources(t *testing.T) {
 		})
 	}
 
-	// Verify exact count matches (all 37 resources from v7.73.0 parity)
+	// Verify exact count matches (all 37 resources from v7.73.0 parity, plus castai_cluster_readiness)
 	assert.Equal(t, len(expectedResources), len(prov.Resources),
 		"Expected exactly %d resources, got %d", len(expectedResources), len(prov.Resources))
 }
@@ -451,6 +553,7 @@ func TestResourceModuleAssignment(t *testing.T) {
 		{"castai_reservations", "index"},
 		{"castai_security_runtime_rule", "index"},
 		{"castai_pod_mutation", "index"},
+		{"castai_cluster_readiness", "index"},
 
 		// Organization resources
 		{"castai_enterprise_group", "organization"},
@@ -520,3 +623,235 @@ func TestDataSourceModuleAssignment(t *testing.T) {
 		})
 	}
 }
+
+// TestCredentialFieldsAreSecret tests that credential-bearing fields are marked secret in resources.go,
+// independently of the upstream Sensitive flags
+func TestCredentialFieldsAreSecret(t *testing.T) {
+	prov := Provider()
+
+	tests := []struct {
+		resource string
+		path     []string
+	}{
+		{"castai_eks_cluster", []string{"cluster_token"}},
+		{"castai_gke_cluster", []string{"cluster_token"}},
+		{"castai_gke_cluster", []string{"credentials_json"}},
+		{"castai_gke_cluster_id", []string{"cluster_token"}},
+		{"castai_aks_cluster", []string{"cluster_token"}},
+		{"castai_aks_cluster", []string{"client_secret"}},
+		{"castai_service_account_key", []string{"token"}},
+		{"castai_sso_connection", []string{"sync_auth_token"}},
+		{"castai_sso_connection", []string{"aad", "client_secret"}},
+		{"castai_sso_connection", []string{"oidc", "client_secret"}},
+		{"castai_sso_connection", []string{"okta", "client_secret"}},
+		{"castai_ai_optimizer_model_registry", []string{"credentials"}},
+		{"castai_ai_optimizer_hosted_model", []string{"vllm_config", "hugging_face_token"}},
+	}
+
+	for _, tt := range tests {
+		name := tt.resource + "." + strings.Join(tt.path, ".")
+		t.Run(name, func(t *testing.T) {
+			res, ok := prov.Resources[tt.resource]
+			require.True(t, ok, "Resource %s must exist", tt.resource)
+
+			fields := res.Fields
+			var info *tfbridge.SchemaInfo
+			for i, key := range tt.path {
+				info = fields[key]
+				require.NotNil(t, info, "%s must have a field override", name)
+				if i < len(tt.path)-1 {
+					require.NotNil(t, info.Elem, "%s must override the nested block", name)
+					fields = info.Elem.Fields
+				}
+			}
+			require.NotNil(t, info.Secret, "%s must set Secret", name)
+			assert.True(t, *info.Secret, "%s should be marked as secret", name)
+		})
+	}
+}
+
+// TestJSONValuedFields tests that JSON fields the API returns reformatted
+// compare equal to the inputs they were created from
+func TestJSONValuedFields(t *testing.T) {
+	prov := Provider()
+
+	tests := []struct {
+		resource string
+		field    string
+		output   resource.PropertyKey
+		input    string
+		api      string
+	}{
+		{
+			resource: "castai_autoscaler",
+			field:    "autoscaler_policies_json",
+			output:   "autoscalerPoliciesJson",
+			input: `{
+  "enabled": true,
+  "unschedulablePods": {"enabled": true, "headroom": {"cpuPercentage": 10, "memoryPercentage": 10}},
+  "nodeDownscaler": {"emptyNodes": {"enabled": true, "delaySeconds": 300}}
+}`,
+			api: `{"nodeDownscaler":{"emptyNodes":{"delaySeconds":300,"enabled":true}},"enabled":true,` +
+				`"unschedulablePods":{"headroom":{"memoryPercentage":10,"cpuPercentage":10},"enabled":true}}`,
+		},
+		{
+			resource: "castai_node_configuration",
+			field:    "kubelet_config",
+			output:   "kubeletConfig",
+			input:    `{"registryBurst": 20, "registryPullQPS": 10, "evictionHard": {"nodefs.available": "10%", "memory.available": "200Mi"}}`,
+			api:      "{\n  \"evictionHard\": {\n    \"memory.available\": \"200Mi\",\n    \"nodefs.available\": \"10%\"\n  },\n  \"registryPullQPS\": 10,\n  \"registryBurst\": 20\n}",
+		},
+		{
+			resource: "castai_node_configuration",
+			field:    "docker_config",
+			output:   "dockerConfig",
+			input:    `{"max-concurrent-downloads": 10, "log-opts": {"max-size": "10m", "max-file": "3"}}`,
+			api:      `{"log-opts":{"max-file":"3","max-size":"10m"},"max-concurrent-downloads":10}`,
+		},
+		{
+			resource: "castai_pod_mutation",
+			field:    "patch",
+			output:   "patch",
+			input: `[
+  {"op": "add", "path": "/metadata/labels/team", "value": "platform"},
+  {"op": "replace", "path": "/spec/priorityClassName", "value": "high"}
+]`,
+			api: `[{"path":"/metadata/labels/team","op":"add","value":"platform"},{"value":"high","op":"replace","path":"/spec/priorityClassName"}]`,
+		},
+		{
+			resource: "castai_commitments",
+			field:    "gcp_cuds_json",
+			output:   "gcpCudsJson",
+			input:    `[{"name": "cud-1", "plan": "TWELVE_MONTH", "resources": [{"type": "VCPU", "amount": "16"}, {"type": "MEMORY", "amount": "65536"}]}]`,
+			api:      `[{"resources":[{"amount":"16","type":"VCPU"},{"amount":"65536","type":"MEMORY"}],"plan":"TWELVE_MONTH","name":"cud-1"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resource+"."+tt.field, func(t *testing.T) {
+			res, ok := prov.Resources[tt.resource]
+			require.True(t, ok, "Resource %s must exist", tt.resource)
+			info := res.Fields[tt.field]
+			require.NotNil(t, info, "%s must have a field override", tt.field)
+			require.NotNil(t, info.Transform, "%s must set Transform", tt.field)
+			require.NotNil(t, res.TransformOutputs, "%s must normalize its outputs", tt.resource)
+
+			checked, err := info.Transform(resource.NewStringProperty(tt.input))
+			require.NoError(t, err)
+			again, err := info.Transform(checked)
+			require.NoError(t, err)
+			assert.Equal(t, checked, again, "the transform must be idempotent")
+
+			outputs, err := res.TransformOutputs(context.Background(), resource.PropertyMap{
+				tt.output: resource.NewStringProperty(tt.api),
+				"id":      resource.NewStringProperty(`{ "id": 1 }`),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, checked, outputs[tt.output], "the API value must equal the input")
+			assert.Equal(t, `{ "id": 1 }`, outputs["id"].StringValue(), "other outputs must be unchanged")
+		})
+	}
+}
+
+// TestCanonicalJSONValue tests the normalization of JSON-valued fields
+func TestCanonicalJSONValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    resource.PropertyValue
+		expected resource.PropertyValue
+	}{
+		{
+			name:     "nested keys are sorted",
+			input:    resource.NewStringProperty(`{"b": [{"y": 1, "x": 2}], "a": null}`),
+			expected: resource.NewStringProperty(`{"a":null,"b":[{"x":2,"y":1}]}`),
+		},
+		{
+			name:     "numbers keep their text",
+			input:    resource.NewStringProperty(`{"podPidsLimit": 12345678901234567890, "ratio": 1.50}`),
+			expected: resource.NewStringProperty(`{"podPidsLimit":12345678901234567890,"ratio":1.50}`),
+		},
+		{
+			name:     "html characters are not escaped",
+			input:    resource.NewStringProperty(`{"selector": "a && b <c>"}`),
+			expected: resource.NewStringProperty(`{"selector":"a && b <c>"}`),
+		},
+		{
+			name:     "invalid JSON is left for validation",
+			input:    resource.NewStringProperty(`{"maxPods": `),
+			expected: resource.NewStringProperty(`{"maxPods": `),
+		},
+		{
+			name:     "secrets stay secret",
+			input:    resource.MakeSecret(resource.NewStringProperty(`{ "token": "x" }`)),
+			expected: resource.MakeSecret(resource.NewStringProperty(`{"token":"x"}`)),
+		},
+		{
+			name:     "unknowns are unchanged",
+			input:    resource.MakeComputed(resource.NewStringProperty("")),
+			expected: resource.MakeComputed(resource.NewStringProperty("")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := canonicalJSONValue(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// TestCheckNodeTemplateConstraints tests that node template constraints are
+// checked against the instance catalog
+func TestCheckNodeTemplateConstraints(t *testing.T) {
+	constraints := func(v map[string]any) resource.PropertyMap {
+		return resource.NewPropertyMapFromMap(map[string]any{"name": "default", "constraints": v})
+	}
+	tests := []struct {
+		name   string
+		config resource.PropertyMap
+		errMsg string
+	}{
+		{
+			name:   "no constraints",
+			config: resource.NewPropertyMapFromMap(map[string]any{"name": "default"}),
+		},
+		{
+			name:   "satisfiable constraints",
+			config: constraints(map[string]any{"minCpu": 2, "maxCpu": 8, "instanceFamilies": map[string]any{"includes": []any{"m5"}}}),
+		},
+		{
+			name:   "conflicting range",
+			config: constraints(map[string]any{"minCpu": 8, "maxCpu": 4}),
+			errMsg: "constraints.minCpu: 8 is greater than maxCpu 4",
+		},
+		{
+			name:   "family of another cloud",
+			config: constraints(map[string]any{"aws": map[string]any{}, "instanceFamilies": map[string]any{"includes": []any{"n2"}}}),
+			errMsg: "constraints.instanceFamilies.includes[0]: n2 is a GCP instance family, but the template is for AWS",
+		},
+		{
+			name:   "family missing from the catalog only warns",
+			config: constraints(map[string]any{"aws": map[string]any{}, "instanceFamilies": map[string]any{"includes": []any{"r5d"}}}),
+		},
+		{
+			name: "unknown constraints are not checked",
+			config: resource.PropertyMap{"constraints": resource.NewObjectProperty(resource.PropertyMap{
+				"minCpu": resource.NewNumberProperty(8),
+				"maxCpu": resource.MakeComputed(resource.NewNumberProperty(0)),
+			})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checked, err := checkNodeTemplateConstraints(context.Background(), tt.config, nil)
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.config, checked)
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package castai
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goSDKDir is the generated Go SDK, relative to the provider directory.
+var goSDKDir = filepath.Join("..", "sdk", "go", "castai")
+
+// secretLooking matches TF field names that usually carry credentials.
+var secretLooking = regexp.MustCompile(`token|secret|credentials|password|private_key`)
+
+// notSecretSuffixes are suffixes of fields that match secretLooking but only
+// reference a credential, such as `credentials_id` or `secret_name`.
+var notSecretSuffixes = []string{"_id", "_ids", "_name", "_arn"}
+
+// knownUnmarkedSecrets lists credential fields that are known to be neither
+// Sensitive upstream nor overridden in resources.go. Entries are
+// "<tf resource>.<field path>". Keep this empty.
+var knownUnmarkedSecrets = map[string]bool{}
+
+// TestAllResourcesMapped tests that every TF resource is mapped exactly once
+func TestAllResourcesMapped(t *testing.T) {
+	prov := Provider()
+	ignored := ignoredMappings(prov)
+
+	for _, name := range upstreamNames(prov.P.ResourcesMap()) {
+		if ignored[name] {
+			continue
+		}
+		_, ok := prov.Resources[name]
+		assert.True(t, ok, "TF resource %s is not mapped in resources.go", name)
+	}
+	for name := range prov.Resources {
+		_, ok := prov.P.ResourcesMap().GetOk(name)
+		assert.True(t, ok, "resources.go maps %s, which does not exist in the TF provider", name)
+	}
+
+	tokens := map[string][]string{}
+	for name, res := range prov.Resources {
+		tokens[string(res.Tok)] = append(tokens[string(res.Tok)], name)
+	}
+	assertUnique(t, tokens)
+}
+
+// TestAllDataSourcesMapped tests that every TF data source is mapped exactly once
+func TestAllDataSourcesMapped(t *testing.T) {
+	prov := Provider()
+	ignored := ignoredMappings(prov)
+
+	for _, name := range upstreamNames(prov.P.DataSourcesMap()) {
+		if ignored[name] {
+			continue
+		}
+		_, ok := prov.DataSources[name]
+		assert.True(t, ok, "TF data source %s is not mapped in resources.go", name)
+	}
+	for name := range prov.DataSources {
+		_, ok := prov.P.DataSourcesMap().GetOk(name)
+		assert.True(t, ok, "resources.go maps %s, which does not exist in the TF provider", name)
+	}
+
+	tokens := map[string][]string{}
+	for name, ds := range prov.DataSources {
+		tokens[string(ds.Tok)] = append(tokens[string(ds.Tok)], name)
+	}
+	assertUnique(t, tokens)
+}
+
+// TestTokensDoNotCollideCaseInsensitively tests that no two tokens differ only in case,
+// which breaks SDK generation on case-insensitive file systems
+func TestTokensDoNotCollideCaseInsensitively(t *testing.T) {
+	prov := Provider()
+
+	seen := map[string]string{}
+	check := func(tok string) {
+		key := strings.ToLower(tok)
+		if other, ok := seen[key]; ok && other != tok {
+			t.Errorf("token %s collides with %s", tok, other)
+		}
+		seen[key] = tok
+	}
+	for _, name := range sortedKeys(prov.Resources) {
+		check(string(prov.Resources[name].Tok))
+	}
+	for _, name := range sortedKeys(prov.DataSources) {
+		check(string(prov.DataSources[name].Tok))
+	}
+}
+
+// TestTokenModulesMatchGoSDK tests that every token is generated into the Go SDK package its module implies
+func TestTokenModulesMatchGoSDK(t *testing.T) {
+	if _, err := os.Stat(goSDKDir); err != nil {
+		t.Skipf("Go SDK not found at %s", goSDKDir)
+	}
+	prov := Provider()
+
+	var toks []string
+	for _, res := range prov.Resources {
+		toks = append(toks, string(res.Tok))
+	}
+	for _, ds := range prov.DataSources {
+		toks = append(toks, string(ds.Tok))
+	}
+	sort.Strings(toks)
+
+	sources := map[string]string{}
+	for _, tok := range toks {
+		dir := goPackageDir(tok)
+		src, ok := sources[dir]
+		if !ok {
+			src = readGoPackage(t, filepath.Join(goSDKDir, dir))
+			sources[dir] = src
+		}
+		assert.Contains(t, src, `"`+tok+`"`,
+			"token %s should be generated into sdk/go/castai/%s; check its module in resources.go", tok, dir)
+	}
+}
+
+// TestSecretLookingFieldsAreSecret tests that credential-bearing fields are marked secret,
+// either upstream (Sensitive) or by a SchemaInfo override
+func TestSecretLookingFieldsAreSecret(t *testing.T) {
+	prov := Provider()
+
+	var unmarked []string
+	prov.P.ResourcesMap().Range(func(name string, res shim.Resource) bool {
+		var fields map[string]*tfbridge.SchemaInfo
+		if info, ok := prov.Resources[name]; ok {
+			fields = info.Fields
+		}
+		unmarked = append(unmarked, unmarkedSecrets(name, res.Schema(), fields)...)
+		return true
+	})
+	prov.P.DataSourcesMap().Range(func(name string, ds shim.Resource) bool {
+		var fields map[string]*tfbridge.SchemaInfo
+		if info, ok := prov.DataSources[name]; ok {
+			fields = info.Fields
+		}
+		unmarked = append(unmarked, unmarkedSecrets("data."+name, ds.Schema(), fields)...)
+		return true
+	})
+	sort.Strings(unmarked)
+
+	for _, field := range unmarked {
+		if knownUnmarkedSecrets[field] {
+			continue
+		}
+		t.Errorf("%s looks like a credential but is not marked secret; add `Secret: tfbridge.BoolRef(true)` in resources.go", field)
+	}
+	for field := range knownUnmarkedSecrets {
+		assert.Contains(t, unmarked, field, "%s is marked secret now; remove it from knownUnmarkedSecrets", field)
+	}
+}
+
+// TestGoPackageDir tests how token modules map to Go SDK directories
+func TestGoPackageDir(t *testing.T) {
+	tests := map[string]string{
+		"castai:aws:EksCluster":                           "",
+		"castai:index:PodMutation":                        "",
+		"castai:index/aiOptimizer:AiOptimizerHostedModel": "",
+		"castai:config/node:NodeTemplate":                 "config",
+		"castai:organization:getOrganization":             "",
+	}
+	for tok, expected := range tests {
+		assert.Equal(t, expected, goPackageDir(tok), tok)
+	}
+}
+
+// goPackageDir returns the Go SDK directory of a token. It mirrors the module
+// format tfgen uses ("(.*)(?:/[^/]*)"): only the part of the module before the
+// last slash becomes a package, and "index" is the root package.
+func goPackageDir(tok string) string {
+	parts := strings.Split(tok, ":")
+	if len(parts) != 3 {
+		return ""
+	}
+	i := strings.LastIndex(parts[1], "/")
+	if i < 0 {
+		return ""
+	}
+	dir := parts[1][:i]
+	if dir == "index" || strings.HasPrefix(dir, "index/") {
+		return ""
+	}
+	return dir
+}
+
+// readGoPackage returns the concatenated Go sources of a directory.
+func readGoPackage(t *testing.T, dir string) string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+
+	var b strings.Builder
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		require.NoError(t, err)
+		b.Write(data)
+	}
+	return b.String()
+}
+
+// unmarkedSecrets returns the secret-looking fields of a schema, including
+// nested blocks, that are neither Sensitive nor overridden as Secret.
+func unmarkedSecrets(path string, schema shim.SchemaMap, infos map[string]*tfbridge.SchemaInfo) []string {
+	var out []string
+	schema.Range(func(key string, field shim.Schema) bool {
+		info := infos[key]
+		fieldPath := path + "." + key
+		if isSecretLooking(key) && !field.Sensitive() && !(info != nil && info.Secret != nil && *info.Secret) {
+			out = append(out, fieldPath)
+		}
+		if block, ok := field.Elem().(shim.Resource); ok {
+			var nested map[string]*tfbridge.SchemaInfo
+			if info != nil {
+				nested = info.Fields
+				if nested == nil && info.Elem != nil {
+					nested = info.Elem.Fields
+				}
+			}
+			out = append(out, unmarkedSecrets(fieldPath, block.Schema(), nested)...)
+		}
+		return true
+	})
+	return out
+}
+
+func isSecretLooking(name string) bool {
+	if !secretLooking.MatchString(name) {
+		return false
+	}
+	for _, suffix := range notSecretSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+func upstreamNames(m shim.ResourceMap) []string {
+	var names []string
+	m.Range(func(name string, _ shim.Resource) bool {
+		names = append(names, name)
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+func ignoredMappings(prov tfbridge.ProviderInfo) map[string]bool {
+	ignored := map[string]bool{}
+	for _, name := range prov.IgnoreMappings {
+		ignored[name] = true
+	}
+	return ignored
+}
+
+func assertUnique(t *testing.T, tokens map[string][]string) {
+	t.Helper()
+	for tok, names := range tokens {
+		if len(names) > 1 {
+			sort.Strings(names)
+			t.Errorf("token %s is mapped more than once: %s", tok, strings.Join(names, ", "))
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -56,9 +56,6 @@ func NewAiOptimizerHostedModel(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.ModelSpecsId == nil {
 		return nil, errors.New("invalid value for required argument 'ModelSpecsId'")
 	}
@@ -166,7 +163,7 @@ func (AiOptimizerHostedModelState) ElementType() reflect.Type {
 
 type aiOptimizerHostedModelArgs struct {
 	// CAST AI cluster ID where the model will be deployed.
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// List of edge location IDs where the model can be deployed.
 	EdgeLocationIds []string `pulumi:"edgeLocationIds"`
 	// Fallback model settings.
@@ -190,7 +187,7 @@ type aiOptimizerHostedModelArgs struct {
 // The set of arguments for constructing a AiOptimizerHostedModel resource.
 type AiOptimizerHostedModelArgs struct {
 	// CAST AI cluster ID where the model will be deployed.
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// List of edge location IDs where the model can be deployed.
 	EdgeLocationIds pulumi.StringArrayInput
 	// Fallback model settings.
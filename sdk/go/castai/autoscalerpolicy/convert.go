@@ -0,0 +1,315 @@
+package autoscalerpolicy
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscaling"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// ToSettingsArgs converts the policy into the typed autoscalerSettings input of
+// castai.Autoscaler. Unset fields are left nil so the provider keeps its defaults.
+func (p *Policy) ToSettingsArgs() *autoscaling.AutoscalerAutoscalerSettingsArgs {
+	if p == nil {
+		return nil
+	}
+	args := &autoscaling.AutoscalerAutoscalerSettingsArgs{
+		Enabled:                             pulumi.BoolPtrFromPtr(p.Enabled),
+		IsScopedMode:                        pulumi.BoolPtrFromPtr(p.IsScopedMode),
+		NodeTemplatesPartialMatchingEnabled: pulumi.BoolPtrFromPtr(p.NodeTemplatesPartialMatchingEnabled),
+	}
+	if up := p.UnschedulablePods; up != nil {
+		upArgs := &autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsArgs{
+			Enabled:                pulumi.BoolPtrFromPtr(up.Enabled),
+			CustomInstancesEnabled: pulumi.BoolPtrFromPtr(up.CustomInstancesEnabled),
+		}
+		if h := up.Headroom; h != nil {
+			upArgs.Headroom = &autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsHeadroomArgs{
+				Enabled:          pulumi.BoolPtrFromPtr(h.Enabled),
+				CpuPercentage:    pulumi.IntPtrFromPtr(h.CpuPercentage),
+				MemoryPercentage: pulumi.IntPtrFromPtr(h.MemoryPercentage),
+			}
+		}
+		if h := up.HeadroomSpot; h != nil {
+			upArgs.HeadroomSpot = &autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsHeadroomSpotArgs{
+				Enabled:          pulumi.BoolPtrFromPtr(h.Enabled),
+				CpuPercentage:    pulumi.IntPtrFromPtr(h.CpuPercentage),
+				MemoryPercentage: pulumi.IntPtrFromPtr(h.MemoryPercentage),
+			}
+		}
+		if nc := up.NodeConstraints; nc != nil {
+			upArgs.NodeConstraints = &autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsNodeConstraintsArgs{
+				Enabled:     pulumi.BoolPtrFromPtr(nc.Enabled),
+				MinCpuCores: pulumi.IntPtrFromPtr(nc.MinCpuCores),
+				MaxCpuCores: pulumi.IntPtrFromPtr(nc.MaxCpuCores),
+				MinRamMib:   pulumi.IntPtrFromPtr(nc.MinRamMib),
+				MaxRamMib:   pulumi.IntPtrFromPtr(nc.MaxRamMib),
+			}
+		}
+		if pp := up.PodPinner; pp != nil {
+			upArgs.PodPinner = &autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsPodPinnerArgs{
+				Enabled: pulumi.BoolPtrFromPtr(pp.Enabled),
+			}
+		}
+		args.UnschedulablePods = upArgs
+	}
+	if si := p.SpotInstances; si != nil {
+		siArgs := &autoscaling.AutoscalerAutoscalerSettingsSpotInstancesArgs{
+			Enabled:                         pulumi.BoolPtrFromPtr(si.Enabled),
+			MaxReclaimRate:                  pulumi.IntPtrFromPtr(si.MaxReclaimRate),
+			SpotDiversityEnabled:            pulumi.BoolPtrFromPtr(si.SpotDiversityEnabled),
+			SpotDiversityPriceIncreaseLimit: pulumi.IntPtrFromPtr(si.SpotDiversityPriceIncreaseLimitPercent),
+		}
+		if sb := si.SpotBackups; sb != nil {
+			siArgs.SpotBackups = &autoscaling.AutoscalerAutoscalerSettingsSpotInstancesSpotBackupsArgs{
+				Enabled:                      pulumi.BoolPtrFromPtr(sb.Enabled),
+				SpotBackupRestoreRateSeconds: pulumi.IntPtrFromPtr(sb.SpotBackupRestoreRateSeconds),
+			}
+		}
+		if sip := si.SpotInterruptionPredictions; sip != nil {
+			siArgs.SpotInterruptionPredictions = &autoscaling.AutoscalerAutoscalerSettingsSpotInstancesSpotInterruptionPredictionsArgs{
+				Enabled:                         pulumi.BoolPtrFromPtr(sip.Enabled),
+				SpotInterruptionPredictionsType: pulumi.StringPtrFromPtr(sip.Type),
+			}
+		}
+		args.SpotInstances = siArgs
+	}
+	if cl := p.ClusterLimits; cl != nil {
+		clArgs := &autoscaling.AutoscalerAutoscalerSettingsClusterLimitsArgs{
+			Enabled: pulumi.BoolPtrFromPtr(cl.Enabled),
+		}
+		if cpu := cl.Cpu; cpu != nil {
+			clArgs.Cpu = &autoscaling.AutoscalerAutoscalerSettingsClusterLimitsCpuArgs{
+				MinCores: pulumi.IntPtrFromPtr(cpu.MinCores),
+				MaxCores: pulumi.IntPtrFromPtr(cpu.MaxCores),
+			}
+		}
+		args.ClusterLimits = clArgs
+	}
+	if nd := p.NodeDownscaler; nd != nil {
+		ndArgs := &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerArgs{
+			Enabled: pulumi.BoolPtrFromPtr(nd.Enabled),
+		}
+		if en := nd.EmptyNodes; en != nil {
+			ndArgs.EmptyNodes = &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerEmptyNodesArgs{
+				Enabled:      pulumi.BoolPtrFromPtr(en.Enabled),
+				DelaySeconds: pulumi.IntPtrFromPtr(en.DelaySeconds),
+			}
+		}
+		if ev := nd.Evictor; ev != nil {
+			ndArgs.Evictor = &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerEvictorArgs{
+				Enabled:                           pulumi.BoolPtrFromPtr(ev.Enabled),
+				DryRun:                            pulumi.BoolPtrFromPtr(ev.DryRun),
+				AggressiveMode:                    pulumi.BoolPtrFromPtr(ev.AggressiveMode),
+				ScopedMode:                        pulumi.BoolPtrFromPtr(ev.ScopedMode),
+				CycleInterval:                     pulumi.StringPtrFromPtr(ev.CycleInterval),
+				NodeGracePeriodMinutes:            pulumi.IntPtrFromPtr(ev.NodeGracePeriodMinutes),
+				PodEvictionFailureBackOffInterval: pulumi.StringPtrFromPtr(ev.PodEvictionFailureBackOffInterval),
+				IgnorePodDisruptionBudgets:        pulumi.BoolPtrFromPtr(ev.IgnorePodDisruptionBudgets),
+			}
+		}
+		args.NodeDownscaler = ndArgs
+	}
+	return args
+}
+
+// FromSettingsArgs builds a Policy from existing autoscalerSettings inputs.
+//
+// Only plain inputs such as pulumi.Bool, pulumi.IntPtr or nested *Args values
+// can be converted. Inputs that are pulumi.Output values are not known until
+// deployment and produce an error.
+func FromSettingsArgs(args *autoscaling.AutoscalerAutoscalerSettingsArgs) (*Policy, error) {
+	if args == nil {
+		return nil, nil
+	}
+	c := &converter{}
+	p := &Policy{
+		Enabled:                             c.bool("enabled", args.Enabled),
+		IsScopedMode:                        c.bool("isScopedMode", args.IsScopedMode),
+		NodeTemplatesPartialMatchingEnabled: c.bool("nodeTemplatesPartialMatchingEnabled", args.NodeTemplatesPartialMatchingEnabled),
+	}
+
+	var up autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsArgs
+	if c.args("unschedulablePods", args.UnschedulablePods, &up) {
+		p.UnschedulablePods = &UnschedulablePods{
+			Enabled:                c.bool("unschedulablePods.enabled", up.Enabled),
+			CustomInstancesEnabled: c.bool("unschedulablePods.customInstancesEnabled", up.CustomInstancesEnabled),
+		}
+		var h autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsHeadroomArgs
+		if c.args("unschedulablePods.headroom", up.Headroom, &h) {
+			p.UnschedulablePods.Headroom = &Headroom{
+				Enabled:          c.bool("unschedulablePods.headroom.enabled", h.Enabled),
+				CpuPercentage:    c.int("unschedulablePods.headroom.cpuPercentage", h.CpuPercentage),
+				MemoryPercentage: c.int("unschedulablePods.headroom.memoryPercentage", h.MemoryPercentage),
+			}
+		}
+		var hs autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsHeadroomSpotArgs
+		if c.args("unschedulablePods.headroomSpot", up.HeadroomSpot, &hs) {
+			p.UnschedulablePods.HeadroomSpot = &Headroom{
+				Enabled:          c.bool("unschedulablePods.headroomSpot.enabled", hs.Enabled),
+				CpuPercentage:    c.int("unschedulablePods.headroomSpot.cpuPercentage", hs.CpuPercentage),
+				MemoryPercentage: c.int("unschedulablePods.headroomSpot.memoryPercentage", hs.MemoryPercentage),
+			}
+		}
+		var nc autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsNodeConstraintsArgs
+		if c.args("unschedulablePods.nodeConstraints", up.NodeConstraints, &nc) {
+			p.UnschedulablePods.NodeConstraints = &NodeConstraints{
+				Enabled:     c.bool("unschedulablePods.nodeConstraints.enabled", nc.Enabled),
+				MinCpuCores: c.int("unschedulablePods.nodeConstraints.minCpuCores", nc.MinCpuCores),
+				MaxCpuCores: c.int("unschedulablePods.nodeConstraints.maxCpuCores", nc.MaxCpuCores),
+				MinRamMib:   c.int("unschedulablePods.nodeConstraints.minRamMib", nc.MinRamMib),
+				MaxRamMib:   c.int("unschedulablePods.nodeConstraints.maxRamMib", nc.MaxRamMib),
+			}
+		}
+		var pp autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsPodPinnerArgs
+		if c.args("unschedulablePods.podPinner", up.PodPinner, &pp) {
+			p.UnschedulablePods.PodPinner = &PodPinner{
+				Enabled: c.bool("unschedulablePods.podPinner.enabled", pp.Enabled),
+			}
+		}
+	}
+
+	var si autoscaling.AutoscalerAutoscalerSettingsSpotInstancesArgs
+	if c.args("spotInstances", args.SpotInstances, &si) {
+		p.SpotInstances = &SpotInstances{
+			Enabled:                                c.bool("spotInstances.enabled", si.Enabled),
+			MaxReclaimRate:                         c.int("spotInstances.maxReclaimRate", si.MaxReclaimRate),
+			SpotDiversityEnabled:                   c.bool("spotInstances.spotDiversityEnabled", si.SpotDiversityEnabled),
+			SpotDiversityPriceIncreaseLimitPercent: c.int("spotInstances.spotDiversityPriceIncreaseLimit", si.SpotDiversityPriceIncreaseLimit),
+		}
+		var sb autoscaling.AutoscalerAutoscalerSettingsSpotInstancesSpotBackupsArgs
+		if c.args("spotInstances.spotBackups", si.SpotBackups, &sb) {
+			p.SpotInstances.SpotBackups = &SpotBackups{
+				Enabled:                      c.bool("spotInstances.spotBackups.enabled", sb.Enabled),
+				SpotBackupRestoreRateSeconds: c.int("spotInstances.spotBackups.spotBackupRestoreRateSeconds", sb.SpotBackupRestoreRateSeconds),
+			}
+		}
+		var sip autoscaling.AutoscalerAutoscalerSettingsSpotInstancesSpotInterruptionPredictionsArgs
+		if c.args("spotInstances.spotInterruptionPredictions", si.SpotInterruptionPredictions, &sip) {
+			p.SpotInstances.SpotInterruptionPredictions = &SpotInterruptionPredictions{
+				Enabled: c.bool("spotInstances.spotInterruptionPredictions.enabled", sip.Enabled),
+				Type:    c.string("spotInstances.spotInterruptionPredictions.spotInterruptionPredictionsType", sip.SpotInterruptionPredictionsType),
+			}
+		}
+	}
+
+	var cl autoscaling.AutoscalerAutoscalerSettingsClusterLimitsArgs
+	if c.args("clusterLimits", args.ClusterLimits, &cl) {
+		p.ClusterLimits = &ClusterLimits{
+			Enabled: c.bool("clusterLimits.enabled", cl.Enabled),
+		}
+		var cpu autoscaling.AutoscalerAutoscalerSettingsClusterLimitsCpuArgs
+		if c.args("clusterLimits.cpu", cl.Cpu, &cpu) {
+			p.ClusterLimits.Cpu = &CpuLimit{
+				MinCores: c.int("clusterLimits.cpu.minCores", cpu.MinCores),
+				MaxCores: c.int("clusterLimits.cpu.maxCores", cpu.MaxCores),
+			}
+		}
+	}
+
+	var nd autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerArgs
+	if c.args("nodeDownscaler", args.NodeDownscaler, &nd) {
+		p.NodeDownscaler = &NodeDownscaler{
+			Enabled: c.bool("nodeDownscaler.enabled", nd.Enabled),
+		}
+		var en autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerEmptyNodesArgs
+		if c.args("nodeDownscaler.emptyNodes", nd.EmptyNodes, &en) {
+			p.NodeDownscaler.EmptyNodes = &EmptyNodes{
+				Enabled:      c.bool("nodeDownscaler.emptyNodes.enabled", en.Enabled),
+				DelaySeconds: c.int("nodeDownscaler.emptyNodes.delaySeconds", en.DelaySeconds),
+			}
+		}
+		var ev autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerEvictorArgs
+		if c.args("nodeDownscaler.evictor", nd.Evictor, &ev) {
+			p.NodeDownscaler.Evictor = &Evictor{
+				Enabled:                           c.bool("nodeDownscaler.evictor.enabled", ev.Enabled),
+				DryRun:                            c.bool("nodeDownscaler.evictor.dryRun", ev.DryRun),
+				AggressiveMode:                    c.bool("nodeDownscaler.evictor.aggressiveMode", ev.AggressiveMode),
+				ScopedMode:                        c.bool("nodeDownscaler.evictor.scopedMode", ev.ScopedMode),
+				CycleInterval:                     c.string("nodeDownscaler.evictor.cycleInterval", ev.CycleInterval),
+				NodeGracePeriodMinutes:            c.int("nodeDownscaler.evictor.nodeGracePeriodMinutes", ev.NodeGracePeriodMinutes),
+				PodEvictionFailureBackOffInterval: c.string("nodeDownscaler.evictor.podEvictionFailureBackOffInterval", ev.PodEvictionFailureBackOffInterval),
+				IgnorePodDisruptionBudgets:        c.bool("nodeDownscaler.evictor.ignorePodDisruptionBudgets", ev.IgnorePodDisruptionBudgets),
+			}
+		}
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	return p, nil
+}
+
+// converter unwraps plain Pulumi inputs. It records the first failure and
+// turns later calls into no-ops so FromSettingsArgs can stay linear.
+type converter struct {
+	err error
+}
+
+// plain dereferences an input down to its underlying value. The boolean result
+// is false when the input is unset.
+func (c *converter) plain(path string, in pulumi.Input) (reflect.Value, bool) {
+	if c.err != nil || in == nil {
+		return reflect.Value{}, false
+	}
+	if _, ok := in.(pulumi.Output); ok {
+		c.err = fmt.Errorf("%s: outputs cannot be converted to a policy, only plain values", path)
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(in)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+func (c *converter) convert(path string, in pulumi.Input, to reflect.Type) (reflect.Value, bool) {
+	v, ok := c.plain(path, in)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	if !v.Type().ConvertibleTo(to) {
+		c.err = fmt.Errorf("%s: unsupported input type %T", path, in)
+		return reflect.Value{}, false
+	}
+	return v.Convert(to), true
+}
+
+func (c *converter) bool(path string, in pulumi.Input) *bool {
+	v, ok := c.convert(path, in, reflect.TypeOf(false))
+	if !ok {
+		return nil
+	}
+	return Bool(v.Bool())
+}
+
+func (c *converter) int(path string, in pulumi.Input) *int {
+	v, ok := c.convert(path, in, reflect.TypeOf(0))
+	if !ok {
+		return nil
+	}
+	return Int(int(v.Int()))
+}
+
+func (c *converter) string(path string, in pulumi.Input) *string {
+	v, ok := c.convert(path, in, reflect.TypeOf(""))
+	if !ok {
+		return nil
+	}
+	return String(v.String())
+}
+
+// args copies a nested *Args input into dst, which must point to the matching Args struct.
+func (c *converter) args(path string, in pulumi.Input, dst interface{}) bool {
+	target := reflect.ValueOf(dst).Elem()
+	v, ok := c.convert(path, in, target.Type())
+	if !ok {
+		return false
+	}
+	target.Set(v)
+	return true
+}
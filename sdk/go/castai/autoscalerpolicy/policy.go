@@ -0,0 +1,276 @@
+// Package autoscalerpolicy provides a typed model of the CAST AI autoscaler
+// policies document.
+//
+// It is an alternative to building the deprecated `autoscalerPoliciesJson`
+// string by hand. A Policy can be validated, rendered to deterministic JSON and
+// converted to and from autoscaling.AutoscalerAutoscalerSettingsArgs, which is
+// the recommended way to configure castai.Autoscaler.
+package autoscalerpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Spot interruption prediction types accepted by the CAST AI API.
+const (
+	SpotInterruptionPredictionsAWSRebalanceRecommendations = "AWSRebalanceRecommendations"
+	SpotInterruptionPredictionsCASTAI                      = "CASTAIInterruptionPredictions"
+)
+
+// Policy is the root of the autoscaler policies document.
+type Policy struct {
+	// Enabled turns the autoscaler policies on or off.
+	Enabled *bool `json:"enabled,omitempty"`
+	// IsScopedMode makes the autoscaler consider only marked pods and nodes.
+	IsScopedMode *bool `json:"isScopedMode,omitempty"`
+	// NodeTemplatesPartialMatchingEnabled allows partial matching when selecting a custom node template.
+	NodeTemplatesPartialMatchingEnabled *bool `json:"nodeTemplatesPartialMatchingEnabled,omitempty"`
+	// UnschedulablePods defines the behaviour when unschedulable pods are detected.
+	UnschedulablePods *UnschedulablePods `json:"unschedulablePods,omitempty"`
+	// SpotInstances defines whether spot instances may be used for additional workloads.
+	SpotInstances *SpotInstances `json:"spotInstances,omitempty"`
+	// ClusterLimits defines the minimum and maximum amount of CPU the cluster can have.
+	ClusterLimits *ClusterLimits `json:"clusterLimits,omitempty"`
+	// NodeDownscaler defines policies for removing nodes.
+	NodeDownscaler *NodeDownscaler `json:"nodeDownscaler,omitempty"`
+}
+
+// UnschedulablePods is the unschedulable pods policy.
+type UnschedulablePods struct {
+	Enabled                *bool            `json:"enabled,omitempty"`
+	CustomInstancesEnabled *bool            `json:"customInstancesEnabled,omitempty"`
+	Headroom               *Headroom        `json:"headroom,omitempty"`
+	HeadroomSpot           *Headroom        `json:"headroomSpot,omitempty"`
+	NodeConstraints        *NodeConstraints `json:"nodeConstraints,omitempty"`
+	PodPinner              *PodPinner       `json:"podPinner,omitempty"`
+}
+
+// Headroom reserves additional capacity as a percentage of the cluster's total capacity.
+type Headroom struct {
+	Enabled          *bool `json:"enabled,omitempty"`
+	CpuPercentage    *int  `json:"cpuPercentage,omitempty"`
+	MemoryPercentage *int  `json:"memoryPercentage,omitempty"`
+}
+
+// NodeConstraints bounds the size of nodes picked by the unschedulable pods policy.
+type NodeConstraints struct {
+	Enabled     *bool `json:"enabled,omitempty"`
+	MinCpuCores *int  `json:"minCpuCores,omitempty"`
+	MaxCpuCores *int  `json:"maxCpuCores,omitempty"`
+	MinRamMib   *int  `json:"minRamMib,omitempty"`
+	MaxRamMib   *int  `json:"maxRamMib,omitempty"`
+}
+
+// PodPinner controls automatic management of the Pod Pinner component.
+type PodPinner struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// SpotInstances is the spot instances policy.
+type SpotInstances struct {
+	Enabled                                *bool                        `json:"enabled,omitempty"`
+	MaxReclaimRate                         *int                         `json:"maxReclaimRate,omitempty"`
+	SpotBackups                            *SpotBackups                 `json:"spotBackups,omitempty"`
+	SpotDiversityEnabled                   *bool                        `json:"spotDiversityEnabled,omitempty"`
+	SpotDiversityPriceIncreaseLimitPercent *int                         `json:"spotDiversityPriceIncreaseLimitPercent,omitempty"`
+	SpotInterruptionPredictions            *SpotInterruptionPredictions `json:"spotInterruptionPredictions,omitempty"`
+}
+
+// SpotBackups allows on-demand backups when spot instances are not available.
+type SpotBackups struct {
+	Enabled                      *bool `json:"enabled,omitempty"`
+	SpotBackupRestoreRateSeconds *int  `json:"spotBackupRestoreRateSeconds,omitempty"`
+}
+
+// SpotInterruptionPredictions configures handling of spot interruption predictions.
+type SpotInterruptionPredictions struct {
+	Enabled *bool   `json:"enabled,omitempty"`
+	Type    *string `json:"type,omitempty"`
+}
+
+// ClusterLimits is the cluster size limits policy.
+type ClusterLimits struct {
+	Enabled *bool     `json:"enabled,omitempty"`
+	Cpu     *CpuLimit `json:"cpu,omitempty"`
+}
+
+// CpuLimit bounds the amount of vCPUs in the whole cluster.
+type CpuLimit struct {
+	MinCores *int `json:"minCores,omitempty"`
+	MaxCores *int `json:"maxCores,omitempty"`
+}
+
+// NodeDownscaler is the node downscaler policy.
+type NodeDownscaler struct {
+	Enabled    *bool       `json:"enabled,omitempty"`
+	EmptyNodes *EmptyNodes `json:"emptyNodes,omitempty"`
+	Evictor    *Evictor    `json:"evictor,omitempty"`
+}
+
+// EmptyNodes controls removal of empty worker nodes.
+type EmptyNodes struct {
+	Enabled      *bool `json:"enabled,omitempty"`
+	DelaySeconds *int  `json:"delaySeconds,omitempty"`
+}
+
+// Evictor configures the CAST AI Evictor component.
+type Evictor struct {
+	Enabled                           *bool   `json:"enabled,omitempty"`
+	DryRun                            *bool   `json:"dryRun,omitempty"`
+	AggressiveMode                    *bool   `json:"aggressiveMode,omitempty"`
+	ScopedMode                        *bool   `json:"scopedMode,omitempty"`
+	CycleInterval                     *string `json:"cycleInterval,omitempty"`
+	NodeGracePeriodMinutes            *int    `json:"nodeGracePeriodMinutes,omitempty"`
+	PodEvictionFailureBackOffInterval *string `json:"podEvictionFailureBackOffInterval,omitempty"`
+	IgnorePodDisruptionBudgets        *bool   `json:"ignorePodDisruptionBudgets,omitempty"`
+}
+
+// Bool returns a pointer to v.
+func Bool(v bool) *bool { return &v }
+
+// Int returns a pointer to v.
+func Int(v int) *int { return &v }
+
+// String returns a pointer to v.
+func String(v string) *string { return &v }
+
+// Parse decodes an autoscaler policies JSON document. Unknown keys are rejected,
+// so a field placed in the wrong block fails here rather than at apply time.
+func Parse(data []byte) (*Policy, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("decoding autoscaler policies: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("decoding autoscaler policies: unexpected data after the top-level object")
+	}
+	return &p, nil
+}
+
+// JSON validates the policy and renders it as compact JSON. The output is
+// deterministic: keys always appear in the same order and unset fields are omitted.
+func (p *Policy) JSON() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("encoding autoscaler policies: %w", err)
+	}
+	return string(b), nil
+}
+
+// Validate checks value ranges and cross-field constraints. All problems are
+// reported at once, each prefixed with the path of the offending field.
+func (p *Policy) Validate() error {
+	if p == nil {
+		return errors.New("autoscaler policy is nil")
+	}
+
+	v := &validator{}
+	if up := p.UnschedulablePods; up != nil {
+		v.headroom("unschedulablePods.headroom", up.Headroom)
+		v.headroom("unschedulablePods.headroomSpot", up.HeadroomSpot)
+		if nc := up.NodeConstraints; nc != nil {
+			v.nonNegative("unschedulablePods.nodeConstraints.minCpuCores", nc.MinCpuCores)
+			v.nonNegative("unschedulablePods.nodeConstraints.maxCpuCores", nc.MaxCpuCores)
+			v.nonNegative("unschedulablePods.nodeConstraints.minRamMib", nc.MinRamMib)
+			v.nonNegative("unschedulablePods.nodeConstraints.maxRamMib", nc.MaxRamMib)
+			v.ordered("unschedulablePods.nodeConstraints.minCpuCores", nc.MinCpuCores, "maxCpuCores", nc.MaxCpuCores)
+			v.ordered("unschedulablePods.nodeConstraints.minRamMib", nc.MinRamMib, "maxRamMib", nc.MaxRamMib)
+		}
+	}
+	if si := p.SpotInstances; si != nil {
+		v.percentage("spotInstances.maxReclaimRate", si.MaxReclaimRate)
+		v.nonNegative("spotInstances.spotDiversityPriceIncreaseLimitPercent", si.SpotDiversityPriceIncreaseLimitPercent)
+		if sb := si.SpotBackups; sb != nil {
+			v.nonNegative("spotInstances.spotBackups.spotBackupRestoreRateSeconds", sb.SpotBackupRestoreRateSeconds)
+		}
+		if sip := si.SpotInterruptionPredictions; sip != nil && sip.Type != nil {
+			switch *sip.Type {
+			case SpotInterruptionPredictionsAWSRebalanceRecommendations, SpotInterruptionPredictionsCASTAI:
+			default:
+				v.addf("spotInstances.spotInterruptionPredictions.type: unsupported value %q, expected %q or %q",
+					*sip.Type, SpotInterruptionPredictionsCASTAI, SpotInterruptionPredictionsAWSRebalanceRecommendations)
+			}
+		}
+	}
+	if cl := p.ClusterLimits; cl != nil && cl.Cpu != nil {
+		v.nonNegative("clusterLimits.cpu.minCores", cl.Cpu.MinCores)
+		v.nonNegative("clusterLimits.cpu.maxCores", cl.Cpu.MaxCores)
+		v.ordered("clusterLimits.cpu.minCores", cl.Cpu.MinCores, "maxCores", cl.Cpu.MaxCores)
+	}
+	if nd := p.NodeDownscaler; nd != nil {
+		if en := nd.EmptyNodes; en != nil {
+			v.nonNegative("nodeDownscaler.emptyNodes.delaySeconds", en.DelaySeconds)
+		}
+		if ev := nd.Evictor; ev != nil {
+			v.duration("nodeDownscaler.evictor.cycleInterval", ev.CycleInterval)
+			v.duration("nodeDownscaler.evictor.podEvictionFailureBackOffInterval", ev.PodEvictionFailureBackOffInterval)
+			v.nonNegative("nodeDownscaler.evictor.nodeGracePeriodMinutes", ev.NodeGracePeriodMinutes)
+		}
+	}
+	return v.err()
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid autoscaler policy: %w", errors.Join(v.errs...))
+}
+
+func (v *validator) nonNegative(path string, n *int) {
+	if n != nil && *n < 0 {
+		v.addf("%s: must not be negative, got %d", path, *n)
+	}
+}
+
+func (v *validator) percentage(path string, n *int) {
+	if n != nil && (*n < 0 || *n > 100) {
+		v.addf("%s: must be between 0 and 100, got %d", path, *n)
+	}
+}
+
+func (v *validator) ordered(minPath string, lo *int, maxName string, hi *int) {
+	if lo != nil && hi != nil && *lo > *hi {
+		v.addf("%s: %d must not exceed %s %d", minPath, *lo, maxName, *hi)
+	}
+}
+
+func (v *validator) duration(path string, s *string) {
+	if s == nil {
+		return
+	}
+	d, err := time.ParseDuration(*s)
+	if err != nil {
+		v.addf("%s: %q is not a valid duration such as \"5m10s\"", path, *s)
+		return
+	}
+	if d <= 0 {
+		v.addf("%s: must be positive, got %q", path, *s)
+	}
+}
+
+func (v *validator) headroom(path string, h *Headroom) {
+	if h == nil {
+		return
+	}
+	v.percentage(path+".cpuPercentage", h.CpuPercentage)
+	v.percentage(path+".memoryPercentage", h.MemoryPercentage)
+}
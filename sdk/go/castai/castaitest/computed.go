@@ -0,0 +1,136 @@
+package castaitest
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// state is the resource being registered while its computed outputs are filled.
+type state struct {
+	id      string
+	name    string
+	outputs resource.PropertyMap
+}
+
+func (s *state) set(key resource.PropertyKey, v resource.PropertyValue) {
+	setDefault(s.outputs, key, v)
+}
+
+func (s *state) input(key resource.PropertyKey) string {
+	return stringInput(s.outputs, key)
+}
+
+// computed holds, for every resource token, the function that fills the
+// output-only properties of that resource. A nil entry means the resource has
+// no computed outputs besides the common `name` and `organizationId` ones.
+var computed = map[string]func(*state){
+	"castai:autoscaling:Autoscaler": func(s *state) {
+		policies := s.input("autoscalerPoliciesJson")
+		if policies == "" {
+			policies = "{}"
+		}
+		s.set("autoscalerPolicies", resource.NewStringProperty(policies))
+	},
+	"castai:autoscaling:EvictorAdvancedConfig": nil,
+	"castai:aws:EksCluster":                    clusterOutputs,
+	"castai:aws:EksClusterId":                  nil,
+	"castai:aws:EksUserArn": func(s *state) {
+		s.set("arn", resource.NewStringProperty("arn:aws:iam::028075177508:user/cast-crossrole-"+secret(8, s.input("clusterId"))))
+	},
+	"castai:azure:AksCluster":                     clusterOutputs,
+	"castai:cache:CacheConfiguration":             nil,
+	"castai:cache:CacheGroup":                     nil,
+	"castai:cache:CacheRule":                      nil,
+	"castai:config/node:NodeConfiguration":        nil,
+	"castai:config/node:NodeConfigurationDefault": nil,
+	"castai:config/node:NodeTemplate": func(s *state) {
+		s.set("isDefault", resource.NewBoolProperty(false))
+		s.set("isEnabled", resource.NewBoolProperty(true))
+	},
+	"castai:gcp:GkeCluster": clusterOutputs,
+	"castai:gcp:GkeClusterId": func(s *state) {
+		s.set("clusterToken", resource.NewStringProperty(secret(64, "cluster-token", s.id)))
+		project := s.input("projectId")
+		if project == "" {
+			project = "castai-project"
+		}
+		s.set("castServiceAccount", resource.NewStringProperty("castai-gke-"+secret(8, s.id)+"@"+project+".iam.gserviceaccount.com"))
+	},
+	"castai:iam:EnterpriseRoleBinding": nil,
+	"castai:iam:RoleBindings":          nil,
+	"castai:index/aiOptimizer:AiOptimizerHostedModel": func(s *state) {
+		s.set("cloudProvider", resource.NewStringProperty("aws"))
+		s.set("currentReplicas", resource.NewNumberProperty(1))
+		s.set("namespace", resource.NewStringProperty("castai-llms"))
+		s.set("region", resource.NewStringProperty("us-east-1"))
+		s.set("status", resource.NewStringProperty("RUNNING"))
+		s.set("statusReason", resource.NewStringProperty(""))
+	},
+	"castai:index/aiOptimizer:AiOptimizerModelRegistry": func(s *state) {
+		s.set("status", resource.NewStringProperty("CONNECTED"))
+		s.set("statusReason", resource.NewStringProperty(""))
+	},
+	"castai:index/aiOptimizer:AiOptimizerModelSpecs": nil,
+	"castai:index:AllocationGroup":                   nil,
+	"castai:index:ClusterReadiness": func(s *state) {
+		s.set("waitFor", resource.NewStringProperty("connected"))
+		s.set("timeout", resource.NewStringProperty("15m"))
+		s.set("pollInterval", resource.NewStringProperty("10s"))
+		s.set("status", resource.NewStringProperty("ready"))
+		s.set("agentStatus", resource.NewStringProperty("online"))
+	},
+	"castai:index:Commitments": func(s *state) {
+		s.set("gcpCuds", resource.NewArrayProperty([]resource.PropertyValue{}))
+		s.set("azureReservations", resource.NewArrayProperty([]resource.PropertyValue{}))
+	},
+	"castai:index:PodMutation": func(s *state) {
+		s.set("source", resource.NewStringProperty("API"))
+	},
+	"castai:index:Reservations": func(s *state) {
+		s.set("reservations", resource.NewArrayProperty([]resource.PropertyValue{}))
+	},
+	"castai:index:SecurityRuntimeRule": func(s *state) {
+		s.set("anomaliesCount", resource.NewNumberProperty(0))
+		s.set("isBuiltIn", resource.NewBoolProperty(false))
+		s.set("type", resource.NewStringProperty("CUSTOM"))
+		s.set("usedCustomLists", resource.NewArrayProperty([]resource.PropertyValue{}))
+	},
+	"castai:organization:EnterpriseGroup": nil,
+	"castai:organization:EnterpriseServiceAccount": func(s *state) {
+		s.set("email", resource.NewStringProperty(serviceAccountEmail(s)))
+	},
+	"castai:organization:OrganizationGroup":   nil,
+	"castai:organization:OrganizationMembers": nil,
+	"castai:organization:SSOConnection": func(s *state) {
+		s.set("syncAuthToken", resource.NewStringProperty(secret(48, "sync-auth-token", s.id)))
+	},
+	"castai:organization:ServiceAccount": func(s *state) {
+		s.set("email", resource.NewStringProperty(serviceAccountEmail(s)))
+		s.set("authors", resource.NewArrayProperty([]resource.PropertyValue{}))
+	},
+	"castai:organization:ServiceAccountKey": func(s *state) {
+		token := secret(64, "service-account-key", s.id)
+		s.set("token", resource.NewStringProperty(token))
+		s.set("prefix", resource.NewStringProperty(token[:6]))
+		s.set("lastUsedAt", resource.NewStringProperty(""))
+	},
+	"castai:rebalancing:HibernationSchedule": nil,
+	"castai:rebalancing:RebalancingJob":      nil,
+	"castai:rebalancing:RebalancingSchedule": nil,
+	"castai:workload:WorkloadCustomMetricsDataSource": func(s *state) {
+		s.set("kubeResourceName", resource.NewStringProperty(s.input("name")))
+		s.set("managedByCast", resource.NewBoolProperty(false))
+		s.set("status", resource.NewStringProperty("CONNECTED"))
+	},
+	"castai:workload:WorkloadScalingPolicy":      nil,
+	"castai:workload:WorkloadScalingPolicyOrder": nil,
+}
+
+// clusterOutputs fills the outputs shared by EksCluster, GkeCluster and AksCluster.
+func clusterOutputs(s *state) {
+	s.set("clusterToken", resource.NewStringProperty(secret(64, "cluster-token", s.id)))
+	s.set("credentialsId", resource.NewStringProperty(uuid("credentials", s.id)))
+}
+
+func serviceAccountEmail(s *state) string {
+	return "service-account-" + secret(8, s.id) + "@" + s.input("organizationId") + ".sa.cast.ai"
+}
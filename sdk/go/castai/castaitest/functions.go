@@ -0,0 +1,179 @@
+package castaitest
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ImpersonationServiceAccount is the service account returned by
+// getImpersonationServiceAccount.
+const ImpersonationServiceAccount = "castai-impersonation@castai-prod.iam.gserviceaccount.com"
+
+// EksManagedPolicies are the AWS managed policies returned by getEksSettings.
+var EksManagedPolicies = []string{
+	"arn:aws:iam::aws:policy/AmazonEC2ReadOnlyAccess",
+	"arn:aws:iam::aws:policy/IAMReadOnlyAccess",
+	"arn:aws:iam::aws:policy/AmazonSQSReadOnlyAccess",
+}
+
+// GkePolicies are the permissions returned by getGkePolicies.
+var GkePolicies = []string{
+	"compute.addresses.use",
+	"compute.disks.create",
+	"compute.disks.setLabels",
+	"compute.disks.use",
+	"compute.instanceGroupManagers.get",
+	"compute.instanceGroupManagers.update",
+	"compute.instanceGroups.get",
+	"compute.instanceTemplates.create",
+	"compute.instanceTemplates.delete",
+	"compute.instanceTemplates.get",
+	"compute.instances.create",
+	"compute.instances.delete",
+	"compute.instances.get",
+	"compute.instances.list",
+	"compute.instances.setLabels",
+	"compute.instances.setMetadata",
+	"compute.instances.setServiceAccount",
+	"compute.instances.setTags",
+	"compute.networks.use",
+	"compute.subnetworks.use",
+	"compute.subnetworks.useExternalIp",
+	"container.clusters.get",
+	"container.clusters.update",
+	"container.operations.get",
+	"serviceusage.services.list",
+}
+
+// gkeLoadBalancerPolicies are added to GkePolicies when the
+// `loadBalancersNetworkEndpointGroup` feature is requested.
+var gkeLoadBalancerPolicies = []string{
+	"compute.networkEndpointGroups.attachNetworkEndpoints",
+	"compute.networkEndpointGroups.detachNetworkEndpoints",
+	"compute.networkEndpointGroups.get",
+	"compute.networkEndpointGroups.list",
+}
+
+// functions answers the data source calls, keyed by function token.
+var functions = map[string]func(*Mocks, resource.PropertyMap) resource.PropertyMap{
+	"castai:aws:getEksSettings": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		result := args.Copy()
+		accountID := stringInput(args, "accountId")
+		result["id"] = resource.NewStringProperty(uuid("eks-settings", accountID, stringInput(args, "region"), stringInput(args, "cluster")))
+		result["iamManagedPolicies"] = stringArray(EksManagedPolicies)
+		result["iamPolicyJson"] = resource.NewStringProperty(policyDocument("ec2:RunInstances", "ec2:TerminateInstances", "ec2:CreateTags", "autoscaling:UpdateAutoScalingGroup"))
+		result["iamUserPolicyJson"] = resource.NewStringProperty(policyDocument("eks:DescribeCluster", "eks:DescribeNodegroup", "iam:PassRole"))
+		return result
+	},
+	"castai:cache:getCacheGroup": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		id := stringInput(args, "id")
+		return resource.PropertyMap{
+			"id":           resource.NewStringProperty(id),
+			"name":         resource.NewStringProperty("cache-group-" + secret(8, id)),
+			"protocolType": resource.NewStringProperty("PostgreSQL"),
+			"directMode":   resource.NewBoolProperty(false),
+			"endpoints":    resource.NewArrayProperty([]resource.PropertyValue{}),
+		}
+	},
+	"castai:gcp:getGkePolicies": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		result := args.Copy()
+		policies := append([]string{}, GkePolicies...)
+		if f, ok := args["features"]; ok && f.IsObject() {
+			if lb, ok := f.ObjectValue()["loadBalancersNetworkEndpointGroup"]; ok && lb.IsBool() && lb.BoolValue() {
+				policies = append(policies, gkeLoadBalancerPolicies...)
+			}
+		}
+		sort.Strings(policies)
+		result["id"] = resource.NewStringProperty("gke")
+		result["policies"] = stringArray(policies)
+		return result
+	},
+	"castai:organization:getImpersonationServiceAccount": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		return resource.PropertyMap{
+			"id": resource.NewStringProperty(ImpersonationServiceAccount),
+		}
+	},
+	"castai:organization:getOrganization": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		name := stringInput(args, "name")
+		return resource.PropertyMap{
+			"id":   resource.NewStringProperty(m.organizationID()),
+			"name": resource.NewStringProperty(name),
+		}
+	},
+	"castai:rebalancing:getHibernationSchedule": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		result := args.Copy()
+		setDefault(result, "organizationId", resource.NewStringProperty(m.organizationID()))
+		result["id"] = resource.NewStringProperty(uuid("hibernation-schedule", stringInput(args, "name")))
+		result["enabled"] = resource.NewBoolProperty(true)
+		result["clusterAssignments"] = resource.NewArrayProperty([]resource.PropertyValue{})
+		result["pauseConfigs"] = resource.NewArrayProperty([]resource.PropertyValue{})
+		result["resumeConfigs"] = resource.NewArrayProperty([]resource.PropertyValue{})
+		return result
+	},
+	"castai:rebalancing:getRebalancingSchedule": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		result := args.Copy()
+		result["id"] = resource.NewStringProperty(uuid("rebalancing-schedule", stringInput(args, "name")))
+		result["schedules"] = resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"cron": resource.NewStringProperty("0 3 * * *"),
+			}),
+		})
+		result["launchConfigurations"] = resource.NewArrayProperty([]resource.PropertyValue{})
+		result["triggerConditions"] = resource.NewArrayProperty([]resource.PropertyValue{})
+		return result
+	},
+	"castai:workload:getWorkloadScalingPolicies": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		result := args.Copy()
+		clusterID := stringInput(args, "clusterId")
+		result["id"] = resource.NewStringProperty(clusterID)
+		result["policies"] = resource.NewArrayProperty([]resource.PropertyValue{})
+		result["policiesByName"] = resource.NewObjectProperty(resource.PropertyMap{})
+		for _, r := range m.RegistrationsOf("castai:workload:WorkloadScalingPolicy") {
+			if stringInput(r.Outputs, "clusterId") != clusterID {
+				continue
+			}
+			result["policiesByName"].ObjectValue()[resource.PropertyKey(stringInput(r.Outputs, "name"))] = resource.NewStringProperty(r.ID)
+		}
+		return result
+	},
+	"castai:workload:getWorkloadScalingPolicyOrder": func(m *Mocks, args resource.PropertyMap) resource.PropertyMap {
+		result := args.Copy()
+		clusterID := stringInput(args, "clusterId")
+		var ids []string
+		for _, r := range m.RegistrationsOf("castai:workload:WorkloadScalingPolicy") {
+			if stringInput(r.Outputs, "clusterId") == clusterID {
+				ids = append(ids, r.ID)
+			}
+		}
+		result["id"] = resource.NewStringProperty(clusterID)
+		result["policyIds"] = stringArray(ids)
+		return result
+	},
+}
+
+func stringArray(values []string) resource.PropertyValue {
+	arr := make([]resource.PropertyValue, 0, len(values))
+	for _, v := range values {
+		arr = append(arr, resource.NewStringProperty(v))
+	}
+	return resource.NewArrayProperty(arr)
+}
+
+// policyDocument renders an IAM policy allowing actions on all resources.
+func policyDocument(actions ...string) string {
+	doc := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{{
+			"Effect":   "Allow",
+			"Action":   actions,
+			"Resource": "*",
+		}},
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,295 @@
+// Package castaitest provides a pulumi.MockResourceMonitor that behaves like
+// the CAST AI provider for unit tests written with pulumi.WithMocks.
+//
+// Mocks knows every castai:* resource and data source token. Resource inputs
+// are echoed back together with the computed outputs the provider would
+// return (cluster tokens, credentials ids, organization ids, service account
+// emails, statuses, ...), and data source calls are answered with plausible
+// data. Every registration and call is recorded so tests can assert on what a
+// program created:
+//
+//	mocks := castaitest.Run(t, program)
+//	cluster, ok := mocks.Find("castai:aws:EksCluster", "my-cluster")
+//
+// Resources from other providers (aws:*, kubernetes:*, ...) are delegated to
+// Mocks.Fallback when it is set and echoed back otherwise.
+package castaitest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// DefaultOrganizationID is the organization id reported when
+// Mocks.OrganizationID is empty.
+const DefaultOrganizationID = "00000000-0000-4000-8000-000000000001"
+
+// Registration is a resource registered with Mocks.
+type Registration struct {
+	// Type is the resource type token, for example "castai:aws:EksCluster".
+	Type string
+	// Name is the Pulumi resource name.
+	Name string
+	// ID is the id assigned by the mock.
+	ID string
+	// Provider is the provider reference, if the resource used an explicit provider.
+	Provider string
+	// Inputs are the inputs the program passed.
+	Inputs resource.PropertyMap
+	// Outputs are the outputs returned by the mock, including computed fields.
+	Outputs resource.PropertyMap
+	// Dependencies are the URNs of the resources the resource depends on,
+	// such as the ones passed with pulumi.DependsOn.
+	Dependencies []string
+}
+
+// Invocation is a data source call answered by Mocks.
+type Invocation struct {
+	// Token is the function token, for example "castai:aws:getEksSettings".
+	Token string
+	// Args are the arguments the program passed.
+	Args resource.PropertyMap
+	// Result is the result returned by the mock.
+	Result resource.PropertyMap
+}
+
+// Mocks implements pulumi.MockResourceMonitor for CAST AI resources. The zero
+// value is ready to use.
+type Mocks struct {
+	// OrganizationID is reported as the organization of every
+	// organization-scoped resource. Defaults to DefaultOrganizationID.
+	OrganizationID string
+	// Fallback handles resources and calls outside the castai package.
+	Fallback pulumi.MockResourceMonitor
+
+	mu            sync.Mutex
+	registrations []Registration
+	invocations   []Invocation
+}
+
+// NewMocks returns Mocks reporting DefaultOrganizationID.
+func NewMocks() *Mocks {
+	return &Mocks{OrganizationID: DefaultOrganizationID}
+}
+
+// NewResource implements pulumi.MockResourceMonitor.
+func (m *Mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	var (
+		id      string
+		outputs resource.PropertyMap
+	)
+	switch {
+	case args.Custom && isCastAI(args.TypeToken):
+		fill, ok := computed[args.TypeToken]
+		if !ok {
+			return "", nil, fmt.Errorf("castaitest: unknown CAST AI resource type %q", args.TypeToken)
+		}
+		outputs = args.Inputs.Copy()
+		id = resourceID(args.TypeToken, args.Name, outputs)
+		if named[args.TypeToken] {
+			setDefault(outputs, "name", resource.NewStringProperty(args.Name))
+		}
+		if organizationScoped[args.TypeToken] {
+			setDefault(outputs, "organizationId", resource.NewStringProperty(m.organizationID()))
+		}
+		if fill != nil {
+			fill(&state{id: id, name: args.Name, outputs: outputs})
+		}
+	case m.Fallback != nil:
+		var err error
+		id, outputs, err = m.Fallback.NewResource(args)
+		if err != nil {
+			return "", nil, err
+		}
+	default:
+		outputs = args.Inputs.Copy()
+		id = args.Name + "-id"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.registrations = append(m.registrations, Registration{
+		Type:         args.TypeToken,
+		Name:         args.Name,
+		ID:           id,
+		Provider:     args.Provider,
+		Inputs:       args.Inputs.Copy(),
+		Outputs:      outputs.Copy(),
+		Dependencies: args.RegisterRPC.GetDependencies(),
+	})
+	return id, outputs, nil
+}
+
+// Call implements pulumi.MockResourceMonitor.
+func (m *Mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	var result resource.PropertyMap
+	switch {
+	case isCastAI(args.Token):
+		answer, ok := functions[args.Token]
+		if !ok {
+			return nil, fmt.Errorf("castaitest: unknown CAST AI function %q", args.Token)
+		}
+		result = answer(m, args.Args)
+	case m.Fallback != nil:
+		var err error
+		result, err = m.Fallback.Call(args)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		result = resource.PropertyMap{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.invocations = append(m.invocations, Invocation{
+		Token:  args.Token,
+		Args:   args.Args.Copy(),
+		Result: result.Copy(),
+	})
+	return result, nil
+}
+
+// Registrations returns all resources registered so far, in registration order.
+func (m *Mocks) Registrations() []Registration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]Registration, len(m.registrations))
+	copy(out, m.registrations)
+	return out
+}
+
+// RegistrationsOf returns the resources of the given type registered so far.
+func (m *Mocks) RegistrationsOf(typ string) []Registration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []Registration
+	for _, r := range m.registrations {
+		if r.Type == typ {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Find returns the resource with the given type and name.
+func (m *Mocks) Find(typ, name string) (Registration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.registrations {
+		if r.Type == typ && r.Name == name {
+			return r, true
+		}
+	}
+	return Registration{}, false
+}
+
+// Invocations returns all data source calls answered so far, in call order.
+func (m *Mocks) Invocations() []Invocation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]Invocation, len(m.invocations))
+	copy(out, m.invocations)
+	return out
+}
+
+// InvocationsOf returns the calls of the given function answered so far.
+func (m *Mocks) InvocationsOf(token string) []Invocation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []Invocation
+	for _, c := range m.invocations {
+		if c.Token == token {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (m *Mocks) organizationID() string {
+	if m.OrganizationID == "" {
+		return DefaultOrganizationID
+	}
+	return m.OrganizationID
+}
+
+// isCastAI reports whether token belongs to the castai package. The provider
+// resource itself ("pulumi:providers:castai") is not included, and component
+// resources using a castai:* type are told apart by MockResourceArgs.Custom.
+func isCastAI(token string) bool {
+	return strings.HasPrefix(token, "castai:")
+}
+
+// resourceID returns the id the provider would assign. Most resources get a
+// UUID; resources that are addressed by their parent cluster or by name reuse
+// that value, as the provider does.
+func resourceID(typ, name string, inputs resource.PropertyMap) string {
+	switch typ {
+	case "castai:autoscaling:Autoscaler",
+		"castai:index:ClusterReadiness",
+		"castai:workload:WorkloadScalingPolicyOrder":
+		if v := stringInput(inputs, "clusterId"); v != "" {
+			return v
+		}
+	case "castai:config/node:NodeConfigurationDefault":
+		if v := stringInput(inputs, "configurationId"); v != "" {
+			return v
+		}
+	case "castai:config/node:NodeTemplate":
+		if v := stringInput(inputs, "name"); v != "" {
+			return v
+		}
+		return name
+	}
+	return uuid(typ, name)
+}
+
+// uuid returns a version 4 formatted UUID derived from parts, so that the same
+// program always gets the same ids.
+func uuid(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "/")))
+	sum[6] = (sum[6] & 0x0f) | 0x40
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(sum[:16])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// secret returns a deterministic hex string of n characters derived from parts.
+func secret(n int, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "/")))
+	h := hex.EncodeToString(sum[:])
+	for len(h) < n {
+		h += h
+	}
+	return h[:n]
+}
+
+func stringInput(props resource.PropertyMap, key resource.PropertyKey) string {
+	v, ok := props[key]
+	if ok && v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	if !ok || !v.IsString() {
+		return ""
+	}
+	return v.StringValue()
+}
+
+// setDefault sets key to v unless the program already provided a value.
+func setDefault(props resource.PropertyMap, key resource.PropertyKey, v resource.PropertyValue) {
+	if cur, ok := props[key]; ok && !cur.IsNull() {
+		return
+	}
+	props[key] = v
+}
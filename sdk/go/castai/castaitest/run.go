@@ -0,0 +1,56 @@
+package castaitest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/internals"
+)
+
+// Run runs a program against NewMocks and returns the mocks for assertions.
+// It fails the test if the program returns an error:
+//
+//	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+//		cluster, err := ekscluster.NewCastAiEksCluster(ctx, "my-cluster", args)
+//		require.NoError(t, err)
+//		assert.NotEmpty(t, castaitest.Await(t, cluster.ClusterId).Value)
+//		return nil
+//	})
+func Run(t testing.TB, program pulumi.RunFunc) *Mocks {
+	t.Helper()
+	return NewMocks().Run(t, program)
+}
+
+// Run runs a program against m, e.g. after setting Fallback, and returns m.
+// It fails the test if the program returns an error.
+func (m *Mocks) Run(t testing.TB, program pulumi.RunFunc) *Mocks {
+	t.Helper()
+	if err := pulumi.RunErr(program, pulumi.WithMocks("project", "stack", m)); err != nil {
+		t.Fatalf("running the program: %v", err)
+	}
+	return m
+}
+
+// Await waits for an output of a program run with Run and returns its value
+// together with whether it is known and secret. It fails the test if the
+// output is rejected.
+func Await(t testing.TB, out pulumi.Output) internals.UnsafeAwaitOutputResult {
+	t.Helper()
+	result, err := internals.UnsafeAwaitOutput(context.Background(), out)
+	if err != nil {
+		t.Fatalf("awaiting output: %v", err)
+	}
+	return result
+}
+
+// Strings returns the items of a string array input or output, such as
+// Registration.Inputs["subnets"].
+func Strings(v resource.PropertyValue) []string {
+	var out []string
+	for _, item := range v.ArrayValue() {
+		out = append(out, item.StringValue())
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package castaitest
+
+// ResourceTypes lists every resource token registered in provider/resources.go.
+var ResourceTypes = []string{
+	"castai:autoscaling:Autoscaler",
+	"castai:autoscaling:EvictorAdvancedConfig",
+	"castai:aws:EksCluster",
+	"castai:aws:EksClusterId",
+	"castai:aws:EksUserArn",
+	"castai:azure:AksCluster",
+	"castai:cache:CacheConfiguration",
+	"castai:cache:CacheGroup",
+	"castai:cache:CacheRule",
+	"castai:config/node:NodeConfiguration",
+	"castai:config/node:NodeConfigurationDefault",
+	"castai:config/node:NodeTemplate",
+	"castai:gcp:GkeCluster",
+	"castai:gcp:GkeClusterId",
+	"castai:iam:EnterpriseRoleBinding",
+	"castai:iam:RoleBindings",
+	"castai:index/aiOptimizer:AiOptimizerHostedModel",
+	"castai:index/aiOptimizer:AiOptimizerModelRegistry",
+	"castai:index/aiOptimizer:AiOptimizerModelSpecs",
+	"castai:index:AllocationGroup",
+	"castai:index:ClusterReadiness",
+	"castai:index:Commitments",
+	"castai:index:PodMutation",
+	"castai:index:Reservations",
+	"castai:index:SecurityRuntimeRule",
+	"castai:organization:EnterpriseGroup",
+	"castai:organization:EnterpriseServiceAccount",
+	"castai:organization:OrganizationGroup",
+	"castai:organization:OrganizationMembers",
+	"castai:organization:SSOConnection",
+	"castai:organization:ServiceAccount",
+	"castai:organization:ServiceAccountKey",
+	"castai:rebalancing:HibernationSchedule",
+	"castai:rebalancing:RebalancingJob",
+	"castai:rebalancing:RebalancingSchedule",
+	"castai:workload:WorkloadCustomMetricsDataSource",
+	"castai:workload:WorkloadScalingPolicy",
+	"castai:workload:WorkloadScalingPolicyOrder",
+}
+
+// FunctionTokens lists every data source token registered in provider/resources.go.
+var FunctionTokens = []string{
+	"castai:aws:getEksSettings",
+	"castai:cache:getCacheGroup",
+	"castai:gcp:getGkePolicies",
+	"castai:organization:getImpersonationServiceAccount",
+	"castai:organization:getOrganization",
+	"castai:rebalancing:getHibernationSchedule",
+	"castai:rebalancing:getRebalancingSchedule",
+	"castai:workload:getWorkloadScalingPolicies",
+	"castai:workload:getWorkloadScalingPolicyOrder",
+}
+
+// organizationScoped lists the resources with an `organizationId` output that
+// the provider fills in from the API token when it is not set explicitly.
+var organizationScoped = map[string]bool{
+	"castai:aws:EksCluster":                        true,
+	"castai:aws:EksClusterId":                      true,
+	"castai:azure:AksCluster":                      true,
+	"castai:gcp:GkeCluster":                        true,
+	"castai:gcp:GkeClusterId":                      true,
+	"castai:index:Commitments":                     true,
+	"castai:index:PodMutation":                     true,
+	"castai:index:Reservations":                    true,
+	"castai:organization:EnterpriseServiceAccount": true,
+	"castai:rebalancing:HibernationSchedule":       true,
+}
+
+// named lists the resources whose `name` output is computed when no name is
+// given.
+var named = map[string]bool{
+	"castai:aws:EksCluster":                           true,
+	"castai:azure:AksCluster":                         true,
+	"castai:cache:CacheGroup":                         true,
+	"castai:config/node:NodeConfiguration":            true,
+	"castai:config/node:NodeTemplate":                 true,
+	"castai:gcp:GkeCluster":                           true,
+	"castai:gcp:GkeClusterId":                         true,
+	"castai:iam:EnterpriseRoleBinding":                true,
+	"castai:iam:RoleBindings":                         true,
+	"castai:index:AllocationGroup":                    true,
+	"castai:index:PodMutation":                        true,
+	"castai:index:SecurityRuntimeRule":                true,
+	"castai:organization:EnterpriseGroup":             true,
+	"castai:organization:EnterpriseServiceAccount":    true,
+	"castai:organization:OrganizationGroup":           true,
+	"castai:organization:SSOConnection":               true,
+	"castai:organization:ServiceAccount":              true,
+	"castai:organization:ServiceAccountKey":           true,
+	"castai:rebalancing:HibernationSchedule":          true,
+	"castai:rebalancing:RebalancingSchedule":          true,
+	"castai:workload:WorkloadCustomMetricsDataSource": true,
+	"castai:workload:WorkloadScalingPolicy":           true,
+}
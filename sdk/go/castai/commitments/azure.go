@@ -0,0 +1,247 @@
+package commitments
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Azure reservation terms, as the Azure portal writes them.
+const (
+	AzureTermOneYear    = "One Year"
+	AzureTermThreeYears = "Three Years"
+	AzureTermFiveYears  = "Five Years"
+)
+
+// Azure reservation scopes, as the Azure portal writes them.
+const (
+	AzureScopeShared              = "Shared"
+	AzureScopeSingleSubscription  = "Single subscription"
+	AzureScopeSingleResourceGroup = "Single resource group"
+	AzureScopeManagementGroup     = "Management group"
+)
+
+const (
+	azureTypeVirtualMachines = "VirtualMachines"
+
+	azureColumnName               = "Name"
+	azureColumnReservationID      = "Reservation Id"
+	azureColumnReservationOrderID = "Reservation order Id"
+	azureColumnStatus             = "Status"
+	azureColumnExpiryDate         = "Expiry date"
+	azureColumnPurchaseDate       = "Purchase date"
+	azureColumnTerm               = "Term"
+	azureColumnScope              = "Scope"
+	azureColumnScopeSubscription  = "Scope subscription"
+	azureColumnScopeResourceGroup = "Scope resource group"
+	azureColumnType               = "Type"
+	azureColumnProductName        = "Product name"
+	azureColumnRegion             = "Region"
+	azureColumnQuantity           = "Quantity"
+)
+
+// azureColumns are the columns of the Azure portal export, in its order.
+var azureColumns = []string{
+	azureColumnName,
+	azureColumnReservationID,
+	azureColumnReservationOrderID,
+	azureColumnStatus,
+	azureColumnExpiryDate,
+	azureColumnPurchaseDate,
+	azureColumnTerm,
+	azureColumnScope,
+	azureColumnScopeSubscription,
+	azureColumnScopeResourceGroup,
+	azureColumnType,
+	azureColumnProductName,
+	azureColumnRegion,
+	azureColumnQuantity,
+	"Utilization % 1 Day",
+	"Utilization % 7 Day",
+	"Utilization % 30 Day",
+	"Deep link to reservation",
+}
+
+// AzureReservation is an Azure virtual machine reservation.
+type AzureReservation struct {
+	Name string
+	// ReservationID and ReservationOrderID are the GUIDs, or resource IDs, of
+	// the reservation and the order it was bought in.
+	ReservationID      string
+	ReservationOrderID string
+	// Status is the provisioning state, e.g. "Succeeded" or "Expired".
+	Status string
+	// Start is the purchase date and End the expiry date.
+	Start time.Time
+	End   time.Time
+	// Term is one of AzureTermOneYear, AzureTermThreeYears and
+	// AzureTermFiveYears.
+	Term string
+	// Scope is one of the AzureScope constants. ScopeSubscription is set for
+	// single subscription and single resource group scopes, and
+	// ScopeResourceGroup for the latter.
+	Scope              string
+	ScopeSubscription  string
+	ScopeResourceGroup string
+	// Type is the reserved resource type, "VirtualMachines" by default.
+	Type string
+	// InstanceType is the VM size, the product name of the export, e.g.
+	// "Standard_D4s_v3".
+	InstanceType string
+	Region       string
+	Quantity     int
+}
+
+// AzureReservations is the `azureReservationsCsv` input of
+// castai.Commitments.
+type AzureReservations []AzureReservation
+
+// azureTerms maps the spellings of terms in exports and the reservations API
+// to the portal's.
+var azureTerms = map[string]string{
+	"one year":    AzureTermOneYear,
+	"1 year":      AzureTermOneYear,
+	"p1y":         AzureTermOneYear,
+	"three years": AzureTermThreeYears,
+	"3 years":     AzureTermThreeYears,
+	"p3y":         AzureTermThreeYears,
+	"five years":  AzureTermFiveYears,
+	"5 years":     AzureTermFiveYears,
+	"p5y":         AzureTermFiveYears,
+}
+
+// ParseAzureReservations decodes the CSV the Azure portal exports from the
+// Reservations page. Columns are matched by header name, and the utilization
+// and link columns are ignored. Dates may be RFC 3339, ISO dates or the
+// portal's US dates, and terms may also be ISO 8601 durations such as P1Y.
+func ParseAzureReservations(data []byte) (AzureReservations, error) {
+	t, err := readTable(string(data),
+		azureColumnName, azureColumnReservationID, azureColumnStatus, azureColumnExpiryDate, azureColumnPurchaseDate,
+		azureColumnTerm, azureColumnScope, azureColumnProductName, azureColumnRegion, azureColumnQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("decoding Azure reservations: %w", err)
+	}
+
+	rs := make(AzureReservations, 0, len(t.rows))
+	for i, row := range t.rows {
+		line := i + 2
+		r := AzureReservation{
+			Name:               t.get(row, azureColumnName),
+			ReservationID:      t.get(row, azureColumnReservationID),
+			ReservationOrderID: t.get(row, azureColumnReservationOrderID),
+			Status:             t.get(row, azureColumnStatus),
+			Term:               t.get(row, azureColumnTerm),
+			Scope:              t.get(row, azureColumnScope),
+			ScopeSubscription:  t.get(row, azureColumnScopeSubscription),
+			ScopeResourceGroup: t.get(row, azureColumnScopeResourceGroup),
+			Type:               t.get(row, azureColumnType),
+			InstanceType:       t.get(row, azureColumnProductName),
+			Region:             t.get(row, azureColumnRegion),
+		}
+		if term, ok := azureTerms[strings.ToLower(r.Term)]; ok {
+			r.Term = term
+		}
+		if r.Start, err = parseAzureTime(t.get(row, azureColumnPurchaseDate)); err != nil {
+			return nil, fmt.Errorf("decoding Azure reservations: line %d: %s: %w", line, azureColumnPurchaseDate, err)
+		}
+		if r.End, err = parseAzureTime(t.get(row, azureColumnExpiryDate)); err != nil {
+			return nil, fmt.Errorf("decoding Azure reservations: line %d: %s: %w", line, azureColumnExpiryDate, err)
+		}
+		if q := t.get(row, azureColumnQuantity); q != "" {
+			if r.Quantity, err = strconv.Atoi(q); err != nil {
+				return nil, fmt.Errorf("decoding Azure reservations: line %d: %s: invalid number %q", line, azureColumnQuantity, q)
+			}
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func parseAzureTime(value string) (time.Time, error) {
+	return parseTime(value, time.RFC3339, "2006-01-02", "1/2/2006", "1/2/2006 3:04:05 PM")
+}
+
+// CSV validates the reservations and renders them in the column layout of the
+// Azure portal export, with dates in RFC 3339 and the utilization and link
+// columns left empty.
+func (r AzureReservations) CSV() (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+
+	rows := make([][]string, 0, len(r))
+	for _, res := range r {
+		typ := res.Type
+		if typ == "" {
+			typ = azureTypeVirtualMachines
+		}
+		rows = append(rows, []string{
+			res.Name,
+			res.ReservationID,
+			res.ReservationOrderID,
+			res.Status,
+			formatTime(res.End),
+			formatTime(res.Start),
+			res.Term,
+			res.Scope,
+			res.ScopeSubscription,
+			res.ScopeResourceGroup,
+			typ,
+			res.InstanceType,
+			res.Region,
+			strconv.Itoa(res.Quantity),
+			"", "", "", "",
+		})
+	}
+	out, err := writeTable(azureColumns, rows)
+	if err != nil {
+		return "", fmt.Errorf("encoding Azure reservations: %w", err)
+	}
+	return out, nil
+}
+
+// Validate checks the fields, terms, scopes and periods of the reservations.
+// All problems are reported at once, each prefixed with the index and name of
+// the reservation.
+func (r AzureReservations) Validate() error {
+	v := &validator{}
+	ids := map[string]int{}
+	for i, res := range r {
+		path := fmt.Sprintf("[%d] %s", i, res.Name)
+		v.required(path+": name", res.Name)
+		v.required(path+": status", res.Status)
+		v.required(path+": instance type", res.InstanceType)
+		v.required(path+": region", res.Region)
+		if res.ReservationID == "" {
+			v.addf("%s: reservation ID: must be set", path)
+		} else if j, ok := ids[strings.ToLower(res.ReservationID)]; ok {
+			v.addf("%s: reservation ID %s is also used by [%d]", path, res.ReservationID, j)
+		} else {
+			ids[strings.ToLower(res.ReservationID)] = i
+		}
+		switch res.Term {
+		case AzureTermOneYear, AzureTermThreeYears, AzureTermFiveYears:
+		default:
+			v.addf("%s: term: unsupported value %q, expected %q, %q or %q", path, res.Term, AzureTermOneYear, AzureTermThreeYears, AzureTermFiveYears)
+		}
+		switch res.Scope {
+		case AzureScopeShared, AzureScopeManagementGroup:
+		case AzureScopeSingleSubscription:
+			v.required(path+": scope subscription", res.ScopeSubscription)
+		case AzureScopeSingleResourceGroup:
+			v.required(path+": scope subscription", res.ScopeSubscription)
+			v.required(path+": scope resource group", res.ScopeResourceGroup)
+		default:
+			v.addf("%s: scope: unsupported value %q", path, res.Scope)
+		}
+		if res.Type != "" && res.Type != azureTypeVirtualMachines {
+			v.addf("%s: type: only %s reservations can be imported, got %q", path, azureTypeVirtualMachines, res.Type)
+		}
+		if res.Quantity <= 0 {
+			v.addf("%s: quantity: must be positive, got %d", path, res.Quantity)
+		}
+		v.period(path, res.Start, res.End, true)
+	}
+	return v.err("Azure reservations")
+}
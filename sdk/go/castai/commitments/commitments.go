@@ -0,0 +1,150 @@
+// Package commitments provides typed models of the commitment files that
+// castai.Commitments and castai.Reservations import.
+//
+// castai.Commitments takes the GCP committed use discounts as `gcpCudsJson`,
+// the output of `gcloud compute commitments list --format=json`, and the Azure
+// reservations as `azureReservationsCsv`, the CSV the Azure portal exports from
+// the Reservations page. castai.Reservations takes `reservationsCsv`, a CSV
+// with one row per reservation. Instead of producing these strings with
+// scripts, parse the cloud exports into GCPCommitments, AzureReservations or
+// Reservations, validate or edit them, and render the input the resource
+// expects:
+//
+//	cuds, err := commitments.ParseGCPCommitments(export)
+//	if err != nil {
+//		return err
+//	}
+//	gcpCudsJson, err := cuds.JSON()
+//	if err != nil {
+//		return err
+//	}
+//	_, err = castai.NewCommitments(ctx, "gcp", &castai.CommitmentsArgs{
+//		GcpCudsJson: pulumi.String(gcpCudsJson),
+//	})
+//
+// ParseAWSReservedInstances turns the output of `aws ec2
+// describe-reserved-instances` into Reservations.
+package commitments
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) err(what string) error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid %s: %w", what, errors.Join(v.errs...))
+}
+
+func (v *validator) required(path, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s: must be set", path)
+	}
+}
+
+// period checks that start is set and that end, if set, comes after it.
+func (v *validator) period(path string, start, end time.Time, endRequired bool) {
+	switch {
+	case start.IsZero():
+		v.addf("%s: start must be set", path)
+	case end.IsZero():
+		if endRequired {
+			v.addf("%s: end must be set", path)
+		}
+	case !end.After(start):
+		v.addf("%s: end %s is not after start %s", path, end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+}
+
+// table is a CSV file whose columns are looked up by header name.
+type table struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+// readTable reads a CSV file with a header row. Header names are matched
+// case-insensitively and every name in required must be present.
+func readTable(data string, required ...string) (*table, error) {
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("missing header row")
+	}
+
+	t := &table{columns: map[string]int{}, rows: records[1:]}
+	for i, name := range records[0] {
+		t.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var missing []string
+	for _, name := range required {
+		if _, ok := t.columns[strings.ToLower(name)]; !ok {
+			missing = append(missing, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing columns %s", strings.Join(missing, ", "))
+	}
+	return t, nil
+}
+
+// get returns the trimmed value of a column of a row, or "" if the file has
+// no such column.
+func (t *table) get(row []string, name string) string {
+	i, ok := t.columns[strings.ToLower(name)]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// writeTable renders a header and rows as CSV.
+func writeTable(header []string, rows [][]string) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// parseTime parses a timestamp in the first of layouts that matches. An empty
+// value is the zero time.
+func parseTime(value string, layouts ...string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// formatTime renders t as RFC 3339, or "" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
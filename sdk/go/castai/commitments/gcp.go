@@ -0,0 +1,285 @@
+package commitments
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GCP commitment plans.
+const (
+	GCPPlanTwelveMonth    = "TWELVE_MONTH"
+	GCPPlanThirtySixMonth = "THIRTY_SIX_MONTH"
+)
+
+// GCP commitment categories.
+const (
+	GCPCategoryMachine = "MACHINE"
+	GCPCategoryLicense = "LICENSE"
+)
+
+const (
+	gcpKind            = "compute#commitment"
+	gcpComputeURL      = "https://www.googleapis.com/compute/v1/"
+	gcpTimestampLayout = "2006-01-02T15:04:05.000-07:00"
+
+	gcpResourceVCPU        = "VCPU"
+	gcpResourceMemory      = "MEMORY"
+	gcpResourceLocalSSD    = "LOCAL_SSD"
+	gcpResourceAccelerator = "ACCELERATOR"
+)
+
+// GCP commitment statuses.
+const (
+	GCPStatusActive       = "ACTIVE"
+	GCPStatusCreating     = "CREATING"
+	GCPStatusNotYetActive = "NOT_YET_ACTIVE"
+	GCPStatusExpired      = "EXPIRED"
+	GCPStatusCancelled    = "CANCELLED"
+)
+
+var gcpName = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// GCPCommitment is a GCP committed use discount.
+type GCPCommitment struct {
+	// ID is the numeric ID GCP assigns to the commitment.
+	ID   string
+	Name string
+	// Project and Region locate the commitment, e.g. "my-project" and
+	// "us-east4".
+	Project string
+	Region  string
+	// Plan is GCPPlanTwelveMonth or GCPPlanThirtySixMonth.
+	Plan string
+	// Type is the machine series the commitment covers, e.g.
+	// "GENERAL_PURPOSE_E2" or "COMPUTE_OPTIMIZED_C2D".
+	Type string
+	// Category is GCPCategoryMachine, the default, or GCPCategoryLicense.
+	Category  string
+	Status    string
+	Start     time.Time
+	End       time.Time
+	AutoRenew bool
+
+	VCPUs        int64
+	MemoryMB     int64
+	LocalSSDGB   int64
+	Accelerators []GCPAccelerator
+}
+
+// GCPAccelerator is a number of GPUs of one type covered by a commitment.
+type GCPAccelerator struct {
+	// Type is the accelerator type URL or name, e.g. "nvidia-tesla-t4".
+	Type  string
+	Count int64
+}
+
+// GCPCommitments is the `gcpCudsJson` input of castai.Commitments.
+type GCPCommitments []GCPCommitment
+
+// gcpCommitment is a commitment as gcloud lists it. The keys are in the order
+// gcloud writes them.
+type gcpCommitment struct {
+	AutoRenew      bool          `json:"autoRenew"`
+	Category       string        `json:"category"`
+	EndTimestamp   string        `json:"endTimestamp"`
+	ID             string        `json:"id"`
+	Kind           string        `json:"kind"`
+	Name           string        `json:"name"`
+	Plan           string        `json:"plan"`
+	Region         string        `json:"region"`
+	Resources      []gcpResource `json:"resources"`
+	SelfLink       string        `json:"selfLink,omitempty"`
+	StartTimestamp string        `json:"startTimestamp"`
+	Status         string        `json:"status"`
+	Type           string        `json:"type"`
+}
+
+type gcpResource struct {
+	AcceleratorType string    `json:"acceleratorType,omitempty"`
+	Amount          gcpAmount `json:"amount"`
+	Type            string    `json:"type"`
+}
+
+// gcpAmount is an int64, which the GCP API writes as a JSON string.
+type gcpAmount int64
+
+func (a gcpAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(a), 10))
+}
+
+func (a *gcpAmount) UnmarshalJSON(data []byte) error {
+	n, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s", data)
+	}
+	*a = gcpAmount(n)
+	return nil
+}
+
+// ParseGCPCommitments decodes the output of `gcloud compute commitments list
+// --format=json`. Fields the model does not cover, such as descriptions and
+// reservations, are dropped.
+func ParseGCPCommitments(data []byte) (GCPCommitments, error) {
+	var raw []gcpCommitment
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decoding GCP commitments: %w", err)
+	}
+
+	cuds := make(GCPCommitments, 0, len(raw))
+	for i, r := range raw {
+		c := GCPCommitment{
+			ID:        r.ID,
+			Name:      r.Name,
+			Plan:      r.Plan,
+			Type:      r.Type,
+			Category:  r.Category,
+			Status:    r.Status,
+			AutoRenew: r.AutoRenew,
+		}
+		c.Project, c.Region = splitGCPRegion(r.Region)
+
+		var err error
+		if c.Start, err = parseTime(r.StartTimestamp, time.RFC3339); err != nil {
+			return nil, fmt.Errorf("decoding GCP commitments: [%d].startTimestamp: %w", i, err)
+		}
+		if c.End, err = parseTime(r.EndTimestamp, time.RFC3339); err != nil {
+			return nil, fmt.Errorf("decoding GCP commitments: [%d].endTimestamp: %w", i, err)
+		}
+		for j, res := range r.Resources {
+			switch res.Type {
+			case gcpResourceVCPU:
+				c.VCPUs += int64(res.Amount)
+			case gcpResourceMemory:
+				c.MemoryMB += int64(res.Amount)
+			case gcpResourceLocalSSD:
+				c.LocalSSDGB += int64(res.Amount)
+			case gcpResourceAccelerator:
+				c.Accelerators = append(c.Accelerators, GCPAccelerator{Type: res.AcceleratorType, Count: int64(res.Amount)})
+			default:
+				return nil, fmt.Errorf("decoding GCP commitments: [%d].resources[%d].type: unsupported value %q", i, j, res.Type)
+			}
+		}
+		cuds = append(cuds, c)
+	}
+	return cuds, nil
+}
+
+// splitGCPRegion returns the project and region of a region URL. A bare
+// region name has no project.
+func splitGCPRegion(region string) (project, name string) {
+	path := strings.TrimPrefix(region, gcpComputeURL)
+	if p, r, ok := strings.Cut(path, "/regions/"); ok && strings.HasPrefix(p, "projects/") {
+		return strings.TrimPrefix(p, "projects/"), r
+	}
+	return "", region
+}
+
+// JSON validates the commitments and renders them the way gcloud lists them.
+// Regions are written as URLs when the project is known. Timestamps keep the
+// offset they were parsed with.
+func (c GCPCommitments) JSON() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+
+	raw := make([]gcpCommitment, 0, len(c))
+	for _, cud := range c {
+		r := gcpCommitment{
+			AutoRenew:      cud.AutoRenew,
+			Category:       cud.Category,
+			EndTimestamp:   cud.End.Format(gcpTimestampLayout),
+			ID:             cud.ID,
+			Kind:           gcpKind,
+			Name:           cud.Name,
+			Plan:           cud.Plan,
+			Region:         cud.Region,
+			StartTimestamp: cud.Start.Format(gcpTimestampLayout),
+			Status:         cud.Status,
+			Type:           cud.Type,
+		}
+		if r.Category == "" {
+			r.Category = GCPCategoryMachine
+		}
+		if cud.Project != "" {
+			r.Region = fmt.Sprintf("%sprojects/%s/regions/%s", gcpComputeURL, cud.Project, cud.Region)
+			r.SelfLink = r.Region + "/commitments/" + cud.Name
+		}
+		if cud.VCPUs > 0 {
+			r.Resources = append(r.Resources, gcpResource{Type: gcpResourceVCPU, Amount: gcpAmount(cud.VCPUs)})
+		}
+		if cud.MemoryMB > 0 {
+			r.Resources = append(r.Resources, gcpResource{Type: gcpResourceMemory, Amount: gcpAmount(cud.MemoryMB)})
+		}
+		if cud.LocalSSDGB > 0 {
+			r.Resources = append(r.Resources, gcpResource{Type: gcpResourceLocalSSD, Amount: gcpAmount(cud.LocalSSDGB)})
+		}
+		for _, a := range cud.Accelerators {
+			r.Resources = append(r.Resources, gcpResource{Type: gcpResourceAccelerator, AcceleratorType: a.Type, Amount: gcpAmount(a.Count)})
+		}
+		raw = append(raw, r)
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(raw); err != nil {
+		return "", fmt.Errorf("encoding GCP commitments: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Validate checks names, IDs, plans, statuses, resources and periods. All
+// problems are reported at once, each prefixed with the index and name of the
+// commitment.
+func (c GCPCommitments) Validate() error {
+	v := &validator{}
+	ids := map[string]int{}
+	for i, cud := range c {
+		path := fmt.Sprintf("[%d] %s", i, cud.Name)
+		if !gcpName.MatchString(cud.Name) {
+			v.addf("%s: name %q is not a valid GCP resource name", path, cud.Name)
+		}
+		if _, err := strconv.ParseUint(cud.ID, 10, 64); err != nil {
+			v.addf("%s: id %q is not a numeric GCP ID", path, cud.ID)
+		} else if j, ok := ids[cud.ID]; ok {
+			v.addf("%s: id %s is also used by [%d]", path, cud.ID, j)
+		} else {
+			ids[cud.ID] = i
+		}
+		v.required(path+": region", cud.Region)
+		v.required(path+": type", cud.Type)
+		switch cud.Plan {
+		case GCPPlanTwelveMonth, GCPPlanThirtySixMonth:
+		default:
+			v.addf("%s: plan: unsupported value %q, expected %q or %q", path, cud.Plan, GCPPlanTwelveMonth, GCPPlanThirtySixMonth)
+		}
+		switch cud.Status {
+		case GCPStatusActive, GCPStatusCreating, GCPStatusNotYetActive, GCPStatusExpired, GCPStatusCancelled:
+		default:
+			v.addf("%s: status: unsupported value %q", path, cud.Status)
+		}
+		switch cud.Category {
+		case "", GCPCategoryMachine:
+			if cud.VCPUs <= 0 || cud.MemoryMB <= 0 {
+				v.addf("%s: machine commitments must cover vCPUs and memory, got %d vCPUs and %d MB", path, cud.VCPUs, cud.MemoryMB)
+			}
+		case GCPCategoryLicense:
+		default:
+			v.addf("%s: category: unsupported value %q", path, cud.Category)
+		}
+		for j, a := range cud.Accelerators {
+			v.required(fmt.Sprintf("%s: accelerators[%d].type", path, j), a.Type)
+			if a.Count <= 0 {
+				v.addf("%s: accelerators[%d].count: must be positive, got %d", path, j, a.Count)
+			}
+		}
+		v.period(path, cud.Start, cud.End, true)
+	}
+	return v.err("GCP commitments")
+}
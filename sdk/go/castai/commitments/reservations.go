@@ -0,0 +1,218 @@
+package commitments
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reservation providers.
+const (
+	ProviderAWS   = "aws"
+	ProviderGCP   = "gcp"
+	ProviderAzure = "azure"
+)
+
+// reservationColumns are the columns of the `reservationsCsv` input, in the
+// order CSV writes them.
+var reservationColumns = []string{
+	"name", "provider", "region", "instance_type", "price", "count", "start_date", "end_date", "zone_id", "zone_name",
+}
+
+// Reservation is a reserved instance that castai.Reservations imports.
+type Reservation struct {
+	// Name must be unique among the reservations of an instance type in a
+	// region.
+	Name string
+	// Provider is one of ProviderAWS, ProviderGCP and ProviderAzure.
+	Provider     string
+	Region       string
+	InstanceType string
+	// Price is the effective hourly price of one instance. Zero leaves it
+	// unset.
+	Price float64
+	Count int
+	Start time.Time
+	// End is the zero time for reservations without an end date.
+	End time.Time
+	// ZoneID and ZoneName restrict zonal reservations to a zone, e.g.
+	// "use1-az1" and "us-east-1a". AWS and GCP zone names start with the
+	// region.
+	ZoneID   string
+	ZoneName string
+}
+
+// Reservations is the `reservationsCsv` input of castai.Reservations.
+type Reservations []Reservation
+
+// ParseReservations decodes a `reservationsCsv` file. Columns are matched by
+// header name; price, end_date, zone_id and zone_name are optional.
+func ParseReservations(data []byte) (Reservations, error) {
+	t, err := readTable(string(data), "name", "provider", "region", "instance_type", "count", "start_date")
+	if err != nil {
+		return nil, fmt.Errorf("decoding reservations: %w", err)
+	}
+
+	rs := make(Reservations, 0, len(t.rows))
+	for i, row := range t.rows {
+		line := i + 2
+		r := Reservation{
+			Name:         t.get(row, "name"),
+			Provider:     t.get(row, "provider"),
+			Region:       t.get(row, "region"),
+			InstanceType: t.get(row, "instance_type"),
+			ZoneID:       t.get(row, "zone_id"),
+			ZoneName:     t.get(row, "zone_name"),
+		}
+		if p := t.get(row, "price"); p != "" {
+			if r.Price, err = strconv.ParseFloat(p, 64); err != nil {
+				return nil, fmt.Errorf("decoding reservations: line %d: price: invalid number %q", line, p)
+			}
+		}
+		if c := t.get(row, "count"); c != "" {
+			if r.Count, err = strconv.Atoi(c); err != nil {
+				return nil, fmt.Errorf("decoding reservations: line %d: count: invalid number %q", line, c)
+			}
+		}
+		if r.Start, err = parseTime(t.get(row, "start_date"), time.RFC3339); err != nil {
+			return nil, fmt.Errorf("decoding reservations: line %d: start_date: %w", line, err)
+		}
+		if r.End, err = parseTime(t.get(row, "end_date"), time.RFC3339); err != nil {
+			return nil, fmt.Errorf("decoding reservations: line %d: end_date: %w", line, err)
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// awsReservedInstances is the output of `aws ec2 describe-reserved-instances`.
+type awsReservedInstances struct {
+	ReservedInstances []struct {
+		ReservedInstancesID string    `json:"ReservedInstancesId"`
+		InstanceType        string    `json:"InstanceType"`
+		AvailabilityZone    string    `json:"AvailabilityZone"`
+		InstanceCount       int       `json:"InstanceCount"`
+		Start               time.Time `json:"Start"`
+		End                 time.Time `json:"End"`
+		Duration            int64     `json:"Duration"`
+		FixedPrice          float64   `json:"FixedPrice"`
+		UsagePrice          float64   `json:"UsagePrice"`
+		RecurringCharges    []struct {
+			Amount    float64 `json:"Amount"`
+			Frequency string  `json:"Frequency"`
+		} `json:"RecurringCharges"`
+		State string `json:"State"`
+	} `json:"ReservedInstances"`
+}
+
+// ParseAWSReservedInstances converts the output of `aws ec2
+// describe-reserved-instances` in region into Reservations. Retired
+// reservations are skipped. Zonal reservations keep their availability zone.
+// The price is the hourly usage and recurring charges plus the upfront price
+// spread over the term.
+func ParseAWSReservedInstances(data []byte, region string) (Reservations, error) {
+	var out awsReservedInstances
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decoding AWS reserved instances: %w", err)
+	}
+
+	var rs Reservations
+	for _, ri := range out.ReservedInstances {
+		if ri.State == "retired" {
+			continue
+		}
+		price := ri.UsagePrice
+		for _, c := range ri.RecurringCharges {
+			if c.Frequency == "Hourly" {
+				price += c.Amount
+			}
+		}
+		if ri.Duration > 0 {
+			price += ri.FixedPrice / (float64(ri.Duration) / 3600)
+		}
+		rs = append(rs, Reservation{
+			Name:         ri.ReservedInstancesID,
+			Provider:     ProviderAWS,
+			Region:       region,
+			InstanceType: ri.InstanceType,
+			Price:        math.Round(price*1e6) / 1e6,
+			Count:        ri.InstanceCount,
+			Start:        ri.Start.UTC(),
+			End:          ri.End.UTC(),
+			ZoneName:     ri.AvailabilityZone,
+		})
+	}
+	return rs, nil
+}
+
+// CSV validates the reservations and renders the `reservationsCsv` input,
+// with dates in RFC 3339.
+func (r Reservations) CSV() (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+
+	rows := make([][]string, 0, len(r))
+	for _, res := range r {
+		price := ""
+		if res.Price != 0 {
+			price = strconv.FormatFloat(res.Price, 'f', -1, 64)
+		}
+		rows = append(rows, []string{
+			res.Name,
+			res.Provider,
+			res.Region,
+			res.InstanceType,
+			price,
+			strconv.Itoa(res.Count),
+			formatTime(res.Start),
+			formatTime(res.End),
+			res.ZoneID,
+			res.ZoneName,
+		})
+	}
+	out, err := writeTable(reservationColumns, rows)
+	if err != nil {
+		return "", fmt.Errorf("encoding reservations: %w", err)
+	}
+	return out, nil
+}
+
+// Validate checks the fields and periods of the reservations and that names
+// are unique per region and instance type. All problems are reported at once,
+// each prefixed with the index and name of the reservation.
+func (r Reservations) Validate() error {
+	v := &validator{}
+	names := map[string]int{}
+	for i, res := range r {
+		path := fmt.Sprintf("[%d] %s", i, res.Name)
+		v.required(path+": name", res.Name)
+		v.required(path+": region", res.Region)
+		v.required(path+": instance type", res.InstanceType)
+		switch res.Provider {
+		case ProviderAWS, ProviderGCP, ProviderAzure:
+		default:
+			v.addf("%s: provider: unsupported value %q, expected %q, %q or %q", path, res.Provider, ProviderAWS, ProviderGCP, ProviderAzure)
+		}
+		key := strings.Join([]string{res.Region, res.InstanceType, res.Name}, "/")
+		if j, ok := names[key]; ok && res.Name != "" {
+			v.addf("%s: name is also used by [%d] for %s in %s", path, j, res.InstanceType, res.Region)
+		} else {
+			names[key] = i
+		}
+		if res.Count <= 0 {
+			v.addf("%s: count: must be positive, got %d", path, res.Count)
+		}
+		if res.Price < 0 {
+			v.addf("%s: price: must not be negative, got %g", path, res.Price)
+		}
+		if res.Provider != ProviderAzure && res.ZoneName != "" && res.Region != "" && !strings.HasPrefix(res.ZoneName, res.Region) {
+			v.addf("%s: zone %s is not in region %s", path, res.ZoneName, res.Region)
+		}
+		v.period(path, res.Start, res.End, false)
+	}
+	return v.err("reservations")
+}
@@ -37,7 +37,80 @@ func GetApiUrl(ctx *pulumi.Context) string {
 	return value
 }
 
+// Default CAST AI cluster ID for resources that do not set one.
+func GetDefaultClusterId(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "castai:defaultClusterId")
+	if err == nil {
+		return v
+	}
+	var value string
+	if d := internal.GetEnvOrDefault(nil, nil, "CASTAI_DEFAULT_CLUSTER_ID"); d != nil {
+		value = d.(string)
+	}
+	return value
+}
+
+// Maximum number of CAST AI API requests in flight. `0` means no limit.
+func GetMaxConcurrentRequests(ctx *pulumi.Context) int {
+	v, err := config.TryInt(ctx, "castai:maxConcurrentRequests")
+	if err == nil {
+		return v
+	}
+	var value int
+	if d := internal.GetEnvOrDefault(0, internal.ParseEnvInt, "CASTAI_MAX_CONCURRENT_REQUESTS"); d != nil {
+		value = d.(int)
+	}
+	return value
+}
+
+// Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+func GetMaxRetries(ctx *pulumi.Context) int {
+	v, err := config.TryInt(ctx, "castai:maxRetries")
+	if err == nil {
+		return v
+	}
+	var value int
+	if d := internal.GetEnvOrDefault(3, internal.ParseEnvInt, "CASTAI_MAX_RETRIES"); d != nil {
+		value = d.(int)
+	}
+	return value
+}
+
 // CAST AI organization ID. Required when the API token has access to multiple organizations.
 func GetOrganizationId(ctx *pulumi.Context) string {
-	return config.Get(ctx, "castai:organizationId")
+	v, err := config.Try(ctx, "castai:organizationId")
+	if err == nil {
+		return v
+	}
+	var value string
+	if d := internal.GetEnvOrDefault(nil, nil, "CASTAI_ORGANIZATION_ID"); d != nil {
+		value = d.(string)
+	}
+	return value
+}
+
+// Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+func GetRequestTimeout(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "castai:requestTimeout")
+	if err == nil {
+		return v
+	}
+	var value string
+	if d := internal.GetEnvOrDefault("1m", nil, "CASTAI_REQUEST_TIMEOUT"); d != nil {
+		value = d.(string)
+	}
+	return value
+}
+
+// Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+func GetRetryBackoff(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "castai:retryBackoff")
+	if err == nil {
+		return v
+	}
+	var value string
+	if d := internal.GetEnvOrDefault("1s", nil, "CASTAI_RETRY_BACKOFF"); d != nil {
+		value = d.(string)
+	}
+	return value
 }
@@ -54,9 +54,6 @@ func NewNodeConfiguration(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.Subnets == nil {
 		return nil, errors.New("invalid value for required argument 'Subnets'")
 	}
@@ -155,7 +152,7 @@ func (NodeConfigurationState) ElementType() reflect.Type {
 type nodeConfigurationArgs struct {
 	Aks *NodeConfigurationAks `pulumi:"aks"`
 	// CAST AI cluster id
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// Optional container runtime to be used by kubelet. Applicable for EKS only.  Supported values include: `dockerd`, `containerd`
 	ContainerRuntime *string `pulumi:"containerRuntime"`
 	// Disk to CPU ratio. Sets the number of GiBs to be added for every CPU on the node. Defaults to 0
@@ -189,7 +186,7 @@ type nodeConfigurationArgs struct {
 type NodeConfigurationArgs struct {
 	Aks NodeConfigurationAksPtrInput
 	// CAST AI cluster id
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// Optional container runtime to be used by kubelet. Applicable for EKS only.  Supported values include: `dockerd`, `containerd`
 	ContainerRuntime pulumi.StringPtrInput
 	// Disk to CPU ratio. Sets the number of GiBs to be added for every CPU on the node. Defaults to 0
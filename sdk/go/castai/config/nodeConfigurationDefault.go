@@ -28,9 +28,6 @@ func NewNodeConfigurationDefault(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.ConfigurationId == nil {
 		return nil, errors.New("invalid value for required argument 'ConfigurationId'")
 	}
@@ -76,7 +73,7 @@ func (NodeConfigurationDefaultState) ElementType() reflect.Type {
 
 type nodeConfigurationDefaultArgs struct {
 	// CAST AI cluster id
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// Id of the node configuration
 	ConfigurationId string `pulumi:"configurationId"`
 }
@@ -84,7 +81,7 @@ type nodeConfigurationDefaultArgs struct {
 // The set of arguments for constructing a NodeConfigurationDefault resource.
 type NodeConfigurationDefaultArgs struct {
 	// CAST AI cluster id
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// Id of the node configuration
 	ConfigurationId pulumi.StringInput
 }
@@ -7,7 +7,6 @@ import (
 	"context"
 	"reflect"
 
-	"errors"
 	"github.com/castai/pulumi-castai/sdk/go/castai/internal"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
@@ -23,12 +22,9 @@ type EksUserArn struct {
 func NewEksUserArn(ctx *pulumi.Context,
 	name string, args *EksUserArnArgs, opts ...pulumi.ResourceOption) (*EksUserArn, error) {
 	if args == nil {
-		return nil, errors.New("missing one or more required arguments")
+		args = &EksUserArnArgs{}
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	opts = internal.PkgResourceDefaultOpts(opts)
 	var resource EksUserArn
 	err := ctx.RegisterResource("castai:aws:EksUserArn", name, args, &resource, opts...)
@@ -66,12 +62,12 @@ func (EksUserArnState) ElementType() reflect.Type {
 }
 
 type eksUserArnArgs struct {
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 }
 
 // The set of arguments for constructing a EksUserArn resource.
 type EksUserArnArgs struct {
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 }
 
 func (EksUserArnArgs) ElementType() reflect.Type {
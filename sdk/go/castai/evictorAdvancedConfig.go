@@ -29,9 +29,6 @@ func NewEvictorAdvancedConfig(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.EvictorAdvancedConfigs == nil {
 		return nil, errors.New("invalid value for required argument 'EvictorAdvancedConfigs'")
 	}
@@ -77,7 +74,7 @@ func (EvictorAdvancedConfigState) ElementType() reflect.Type {
 
 type evictorAdvancedConfigArgs struct {
 	// CAST AI cluster id.
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// evictor advanced configuration to target specific node/pod
 	EvictorAdvancedConfigs []autoscaling.EvictorAdvancedConfigEvictorAdvancedConfig `pulumi:"evictorAdvancedConfigs"`
 }
@@ -85,7 +82,7 @@ type evictorAdvancedConfigArgs struct {
 // The set of arguments for constructing a EvictorAdvancedConfig resource.
 type EvictorAdvancedConfigArgs struct {
 	// CAST AI cluster id.
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// evictor advanced configuration to target specific node/pod
 	EvictorAdvancedConfigs autoscaling.EvictorAdvancedConfigEvictorAdvancedConfigArrayInput
 }
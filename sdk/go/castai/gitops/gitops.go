@@ -0,0 +1,274 @@
+// Package gitops loads CAST AI resources from a directory of YAML specs and
+// registers them for a cluster.
+//
+// Each YAML document describes one resource by its kind, a name and the
+// resource inputs, using the input names of the provider schema:
+//
+//	kind: NodeTemplate
+//	name: gpu
+//	spec:
+//	  configurationId: default # a NodeConfiguration in the same set, or an ID
+//	  constraints:
+//	    gpu:
+//	      manufacturers: [NVIDIA]
+//
+// The supported kinds are NodeTemplate, NodeConfiguration,
+// WorkloadScalingPolicy, EvictorAdvancedConfig, PodMutation and
+// HibernationSchedule. LoadDir reads every .yaml and .yml file of a directory
+// tree and checks each spec against the inputs of its resource, reporting
+// problems with their file and line. Register then creates the resources for
+// one cluster:
+//
+//	set, err := gitops.LoadDir("castai")
+//	if err != nil {
+//		return err
+//	}
+//	resources, err := gitops.Register(ctx, cluster.ID(), set)
+//
+// The cluster is set by Register: `clusterId` for most kinds and the cluster
+// assignments of a HibernationSchedule. The resource `name` defaults to the
+// document name.
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a resource spec read from a YAML document.
+type Document struct {
+	// Kind is one of the Kind constants.
+	Kind string
+	// Name is the Pulumi resource name, unique per kind.
+	Name string
+	// Spec are the resource inputs, as plain values keyed by input name.
+	Spec map[string]interface{}
+	// Pos is where the document starts.
+	Pos Pos
+}
+
+// Pos is a position in a YAML file.
+type Pos struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Error is a problem with a document, at the position of the offending value.
+type Error struct {
+	Pos Pos
+	// Path is the input the error is about, e.g.
+	// "spec.constraints.minCpu", or empty for the document itself.
+	Path string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.Pos, e.Path, e.Msg)
+}
+
+// Errors are all the problems found in a set of documents, in file order.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Set are the documents loaded from a directory.
+type Set struct {
+	Documents []*Document
+}
+
+// Find returns the document of a kind with a name, or nil.
+func (s *Set) Find(kind, name string) *Document {
+	for _, d := range s.Documents {
+		if d.Kind == kind && d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// LoadDir reads the .yaml and .yml files under dir, in lexical order. If any
+// document is invalid, the error is an Errors listing every problem.
+func LoadDir(dir string) (*Set, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	l := newLoader()
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		l.read(path, f)
+		f.Close()
+	}
+	return l.result()
+}
+
+// Load reads the documents of a single YAML stream. file is used in error
+// positions.
+func Load(file string, r io.Reader) (*Set, error) {
+	l := newLoader()
+	l.read(file, r)
+	return l.result()
+}
+
+type loader struct {
+	set  *Set
+	errs Errors
+	// seen are the positions of the documents read so far, valid or not, by
+	// kind and name.
+	seen map[[2]string]Pos
+	// singles are the first documents of the single kinds.
+	singles map[string]*Document
+}
+
+func newLoader() *loader {
+	return &loader{set: &Set{}, seen: map[[2]string]Pos{}, singles: map[string]*Document{}}
+}
+
+// syntaxError matches the position yaml.v3 puts in syntax errors.
+var syntaxError = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+func (l *loader) read(file string, r io.Reader) {
+	dec := yaml.NewDecoder(r)
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			// The decoder cannot continue after a syntax error.
+			e := &Error{Pos: Pos{File: file}, Msg: err.Error()}
+			if m := syntaxError.FindStringSubmatch(err.Error()); m != nil {
+				e.Pos.Line, _ = strconv.Atoi(m[1])
+				e.Msg = m[2]
+			}
+			l.errs = append(l.errs, e)
+			return
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		if doc := l.document(file, node.Content[0]); doc != nil {
+			l.set.Documents = append(l.set.Documents, doc)
+		}
+	}
+}
+
+func (l *loader) result() (*Set, error) {
+	if len(l.errs) > 0 {
+		return nil, l.errs
+	}
+	return l.set, nil
+}
+
+func (l *loader) errorf(file string, n *yaml.Node, path, format string, args ...interface{}) {
+	l.errs = append(l.errs, &Error{Pos: Pos{File: file, Line: n.Line, Column: n.Column}, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// document checks the kind, name and spec of a document and returns it, or
+// nil if it is invalid.
+func (l *loader) document(file string, n *yaml.Node) *Document {
+	if n.Kind != yaml.MappingNode {
+		l.errorf(file, n, "", "expected a mapping with kind, name and spec")
+		return nil
+	}
+	doc := &Document{Pos: Pos{File: file, Line: n.Line, Column: n.Column}}
+	var spec *yaml.Node
+	valid := true
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		switch key.Value {
+		case "kind", "name":
+			if value.Kind != yaml.ScalarNode || value.Tag != "!!str" || value.Value == "" {
+				l.errorf(file, value, key.Value, "expected a non-empty string")
+				valid = false
+				continue
+			}
+			if key.Value == "kind" {
+				doc.Kind = value.Value
+			} else {
+				doc.Name = value.Value
+			}
+		case "spec":
+			spec = value
+		default:
+			l.errorf(file, key, key.Value, "unknown field, expected kind, name or spec")
+			valid = false
+		}
+	}
+	if doc.Kind == "" || doc.Name == "" {
+		if valid {
+			l.errorf(file, n, "", "kind and name must be set")
+		}
+		return nil
+	}
+
+	k, ok := kinds[doc.Kind]
+	if !ok {
+		l.errorf(file, n, "kind", "unknown kind %q, expected one of %s", doc.Kind, strings.Join(kindNames(), ", "))
+		return nil
+	}
+	if prev, ok := l.seen[[2]string{doc.Kind, doc.Name}]; ok {
+		l.errorf(file, n, "name", "%s %s is also defined at %s", doc.Kind, doc.Name, prev)
+		valid = false
+	} else if first := l.singles[doc.Kind]; first != nil {
+		l.errorf(file, n, "kind", "a cluster has a single %s, %s is also defined at %s", doc.Kind, first.Name, first.Pos)
+		valid = false
+	}
+	l.seen[[2]string{doc.Kind, doc.Name}] = doc.Pos
+	if k.single && l.singles[doc.Kind] == nil {
+		l.singles[doc.Kind] = doc
+	}
+
+	c := &checker{file: file}
+	if spec == nil {
+		spec = &yaml.Node{Kind: yaml.MappingNode, Line: n.Line, Column: n.Column}
+	}
+	if v, ok := c.object(spec, "spec", k.inputs, k.managed); ok {
+		doc.Spec = v.(map[string]interface{})
+	}
+	l.errs = append(l.errs, c.errs...)
+	if !valid || len(c.errs) > 0 {
+		return nil
+	}
+	return doc
+}
@@ -0,0 +1,97 @@
+package gitops
+
+import (
+	"fmt"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Resources are the resources Register created, by document name.
+type Resources struct {
+	NodeConfigurations      map[string]*config.NodeConfiguration
+	NodeTemplates           map[string]*config.NodeTemplate
+	WorkloadScalingPolicies map[string]*castai.WorkloadScalingPolicy
+	EvictorAdvancedConfigs  map[string]*castai.EvictorAdvancedConfig
+	PodMutations            map[string]*castai.PodMutation
+	HibernationSchedules    map[string]*castai.HibernationSchedule
+}
+
+// Register creates the resources of a set for the cluster with clusterID.
+// Node configurations are created first: a node template whose
+// `configurationId` is the name of a NodeConfiguration document of the set
+// gets the ID of that resource. opts apply to every resource.
+func Register(ctx *pulumi.Context, clusterID pulumi.StringInput, set *Set, opts ...pulumi.ResourceOption) (*Resources, error) {
+	res := &Resources{
+		NodeConfigurations:      map[string]*config.NodeConfiguration{},
+		NodeTemplates:           map[string]*config.NodeTemplate{},
+		WorkloadScalingPolicies: map[string]*castai.WorkloadScalingPolicy{},
+		EvictorAdvancedConfigs:  map[string]*castai.EvictorAdvancedConfig{},
+		PodMutations:            map[string]*castai.PodMutation{},
+		HibernationSchedules:    map[string]*castai.HibernationSchedule{},
+	}
+
+	for _, kind := range registerOrder {
+		for _, doc := range set.Documents {
+			if doc.Kind != kind {
+				continue
+			}
+			if err := res.register(ctx, clusterID, doc, opts); err != nil {
+				return nil, fmt.Errorf("%s: %s %s: %w", doc.Pos, doc.Kind, doc.Name, err)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (res *Resources) register(ctx *pulumi.Context, clusterID pulumi.StringInput, doc *Document, opts []pulumi.ResourceOption) error {
+	props := pulumi.Map{}
+	for name, v := range doc.Spec {
+		props[name] = pulumi.Any(v)
+	}
+	if _, ok := doc.Spec["name"]; !ok && doc.Kind != KindEvictorAdvancedConfig {
+		props["name"] = pulumi.String(doc.Name)
+	}
+	if doc.Kind == KindHibernationSchedule {
+		props["clusterAssignments"] = pulumi.Map{
+			"assignments": pulumi.Array{pulumi.Map{"clusterId": clusterID}},
+		}
+	} else {
+		props["clusterId"] = clusterID
+	}
+
+	token := kinds[doc.Kind].token
+	switch doc.Kind {
+	case KindNodeConfiguration:
+		var r config.NodeConfiguration
+		res.NodeConfigurations[doc.Name] = &r
+		return ctx.RegisterResource(token, doc.Name, props, &r, opts...)
+	case KindNodeTemplate:
+		if id, ok := doc.Spec["configurationId"].(string); ok {
+			if nc, ok := res.NodeConfigurations[id]; ok {
+				props["configurationId"] = nc.ID()
+			}
+		}
+		var r config.NodeTemplate
+		res.NodeTemplates[doc.Name] = &r
+		return ctx.RegisterResource(token, doc.Name, props, &r, opts...)
+	case KindWorkloadScalingPolicy:
+		var r castai.WorkloadScalingPolicy
+		res.WorkloadScalingPolicies[doc.Name] = &r
+		return ctx.RegisterResource(token, doc.Name, props, &r, opts...)
+	case KindEvictorAdvancedConfig:
+		var r castai.EvictorAdvancedConfig
+		res.EvictorAdvancedConfigs[doc.Name] = &r
+		return ctx.RegisterResource(token, doc.Name, props, &r, opts...)
+	case KindPodMutation:
+		var r castai.PodMutation
+		res.PodMutations[doc.Name] = &r
+		return ctx.RegisterResource(token, doc.Name, props, &r, opts...)
+	case KindHibernationSchedule:
+		var r castai.HibernationSchedule
+		res.HibernationSchedules[doc.Name] = &r
+		return ctx.RegisterResource(token, doc.Name, props, &r, opts...)
+	}
+	return fmt.Errorf("unknown kind")
+}
@@ -0,0 +1,299 @@
+package gitops
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of documents.
+const (
+	KindNodeConfiguration     = "NodeConfiguration"
+	KindNodeTemplate          = "NodeTemplate"
+	KindWorkloadScalingPolicy = "WorkloadScalingPolicy"
+	KindEvictorAdvancedConfig = "EvictorAdvancedConfig"
+	KindPodMutation           = "PodMutation"
+	KindHibernationSchedule   = "HibernationSchedule"
+)
+
+// kind describes the resource behind a document kind.
+type kind struct {
+	token string
+	// inputs is the plain input type of the resource, whose pulumi tags are
+	// the input names of the provider schema. Pointers, slices and maps are
+	// optional inputs, other fields are required.
+	inputs reflect.Type
+	// managed are the inputs Register sets.
+	managed []string
+	// single is set for resources a cluster has one of.
+	single bool
+}
+
+var kinds = map[string]kind{
+	KindNodeConfiguration: {
+		token:   "castai:config/node:NodeConfiguration",
+		inputs:  config.NodeConfigurationArgs{}.ElementType(),
+		managed: []string{"clusterId"},
+	},
+	KindNodeTemplate: {
+		token:   "castai:config/node:NodeTemplate",
+		inputs:  config.NodeTemplateArgs{}.ElementType(),
+		managed: []string{"clusterId"},
+	},
+	KindWorkloadScalingPolicy: {
+		token:   "castai:workload:WorkloadScalingPolicy",
+		inputs:  castai.WorkloadScalingPolicyArgs{}.ElementType(),
+		managed: []string{"clusterId"},
+	},
+	KindEvictorAdvancedConfig: {
+		token:   "castai:autoscaling:EvictorAdvancedConfig",
+		inputs:  castai.EvictorAdvancedConfigArgs{}.ElementType(),
+		managed: []string{"clusterId"},
+		single:  true,
+	},
+	KindPodMutation: {
+		token:   "castai:index:PodMutation",
+		inputs:  castai.PodMutationArgs{}.ElementType(),
+		managed: []string{"clusterId"},
+	},
+	KindHibernationSchedule: {
+		token:   "castai:rebalancing:HibernationSchedule",
+		inputs:  castai.HibernationScheduleArgs{}.ElementType(),
+		managed: []string{"clusterAssignments"},
+	},
+}
+
+// registerOrder is the order Register creates kinds in, so that node
+// templates can refer to node configurations.
+var registerOrder = []string{
+	KindNodeConfiguration,
+	KindNodeTemplate,
+	KindWorkloadScalingPolicy,
+	KindEvictorAdvancedConfig,
+	KindPodMutation,
+	KindHibernationSchedule,
+}
+
+func kindNames() []string {
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checker converts YAML nodes to plain values of an input type, recording
+// the values that do not fit.
+type checker struct {
+	file string
+	errs Errors
+}
+
+func (c *checker) errorf(n *yaml.Node, path, format string, args ...interface{}) {
+	c.errs = append(c.errs, &Error{Pos: Pos{File: c.file, Line: n.Line, Column: n.Column}, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// value converts n to a plain value of type t: a string, bool, int, float64,
+// []interface{} or map[string]interface{}. A nil result is an unset optional
+// input.
+func (c *checker) value(n *yaml.Node, path string, t reflect.Type) (interface{}, bool) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	null := n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if null {
+			return nil, true
+		}
+		return c.value(n, path, t.Elem())
+	case reflect.Interface:
+		var v interface{}
+		if err := n.Decode(&v); err != nil {
+			c.errorf(n, path, "%v", err)
+			return nil, false
+		}
+		return v, true
+	}
+	if null {
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			return nil, true
+		}
+		c.errorf(n, path, "must be set")
+		return nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return c.object(n, path, t, nil)
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			c.errorf(n, path, "expected a list, got %s", describe(n))
+			return nil, false
+		}
+		items := make([]interface{}, 0, len(n.Content))
+		ok := true
+		for i, item := range n.Content {
+			v, itemOK := c.value(item, fmt.Sprintf("%s[%d]", path, i), t.Elem())
+			items = append(items, v)
+			ok = ok && itemOK
+		}
+		return items, ok
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			c.errorf(n, path, "expected a mapping, got %s", describe(n))
+			return nil, false
+		}
+		m := make(map[string]interface{}, len(n.Content)/2)
+		ok := true
+		for i := 0; i < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			v, valueOK := c.value(n.Content[i+1], path+"."+key, t.Elem())
+			m[key] = v
+			ok = ok && valueOK
+		}
+		return m, ok
+	}
+
+	if n.Kind != yaml.ScalarNode {
+		c.errorf(n, path, "expected %s, got %s", kindName(t), describe(n))
+		return nil, false
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return n.Value, true
+	case reflect.Bool:
+		var b bool
+		if n.Tag == "!!bool" && n.Decode(&b) == nil {
+			return b, true
+		}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		var i int
+		if n.Tag == "!!int" && n.Decode(&i) == nil {
+			return i, true
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if (n.Tag == "!!int" || n.Tag == "!!float") && n.Decode(&f) == nil {
+			return f, true
+		}
+	default:
+		c.errorf(n, path, "unsupported input type %s", t)
+		return nil, false
+	}
+	c.errorf(n, path, "expected %s, got %s", kindName(t), describe(n))
+	return nil, false
+}
+
+// object converts a mapping to the inputs of struct type t. Inputs in
+// managed may not be set.
+func (c *checker) object(n *yaml.Node, path string, t reflect.Type, managed []string) (interface{}, bool) {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		n = &yaml.Node{Kind: yaml.MappingNode, Line: n.Line, Column: n.Column}
+	}
+	if n.Kind != yaml.MappingNode {
+		c.errorf(n, path, "expected a mapping, got %s", describe(n))
+		return nil, false
+	}
+
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name, _, _ := strings.Cut(f.Tag.Get("pulumi"), ","); name != "" {
+			fields[name] = f
+		}
+	}
+
+	m := map[string]interface{}{}
+	set := map[string]bool{}
+	ok := true
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		name := key.Value
+		field, known := fields[name]
+		switch {
+		case contains(managed, name):
+			c.errorf(key, path+"."+name, "is set by Register")
+			ok = false
+			continue
+		case !known:
+			c.errorf(key, path+"."+name, "unknown input%s", suggest(name, fields))
+			ok = false
+			continue
+		}
+		set[name] = true
+		v, valueOK := c.value(value, path+"."+name, field.Type)
+		if v != nil {
+			m[name] = v
+		}
+		ok = ok && valueOK
+	}
+
+	var missing []string
+	for name, f := range fields {
+		switch f.Type.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			continue
+		}
+		if !set[name] && !contains(managed, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		c.errorf(n, path, "missing required inputs %s", strings.Join(missing, ", "))
+		ok = false
+	}
+	return m, ok
+}
+
+// suggest returns a hint for an unknown input that differs from a known one
+// only in case.
+func suggest(name string, fields map[string]reflect.StructField) string {
+	for known := range fields {
+		if strings.EqualFold(known, name) {
+			return fmt.Sprintf(", did you mean %s?", known)
+		}
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// kindName describes a scalar Go type in YAML terms.
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a string"
+}
+
+// describe describes a YAML node in error messages.
+func describe(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	return fmt.Sprintf("%q", n.Value)
+}
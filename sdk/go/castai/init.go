@@ -55,6 +55,8 @@ func (m *module) Construct(ctx *pulumi.Context, name, typ, urn string) (r pulumi
 		r = &AiOptimizerModelSpecs{}
 	case "castai:index:AllocationGroup":
 		r = &AllocationGroup{}
+	case "castai:index:ClusterReadiness":
+		r = &ClusterReadiness{}
 	case "castai:index:Commitments":
 		r = &Commitments{}
 	case "castai:index:PodMutation":
@@ -0,0 +1,126 @@
+package nodeconfig
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// DockerConfig is the `dockerConfig` input: the daemon.json settings CAST AI
+// overrides for dockerd on the nodes it creates.
+type DockerConfig struct {
+	// RegistryMirrors are the URLs of Docker Hub mirrors.
+	RegistryMirrors []string `json:"registry-mirrors,omitempty"`
+	// InsecureRegistries are registries, as host[:port] or CIDR, pulled from
+	// without TLS verification.
+	InsecureRegistries []string `json:"insecure-registries,omitempty"`
+	// MaxConcurrentDownloads bounds the layers pulled at once.
+	MaxConcurrentDownloads *int `json:"max-concurrent-downloads,omitempty"`
+	// MaxConcurrentUploads bounds the layers pushed at once.
+	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
+	// MaxDownloadAttempts is the number of attempts to pull a layer.
+	MaxDownloadAttempts *int `json:"max-download-attempts,omitempty"`
+
+	// LogDriver is the default logging driver of containers, e.g.
+	// "json-file".
+	LogDriver *string `json:"log-driver,omitempty"`
+	// LogOpts are the options of the logging driver, e.g. "max-size": "10m".
+	// dockerd only accepts string values.
+	LogOpts map[string]string `json:"log-opts,omitempty"`
+
+	// DefaultUlimits maps ulimit names such as "nofile" to the limits of
+	// containers.
+	DefaultUlimits map[string]Ulimit `json:"default-ulimits,omitempty"`
+	// LiveRestore keeps containers running while dockerd restarts.
+	LiveRestore *bool `json:"live-restore,omitempty"`
+	// DNS are the nameservers of containers.
+	DNS []string `json:"dns,omitempty"`
+	// MTU is the MTU of the default bridge network.
+	MTU *int `json:"mtu,omitempty"`
+	// Debug turns on debug logging of dockerd.
+	Debug *bool `json:"debug,omitempty"`
+}
+
+// Ulimit is a soft and hard limit of a DefaultUlimits entry.
+type Ulimit struct {
+	// Name repeats the key of the entry.
+	Name string `json:"Name"`
+	Soft int64  `json:"Soft"`
+	Hard int64  `json:"Hard"`
+}
+
+// ParseDockerConfig decodes a `dockerConfig` input. Unknown keys are
+// rejected, so a misspelled setting fails here rather than on the node.
+func ParseDockerConfig(data []byte) (*DockerConfig, error) {
+	var c DockerConfig
+	if err := decode("docker config", data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// JSON validates the settings for the target nodes and renders them as
+// canonical JSON.
+func (c *DockerConfig) JSON(t Target) (string, error) {
+	if err := c.Validate(t); err != nil {
+		return "", err
+	}
+	return encode("docker config", c)
+}
+
+// Validate checks value ranges and that the target nodes run dockerd. All
+// problems are reported at once, each prefixed with the key of the offending
+// setting.
+func (c *DockerConfig) Validate(t Target) error {
+	if c == nil {
+		return fmt.Errorf("docker config is nil")
+	}
+
+	v := &validator{}
+	v.target("dockerConfig", t)
+	if t.Cloud == CloudEKS {
+		switch {
+		case t.ImageFamily == ImageFamilyAL2023 || t.ImageFamily == ImageFamilyBottlerocket:
+			v.addf("dockerConfig: %s images do not ship dockerd", t.ImageFamily)
+		case t.ContainerRuntime != ContainerRuntimeDockerd:
+			v.addf("dockerConfig: configures dockerd and requires containerRuntime %q, got %q", ContainerRuntimeDockerd, t.ContainerRuntime)
+		}
+	}
+
+	for i, mirror := range c.RegistryMirrors {
+		if u, err := url.Parse(mirror); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.addf("registry-mirrors[%d]: %q is not an http or https URL", i, mirror)
+		}
+	}
+	for i, registry := range c.InsecureRegistries {
+		if registry == "" || strings.Contains(registry, "://") {
+			v.addf("insecure-registries[%d]: %q is not a host[:port] or CIDR", i, registry)
+		}
+	}
+	v.positive("max-concurrent-downloads", c.MaxConcurrentDownloads)
+	v.positive("max-concurrent-uploads", c.MaxConcurrentUploads)
+	v.positive("max-download-attempts", c.MaxDownloadAttempts)
+
+	if c.LogDriver != nil && *c.LogDriver == "" {
+		v.addf("log-driver: must not be empty")
+	}
+
+	for _, name := range sortedKeys(c.DefaultUlimits) {
+		u := c.DefaultUlimits[name]
+		path := "default-ulimits." + name
+		if u.Name != name {
+			v.addf("%s: Name must be %q, got %q", path, name, u.Name)
+		}
+		if u.Hard >= 0 && (u.Soft < 0 || u.Soft > u.Hard) {
+			v.addf("%s: Soft %d must be between 0 and Hard %d", path, u.Soft, u.Hard)
+		}
+	}
+	for i, ip := range c.DNS {
+		if net.ParseIP(ip) == nil {
+			v.addf("dns[%d]: %q is not an IP address", i, ip)
+		}
+	}
+	v.between("mtu", c.MTU, 68, 65535)
+	return v.err("docker config")
+}
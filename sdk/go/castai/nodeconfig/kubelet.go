@@ -0,0 +1,289 @@
+package nodeconfig
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CPU, memory and topology manager policies of the kubelet.
+const (
+	CpuManagerPolicyNone   = "none"
+	CpuManagerPolicyStatic = "static"
+
+	MemoryManagerPolicyNone   = "None"
+	MemoryManagerPolicyStatic = "Static"
+
+	TopologyManagerPolicyNone           = "none"
+	TopologyManagerPolicyBestEffort     = "best-effort"
+	TopologyManagerPolicyRestricted     = "restricted"
+	TopologyManagerPolicySingleNumaNode = "single-numa-node"
+
+	TopologyManagerScopeContainer = "container"
+	TopologyManagerScopePod       = "pod"
+)
+
+// evictionSignals are the signals evictionHard and evictionSoft accept.
+var evictionSignals = []string{
+	"containerfs.available",
+	"containerfs.inodesFree",
+	"imagefs.available",
+	"imagefs.inodesFree",
+	"memory.available",
+	"nodefs.available",
+	"nodefs.inodesFree",
+	"pid.available",
+}
+
+// reservedResources are the resources kubeReserved and systemReserved accept.
+var reservedResources = []string{"cpu", "ephemeral-storage", "memory", "pid"}
+
+// KubeletConfig is the `kubeletConfig` input: the KubeletConfiguration
+// settings CAST AI overrides on the nodes it creates. Unset fields keep the
+// kubelet defaults of the node image.
+type KubeletConfig struct {
+	// MaxPods is the number of pods that can run on a node.
+	MaxPods *int `json:"maxPods,omitempty"`
+	// PodPidsLimit is the number of PIDs a pod may use, or -1 for no limit.
+	PodPidsLimit *int `json:"podPidsLimit,omitempty"`
+
+	// RegistryPullQPS limits image pulls per second, 0 for no limit.
+	RegistryPullQPS *int `json:"registryPullQPS,omitempty"`
+	// RegistryBurst is the pull burst when RegistryPullQPS is set.
+	RegistryBurst *int `json:"registryBurst,omitempty"`
+	// EventRecordQPS limits events per second, 0 for no limit.
+	EventRecordQPS *int `json:"eventRecordQPS,omitempty"`
+	// EventBurst is the event burst when EventRecordQPS is set.
+	EventBurst *int `json:"eventBurst,omitempty"`
+	// KubeAPIQPS limits requests per second to the API server.
+	KubeAPIQPS *int `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the request burst to the API server.
+	KubeAPIBurst *int `json:"kubeAPIBurst,omitempty"`
+	// SerializeImagePulls pulls one image at a time.
+	SerializeImagePulls *bool `json:"serializeImagePulls,omitempty"`
+	// MaxParallelImagePulls bounds parallel pulls when SerializeImagePulls is
+	// false.
+	MaxParallelImagePulls *int `json:"maxParallelImagePulls,omitempty"`
+
+	// ImageGCHighThresholdPercent is the disk usage that always starts image
+	// garbage collection.
+	ImageGCHighThresholdPercent *int `json:"imageGCHighThresholdPercent,omitempty"`
+	// ImageGCLowThresholdPercent is the disk usage image garbage collection
+	// never goes below.
+	ImageGCLowThresholdPercent *int `json:"imageGCLowThresholdPercent,omitempty"`
+	// ImageMinimumGCAge is the minimum age of unused images before they are
+	// collected, e.g. "2m".
+	ImageMinimumGCAge *string `json:"imageMinimumGCAge,omitempty"`
+
+	// CpuManagerPolicy is CpuManagerPolicyNone or CpuManagerPolicyStatic.
+	CpuManagerPolicy *string `json:"cpuManagerPolicy,omitempty"`
+	// CpuCFSQuota enforces CPU limits with CFS quota.
+	CpuCFSQuota *bool `json:"cpuCFSQuota,omitempty"`
+	// CpuCFSQuotaPeriod is the CFS quota period, between 1ms and 1s.
+	CpuCFSQuotaPeriod *string `json:"cpuCFSQuotaPeriod,omitempty"`
+	// MemoryManagerPolicy is MemoryManagerPolicyNone or
+	// MemoryManagerPolicyStatic.
+	MemoryManagerPolicy *string `json:"memoryManagerPolicy,omitempty"`
+	// TopologyManagerPolicy is one of the TopologyManagerPolicy constants.
+	TopologyManagerPolicy *string `json:"topologyManagerPolicy,omitempty"`
+	// TopologyManagerScope is TopologyManagerScopeContainer or
+	// TopologyManagerScopePod.
+	TopologyManagerScope *string `json:"topologyManagerScope,omitempty"`
+
+	// EvictionHard maps eviction signals such as "memory.available" to
+	// thresholds such as "200Mi" or "10%".
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
+	// EvictionSoft maps eviction signals to soft thresholds. Each needs a
+	// grace period in EvictionSoftGracePeriod.
+	EvictionSoft map[string]string `json:"evictionSoft,omitempty"`
+	// EvictionSoftGracePeriod maps eviction signals to durations such as
+	// "1m30s".
+	EvictionSoftGracePeriod map[string]string `json:"evictionSoftGracePeriod,omitempty"`
+	// EvictionMaxPodGracePeriod is the longest termination grace period, in
+	// seconds, given to pods evicted on a soft threshold.
+	EvictionMaxPodGracePeriod *int `json:"evictionMaxPodGracePeriod,omitempty"`
+
+	// KubeReserved maps "cpu", "memory", "ephemeral-storage" and "pid" to
+	// the quantities reserved for Kubernetes components.
+	KubeReserved map[string]string `json:"kubeReserved,omitempty"`
+	// SystemReserved maps the same resources to the quantities reserved for
+	// the operating system.
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+
+	// ContainerLogMaxSize is the size a container log is rotated at, e.g.
+	// "10Mi".
+	ContainerLogMaxSize *string `json:"containerLogMaxSize,omitempty"`
+	// ContainerLogMaxFiles is the number of log files kept per container, at
+	// least 2.
+	ContainerLogMaxFiles *int `json:"containerLogMaxFiles,omitempty"`
+
+	// AllowedUnsafeSysctls are the unsafe sysctls pods may set, e.g.
+	// "net.core.somaxconn" or "kernel.msg*".
+	AllowedUnsafeSysctls []string `json:"allowedUnsafeSysctls,omitempty"`
+	// FeatureGates turns kubelet feature gates on or off.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// ShutdownGracePeriod is how long node shutdown is delayed to terminate
+	// pods, e.g. "30s".
+	ShutdownGracePeriod *string `json:"shutdownGracePeriod,omitempty"`
+	// ShutdownGracePeriodCriticalPods is the part of ShutdownGracePeriod
+	// kept for critical pods.
+	ShutdownGracePeriodCriticalPods *string `json:"shutdownGracePeriodCriticalPods,omitempty"`
+}
+
+// ParseKubeletConfig decodes a `kubeletConfig` input. Unknown keys are
+// rejected, so a misspelled setting fails here rather than on the node.
+func ParseKubeletConfig(data []byte) (*KubeletConfig, error) {
+	var c KubeletConfig
+	if err := decode("kubelet config", data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// JSON validates the settings for the target nodes and renders them as
+// canonical JSON.
+func (c *KubeletConfig) JSON(t Target) (string, error) {
+	if err := c.Validate(t); err != nil {
+		return "", err
+	}
+	return encode("kubelet config", c)
+}
+
+// Validate checks value ranges, cross-field constraints and that the target
+// nodes apply the settings. All problems are reported at once, each prefixed
+// with the key of the offending setting.
+func (c *KubeletConfig) Validate(t Target) error {
+	if c == nil {
+		return fmt.Errorf("kubelet config is nil")
+	}
+
+	v := &validator{}
+	v.target("kubeletConfig", t)
+
+	v.positive("maxPods", c.MaxPods)
+	if c.PodPidsLimit != nil && *c.PodPidsLimit != -1 && *c.PodPidsLimit <= 0 {
+		v.addf("podPidsLimit: must be positive or -1, got %d", *c.PodPidsLimit)
+	}
+	v.nonNegative("registryPullQPS", c.RegistryPullQPS)
+	v.nonNegative("registryBurst", c.RegistryBurst)
+	v.nonNegative("eventRecordQPS", c.EventRecordQPS)
+	v.nonNegative("eventBurst", c.EventBurst)
+	v.nonNegative("kubeAPIQPS", c.KubeAPIQPS)
+	v.nonNegative("kubeAPIBurst", c.KubeAPIBurst)
+	v.positive("maxParallelImagePulls", c.MaxParallelImagePulls)
+	if c.MaxParallelImagePulls != nil && (c.SerializeImagePulls == nil || *c.SerializeImagePulls) {
+		v.addf("maxParallelImagePulls: requires serializeImagePulls to be false")
+	}
+
+	v.between("imageGCHighThresholdPercent", c.ImageGCHighThresholdPercent, 0, 100)
+	v.between("imageGCLowThresholdPercent", c.ImageGCLowThresholdPercent, 0, 100)
+	if lo, hi := c.ImageGCLowThresholdPercent, c.ImageGCHighThresholdPercent; lo != nil && hi != nil && *lo >= *hi {
+		v.addf("imageGCLowThresholdPercent: %d must be less than imageGCHighThresholdPercent %d", *lo, *hi)
+	}
+	v.duration("imageMinimumGCAge", c.ImageMinimumGCAge)
+
+	v.oneOf("cpuManagerPolicy", c.CpuManagerPolicy, CpuManagerPolicyNone, CpuManagerPolicyStatic)
+	if d, ok := v.duration("cpuCFSQuotaPeriod", c.CpuCFSQuotaPeriod); ok && (d < time.Millisecond || d > time.Second) {
+		v.addf("cpuCFSQuotaPeriod: must be between 1ms and 1s, got %q", *c.CpuCFSQuotaPeriod)
+	}
+	v.oneOf("memoryManagerPolicy", c.MemoryManagerPolicy, MemoryManagerPolicyNone, MemoryManagerPolicyStatic)
+	v.oneOf("topologyManagerPolicy", c.TopologyManagerPolicy,
+		TopologyManagerPolicyNone, TopologyManagerPolicyBestEffort, TopologyManagerPolicyRestricted, TopologyManagerPolicySingleNumaNode)
+	v.oneOf("topologyManagerScope", c.TopologyManagerScope, TopologyManagerScopeContainer, TopologyManagerScopePod)
+
+	v.thresholds("evictionHard", c.EvictionHard)
+	v.thresholds("evictionSoft", c.EvictionSoft)
+	for _, signal := range sortedKeys(c.EvictionSoftGracePeriod) {
+		path := "evictionSoftGracePeriod." + signal
+		v.signal(path, signal)
+		period := c.EvictionSoftGracePeriod[signal]
+		v.duration(path, &period)
+		if _, ok := c.EvictionSoft[signal]; !ok {
+			v.addf("%s: has no threshold in evictionSoft", path)
+		}
+	}
+	for _, signal := range sortedKeys(c.EvictionSoft) {
+		if _, ok := c.EvictionSoftGracePeriod[signal]; !ok {
+			v.addf("evictionSoft.%s: needs a grace period in evictionSoftGracePeriod", signal)
+		}
+	}
+	v.nonNegative("evictionMaxPodGracePeriod", c.EvictionMaxPodGracePeriod)
+
+	v.reserved("kubeReserved", c.KubeReserved)
+	v.reserved("systemReserved", c.SystemReserved)
+
+	if c.ContainerLogMaxSize != nil {
+		v.quantity("containerLogMaxSize", *c.ContainerLogMaxSize)
+	}
+	if c.ContainerLogMaxFiles != nil && *c.ContainerLogMaxFiles < 2 {
+		v.addf("containerLogMaxFiles: must be at least 2, got %d", *c.ContainerLogMaxFiles)
+	}
+
+	for i, sysctl := range c.AllowedUnsafeSysctls {
+		if sysctl == "" || strings.ContainsAny(sysctl, " /") {
+			v.addf("allowedUnsafeSysctls[%d]: %q is not a sysctl name such as \"net.core.somaxconn\"", i, sysctl)
+		}
+	}
+
+	shutdown, shutdownOK := v.duration("shutdownGracePeriod", c.ShutdownGracePeriod)
+	critical, criticalOK := v.duration("shutdownGracePeriodCriticalPods", c.ShutdownGracePeriodCriticalPods)
+	switch {
+	case criticalOK && c.ShutdownGracePeriod == nil:
+		v.addf("shutdownGracePeriodCriticalPods: requires shutdownGracePeriod")
+	case criticalOK && shutdownOK && critical > shutdown:
+		v.addf("shutdownGracePeriodCriticalPods: %s must not exceed shutdownGracePeriod %s", critical, shutdown)
+	}
+	return v.err("kubelet config")
+}
+
+func (v *validator) signal(path, signal string) {
+	for _, known := range evictionSignals {
+		if signal == known {
+			return
+		}
+	}
+	v.addf("%s: unknown eviction signal, expected one of %s", path, strings.Join(evictionSignals, ", "))
+}
+
+// thresholds checks eviction thresholds: a quantity or a percentage.
+func (v *validator) thresholds(path string, m map[string]string) {
+	for _, signal := range sortedKeys(m) {
+		p := path + "." + signal
+		v.signal(p, signal)
+		threshold := m[signal]
+		if pct, ok := strings.CutSuffix(threshold, "%"); ok {
+			if n, err := strconv.ParseFloat(pct, 64); err != nil || n < 0 || n > 100 {
+				v.addf("%s: %q is not a percentage between 0%% and 100%%", p, threshold)
+			}
+			continue
+		}
+		v.quantity(p, threshold)
+	}
+}
+
+func (v *validator) reserved(path string, m map[string]string) {
+	for _, resource := range sortedKeys(m) {
+		p := path + "." + resource
+		known := false
+		for _, r := range reservedResources {
+			known = known || resource == r
+		}
+		if !known {
+			v.addf("%s: unknown resource, expected one of %s", p, strings.Join(reservedResources, ", "))
+			continue
+		}
+		v.quantity(p, m[resource])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
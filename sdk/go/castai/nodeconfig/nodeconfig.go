@@ -0,0 +1,264 @@
+// Package nodeconfig provides typed models of the `kubeletConfig` and
+// `dockerConfig` inputs of config.NodeConfiguration.
+//
+// Both inputs are JSON strings that CAST AI passes to the nodes it creates, so
+// a misspelled key or a value of the wrong type only shows up when nodes fail
+// to start. KubeletConfig and DockerConfig hold the supported settings, check
+// their ranges and the restrictions of the nodes they are applied to, and
+// render canonical JSON:
+//
+//	kubelet := &nodeconfig.KubeletConfig{
+//		MaxPods:      nodeconfig.Int(110),
+//		EvictionHard: map[string]string{"memory.available": "200Mi"},
+//	}
+//	kubeletConfig, err := kubelet.JSON(nodeconfig.Target{Cloud: nodeconfig.CloudEKS})
+//	if err != nil {
+//		return err
+//	}
+//	_, err = config.NewNodeConfiguration(ctx, "default", &config.NodeConfigurationArgs{
+//		KubeletConfig: pulumi.String(kubeletConfig),
+//		// ...
+//	})
+//
+// Canonical JSON is compact with object keys in lexical order, the form the
+// provider compares these inputs in.
+package nodeconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Clouds a node configuration applies to, named after its eks, gke, aks and
+// kops blocks.
+const (
+	CloudEKS  = "eks"
+	CloudGKE  = "gke"
+	CloudAKS  = "aks"
+	CloudKOPS = "kops"
+)
+
+// Container runtimes of the `containerRuntime` input.
+const (
+	ContainerRuntimeContainerd = "containerd"
+	ContainerRuntimeDockerd    = "dockerd"
+)
+
+// EKS image families of the `eks.eksImageFamily` input.
+const (
+	ImageFamilyAL2          = "al2"
+	ImageFamilyAL2023       = "al2023"
+	ImageFamilyBottlerocket = "bottlerocket"
+)
+
+// Target describes the nodes a configuration is applied to, for the checks
+// that depend on them.
+type Target struct {
+	// Cloud is one of the Cloud constants.
+	Cloud string
+	// ContainerRuntime is the `containerRuntime` input of the node
+	// configuration, or empty if it is not set.
+	ContainerRuntime string
+	// ImageFamily is the `eks.eksImageFamily` input, or empty if it is not
+	// set.
+	ImageFamily string
+}
+
+// Bool returns a pointer to v.
+func Bool(v bool) *bool { return &v }
+
+// Int returns a pointer to v.
+func Int(v int) *int { return &v }
+
+// String returns a pointer to v.
+func String(v string) *string { return &v }
+
+// Canonical reformats a JSON document as compact JSON with object keys in
+// lexical order. Numbers keep their text.
+func Canonical(data []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+	if dec.More() {
+		return "", errors.New("unexpected data after the top-level value")
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// decode reads a JSON object into v, rejecting unknown keys.
+func decode(what string, data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %w", what, err)
+	}
+	if dec.More() {
+		return fmt.Errorf("decoding %s: unexpected data after the top-level object", what)
+	}
+	if err := exactKeys("", data, reflect.TypeOf(v).Elem()); err != nil {
+		return fmt.Errorf("decoding %s: %w", what, err)
+	}
+	return nil
+}
+
+// exactKeys checks that the keys of the objects decoded into struct types
+// match their json tags exactly. encoding/json matches keys case-insensitively,
+// but the kubelet and dockerd do not, so "maxpods" would be ignored on the
+// node.
+func exactKeys(path string, data []byte, t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return exactKeys(path, data, t.Elem())
+	case reflect.Map:
+		if t.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		for _, key := range sortedKeys(m) {
+			if err := exactKeys(path+key+".", m[key], t.Elem()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			fields[name] = t.Field(i).Type
+		}
+		for _, key := range sortedKeys(m) {
+			ft, ok := fields[key]
+			if !ok {
+				for name := range fields {
+					if strings.EqualFold(name, key) {
+						return fmt.Errorf("unknown field %q, did you mean %q?", path+key, name)
+					}
+				}
+				return fmt.Errorf("unknown field %q", path+key)
+			}
+			if err := exactKeys(path+key+".", m[key], ft); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// encode renders v as canonical JSON.
+func encode(what string, v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err == nil {
+		var s string
+		if s, err = Canonical(b); err == nil {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("encoding %s: %w", what, err)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) err(what string) error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid %s: %w", what, errors.Join(v.errs...))
+}
+
+// target checks the settings apply to the target nodes. Both inputs are only
+// applied to EKS nodes.
+func (v *validator) target(input string, t Target) {
+	switch t.Cloud {
+	case CloudEKS:
+	case CloudGKE, CloudAKS, CloudKOPS:
+		v.addf("%s is only applied to EKS nodes, not %s", input, strings.ToUpper(t.Cloud))
+	default:
+		v.addf("cloud: unsupported value %q, expected %q, %q, %q or %q", t.Cloud, CloudEKS, CloudGKE, CloudAKS, CloudKOPS)
+	}
+}
+
+func (v *validator) positive(path string, n *int) {
+	if n != nil && *n <= 0 {
+		v.addf("%s: must be positive, got %d", path, *n)
+	}
+}
+
+func (v *validator) nonNegative(path string, n *int) {
+	if n != nil && *n < 0 {
+		v.addf("%s: must not be negative, got %d", path, *n)
+	}
+}
+
+func (v *validator) between(path string, n *int, lo, hi int) {
+	if n != nil && (*n < lo || *n > hi) {
+		v.addf("%s: must be between %d and %d, got %d", path, lo, hi, *n)
+	}
+}
+
+func (v *validator) oneOf(path string, s *string, values ...string) {
+	if s == nil {
+		return
+	}
+	for _, value := range values {
+		if *s == value {
+			return
+		}
+	}
+	v.addf("%s: unsupported value %q, expected one of %s", path, *s, strings.Join(values, ", "))
+}
+
+// duration parses a Go duration such as "30s", or returns false after
+// reporting it.
+func (v *validator) duration(path string, s *string) (time.Duration, bool) {
+	if s == nil {
+		return 0, false
+	}
+	d, err := time.ParseDuration(*s)
+	if err != nil {
+		v.addf("%s: %q is not a valid duration such as \"30s\"", path, *s)
+		return 0, false
+	}
+	if d < 0 {
+		v.addf("%s: must not be negative, got %q", path, *s)
+		return 0, false
+	}
+	return d, true
+}
+
+// quantityPattern matches Kubernetes resource quantities such as "100Mi",
+// "0.5" or "1e3".
+var quantityPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+|Ki|Mi|Gi|Ti|Pi|Ei|[numkMGTPE])?$`)
+
+func (v *validator) quantity(path, s string) {
+	if !quantityPattern.MatchString(s) {
+		v.addf("%s: %q is not a valid quantity such as \"100Mi\"", path, s)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"context"
 	"reflect"
 
-	"errors"
 	"github.com/castai/pulumi-castai/sdk/go/castai/internal"
 	"github.com/castai/pulumi-castai/sdk/go/castai/organization"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
@@ -29,12 +28,9 @@ type OrganizationGroup struct {
 func NewOrganizationGroup(ctx *pulumi.Context,
 	name string, args *OrganizationGroupArgs, opts ...pulumi.ResourceOption) (*OrganizationGroup, error) {
 	if args == nil {
-		return nil, errors.New("missing one or more required arguments")
+		args = &OrganizationGroupArgs{}
 	}
 
-	if args.OrganizationId == nil {
-		return nil, errors.New("invalid value for required argument 'OrganizationId'")
-	}
 	opts = internal.PkgResourceDefaultOpts(opts)
 	var resource OrganizationGroup
 	err := ctx.RegisterResource("castai:organization:OrganizationGroup", name, args, &resource, opts...)
@@ -88,7 +84,7 @@ type organizationGroupArgs struct {
 	// Name of the group.
 	Name *string `pulumi:"name"`
 	// CAST AI organization ID.
-	OrganizationId string `pulumi:"organizationId"`
+	OrganizationId *string `pulumi:"organizationId"`
 }
 
 // The set of arguments for constructing a OrganizationGroup resource.
@@ -99,7 +95,7 @@ type OrganizationGroupArgs struct {
 	// Name of the group.
 	Name pulumi.StringPtrInput
 	// CAST AI organization ID.
-	OrganizationId pulumi.StringInput
+	OrganizationId pulumi.StringPtrInput
 }
 
 func (OrganizationGroupArgs) ElementType() reflect.Type {
@@ -7,7 +7,6 @@ import (
 	"context"
 	"reflect"
 
-	"errors"
 	"github.com/castai/pulumi-castai/sdk/go/castai/internal"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
@@ -35,12 +34,9 @@ type OrganizationMembers struct {
 func NewOrganizationMembers(ctx *pulumi.Context,
 	name string, args *OrganizationMembersArgs, opts ...pulumi.ResourceOption) (*OrganizationMembers, error) {
 	if args == nil {
-		return nil, errors.New("missing one or more required arguments")
+		args = &OrganizationMembersArgs{}
 	}
 
-	if args.OrganizationId == nil {
-		return nil, errors.New("invalid value for required argument 'OrganizationId'")
-	}
 	opts = internal.PkgResourceDefaultOpts(opts)
 	var resource OrganizationMembers
 	err := ctx.RegisterResource("castai:organization:OrganizationMembers", name, args, &resource, opts...)
@@ -107,7 +103,7 @@ type organizationMembersArgs struct {
 	// Deprecated: The 'members' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.
 	Members []string `pulumi:"members"`
 	// CAST AI organization ID.
-	OrganizationId string `pulumi:"organizationId"`
+	OrganizationId *string `pulumi:"organizationId"`
 	// A list of email addresses corresponding to users who should be given owner access to the organization.
 	//
 	// Deprecated: The 'owners' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.
@@ -125,7 +121,7 @@ type OrganizationMembersArgs struct {
 	// Deprecated: The 'members' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.
 	Members pulumi.StringArrayInput
 	// CAST AI organization ID.
-	OrganizationId pulumi.StringInput
+	OrganizationId pulumi.StringPtrInput
 	// A list of email addresses corresponding to users who should be given owner access to the organization.
 	//
 	// Deprecated: The 'owners' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.
@@ -56,9 +56,6 @@ func NewPodMutation(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.Enabled == nil {
 		return nil, errors.New("invalid value for required argument 'Enabled'")
 	}
@@ -167,7 +164,7 @@ type podMutationArgs struct {
 	// Annotations to add to the pods.
 	Annotations map[string]string `pulumi:"annotations"`
 	// ID of the cluster.
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// Distribution groups for percentage-based pod distribution.
 	DistributionGroups []PodMutationDistributionGroup `pulumi:"distributionGroups"`
 	// Whether the pod mutation is enabled.
@@ -201,7 +198,7 @@ type PodMutationArgs struct {
 	// Annotations to add to the pods.
 	Annotations pulumi.StringMapInput
 	// ID of the cluster.
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// Distribution groups for percentage-based pod distribution.
 	DistributionGroups PodMutationDistributionGroupArrayInput
 	// Whether the pod mutation is enabled.
@@ -24,6 +24,10 @@ type Provider struct {
 	ApiUrl pulumi.StringPtrOutput `pulumi:"apiUrl"`
 	// CAST AI organization ID. Required when the API token has access to multiple organizations.
 	OrganizationId pulumi.StringPtrOutput `pulumi:"organizationId"`
+	// Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+	RequestTimeout pulumi.StringPtrOutput `pulumi:"requestTimeout"`
+	// Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+	RetryBackoff pulumi.StringPtrOutput `pulumi:"retryBackoff"`
 }
 
 // NewProvider registers a new resource with the given unique name, arguments, and options.
@@ -43,6 +47,31 @@ func NewProvider(ctx *pulumi.Context,
 			args.ApiUrl = pulumi.StringPtr(d.(string))
 		}
 	}
+	if args.MaxConcurrentRequests == nil {
+		if d := internal.GetEnvOrDefault(0, internal.ParseEnvInt, "CASTAI_MAX_CONCURRENT_REQUESTS"); d != nil {
+			args.MaxConcurrentRequests = pulumi.IntPtr(d.(int))
+		}
+	}
+	if args.MaxRetries == nil {
+		if d := internal.GetEnvOrDefault(3, internal.ParseEnvInt, "CASTAI_MAX_RETRIES"); d != nil {
+			args.MaxRetries = pulumi.IntPtr(d.(int))
+		}
+	}
+	if args.OrganizationId == nil {
+		if d := internal.GetEnvOrDefault(nil, nil, "CASTAI_ORGANIZATION_ID"); d != nil {
+			args.OrganizationId = pulumi.StringPtr(d.(string))
+		}
+	}
+	if args.RequestTimeout == nil {
+		if d := internal.GetEnvOrDefault("1m", nil, "CASTAI_REQUEST_TIMEOUT"); d != nil {
+			args.RequestTimeout = pulumi.StringPtr(d.(string))
+		}
+	}
+	if args.RetryBackoff == nil {
+		if d := internal.GetEnvOrDefault("1s", nil, "CASTAI_RETRY_BACKOFF"); d != nil {
+			args.RetryBackoff = pulumi.StringPtr(d.(string))
+		}
+	}
 	if args.ApiToken != nil {
 		args.ApiToken = pulumi.ToSecret(args.ApiToken).(pulumi.StringPtrInput)
 	}
@@ -64,8 +93,16 @@ type providerArgs struct {
 	ApiToken *string `pulumi:"apiToken"`
 	// CAST.AI API url.
 	ApiUrl *string `pulumi:"apiUrl"`
+	// Maximum number of CAST AI API requests in flight. `0` means no limit.
+	MaxConcurrentRequests *int `pulumi:"maxConcurrentRequests"`
+	// Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+	MaxRetries *int `pulumi:"maxRetries"`
 	// CAST AI organization ID. Required when the API token has access to multiple organizations.
 	OrganizationId *string `pulumi:"organizationId"`
+	// Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+	RequestTimeout *string `pulumi:"requestTimeout"`
+	// Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+	RetryBackoff *string `pulumi:"retryBackoff"`
 }
 
 // The set of arguments for constructing a Provider resource.
@@ -74,8 +111,16 @@ type ProviderArgs struct {
 	ApiToken pulumi.StringPtrInput
 	// CAST.AI API url.
 	ApiUrl pulumi.StringPtrInput
+	// Maximum number of CAST AI API requests in flight. `0` means no limit.
+	MaxConcurrentRequests pulumi.IntPtrInput
+	// Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+	MaxRetries pulumi.IntPtrInput
 	// CAST AI organization ID. Required when the API token has access to multiple organizations.
 	OrganizationId pulumi.StringPtrInput
+	// Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+	RequestTimeout pulumi.StringPtrInput
+	// Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+	RetryBackoff pulumi.StringPtrInput
 }
 
 func (ProviderArgs) ElementType() reflect.Type {
@@ -153,6 +198,16 @@ func (o ProviderOutput) OrganizationId() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.OrganizationId }).(pulumi.StringPtrOutput)
 }
 
+// Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+func (o ProviderOutput) RequestTimeout() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.RequestTimeout }).(pulumi.StringPtrOutput)
+}
+
+// Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+func (o ProviderOutput) RetryBackoff() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.RetryBackoff }).(pulumi.StringPtrOutput)
+}
+
 func init() {
 	pulumi.RegisterInputType(reflect.TypeOf((*ProviderInput)(nil)).Elem(), &Provider{})
 	pulumi.RegisterOutputType(ProviderOutput{})
@@ -30,9 +30,6 @@ func NewRebalancingJob(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.RebalancingScheduleId == nil {
 		return nil, errors.New("invalid value for required argument 'RebalancingScheduleId'")
 	}
@@ -82,7 +79,7 @@ func (RebalancingJobState) ElementType() reflect.Type {
 
 type rebalancingJobArgs struct {
 	// CAST AI cluster id.
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// The job will only be executed if it's enabled.
 	Enabled *bool `pulumi:"enabled"`
 	// Rebalancing schedule of this job.
@@ -92,7 +89,7 @@ type rebalancingJobArgs struct {
 // The set of arguments for constructing a RebalancingJob resource.
 type RebalancingJobArgs struct {
 	// CAST AI cluster id.
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// The job will only be executed if it's enabled.
 	Enabled pulumi.BoolPtrInput
 	// Rebalancing schedule of this job.
@@ -36,9 +36,6 @@ func NewRoleBindings(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.OrganizationId == nil {
-		return nil, errors.New("invalid value for required argument 'OrganizationId'")
-	}
 	if args.RoleId == nil {
 		return nil, errors.New("invalid value for required argument 'RoleId'")
 	}
@@ -105,7 +102,7 @@ type roleBindingsArgs struct {
 	// Name of role binding.
 	Name *string `pulumi:"name"`
 	// CAST AI organization ID.
-	OrganizationId string `pulumi:"organizationId"`
+	OrganizationId *string `pulumi:"organizationId"`
 	// ID of role from role binding.
 	RoleId string `pulumi:"roleId"`
 	// Scopes of the role binding.
@@ -120,7 +117,7 @@ type RoleBindingsArgs struct {
 	// Name of role binding.
 	Name pulumi.StringPtrInput
 	// CAST AI organization ID.
-	OrganizationId pulumi.StringInput
+	OrganizationId pulumi.StringPtrInput
 	// ID of role from role binding.
 	RoleId pulumi.StringInput
 	// Scopes of the role binding.
@@ -0,0 +1,297 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds the search for the next fire time, so expressions that
+// never fire, such as `0 0 30 2 *`, do not loop forever.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	dayNames = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+)
+
+// field describes one of the five fields of a cron expression.
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var fields = [5]field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	// 7 is accepted for Sunday and folded into 0.
+	{name: "day of week", min: 0, max: 7, names: dayNames},
+}
+
+// Cron is a parsed cron expression in the format of the CAST AI scheduler:
+// five fields (minute, hour, day of month, month, day of week), optionally
+// prefixed by `CRON_TZ=<zone>` and interpreted in UTC otherwise. `?` is the
+// same as `*`, and the @hourly, @daily, @weekly, @monthly and @yearly
+// descriptors are accepted.
+type Cron struct {
+	// Expr is the expression as written.
+	Expr string
+	// Location is the time zone the expression is interpreted in.
+	Location *time.Location
+
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record whether the day fields were `*` or `?`. If
+	// both are restricted, a day matches if either does, as in cron(8).
+	domStar, dowStar bool
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Cron, error) {
+	c := &Cron{Expr: expr, Location: time.UTC}
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		tz, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(tz, "=")
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: unknown time zone %q", expr, name)
+		}
+		c.Location = loc
+		spec = strings.TrimSpace(rest)
+	}
+	if strings.HasPrefix(spec, "@") {
+		d, ok := descriptors[spec]
+		if !ok {
+			return nil, fmt.Errorf("cron %q: unsupported descriptor %s", expr, spec)
+		}
+		spec = d
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(parts))
+	}
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		b, err := fields[i].parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+	c.minute, c.hour, c.dom, c.month, c.dow = bits[0], bits[1], bits[2], bits[3], bits[4]
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.domStar = parts[2] == "*" || parts[2] == "?"
+	c.dowStar = parts[4] == "*" || parts[4] == "?"
+	return c, nil
+}
+
+// MustParse is like Parse but panics if the expression is invalid.
+func MustParse(expr string) *Cron {
+	c, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the expression as written.
+func (c *Cron) String() string {
+	return c.Expr
+}
+
+// parse returns the values a field matches as a bit set.
+func (f field) parse(s string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*" || rng == "?":
+		case strings.Contains(rng, "-"):
+			from, to, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(to); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%s range %s is empty", f.name, rng)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, stepStr)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q, expected %d-%d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first fire time after t, in the expression's time zone.
+// ok is false if the expression does not fire within five years.
+func (c *Cron) Next(t time.Time) (next time.Time, ok bool) {
+	c.walk(t, t.Add(searchLimit), func(fire time.Time, skipped bool) bool {
+		if skipped {
+			return true
+		}
+		next, ok = fire, true
+		return false
+	})
+	return next, ok
+}
+
+// NextN returns up to n fire times after t.
+func (c *Cron) NextN(t time.Time, n int) []time.Time {
+	var out []time.Time
+	if n <= 0 {
+		return out
+	}
+	c.walk(t, t.Add(searchLimit), func(fire time.Time, skipped bool) bool {
+		if !skipped {
+			out = append(out, fire)
+		}
+		return len(out) < n
+	})
+	return out
+}
+
+// Between returns the fire times after from and not after to.
+func (c *Cron) Between(from, to time.Time) []time.Time {
+	var out []time.Time
+	c.walk(from, to, func(fire time.Time, skipped bool) bool {
+		if !skipped {
+			out = append(out, fire)
+		}
+		return true
+	})
+	return out
+}
+
+// Skipped returns the times after from and not after to that the expression
+// names but that do not exist in its time zone, because the clocks move
+// forward for daylight saving time. The expression does not fire at them.
+// They are returned as wall clock times in UTC, e.g. 02:30 UTC for a skipped
+// 02:30 in Europe/Berlin.
+func (c *Cron) Skipped(from, to time.Time) []time.Time {
+	var out []time.Time
+	c.walk(from, to, func(fire time.Time, skipped bool) bool {
+		if skipped {
+			out = append(out, fire)
+		}
+		return true
+	})
+	return out
+}
+
+// walk calls fn, in order, with the times after from and not after to that
+// match the expression, until fn returns false. Times the time zone skips are
+// passed as wall clock times in UTC with skipped set. If the clocks go back,
+// a repeated wall clock time fires once.
+func (c *Cron) walk(from, to time.Time, fn func(t time.Time, skipped bool) bool) {
+	start := from.In(c.Location)
+	end := to.In(c.Location)
+	// Days are iterated in UTC so that the iteration itself is not affected
+	// by daylight saving time.
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !c.matchesDay(day) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if c.hour&(1<<h) == 0 {
+				continue
+			}
+			for m := 0; m < 60; m++ {
+				if c.minute&(1<<m) == 0 {
+					continue
+				}
+				t := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, c.Location)
+				skipped := t.Hour() != h || t.Minute() != m
+				if skipped {
+					t = time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, time.UTC)
+				}
+				lo, hi := from, to
+				if skipped {
+					lo, hi = wall(from, c.Location), wall(to, c.Location)
+				}
+				if !t.After(lo) {
+					continue
+				}
+				if t.After(hi) {
+					return
+				}
+				if !fn(t, skipped) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// wall returns the wall clock time of t in loc, as a time in UTC.
+func wall(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// matchesDay reports whether the expression fires on a day, given as
+// midnight UTC.
+func (c *Cron) matchesDay(day time.Time) bool {
+	if c.month&(1<<int(day.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<day.Day()) != 0
+	dow := c.dow&(1<<int(day.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,340 @@
+// Package schedule previews the cron schedules of CAST AI hibernation and
+// rebalancing schedules.
+//
+// A Plan holds the HibernationSchedule pause and resume expressions and the
+// RebalancingSchedule expressions of a stack, with the clusters they apply to.
+// Preview lists the next fire times per cluster and reports mistakes that only
+// show up when a schedule fires: a resume that comes before its pause, a
+// cluster that is never resumed, fire times that a daylight saving time change
+// skips, and rebalancing that fires while the cluster is hibernated.
+//
+//	plan := &schedule.Plan{
+//		Hibernations: []schedule.Hibernation{{
+//			Name:     "weekends",
+//			Pause:    "CRON_TZ=Europe/Berlin 0 20 * * 5",
+//			Resume:   "CRON_TZ=Europe/Berlin 0 6 * * 1",
+//			Clusters: []string{clusterID},
+//		}},
+//	}
+//	report := schedule.Preview(plan, time.Now(), 5)
+//
+// LoadStackExport builds a Plan from the output of `pulumi stack export`.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CheckHorizon is how far ahead Preview looks for findings. It covers the
+// daylight saving time changes of a full year.
+const CheckHorizon = 366 * 24 * time.Hour
+
+// Hibernation is a HibernationSchedule.
+type Hibernation struct {
+	Name string
+	// Pause and Resume are the cron expressions of the pause and resume
+	// configs. They are empty if the config is disabled.
+	Pause  string
+	Resume string
+	// Clusters are the IDs of the clusters the schedule is assigned to.
+	Clusters []string
+}
+
+// Rebalancing is a RebalancingSchedule.
+type Rebalancing struct {
+	Name string
+	Cron string
+	// Clusters are the IDs of the clusters with an enabled RebalancingJob for
+	// the schedule.
+	Clusters []string
+}
+
+// Plan are the schedules to preview.
+type Plan struct {
+	Hibernations []Hibernation
+	Rebalancings []Rebalancing
+}
+
+// EventKind is what happens to a cluster when a schedule fires.
+type EventKind string
+
+const (
+	Pause     EventKind = "pause"
+	Resume    EventKind = "resume"
+	Rebalance EventKind = "rebalance"
+)
+
+// Event is a fire time of a schedule.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Kind     EventKind `json:"kind"`
+	Schedule string    `json:"schedule"`
+}
+
+// ClusterPreview are the next events of a cluster, in time order.
+type ClusterPreview struct {
+	Cluster string  `json:"cluster"`
+	Events  []Event `json:"events"`
+}
+
+// FindingKind classifies a Finding.
+type FindingKind string
+
+const (
+	// Invalid expressions cannot be parsed or never fire.
+	Invalid FindingKind = "invalid"
+	// ResumeBeforePause is reported if a resume fires while the cluster is
+	// not paused by the schedule, or at the same time as a pause.
+	ResumeBeforePause FindingKind = "resume-before-pause"
+	// NoResume is reported if a pause is not followed by a resume.
+	NoResume FindingKind = "no-resume"
+	// DSTGap is reported for fire times that do not exist in the time zone
+	// of the expression because the clocks move forward. The schedule does
+	// not fire on that day.
+	DSTGap FindingKind = "dst-gap"
+	// Overlap is reported if a rebalancing schedule fires while a
+	// hibernation schedule has paused the cluster.
+	Overlap FindingKind = "overlap"
+)
+
+// Finding is a problem found in a schedule. Findings that repeat are reported
+// once, at their first occurrence.
+type Finding struct {
+	Kind     FindingKind `json:"kind"`
+	Schedule string      `json:"schedule"`
+	// Cluster is set for findings that depend on the cluster.
+	Cluster string `json:"cluster,omitempty"`
+	// Time is the first occurrence, if the finding has one.
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%s: %s", f.Kind, f.Schedule)
+	if f.Cluster != "" {
+		s += " on cluster " + f.Cluster
+	}
+	return s + ": " + f.Message
+}
+
+// Report is the result of Preview.
+type Report struct {
+	// Clusters are sorted by ID.
+	Clusters []ClusterPreview `json:"clusters"`
+	Findings []Finding        `json:"findings"`
+}
+
+// timeFormat is the format of times in finding messages.
+const timeFormat = "Mon 2006-01-02 15:04 MST"
+
+// schedule is a parsed expression of the plan.
+type schedule struct {
+	name string
+	kind EventKind
+	cron *Cron
+}
+
+// Preview lists, for every cluster of the plan, the next n fire times of each
+// of its schedules after from, and checks the schedules over CheckHorizon.
+func Preview(plan *Plan, from time.Time, n int) *Report {
+	r := &Report{Clusters: []ClusterPreview{}, Findings: []Finding{}}
+	to := from.Add(CheckHorizon)
+	clusters := map[string][]schedule{}
+
+	parse := func(name string, kind EventKind, expr string) *Cron {
+		if expr == "" {
+			return nil
+		}
+		c, err := Parse(expr)
+		if err != nil {
+			r.add(Finding{Kind: Invalid, Schedule: name, Message: err.Error()})
+			return nil
+		}
+		if _, ok := c.Next(from); !ok {
+			r.add(Finding{Kind: Invalid, Schedule: name, Message: fmt.Sprintf("%s cron %q never fires", kind, expr)})
+			return nil
+		}
+		r.checkSkipped(name, kind, c, from, to)
+		return c
+	}
+
+	for _, h := range plan.Hibernations {
+		pause := parse(h.Name, Pause, h.Pause)
+		resume := parse(h.Name, Resume, h.Resume)
+		if pause == nil {
+			continue
+		}
+		r.checkHibernation(h.Name, pause, resume, from, to)
+		for _, id := range h.Clusters {
+			clusters[id] = append(clusters[id], schedule{name: h.Name, kind: Pause, cron: pause})
+			if resume != nil {
+				clusters[id] = append(clusters[id], schedule{name: h.Name, kind: Resume, cron: resume})
+			}
+		}
+	}
+	for _, rb := range plan.Rebalancings {
+		c := parse(rb.Name, Rebalance, rb.Cron)
+		if c == nil {
+			continue
+		}
+		for _, id := range rb.Clusters {
+			clusters[id] = append(clusters[id], schedule{name: rb.Name, kind: Rebalance, cron: c})
+		}
+	}
+
+	ids := make([]string, 0, len(clusters))
+	for id := range clusters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		preview := ClusterPreview{Cluster: id, Events: []Event{}}
+		for _, s := range clusters[id] {
+			for _, t := range s.cron.NextN(from, n) {
+				preview.Events = append(preview.Events, Event{Time: t, Kind: s.kind, Schedule: s.name})
+			}
+		}
+		sortEvents(preview.Events)
+		r.Clusters = append(r.Clusters, preview)
+		r.checkOverlaps(id, clusters[id], from, to)
+	}
+	return r
+}
+
+func (r *Report) add(f Finding) {
+	r.Findings = append(r.Findings, f)
+}
+
+// checkSkipped reports the fire times of an expression that daylight saving
+// time skips.
+func (r *Report) checkSkipped(name string, kind EventKind, c *Cron, from, to time.Time) {
+	skipped := c.Skipped(from, to)
+	if len(skipped) == 0 {
+		return
+	}
+	msg := fmt.Sprintf("%s cron %q does not fire on %s: %s does not exist in %s",
+		kind, c.Expr, skipped[0].Format("2006-01-02"), skipped[0].Format("15:04"), c.Location)
+	if len(skipped) > 1 {
+		msg += fmt.Sprintf(" (%d skipped fire times)", len(skipped))
+	}
+	if kind == Resume {
+		msg += "; the cluster stays paused until the next resume"
+	}
+	r.add(Finding{Kind: DSTGap, Schedule: name, Time: skipped[0], Message: msg})
+}
+
+// checkHibernation checks that every pause of a hibernation schedule is
+// followed by a resume, and every resume after the first preceded by a pause.
+func (r *Report) checkHibernation(name string, pause, resume *Cron, from, to time.Time) {
+	pauses := pause.Between(from, to)
+	if len(pauses) == 0 {
+		return
+	}
+	if resume == nil {
+		r.add(Finding{Kind: NoResume, Schedule: name, Time: pauses[0], Message: fmt.Sprintf(
+			"the resume config is disabled, clusters paused at %s are not resumed", pauses[0].Format(timeFormat))})
+		return
+	}
+	events := make([]Event, 0, len(pauses))
+	for _, t := range pauses {
+		events = append(events, Event{Time: t, Kind: Pause})
+	}
+	for _, t := range resume.Between(from, to) {
+		events = append(events, Event{Time: t, Kind: Resume})
+	}
+	sortEvents(events)
+
+	var (
+		first    *Event
+		count    int
+		lastKind EventKind
+	)
+	for i, e := range events {
+		together := i+1 < len(events) && events[i+1].Time.Equal(e.Time)
+		if e.Kind == Resume && (lastKind == Resume || together) {
+			if first == nil {
+				first = &events[i]
+			}
+			count++
+		}
+		lastKind = e.Kind
+	}
+	if first != nil {
+		msg := fmt.Sprintf("resume fires at %s without a pause since the previous resume", first.Time.Format(timeFormat))
+		if count > 1 {
+			msg += fmt.Sprintf(" (%d times)", count)
+		}
+		r.add(Finding{Kind: ResumeBeforePause, Schedule: name, Time: first.Time, Message: msg})
+	}
+
+	last := pauses[len(pauses)-1]
+	if _, ok := resume.Next(last); !ok {
+		r.add(Finding{Kind: NoResume, Schedule: name, Time: last, Message: fmt.Sprintf(
+			"resume cron %q never fires after the pause at %s", resume.Expr, last.Format(timeFormat))})
+	}
+}
+
+// checkOverlaps reports rebalancing schedules that fire while a hibernation
+// schedule has paused the cluster. The cluster is assumed to be running
+// until the first pause.
+func (r *Report) checkOverlaps(cluster string, schedules []schedule, from, to time.Time) {
+	var events []Event
+	for _, s := range schedules {
+		for _, t := range s.cron.Between(from, to) {
+			events = append(events, Event{Time: t, Kind: s.kind, Schedule: s.name})
+		}
+	}
+	sortEvents(events)
+
+	type overlap struct {
+		first    Event
+		pausedBy string
+		count    int
+	}
+	var (
+		overlaps []*overlap
+		byName   = map[string]*overlap{}
+		pausedBy string
+	)
+	for _, e := range events {
+		switch e.Kind {
+		case Pause:
+			pausedBy = e.Schedule
+		case Resume:
+			pausedBy = ""
+		case Rebalance:
+			if pausedBy == "" {
+				continue
+			}
+			o := byName[e.Schedule]
+			if o == nil {
+				o = &overlap{first: e, pausedBy: pausedBy}
+				byName[e.Schedule] = o
+				overlaps = append(overlaps, o)
+			}
+			o.count++
+		}
+	}
+	for _, o := range overlaps {
+		msg := fmt.Sprintf("fires at %s while the cluster is paused by %s", o.first.Time.Format(timeFormat), o.pausedBy)
+		if o.count > 1 {
+			msg += fmt.Sprintf(" (%d times)", o.count)
+		}
+		r.add(Finding{Kind: Overlap, Schedule: o.first.Schedule, Cluster: cluster, Time: o.first.Time, Message: msg})
+	}
+}
+
+// eventOrder orders events at the same time: a rebalance at the time of a
+// resume runs after it, and one at the time of a pause before it.
+var eventOrder = map[EventKind]int{Resume: 0, Rebalance: 1, Pause: 2}
+
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+		return eventOrder[events[i].Kind] < eventOrder[events[j].Kind]
+	})
+}
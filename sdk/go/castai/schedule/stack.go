@@ -0,0 +1,133 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Type tokens of the resources read from a stack export.
+const (
+	HibernationScheduleType = "castai:rebalancing:HibernationSchedule"
+	RebalancingScheduleType = "castai:rebalancing:RebalancingSchedule"
+	RebalancingJobType      = "castai:rebalancing:RebalancingJob"
+)
+
+// stackExport is the part of `pulumi stack export` LoadStackExport reads.
+type stackExport struct {
+	Deployment struct {
+		Resources []struct {
+			URN    string          `json:"urn"`
+			Type   string          `json:"type"`
+			ID     string          `json:"id"`
+			Inputs json.RawMessage `json:"inputs"`
+		} `json:"resources"`
+	} `json:"deployment"`
+}
+
+// hibernationInputs are the inputs of a HibernationSchedule.
+type hibernationInputs struct {
+	Name               string `json:"name"`
+	Enabled            *bool  `json:"enabled"`
+	PauseConfig        config `json:"pauseConfig"`
+	ResumeConfig       config `json:"resumeConfig"`
+	ClusterAssignments struct {
+		Assignments []struct {
+			ClusterID any `json:"clusterId"`
+		} `json:"assignments"`
+	} `json:"clusterAssignments"`
+}
+
+type config struct {
+	Enabled  *bool `json:"enabled"`
+	Schedule struct {
+		CronExpression any `json:"cronExpression"`
+	} `json:"schedule"`
+}
+
+// cron returns the expression of an enabled config. Secret or unknown
+// expressions are not strings and are treated as unset.
+func (c config) cron() string {
+	if c.Enabled != nil && !*c.Enabled {
+		return ""
+	}
+	s, _ := c.Schedule.CronExpression.(string)
+	return s
+}
+
+type rebalancingInputs struct {
+	Name     string `json:"name"`
+	Schedule struct {
+		Cron any `json:"cron"`
+	} `json:"schedule"`
+}
+
+type jobInputs struct {
+	ClusterID             any   `json:"clusterId"`
+	RebalancingScheduleID any   `json:"rebalancingScheduleId"`
+	Enabled               *bool `json:"enabled"`
+}
+
+// LoadStackExport reads the hibernation and rebalancing schedules of the
+// output of `pulumi stack export`. Rebalancing schedules apply to the clusters
+// of their enabled RebalancingJobs. Disabled hibernation schedules are left
+// out, as are cluster IDs that are secret or unknown.
+func LoadStackExport(r io.Reader) (*Plan, error) {
+	var export stackExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("reading stack export: %w", err)
+	}
+
+	plan := &Plan{}
+	rebalancings := map[string]int{}
+	var jobs []jobInputs
+	for _, res := range export.Deployment.Resources {
+		switch res.Type {
+		case HibernationScheduleType:
+			var in hibernationInputs
+			if err := json.Unmarshal(res.Inputs, &in); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", res.URN, err)
+			}
+			if in.Enabled != nil && !*in.Enabled {
+				continue
+			}
+			h := Hibernation{Name: in.Name, Pause: in.PauseConfig.cron(), Resume: in.ResumeConfig.cron()}
+			for _, a := range in.ClusterAssignments.Assignments {
+				if id, ok := a.ClusterID.(string); ok {
+					h.Clusters = append(h.Clusters, id)
+				}
+			}
+			plan.Hibernations = append(plan.Hibernations, h)
+		case RebalancingScheduleType:
+			var in rebalancingInputs
+			if err := json.Unmarshal(res.Inputs, &in); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", res.URN, err)
+			}
+			cron, _ := in.Schedule.Cron.(string)
+			rebalancings[res.ID] = len(plan.Rebalancings)
+			plan.Rebalancings = append(plan.Rebalancings, Rebalancing{Name: in.Name, Cron: cron})
+		case RebalancingJobType:
+			var in jobInputs
+			if err := json.Unmarshal(res.Inputs, &in); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", res.URN, err)
+			}
+			jobs = append(jobs, in)
+		}
+	}
+
+	// Jobs may come before the schedules they refer to.
+	for _, job := range jobs {
+		if job.Enabled != nil && !*job.Enabled {
+			continue
+		}
+		cluster, ok := job.ClusterID.(string)
+		if !ok {
+			continue
+		}
+		scheduleID, _ := job.RebalancingScheduleID.(string)
+		if i, ok := rebalancings[scheduleID]; ok {
+			plan.Rebalancings[i].Clusters = append(plan.Rebalancings[i].Clusters, cluster)
+		}
+	}
+	return plan, nil
+}
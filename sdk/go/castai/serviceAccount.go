@@ -7,7 +7,6 @@ import (
 	"context"
 	"reflect"
 
-	"errors"
 	"github.com/castai/pulumi-castai/sdk/go/castai/internal"
 	"github.com/castai/pulumi-castai/sdk/go/castai/organization"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
@@ -32,12 +31,9 @@ type ServiceAccount struct {
 func NewServiceAccount(ctx *pulumi.Context,
 	name string, args *ServiceAccountArgs, opts ...pulumi.ResourceOption) (*ServiceAccount, error) {
 	if args == nil {
-		return nil, errors.New("missing one or more required arguments")
+		args = &ServiceAccountArgs{}
 	}
 
-	if args.OrganizationId == nil {
-		return nil, errors.New("invalid value for required argument 'OrganizationId'")
-	}
 	opts = internal.PkgResourceDefaultOpts(opts)
 	var resource ServiceAccount
 	err := ctx.RegisterResource("castai:organization:ServiceAccount", name, args, &resource, opts...)
@@ -96,7 +92,7 @@ type serviceAccountArgs struct {
 	// Name of the service account.
 	Name *string `pulumi:"name"`
 	// ID of the organization.
-	OrganizationId string `pulumi:"organizationId"`
+	OrganizationId *string `pulumi:"organizationId"`
 }
 
 // The set of arguments for constructing a ServiceAccount resource.
@@ -106,7 +102,7 @@ type ServiceAccountArgs struct {
 	// Name of the service account.
 	Name pulumi.StringPtrInput
 	// ID of the organization.
-	OrganizationId pulumi.StringInput
+	OrganizationId pulumi.StringPtrInput
 }
 
 func (ServiceAccountArgs) ElementType() reflect.Type {
@@ -40,12 +40,13 @@ func NewServiceAccountKey(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.OrganizationId == nil {
-		return nil, errors.New("invalid value for required argument 'OrganizationId'")
-	}
 	if args.ServiceAccountId == nil {
 		return nil, errors.New("invalid value for required argument 'ServiceAccountId'")
 	}
+	secrets := pulumi.AdditionalSecretOutputs([]string{
+		"token",
+	})
+	opts = append(opts, secrets)
 	opts = internal.PkgResourceDefaultOpts(opts)
 	var resource ServiceAccountKey
 	err := ctx.RegisterResource("castai:organization:ServiceAccountKey", name, args, &resource, opts...)
@@ -118,7 +119,7 @@ type serviceAccountKeyArgs struct {
 	// Name of the service account key.
 	Name *string `pulumi:"name"`
 	// ID of the organization.
-	OrganizationId string `pulumi:"organizationId"`
+	OrganizationId *string `pulumi:"organizationId"`
 	// ID of the service account.
 	ServiceAccountId string `pulumi:"serviceAccountId"`
 }
@@ -132,7 +133,7 @@ type ServiceAccountKeyArgs struct {
 	// Name of the service account key.
 	Name pulumi.StringPtrInput
 	// ID of the organization.
-	OrganizationId pulumi.StringInput
+	OrganizationId pulumi.StringPtrInput
 	// ID of the service account.
 	ServiceAccountId pulumi.StringInput
 }
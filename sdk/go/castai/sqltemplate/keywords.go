@@ -0,0 +1,37 @@
+package sqltemplate
+
+// keywords are upper-cased in templates. An identifier spelled like one is
+// upper-cased too, which keeps templates of the same query equal.
+var keywords = set(
+	"ALL", "AND", "ANY", "ARRAY", "AS", "ASC", "BETWEEN", "BY", "CASE",
+	"COLLATE", "CONFLICT", "CROSS", "CURRENT_DATE", "CURRENT_TIME",
+	"CURRENT_TIMESTAMP", "DEFAULT", "DELETE", "DESC", "DISTINCT", "DIV", "DO",
+	"DUPLICATE", "ELSE", "END", "ESCAPE", "EXCEPT", "EXISTS", "FALSE", "FETCH",
+	"FIRST", "FOR", "FROM", "FULL", "GROUP", "HAVING", "IGNORE", "ILIKE", "IN",
+	"INNER", "INSERT", "INTERSECT", "INTERVAL", "INTO", "IS", "JOIN", "KEY",
+	"LAST", "LATERAL", "LEFT", "LIKE", "LIMIT", "LOCKED", "MOD", "NATURAL",
+	"NEXT", "NOT", "NOTHING", "NOWAIT", "NULL", "NULLS", "OFFSET", "ON", "ONLY",
+	"OR", "ORDER", "OUTER", "OVER", "PARTITION", "RECURSIVE", "REGEXP",
+	"REPLACE", "RETURNING", "RIGHT", "RLIKE", "ROW", "ROWS", "SELECT", "SET",
+	"SHARE", "SIMILAR", "SKIP", "SOME", "SQL_CALC_FOUND_ROWS", "STRAIGHT_JOIN",
+	"THEN", "TO", "TRUE", "UNION", "UPDATE", "USING", "VALUE", "VALUES", "WHEN",
+	"WHERE", "WINDOW", "WITH", "XOR",
+)
+
+// functions are upper-cased in templates and, unlike other keywords, are not
+// followed by a space before their arguments.
+var functions = set(
+	"ABS", "ARRAY_AGG", "AVG", "CAST", "CEIL", "COALESCE", "CONCAT", "CONVERT",
+	"COUNT", "DATE_TRUNC", "DENSE_RANK", "EXTRACT", "FLOOR", "GREATEST", "IF",
+	"IFNULL", "JSON_EXTRACT", "LEAST", "LENGTH", "LOWER", "MAX", "MIN", "NOW",
+	"NULLIF", "RANK", "ROUND", "ROW_NUMBER", "STRING_AGG", "SUBSTRING", "SUM",
+	"TRIM", "UNNEST", "UPPER",
+)
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
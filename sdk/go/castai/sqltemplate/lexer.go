@@ -0,0 +1,323 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	// tokWord is a keyword or an unquoted identifier.
+	tokWord tokenKind = iota
+	// tokQuoted is a quoted identifier, without its quotes.
+	tokQuoted
+	// tokParam is a literal or a parameter, both rendered as "?".
+	tokParam
+	// tokPunct is an operator or punctuation.
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) isWord(upper string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, upper)
+}
+
+// operators are the multi-character operators, longest first.
+var operators = []string{
+	"<=>", "->>", "#>>", "!~*",
+	"<>", "!=", ">=", "<=", "||", "&&", "::", ":=", "->", "#>", "<<", ">>",
+	"@>", "<@", "~~", "!~", "~*", "?|", "?&", "**",
+}
+
+type lexer struct {
+	p      Protocol
+	src    string
+	pos    int
+	tokens []token
+}
+
+// lex splits SQL text into tokens, dropping whitespace and comments and
+// turning literals and parameters into tokParam.
+func lex(p Protocol, src string) ([]token, error) {
+	l := &lexer{p: p, src: src}
+	for l.pos < len(l.src) {
+		if err := l.next(); err != nil {
+			return nil, err
+		}
+	}
+	return l.tokens, nil
+}
+
+func (l *lexer) next() error {
+	c := l.src[l.pos]
+	switch {
+	case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+		l.pos++
+	case l.lineComment():
+		if end := strings.IndexByte(l.src[l.pos:], '\n'); end >= 0 {
+			l.pos += end + 1
+		} else {
+			l.pos = len(l.src)
+		}
+	case strings.HasPrefix(l.src[l.pos:], "/*"):
+		return l.blockComment()
+	case c == '\'':
+		return l.str(l.p == MySQL)
+	case c == '"' && l.p == MySQL:
+		return l.str(true)
+	case c == '"' || (c == '`' && l.p == MySQL):
+		return l.quoted(c)
+	case c == '$' && l.p == PostgreSQL:
+		return l.dollar()
+	case c == '?' && l.p == MySQL:
+		l.pos++
+		l.emit(tokParam, "?")
+	case isDigit(c) || (c == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1])):
+		return l.number()
+	case isWordByte(c):
+		return l.word()
+	default:
+		l.operator()
+	}
+	return nil
+}
+
+// lineComment reports whether a -- or # comment starts at the position. In
+// MySQL, -- only starts a comment when followed by whitespace, and # always
+// does; in PostgreSQL, # is an operator.
+func (l *lexer) lineComment() bool {
+	rest := l.src[l.pos:]
+	switch {
+	case strings.HasPrefix(rest, "--"):
+		return l.p == PostgreSQL || len(rest) == 2 || rest[2] <= ' '
+	case rest[0] == '#':
+		return l.p == MySQL
+	}
+	return false
+}
+
+// blockComment skips a /* */ comment. PostgreSQL comments nest.
+func (l *lexer) blockComment() error {
+	start := l.pos
+	depth := 0
+	for l.pos < len(l.src) {
+		rest := l.src[l.pos:]
+		switch {
+		case strings.HasPrefix(rest, "/*") && (depth == 0 || l.p == PostgreSQL):
+			depth++
+			l.pos += 2
+		case strings.HasPrefix(rest, "*/"):
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return nil
+			}
+		default:
+			l.pos++
+		}
+	}
+	return fmt.Errorf("unterminated comment at offset %d", start)
+}
+
+// str skips a string literal quoted with the character at the position.
+func (l *lexer) str(backslashEscapes bool) error {
+	start := l.pos
+	quote := l.src[l.pos]
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch c := l.src[l.pos]; {
+		case c == '\\' && backslashEscapes:
+			l.pos++
+		case c == quote:
+			if l.pos+1 < len(l.src) && l.src[l.pos+1] == quote {
+				l.pos++
+				continue
+			}
+			l.pos++
+			l.emit(tokParam, "?")
+			return nil
+		}
+	}
+	return fmt.Errorf("unterminated string at offset %d", start)
+}
+
+// quoted reads an identifier quoted with " or `, where a doubled quote stands
+// for itself.
+func (l *lexer) quoted(quote byte) error {
+	start := l.pos
+	var b strings.Builder
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		c := l.src[l.pos]
+		if c != quote {
+			b.WriteByte(c)
+			continue
+		}
+		if l.pos+1 < len(l.src) && l.src[l.pos+1] == quote {
+			b.WriteByte(quote)
+			l.pos++
+			continue
+		}
+		l.pos++
+		l.emit(tokQuoted, b.String())
+		return nil
+	}
+	return fmt.Errorf("unterminated quoted identifier at offset %d", start)
+}
+
+// dollar reads a PostgreSQL $1 parameter or $tag$ quoted string.
+func (l *lexer) dollar() error {
+	start := l.pos
+	end := l.pos + 1
+	if end < len(l.src) && isDigit(l.src[end]) {
+		for end < len(l.src) && isDigit(l.src[end]) {
+			end++
+		}
+		l.pos = end
+		l.emit(tokParam, "?")
+		return nil
+	}
+	for end < len(l.src) && isWordByte(l.src[end]) && l.src[end] != '$' {
+		end++
+	}
+	if end >= len(l.src) || l.src[end] != '$' {
+		l.operator()
+		return nil
+	}
+	delimiter := l.src[start : end+1]
+	body := strings.Index(l.src[end+1:], delimiter)
+	if body < 0 {
+		return fmt.Errorf("unterminated dollar-quoted string at offset %d", start)
+	}
+	l.pos = end + 1 + body + len(delimiter)
+	l.emit(tokParam, "?")
+	return nil
+}
+
+// number reads a number literal. A unary sign before it is part of the
+// literal. In MySQL, a digit may also start an identifier, such as 1st.
+func (l *lexer) number() error {
+	start := l.pos
+	if rest := l.src[l.pos:]; len(rest) > 2 && rest[0] == '0' && (rest[1] == 'x' || rest[1] == 'X') && isHex(rest[2]) {
+		l.pos += 2
+		for l.pos < len(l.src) && isHex(l.src[l.pos]) {
+			l.pos++
+		}
+	} else {
+		l.digits()
+		if l.pos < len(l.src) && l.src[l.pos] == '.' {
+			l.pos++
+			l.digits()
+		}
+		if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+			exp := l.pos + 1
+			if exp < len(l.src) && (l.src[exp] == '+' || l.src[exp] == '-') {
+				exp++
+			}
+			if exp < len(l.src) && isDigit(l.src[exp]) {
+				l.pos = exp
+				l.digits()
+			}
+		}
+	}
+	if l.p == MySQL && l.pos < len(l.src) && isWordByte(l.src[l.pos]) {
+		l.pos = start
+		return l.word()
+	}
+	l.unarySign()
+	l.emit(tokParam, "?")
+	return nil
+}
+
+func (l *lexer) digits() {
+	for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || (l.src[l.pos] == '_' && l.p == PostgreSQL)) {
+		l.pos++
+	}
+}
+
+// unarySign drops a + or - before a number that does not follow an operand.
+func (l *lexer) unarySign() {
+	n := len(l.tokens)
+	if n == 0 || !(l.tokens[n-1].is(tokPunct, "-") || l.tokens[n-1].is(tokPunct, "+")) {
+		return
+	}
+	if n > 1 {
+		switch prev := l.tokens[n-2]; prev.kind {
+		case tokParam, tokQuoted:
+			return
+		case tokWord:
+			if !keywords[strings.ToUpper(prev.text)] {
+				return
+			}
+		case tokPunct:
+			if prev.text == ")" {
+				return
+			}
+		}
+	}
+	l.tokens = l.tokens[:n-1]
+}
+
+// word reads a keyword or identifier, or a string literal with a prefix such
+// as E'...', X'...' or MySQL's _utf8mb4'...'.
+func (l *lexer) word() error {
+	start := l.pos
+	for l.pos < len(l.src) && isWordByte(l.src[l.pos]) {
+		l.pos++
+	}
+	text := l.src[start:l.pos]
+	if l.pos < len(l.src) {
+		prefix := strings.ToLower(text)
+		switch {
+		case l.src[l.pos] == '\'' && (prefix == "e" || prefix == "b" || prefix == "x" || prefix == "n" ||
+			(l.p == MySQL && strings.HasPrefix(prefix, "_"))):
+			return l.str(l.p == MySQL || prefix == "e")
+		case l.p == PostgreSQL && prefix == "u" && strings.HasPrefix(l.src[l.pos:], "&'"):
+			l.pos++
+			return l.str(false)
+		case l.p == PostgreSQL && prefix == "u" && strings.HasPrefix(l.src[l.pos:], `&"`):
+			l.pos++
+			return l.quoted('"')
+		}
+	}
+	l.emit(tokWord, text)
+	return nil
+}
+
+func (l *lexer) operator() {
+	rest := l.src[l.pos:]
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			l.pos += len(op)
+			l.emit(tokPunct, op)
+			return
+		}
+	}
+	l.pos++
+	l.emit(tokPunct, rest[:1])
+}
+
+func (l *lexer) emit(kind tokenKind, text string) {
+	l.tokens = append(l.tokens, token{kind, text})
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isWordByte reports whether c may be part of an unquoted identifier. Bytes
+// of multi-byte UTF-8 characters are.
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
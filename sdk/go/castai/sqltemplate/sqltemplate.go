@@ -0,0 +1,245 @@
+// Package sqltemplate normalizes SQL text into the query templates DB
+// Optimizer groups queries by.
+//
+// A template is a query with its literals replaced by placeholders, so that
+// all executions of a query differing only in their parameters share one
+// template. Normalize turns SQL text into its template for the
+// `protocolType` of a castai.CacheGroup:
+//
+//   - String and number literals, MySQL `?` and PostgreSQL `$1` parameters
+//     become `?`. A unary minus is part of the number it precedes.
+//   - Lists of placeholders after IN collapse to `(?)`, and repeated identical
+//     rows after VALUES to one row.
+//   - Comments are dropped and whitespace collapses to single spaces, with
+//     none inside parentheses and brackets, before commas and around dots
+//     and `::`.
+//   - Keywords and common functions are upper-cased. Unquoted identifiers are
+//     kept as written for MySQL, whose table names are case sensitive, and
+//     lower-cased for PostgreSQL, which folds them. Quoted identifiers that
+//     mean the same unquoted, such as `users` or "users", lose their quotes.
+//   - A trailing semicolon is dropped.
+//
+// The package does not compute castai.CacheRule `templateHash` values yet.
+// DB Optimizer does not document how it hashes templates, and a rule with a
+// hash no query produces is accepted and never applies, so until the
+// algorithm is confirmed against hashes of real rules, they are taken from
+// the CAST AI console.
+package sqltemplate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Protocol is the `protocolType` of a castai.CacheGroup.
+type Protocol string
+
+const (
+	MySQL      Protocol = "MySQL"
+	PostgreSQL Protocol = "PostgreSQL"
+)
+
+// Protocols lists the supported protocols.
+var Protocols = []Protocol{MySQL, PostgreSQL}
+
+func (p Protocol) validate() error {
+	for _, supported := range Protocols {
+		if p == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported protocol %q, must be %q or %q", p, MySQL, PostgreSQL)
+}
+
+// Normalize returns the template of a query. It fails on unsupported
+// protocols, unterminated strings, quoted identifiers and comments, and text
+// without a query.
+func Normalize(p Protocol, sql string) (string, error) {
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+	tokens, err := lex(p, sql)
+	if err != nil {
+		return "", err
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].is(tokPunct, ";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return "", errors.New("no query in SQL text")
+	}
+	tokens = collapseValues(collapseIn(tokens))
+	return render(p, tokens), nil
+}
+
+// collapseIn replaces placeholder lists after IN with a single placeholder.
+func collapseIn(tokens []token) []token {
+	out := make([]token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		out = append(out, tokens[i])
+		if !tokens[i].isWord("IN") {
+			continue
+		}
+		end, ok := placeholderList(tokens, i+1)
+		if !ok {
+			continue
+		}
+		out = append(out, token{tokPunct, "("}, token{tokParam, "?"}, token{tokPunct, ")"})
+		i = end
+	}
+	return out
+}
+
+// placeholderList returns the index of the closing parenthesis of a
+// parenthesized list of placeholders starting at i.
+func placeholderList(tokens []token, i int) (int, bool) {
+	if i >= len(tokens) || !tokens[i].is(tokPunct, "(") {
+		return 0, false
+	}
+	for j := i + 1; j+1 < len(tokens); j += 2 {
+		if tokens[j].kind != tokParam {
+			return 0, false
+		}
+		switch {
+		case tokens[j+1].is(tokPunct, ")"):
+			return j + 1, true
+		case !tokens[j+1].is(tokPunct, ","):
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// collapseValues drops rows after VALUES that repeat the row before them.
+func collapseValues(tokens []token) []token {
+	out := make([]token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		out = append(out, tokens[i])
+		if !tokens[i].isWord("VALUES") && !tokens[i].isWord("VALUE") {
+			continue
+		}
+		end, ok := group(tokens, i+1)
+		if !ok {
+			continue
+		}
+		row := tokens[i+1 : end+1]
+		out = append(out, row...)
+		i = end
+		for i+1 < len(tokens) && tokens[i+1].is(tokPunct, ",") {
+			next, ok := group(tokens, i+2)
+			if !ok {
+				break
+			}
+			if !equalTokens(row, tokens[i+2:next+1]) {
+				row = tokens[i+2 : next+1]
+				out = append(out, tokens[i+1])
+				out = append(out, row...)
+			}
+			i = next
+		}
+	}
+	return out
+}
+
+// group returns the index of the parenthesis closing the one at i.
+func group(tokens []token, i int) (int, bool) {
+	if i >= len(tokens) || !tokens[i].is(tokPunct, "(") {
+		return 0, false
+	}
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		switch {
+		case tokens[j].is(tokPunct, "("):
+			depth++
+		case tokens[j].is(tokPunct, ")"):
+			depth--
+			if depth == 0 {
+				return j, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func equalTokens(a, b []token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	plainMySQL      = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	plainPostgreSQL = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+)
+
+// render joins the tokens with the casing and spacing of the template.
+func render(p Protocol, tokens []token) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 && spaced(tokens[i-1], t) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.render(p))
+	}
+	return b.String()
+}
+
+func (t token) render(p Protocol) string {
+	switch t.kind {
+	case tokWord:
+		upper := strings.ToUpper(t.text)
+		if keywords[upper] || functions[upper] {
+			return upper
+		}
+		if p == PostgreSQL {
+			return strings.ToLower(t.text)
+		}
+		return t.text
+	case tokQuoted:
+		if p == PostgreSQL {
+			if plainPostgreSQL.MatchString(t.text) {
+				return token{tokWord, t.text}.render(p)
+			}
+			return `"` + strings.ReplaceAll(t.text, `"`, `""`) + `"`
+		}
+		if plainMySQL.MatchString(t.text) {
+			return token{tokWord, t.text}.render(p)
+		}
+		return "`" + strings.ReplaceAll(t.text, "`", "``") + "`"
+	}
+	return t.text
+}
+
+// spaced reports whether the template has a space between two tokens.
+func spaced(prev, cur token) bool {
+	if cur.kind == tokPunct {
+		switch cur.text {
+		case ")", "]", ",", ";", ".", "::", "[":
+			return false
+		case "(":
+			// Function calls, as opposed to keywords followed by a list.
+			if prev.kind == tokQuoted {
+				return false
+			}
+			if prev.kind == tokWord {
+				upper := strings.ToUpper(prev.text)
+				return keywords[upper] && !functions[upper]
+			}
+		}
+	}
+	if prev.kind == tokPunct {
+		switch prev.text {
+		case "(", "[", ".", "::":
+			return false
+		}
+	}
+	return true
+}
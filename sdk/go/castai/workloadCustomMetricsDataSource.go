@@ -37,9 +37,6 @@ func NewWorkloadCustomMetricsDataSource(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.Prometheus == nil {
 		return nil, errors.New("invalid value for required argument 'Prometheus'")
 	}
@@ -101,7 +98,7 @@ func (WorkloadCustomMetricsDataSourceState) ElementType() reflect.Type {
 
 type workloadCustomMetricsDataSourceArgs struct {
 	// CAST AI cluster ID.
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// Name of the custom metrics data source (1-63 characters).
 	Name *string `pulumi:"name"`
 	// Prometheus data source configuration.
@@ -111,7 +108,7 @@ type workloadCustomMetricsDataSourceArgs struct {
 // The set of arguments for constructing a WorkloadCustomMetricsDataSource resource.
 type WorkloadCustomMetricsDataSourceArgs struct {
 	// CAST AI cluster ID.
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// Name of the custom metrics data source (1-63 characters).
 	Name pulumi.StringPtrInput
 	// Prometheus data source configuration.
@@ -66,9 +66,6 @@ func NewWorkloadScalingPolicy(ctx *pulumi.Context,
 	if args.ApplyType == nil {
 		return nil, errors.New("invalid value for required argument 'ApplyType'")
 	}
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.Cpu == nil {
 		return nil, errors.New("invalid value for required argument 'Cpu'")
 	}
@@ -197,7 +194,7 @@ type workloadScalingPolicyArgs struct {
 	// Allows defining conditions for automatically assigning workloads to this scaling policy.
 	AssignmentRules []workload.WorkloadScalingPolicyAssignmentRule `pulumi:"assignmentRules"`
 	// CAST AI cluster id
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// Defines the confidence settings for applying recommendations.
 	Confidence  *workload.WorkloadScalingPolicyConfidence  `pulumi:"confidence"`
 	Cpu         workload.WorkloadScalingPolicyCpu          `pulumi:"cpu"`
@@ -239,7 +236,7 @@ type WorkloadScalingPolicyArgs struct {
 	// Allows defining conditions for automatically assigning workloads to this scaling policy.
 	AssignmentRules workload.WorkloadScalingPolicyAssignmentRuleArrayInput
 	// CAST AI cluster id
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// Defines the confidence settings for applying recommendations.
 	Confidence  workload.WorkloadScalingPolicyConfidencePtrInput
 	Cpu         workload.WorkloadScalingPolicyCpuInput
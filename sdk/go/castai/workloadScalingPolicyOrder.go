@@ -28,9 +28,6 @@ func NewWorkloadScalingPolicyOrder(ctx *pulumi.Context,
 		return nil, errors.New("missing one or more required arguments")
 	}
 
-	if args.ClusterId == nil {
-		return nil, errors.New("invalid value for required argument 'ClusterId'")
-	}
 	if args.PolicyIds == nil {
 		return nil, errors.New("invalid value for required argument 'PolicyIds'")
 	}
@@ -76,7 +73,7 @@ func (WorkloadScalingPolicyOrderState) ElementType() reflect.Type {
 
 type workloadScalingPolicyOrderArgs struct {
 	// CAST AI cluster id
-	ClusterId string `pulumi:"clusterId"`
+	ClusterId *string `pulumi:"clusterId"`
 	// List of scaling policy IDs in the order they should be applied.
 	PolicyIds []string `pulumi:"policyIds"`
 }
@@ -84,7 +81,7 @@ type workloadScalingPolicyOrderArgs struct {
 // The set of arguments for constructing a WorkloadScalingPolicyOrder resource.
 type WorkloadScalingPolicyOrderArgs struct {
 	// CAST AI cluster id
-	ClusterId pulumi.StringInput
+	ClusterId pulumi.StringPtrInput
 	// List of scaling policy IDs in the order they should be applied.
 	PolicyIds pulumi.StringArrayInput
 }
@@ -166,7 +166,7 @@ export interface AiOptimizerHostedModelArgs {
     /**
      * CAST AI cluster ID where the model will be deployed.
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * List of edge location IDs where the model can be deployed.
      */
@@ -87,9 +87,6 @@ class AiOptimizerHostedModel extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             if (args?.modelSpecsId === undefined && !opts.urn) {
                 throw new Error("Missing required property 'modelSpecsId'");
             }
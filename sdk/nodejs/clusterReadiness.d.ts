@@ -0,0 +1,100 @@
+import * as pulumi from "@pulumi/pulumi";
+export declare class ClusterReadiness extends pulumi.CustomResource {
+    /**
+     * Get an existing ClusterReadiness resource's state with the given name, ID, and optional extra
+     * properties used to qualify the lookup.
+     *
+     * @param name The _unique_ name of the resulting resource.
+     * @param id The _unique_ provider ID of the resource to lookup.
+     * @param state Any extra arguments used during the lookup.
+     * @param opts Optional settings to control the behavior of the CustomResource.
+     */
+    static get(name: string, id: pulumi.Input<pulumi.ID>, state?: ClusterReadinessState, opts?: pulumi.CustomResourceOptions): ClusterReadiness;
+    /**
+     * Returns true if the given object is an instance of ClusterReadiness.  This is designed to work even
+     * when multiple copies of the Pulumi SDK have been loaded into the same process.
+     */
+    static isInstance(obj: any): obj is ClusterReadiness;
+    /**
+     * Agent status, e.g. "waiting-connection" or "online".
+     */
+    readonly agentStatus: pulumi.Output<string>;
+    /**
+     * CAST AI cluster ID to wait for.
+     */
+    readonly clusterId: pulumi.Output<string>;
+    /**
+     * Wait between two status checks, e.g. "30s".
+     */
+    readonly pollInterval: pulumi.Output<string | undefined>;
+    /**
+     * Cluster status, e.g. "connecting" or "ready".
+     */
+    readonly status: pulumi.Output<string>;
+    /**
+     * Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+     */
+    readonly timeout: pulumi.Output<string | undefined>;
+    /**
+     * Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+     */
+    readonly waitFor: pulumi.Output<string | undefined>;
+    /**
+     * Create a ClusterReadiness resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the resource.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args?: ClusterReadinessArgs, opts?: pulumi.CustomResourceOptions);
+}
+/**
+ * Input properties used for looking up and filtering ClusterReadiness resources.
+ */
+export interface ClusterReadinessState {
+    /**
+     * Agent status, e.g. "waiting-connection" or "online".
+     */
+    agentStatus?: pulumi.Input<string | undefined>;
+    /**
+     * CAST AI cluster ID to wait for.
+     */
+    clusterId?: pulumi.Input<string | undefined>;
+    /**
+     * Wait between two status checks, e.g. "30s".
+     */
+    pollInterval?: pulumi.Input<string | undefined>;
+    /**
+     * Cluster status, e.g. "connecting" or "ready".
+     */
+    status?: pulumi.Input<string | undefined>;
+    /**
+     * Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+     */
+    timeout?: pulumi.Input<string | undefined>;
+    /**
+     * Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+     */
+    waitFor?: pulumi.Input<string | undefined>;
+}
+/**
+ * The set of arguments for constructing a ClusterReadiness resource.
+ */
+export interface ClusterReadinessArgs {
+    /**
+     * CAST AI cluster ID to wait for.
+     */
+    clusterId?: pulumi.Input<string | undefined>;
+    /**
+     * Wait between two status checks, e.g. "30s".
+     */
+    pollInterval?: pulumi.Input<string | undefined>;
+    /**
+     * Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+     */
+    timeout?: pulumi.Input<string | undefined>;
+    /**
+     * Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+     */
+    waitFor?: pulumi.Input<string | undefined>;
+}
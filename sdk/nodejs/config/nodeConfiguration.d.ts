@@ -155,7 +155,7 @@ export interface NodeConfigurationArgs {
     /**
      * CAST AI cluster id
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * Optional container runtime to be used by kubelet. Applicable for EKS only.  Supported values include: `dockerd`, `containerd`
      */
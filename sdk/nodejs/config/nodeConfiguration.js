@@ -88,9 +88,6 @@ class NodeConfiguration extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             if (args?.subnets === undefined && !opts.urn) {
                 throw new Error("Missing required property 'subnets'");
             }
@@ -52,7 +52,7 @@ export interface NodeConfigurationDefaultArgs {
     /**
      * CAST AI cluster id
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * Id of the node configuration
      */
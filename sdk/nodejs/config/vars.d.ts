@@ -6,8 +6,28 @@ export declare const apiToken: string | undefined;
  * CAST.AI API url.
  */
 export declare const apiUrl: string;
+/**
+ * Default CAST AI cluster ID for resources that do not set one.
+ */
+export declare const defaultClusterId: string | undefined;
+/**
+ * Maximum number of CAST AI API requests in flight. `0` means no limit.
+ */
+export declare const maxConcurrentRequests: number;
+/**
+ * Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+ */
+export declare const maxRetries: number;
 /**
  * CAST AI organization ID. Required when the API token has access to multiple organizations.
  */
 export declare const organizationId: string | undefined;
+/**
+ * Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+ */
+export declare const requestTimeout: string;
+/**
+ * Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+ */
+export declare const retryBackoff: string;
 //# sourceMappingURL=vars.d.ts.map
\ No newline at end of file
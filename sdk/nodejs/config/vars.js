@@ -50,9 +50,39 @@ Object.defineProperty(exports, "apiUrl", {
     },
     enumerable: true,
 });
+Object.defineProperty(exports, "defaultClusterId", {
+    get() {
+        return __config.get("defaultClusterId") ?? utilities.getEnv("CASTAI_DEFAULT_CLUSTER_ID");
+    },
+    enumerable: true,
+});
+Object.defineProperty(exports, "maxConcurrentRequests", {
+    get() {
+        return __config.getObject("maxConcurrentRequests") ?? (utilities.getEnvNumber("CASTAI_MAX_CONCURRENT_REQUESTS") || 0);
+    },
+    enumerable: true,
+});
+Object.defineProperty(exports, "maxRetries", {
+    get() {
+        return __config.getObject("maxRetries") ?? (utilities.getEnvNumber("CASTAI_MAX_RETRIES") || 3);
+    },
+    enumerable: true,
+});
 Object.defineProperty(exports, "organizationId", {
     get() {
-        return __config.get("organizationId");
+        return __config.get("organizationId") ?? utilities.getEnv("CASTAI_ORGANIZATION_ID");
+    },
+    enumerable: true,
+});
+Object.defineProperty(exports, "requestTimeout", {
+    get() {
+        return __config.get("requestTimeout") ?? (utilities.getEnv("CASTAI_REQUEST_TIMEOUT") || "1m");
+    },
+    enumerable: true,
+});
+Object.defineProperty(exports, "retryBackoff", {
+    get() {
+        return __config.get("retryBackoff") ?? (utilities.getEnv("CASTAI_RETRY_BACKOFF") || "1s");
     },
     enumerable: true,
 });
@@ -24,7 +24,7 @@ export declare class EksUserArn extends pulumi.CustomResource {
      * @param args The arguments to use to populate this resource's properties.
      * @param opts A bag of options that control this resource's behavior.
      */
-    constructor(name: string, args: EksUserArnArgs, opts?: pulumi.CustomResourceOptions);
+    constructor(name: string, args?: EksUserArnArgs, opts?: pulumi.CustomResourceOptions);
 }
 /**
  * Input properties used for looking up and filtering EksUserArn resources.
@@ -37,6 +37,6 @@ export interface EksUserArnState {
  * The set of arguments for constructing a EksUserArn resource.
  */
 export interface EksUserArnArgs {
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
 }
 //# sourceMappingURL=eksUserArn.d.ts.map
\ No newline at end of file
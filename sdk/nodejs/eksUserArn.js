@@ -73,9 +73,6 @@ class EksUserArn extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             resourceInputs["clusterId"] = args?.clusterId;
             resourceInputs["arn"] = undefined /*out*/;
         }
@@ -102,7 +102,7 @@ export interface EnterpriseGroupArgs {
     /**
      * Target organization ID for the group.
      */
-    organizationId: pulumi.Input<string>;
+    organizationId?: pulumi.Input<string | undefined>;
     /**
      * List of role bindings for the group.
      */
@@ -80,9 +80,6 @@ class EnterpriseGroup extends pulumi.CustomResource {
             if (args?.enterpriseId === undefined && !opts.urn) {
                 throw new Error("Missing required property 'enterpriseId'");
             }
-            if (args?.organizationId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'organizationId'");
-            }
             resourceInputs["description"] = args?.description;
             resourceInputs["enterpriseId"] = args?.enterpriseId;
             resourceInputs["members"] = args?.members;
@@ -106,7 +106,7 @@ export interface EnterpriseRoleBindingArgs {
     /**
      * Organization ID (either enterprise or it's child) where the role binding is created.
      */
-    organizationId: pulumi.Input<string>;
+    organizationId?: pulumi.Input<string | undefined>;
     /**
      * Role UUID to bind.
      */
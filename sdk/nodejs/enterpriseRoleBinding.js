@@ -81,9 +81,6 @@ class EnterpriseRoleBinding extends pulumi.CustomResource {
             if (args?.enterpriseId === undefined && !opts.urn) {
                 throw new Error("Missing required property 'enterpriseId'");
             }
-            if (args?.organizationId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'organizationId'");
-            }
             if (args?.roleId === undefined && !opts.urn) {
                 throw new Error("Missing required property 'roleId'");
             }
@@ -54,7 +54,7 @@ export interface EvictorAdvancedConfigArgs {
     /**
      * CAST AI cluster id.
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * evictor advanced configuration to target specific node/pod
      */
@@ -73,9 +73,6 @@ class EvictorAdvancedConfig extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             if (args?.evictorAdvancedConfigs === undefined && !opts.urn) {
                 throw new Error("Missing required property 'evictorAdvancedConfigs'");
             }
@@ -25,6 +25,9 @@ export declare const CacheGroup: typeof import("./cacheGroup").CacheGroup;
 export { CacheRuleArgs, CacheRuleState } from "./cacheRule";
 export type CacheRule = import("./cacheRule").CacheRule;
 export declare const CacheRule: typeof import("./cacheRule").CacheRule;
+export { ClusterReadinessArgs, ClusterReadinessState } from "./clusterReadiness";
+export type ClusterReadiness = import("./clusterReadiness").ClusterReadiness;
+export declare const ClusterReadiness: typeof import("./clusterReadiness").ClusterReadiness;
 export { CommitmentsArgs, CommitmentsState } from "./commitments";
 export type Commitments = import("./commitments").Commitments;
 export declare const Commitments: typeof import("./commitments").Commitments;
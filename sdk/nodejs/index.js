@@ -38,7 +38,7 @@ var __exportStar = (this && this.__exportStar) || function(m, exports) {
     for (var p in m) if (p !== "default" && !Object.prototype.hasOwnProperty.call(exports, p)) __createBinding(exports, m, p);
 };
 Object.defineProperty(exports, "__esModule", { value: true });
-exports.WorkloadCustomMetricsDataSource = exports.SSOConnection = exports.ServiceAccountKey = exports.ServiceAccount = exports.SecurityRuntimeRule = exports.RoleBindings = exports.Reservations = exports.RebalancingSchedule = exports.RebalancingJob = exports.PodMutation = exports.OrganizationMembers = exports.OrganizationGroup = exports.HibernationSchedule = exports.GkeClusterId = exports.GkeCluster = exports.getWorkloadScalingPolicyOrderOutput = exports.getWorkloadScalingPolicyOrder = exports.getWorkloadScalingPoliciesOutput = exports.getWorkloadScalingPolicies = exports.getRebalancingScheduleOutput = exports.getRebalancingSchedule = exports.getOrganizationOutput = exports.getOrganization = exports.getImpersonationServiceAccountOutput = exports.getImpersonationServiceAccount = exports.getHibernationScheduleOutput = exports.getHibernationSchedule = exports.getGkePoliciesOutput = exports.getGkePolicies = exports.getEksSettingsOutput = exports.getEksSettings = exports.getCacheGroupOutput = exports.getCacheGroup = exports.EvictorAdvancedConfig = exports.EnterpriseServiceAccount = exports.EnterpriseRoleBinding = exports.EnterpriseGroup = exports.EksUserArn = exports.EksClusterId = exports.EksCluster = exports.Commitments = exports.CacheRule = exports.CacheGroup = exports.CacheConfiguration = exports.Autoscaler = exports.AllocationGroup = exports.AksCluster = exports.AiOptimizerModelSpecs = exports.AiOptimizerModelRegistry = exports.AiOptimizerHostedModel = void 0;
+exports.WorkloadCustomMetricsDataSource = exports.SSOConnection = exports.ServiceAccountKey = exports.ServiceAccount = exports.SecurityRuntimeRule = exports.RoleBindings = exports.Reservations = exports.RebalancingSchedule = exports.RebalancingJob = exports.PodMutation = exports.OrganizationMembers = exports.OrganizationGroup = exports.HibernationSchedule = exports.GkeClusterId = exports.GkeCluster = exports.getWorkloadScalingPolicyOrderOutput = exports.getWorkloadScalingPolicyOrder = exports.getWorkloadScalingPoliciesOutput = exports.getWorkloadScalingPolicies = exports.getRebalancingScheduleOutput = exports.getRebalancingSchedule = exports.getOrganizationOutput = exports.getOrganization = exports.getImpersonationServiceAccountOutput = exports.getImpersonationServiceAccount = exports.getHibernationScheduleOutput = exports.getHibernationSchedule = exports.getGkePoliciesOutput = exports.getGkePolicies = exports.getEksSettingsOutput = exports.getEksSettings = exports.getCacheGroupOutput = exports.getCacheGroup = exports.EvictorAdvancedConfig = exports.EnterpriseServiceAccount = exports.EnterpriseRoleBinding = exports.EnterpriseGroup = exports.EksUserArn = exports.EksClusterId = exports.EksCluster = exports.Commitments = exports.ClusterReadiness = exports.CacheRule = exports.CacheGroup = exports.CacheConfiguration = exports.Autoscaler = exports.AllocationGroup = exports.AksCluster = exports.AiOptimizerModelSpecs = exports.AiOptimizerModelRegistry = exports.AiOptimizerHostedModel = void 0;
 exports.types = exports.rebalancing = exports.config = exports.WorkloadScalingPolicyOrder = exports.WorkloadScalingPolicy = void 0;
 const pulumi = __importStar(require("@pulumi/pulumi"));
 const utilities = __importStar(require("./utilities"));
@@ -60,6 +60,8 @@ exports.CacheGroup = null;
 utilities.lazyLoad(exports, ["CacheGroup"], () => require("./cacheGroup"));
 exports.CacheRule = null;
 utilities.lazyLoad(exports, ["CacheRule"], () => require("./cacheRule"));
+exports.ClusterReadiness = null;
+utilities.lazyLoad(exports, ["ClusterReadiness"], () => require("./clusterReadiness"));
 exports.Commitments = null;
 utilities.lazyLoad(exports, ["Commitments"], () => require("./commitments"));
 exports.EksCluster = null;
@@ -184,6 +186,8 @@ const _module = {
                 return new exports.AiOptimizerModelSpecs(name, undefined, { urn });
             case "castai:index:AllocationGroup":
                 return new exports.AllocationGroup(name, undefined, { urn });
+            case "castai:index:ClusterReadiness":
+                return new exports.ClusterReadiness(name, undefined, { urn });
             case "castai:index:Commitments":
                 return new exports.Commitments(name, undefined, { urn });
             case "castai:index:PodMutation":
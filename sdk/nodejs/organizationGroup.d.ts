@@ -37,7 +37,7 @@ export declare class OrganizationGroup extends pulumi.CustomResource {
      * @param args The arguments to use to populate this resource's properties.
      * @param opts A bag of options that control this resource's behavior.
      */
-    constructor(name: string, args: OrganizationGroupArgs, opts?: pulumi.CustomResourceOptions);
+    constructor(name: string, args?: OrganizationGroupArgs, opts?: pulumi.CustomResourceOptions);
 }
 /**
  * Input properties used for looking up and filtering OrganizationGroup resources.
@@ -73,6 +73,6 @@ export interface OrganizationGroupArgs {
     /**
      * CAST AI organization ID.
      */
-    organizationId: pulumi.Input<string>;
+    organizationId?: pulumi.Input<string | undefined>;
 }
 //# sourceMappingURL=organizationGroup.d.ts.map
\ No newline at end of file
@@ -75,9 +75,6 @@ class OrganizationGroup extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.organizationId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'organizationId'");
-            }
             resourceInputs["description"] = args?.description;
             resourceInputs["members"] = args?.members;
             resourceInputs["name"] = args?.name;
@@ -44,7 +44,7 @@ export declare class OrganizationMembers extends pulumi.CustomResource {
      * @param args The arguments to use to populate this resource's properties.
      * @param opts A bag of options that control this resource's behavior.
      */
-    constructor(name: string, args: OrganizationMembersArgs, opts?: pulumi.CustomResourceOptions);
+    constructor(name: string, args?: OrganizationMembersArgs, opts?: pulumi.CustomResourceOptions);
 }
 /**
  * Input properties used for looking up and filtering OrganizationMembers resources.
@@ -86,7 +86,7 @@ export interface OrganizationMembersArgs {
     /**
      * CAST AI organization ID.
      */
-    organizationId: pulumi.Input<string>;
+    organizationId?: pulumi.Input<string | undefined>;
     /**
      * A list of email addresses corresponding to users who should be given owner access to the organization.
      *
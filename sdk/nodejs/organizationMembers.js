@@ -75,9 +75,6 @@ class OrganizationMembers extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.organizationId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'organizationId'");
-            }
             resourceInputs["members"] = args?.members;
             resourceInputs["organizationId"] = args?.organizationId;
             resourceInputs["owners"] = args?.owners;
@@ -184,7 +184,7 @@ export interface PodMutationArgs {
     /**
      * ID of the cluster.
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * Distribution groups for percentage-based pod distribution.
      */
@@ -23,6 +23,14 @@ export declare class Provider extends pulumi.ProviderResource {
      * CAST AI organization ID. Required when the API token has access to multiple organizations.
      */
     readonly organizationId: pulumi.Output<string | undefined>;
+    /**
+     * Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+     */
+    readonly requestTimeout: pulumi.Output<string | undefined>;
+    /**
+     * Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+     */
+    readonly retryBackoff: pulumi.Output<string | undefined>;
     /**
      * Create a Provider resource with the given unique name, arguments, and options.
      *
@@ -48,10 +56,26 @@ export interface ProviderArgs {
      * CAST.AI API url.
      */
     apiUrl?: pulumi.Input<string | undefined>;
+    /**
+     * Maximum number of CAST AI API requests in flight. `0` means no limit.
+     */
+    maxConcurrentRequests?: pulumi.Input<number | undefined>;
+    /**
+     * Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+     */
+    maxRetries?: pulumi.Input<number | undefined>;
     /**
      * CAST AI organization ID. Required when the API token has access to multiple organizations.
      */
     organizationId?: pulumi.Input<string | undefined>;
+    /**
+     * Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+     */
+    requestTimeout?: pulumi.Input<string | undefined>;
+    /**
+     * Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+     */
+    retryBackoff?: pulumi.Input<string | undefined>;
 }
 export declare namespace Provider {
     /**
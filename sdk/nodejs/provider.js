@@ -70,7 +70,11 @@ class Provider extends pulumi.ProviderResource {
         {
             resourceInputs["apiToken"] = (args?.apiToken ? pulumi.secret(args.apiToken) : undefined) ?? utilities.getEnv("CASTAI_API_TOKEN");
             resourceInputs["apiUrl"] = (args?.apiUrl) ?? (utilities.getEnv("CASTAI_API_URL") || "https://api.cast.ai");
-            resourceInputs["organizationId"] = args?.organizationId;
+            resourceInputs["maxConcurrentRequests"] = pulumi.output((args?.maxConcurrentRequests) ?? (utilities.getEnvNumber("CASTAI_MAX_CONCURRENT_REQUESTS") || 0)).apply(JSON.stringify);
+            resourceInputs["maxRetries"] = pulumi.output((args?.maxRetries) ?? (utilities.getEnvNumber("CASTAI_MAX_RETRIES") || 3)).apply(JSON.stringify);
+            resourceInputs["organizationId"] = (args?.organizationId) ?? utilities.getEnv("CASTAI_ORGANIZATION_ID");
+            resourceInputs["requestTimeout"] = (args?.requestTimeout) ?? (utilities.getEnv("CASTAI_REQUEST_TIMEOUT") || "1m");
+            resourceInputs["retryBackoff"] = (args?.retryBackoff) ?? (utilities.getEnv("CASTAI_RETRY_BACKOFF") || "1s");
         }
         opts = pulumi.mergeOptions(utilities.resourceOptsDefaults(), opts);
         const secretOpts = { additionalSecretOutputs: ["apiToken"] };
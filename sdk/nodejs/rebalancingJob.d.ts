@@ -60,7 +60,7 @@ export interface RebalancingJobArgs {
     /**
      * CAST AI cluster id.
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * The job will only be executed if it's enabled.
      */
@@ -74,9 +74,6 @@ class RebalancingJob extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             if (args?.rebalancingScheduleId === undefined && !opts.urn) {
                 throw new Error("Missing required property 'rebalancingScheduleId'");
             }
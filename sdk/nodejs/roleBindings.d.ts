@@ -88,7 +88,7 @@ export interface RoleBindingsArgs {
     /**
      * CAST AI organization ID.
      */
-    organizationId: pulumi.Input<string>;
+    organizationId?: pulumi.Input<string | undefined>;
     /**
      * ID of role from role binding.
      */
@@ -77,9 +77,6 @@ class RoleBindings extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.organizationId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'organizationId'");
-            }
             if (args?.roleId === undefined && !opts.urn) {
                 throw new Error("Missing required property 'roleId'");
             }
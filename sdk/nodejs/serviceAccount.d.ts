@@ -44,7 +44,7 @@ export declare class ServiceAccount extends pulumi.CustomResource {
      * @param args The arguments to use to populate this resource's properties.
      * @param opts A bag of options that control this resource's behavior.
      */
-    constructor(name: string, args: ServiceAccountArgs, opts?: pulumi.CustomResourceOptions);
+    constructor(name: string, args?: ServiceAccountArgs, opts?: pulumi.CustomResourceOptions);
 }
 /**
  * Input properties used for looking up and filtering ServiceAccount resources.
@@ -86,6 +86,6 @@ export interface ServiceAccountArgs {
     /**
      * ID of the organization.
      */
-    organizationId: pulumi.Input<string>;
+    organizationId?: pulumi.Input<string | undefined>;
 }
 //# sourceMappingURL=serviceAccount.d.ts.map
\ No newline at end of file
@@ -76,9 +76,6 @@ class ServiceAccount extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.organizationId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'organizationId'");
-            }
             resourceInputs["description"] = args?.description;
             resourceInputs["name"] = args?.name;
             resourceInputs["organizationId"] = args?.organizationId;
@@ -112,7 +112,7 @@ export interface ServiceAccountKeyArgs {
     /**
      * ID of the organization.
      */
-    organizationId: pulumi.Input<string>;
+    organizationId?: pulumi.Input<string | undefined>;
     /**
      * ID of the service account.
      */
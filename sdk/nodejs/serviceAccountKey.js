@@ -79,9 +79,6 @@ class ServiceAccountKey extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.organizationId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'organizationId'");
-            }
             if (args?.serviceAccountId === undefined && !opts.urn) {
                 throw new Error("Missing required property 'serviceAccountId'");
             }
@@ -95,6 +92,8 @@ class ServiceAccountKey extends pulumi.CustomResource {
             resourceInputs["token"] = undefined /*out*/;
         }
         opts = pulumi.mergeOptions(utilities.resourceOptsDefaults(), opts);
+        const secretOpts = { additionalSecretOutputs: ["token"] };
+        opts = pulumi.mergeOptions(opts, secretOpts);
         super(ServiceAccountKey.__pulumiType, name, resourceInputs, opts);
     }
 }
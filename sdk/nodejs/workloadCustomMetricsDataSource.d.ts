@@ -86,7 +86,7 @@ export interface WorkloadCustomMetricsDataSourceArgs {
     /**
      * CAST AI cluster ID.
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * Name of the custom metrics data source (1-63 characters).
      */
@@ -77,9 +77,6 @@ class WorkloadCustomMetricsDataSource extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             if (args?.prometheus === undefined && !opts.urn) {
                 throw new Error("Missing required property 'prometheus'");
             }
@@ -173,7 +173,7 @@ export interface WorkloadScalingPolicyArgs {
     /**
      * CAST AI cluster id
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * Defines the confidence settings for applying recommendations.
      */
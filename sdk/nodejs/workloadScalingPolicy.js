@@ -92,9 +92,6 @@ class WorkloadScalingPolicy extends pulumi.CustomResource {
             if (args?.applyType === undefined && !opts.urn) {
                 throw new Error("Missing required property 'applyType'");
             }
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             if (args?.cpu === undefined && !opts.urn) {
                 throw new Error("Missing required property 'cpu'");
             }
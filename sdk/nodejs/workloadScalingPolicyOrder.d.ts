@@ -52,7 +52,7 @@ export interface WorkloadScalingPolicyOrderArgs {
     /**
      * CAST AI cluster id
      */
-    clusterId: pulumi.Input<string>;
+    clusterId?: pulumi.Input<string | undefined>;
     /**
      * List of scaling policy IDs in the order they should be applied.
      */
@@ -73,9 +73,6 @@ class WorkloadScalingPolicyOrder extends pulumi.CustomResource {
         }
         else {
             const args = argsOrState;
-            if (args?.clusterId === undefined && !opts.urn) {
-                throw new Error("Missing required property 'clusterId'");
-            }
             if (args?.policyIds === undefined && !opts.urn) {
                 throw new Error("Missing required property 'policyIds'");
             }
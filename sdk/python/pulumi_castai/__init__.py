@@ -15,6 +15,7 @@ from .autoscaler import *
 from .cache_configuration import *
 from .cache_group import *
 from .cache_rule import *
+from .cluster_readiness import *
 from .commitments import *
 from .eks_cluster import *
 from .eks_cluster_id import *
@@ -155,6 +156,7 @@ _utilities.register(
   "fqn": "pulumi_castai",
   "classes": {
    "castai:index:AllocationGroup": "AllocationGroup",
+   "castai:index:ClusterReadiness": "ClusterReadiness",
    "castai:index:Commitments": "Commitments",
    "castai:index:PodMutation": "PodMutation",
    "castai:index:Reservations": "Reservations",
@@ -21,10 +21,10 @@ __all__ = ['AiOptimizerHostedModelArgs', 'AiOptimizerHostedModel']
 @pulumi.input_type
 class AiOptimizerHostedModelArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
                  model_specs_id: pulumi.Input[_builtins.str],
                  port: pulumi.Input[_builtins.int],
                  service: pulumi.Input[_builtins.str],
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
                  edge_location_ids: pulumi.Input[Optional[Sequence[pulumi.Input[_builtins.str]]]] = None,
                  fallback: pulumi.Input[Optional['AiOptimizerHostedModelFallbackArgs']] = None,
                  hibernation: pulumi.Input[Optional['AiOptimizerHostedModelHibernationArgs']] = None,
@@ -34,10 +34,10 @@ class AiOptimizerHostedModelArgs:
         """
         The set of arguments for constructing a AiOptimizerHostedModel resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID where the model will be deployed.
         :param pulumi.Input[_builtins.str] model_specs_id: ID of the model specs. Can reference a AiOptimizerModelSpecs resource or a pre-existing model specs ID for predefined (CastAI-managed) models.
         :param pulumi.Input[_builtins.int] port: Port on which the model will be exposed.
         :param pulumi.Input[_builtins.str] service: Kubernetes service name for the deployed model.
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID where the model will be deployed.
         :param pulumi.Input[Sequence[pulumi.Input[_builtins.str]]] edge_location_ids: List of edge location IDs where the model can be deployed.
         :param pulumi.Input['AiOptimizerHostedModelFallbackArgs'] fallback: Fallback model settings.
         :param pulumi.Input['AiOptimizerHostedModelHibernationArgs'] hibernation: Automatic hibernation settings.
@@ -45,10 +45,11 @@ class AiOptimizerHostedModelArgs:
         :param pulumi.Input[_builtins.str] node_template_name: Node template name for model deployment.
         :param pulumi.Input['AiOptimizerHostedModelVllmConfigArgs'] vllm_config: vLLM configuration for HuggingFace models.
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "model_specs_id", model_specs_id)
         pulumi.set(__self__, "port", port)
         pulumi.set(__self__, "service", service)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
         if edge_location_ids is not None:
             pulumi.set(__self__, "edge_location_ids", edge_location_ids)
         if fallback is not None:
@@ -62,18 +63,6 @@ class AiOptimizerHostedModelArgs:
         if vllm_config is not None:
             pulumi.set(__self__, "vllm_config", vllm_config)
 
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster ID where the model will be deployed.
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
-
     @_builtins.property
     @pulumi.getter(name="modelSpecsId")
     def model_specs_id(self) -> pulumi.Input[_builtins.str]:
@@ -110,6 +99,18 @@ class AiOptimizerHostedModelArgs:
     def service(self, value: pulumi.Input[_builtins.str]):
         pulumi.set(self, "service", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster ID where the model will be deployed.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
     @_builtins.property
     @pulumi.getter(name="edgeLocationIds")
     def edge_location_ids(self) -> pulumi.Input[Optional[Sequence[pulumi.Input[_builtins.str]]]]:
@@ -524,8 +525,6 @@ class AiOptimizerHostedModel(pulumi.CustomResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = AiOptimizerHostedModelArgs.__new__(AiOptimizerHostedModelArgs)
 
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             __props__.__dict__["edge_location_ids"] = edge_location_ids
             __props__.__dict__["fallback"] = fallback
@@ -0,0 +1,350 @@
+# coding=utf-8
+# *** WARNING: this file was generated by pulumi-language-python. ***
+# *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import builtins as _builtins
+import warnings
+import sys
+import pulumi
+import pulumi.runtime
+from typing import Any, Mapping, Optional, Sequence, Union, overload
+if sys.version_info >= (3, 11):
+    from typing import NotRequired, TypedDict, TypeAlias
+else:
+    from typing_extensions import NotRequired, TypedDict, TypeAlias
+from . import _utilities
+
+__all__ = ['ClusterReadinessArgs', 'ClusterReadiness']
+
+@pulumi.input_type
+class ClusterReadinessArgs:
+    def __init__(__self__, *,
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
+                 poll_interval: pulumi.Input[Optional[_builtins.str]] = None,
+                 timeout: pulumi.Input[Optional[_builtins.str]] = None,
+                 wait_for: pulumi.Input[Optional[_builtins.str]] = None):
+        """
+        The set of arguments for constructing a ClusterReadiness resource.
+
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID to wait for.
+        :param pulumi.Input[_builtins.str] poll_interval: Wait between two status checks, e.g. "30s".
+        :param pulumi.Input[_builtins.str] timeout: Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+        :param pulumi.Input[_builtins.str] wait_for: Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+        """
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
+        if poll_interval is not None:
+            pulumi.set(__self__, "poll_interval", poll_interval)
+        if timeout is not None:
+            pulumi.set(__self__, "timeout", timeout)
+        if wait_for is not None:
+            pulumi.set(__self__, "wait_for", wait_for)
+
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster ID to wait for.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
+    @_builtins.property
+    @pulumi.getter(name="pollInterval")
+    def poll_interval(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Wait between two status checks, e.g. "30s".
+        """
+        return pulumi.get(self, "poll_interval")
+
+    @poll_interval.setter
+    def poll_interval(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "poll_interval", value)
+
+    @_builtins.property
+    @pulumi.getter
+    def timeout(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+        """
+        return pulumi.get(self, "timeout")
+
+    @timeout.setter
+    def timeout(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "timeout", value)
+
+    @_builtins.property
+    @pulumi.getter(name="waitFor")
+    def wait_for(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+        """
+        return pulumi.get(self, "wait_for")
+
+    @wait_for.setter
+    def wait_for(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "wait_for", value)
+
+
+@pulumi.input_type
+class _ClusterReadinessState:
+    def __init__(__self__, *,
+                 agent_status: pulumi.Input[Optional[_builtins.str]] = None,
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
+                 poll_interval: pulumi.Input[Optional[_builtins.str]] = None,
+                 status: pulumi.Input[Optional[_builtins.str]] = None,
+                 timeout: pulumi.Input[Optional[_builtins.str]] = None,
+                 wait_for: pulumi.Input[Optional[_builtins.str]] = None):
+        """
+        Input properties used for looking up and filtering ClusterReadiness resources.
+
+        :param pulumi.Input[_builtins.str] agent_status: Agent status, e.g. "waiting-connection" or "online".
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID to wait for.
+        :param pulumi.Input[_builtins.str] poll_interval: Wait between two status checks, e.g. "30s".
+        :param pulumi.Input[_builtins.str] status: Cluster status, e.g. "connecting" or "ready".
+        :param pulumi.Input[_builtins.str] timeout: Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+        :param pulumi.Input[_builtins.str] wait_for: Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+        """
+        if agent_status is not None:
+            pulumi.set(__self__, "agent_status", agent_status)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
+        if poll_interval is not None:
+            pulumi.set(__self__, "poll_interval", poll_interval)
+        if status is not None:
+            pulumi.set(__self__, "status", status)
+        if timeout is not None:
+            pulumi.set(__self__, "timeout", timeout)
+        if wait_for is not None:
+            pulumi.set(__self__, "wait_for", wait_for)
+
+    @_builtins.property
+    @pulumi.getter(name="agentStatus")
+    def agent_status(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Agent status, e.g. "waiting-connection" or "online".
+        """
+        return pulumi.get(self, "agent_status")
+
+    @agent_status.setter
+    def agent_status(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "agent_status", value)
+
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster ID to wait for.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
+    @_builtins.property
+    @pulumi.getter(name="pollInterval")
+    def poll_interval(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Wait between two status checks, e.g. "30s".
+        """
+        return pulumi.get(self, "poll_interval")
+
+    @poll_interval.setter
+    def poll_interval(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "poll_interval", value)
+
+    @_builtins.property
+    @pulumi.getter
+    def status(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Cluster status, e.g. "connecting" or "ready".
+        """
+        return pulumi.get(self, "status")
+
+    @status.setter
+    def status(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "status", value)
+
+    @_builtins.property
+    @pulumi.getter
+    def timeout(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+        """
+        return pulumi.get(self, "timeout")
+
+    @timeout.setter
+    def timeout(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "timeout", value)
+
+    @_builtins.property
+    @pulumi.getter(name="waitFor")
+    def wait_for(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+        """
+        return pulumi.get(self, "wait_for")
+
+    @wait_for.setter
+    def wait_for(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "wait_for", value)
+
+
+@pulumi.type_token("castai:index:ClusterReadiness")
+class ClusterReadiness(pulumi.CustomResource):
+    @overload
+    def __init__(__self__,
+                 resource_name: str,
+                 opts: Optional[pulumi.ResourceOptions] = None,
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
+                 poll_interval: pulumi.Input[Optional[_builtins.str]] = None,
+                 timeout: pulumi.Input[Optional[_builtins.str]] = None,
+                 wait_for: pulumi.Input[Optional[_builtins.str]] = None,
+                 __props__=None):
+        """
+        Create a ClusterReadiness resource with the given unique name, props, and options.
+
+        :param str resource_name: The name of the resource.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID to wait for.
+        :param pulumi.Input[_builtins.str] poll_interval: Wait between two status checks, e.g. "30s".
+        :param pulumi.Input[_builtins.str] timeout: Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+        :param pulumi.Input[_builtins.str] wait_for: Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+        """
+        ...
+    @overload
+    def __init__(__self__,
+                 resource_name: str,
+                 args: Optional[ClusterReadinessArgs] = None,
+                 opts: Optional[pulumi.ResourceOptions] = None):
+        """
+        Create a ClusterReadiness resource with the given unique name, props, and options.
+
+        :param str resource_name: The name of the resource.
+        :param ClusterReadinessArgs args: The arguments to use to populate this resource's properties.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        """
+        ...
+    def __init__(__self__, resource_name: str, *args, **kwargs):
+        resource_args, opts = _utilities.get_resource_args_opts(ClusterReadinessArgs, pulumi.ResourceOptions, *args, **kwargs)
+        if resource_args is not None:
+            __self__._internal_init(resource_name, opts, **resource_args.__dict__)
+        else:
+            __self__._internal_init(resource_name, *args, **kwargs)
+
+    def _internal_init(__self__,
+                 resource_name: str,
+                 opts: Optional[pulumi.ResourceOptions] = None,
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
+                 poll_interval: pulumi.Input[Optional[_builtins.str]] = None,
+                 timeout: pulumi.Input[Optional[_builtins.str]] = None,
+                 wait_for: pulumi.Input[Optional[_builtins.str]] = None,
+                 __props__=None):
+        opts = pulumi.ResourceOptions.merge(_utilities.get_resource_opts_defaults(), opts)
+        if not isinstance(opts, pulumi.ResourceOptions):
+            raise TypeError('Expected resource options to be a ResourceOptions instance')
+        if opts.id is None:
+            if __props__ is not None:
+                raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
+            __props__ = ClusterReadinessArgs.__new__(ClusterReadinessArgs)
+
+            __props__.__dict__["cluster_id"] = cluster_id
+            __props__.__dict__["poll_interval"] = poll_interval
+            __props__.__dict__["timeout"] = timeout
+            __props__.__dict__["wait_for"] = wait_for
+            __props__.__dict__["agent_status"] = None
+            __props__.__dict__["status"] = None
+        super(ClusterReadiness, __self__).__init__(
+            'castai:index:ClusterReadiness',
+            resource_name,
+            __props__,
+            opts)
+
+    @staticmethod
+    def get(resource_name: str,
+            id: pulumi.Input[str],
+            opts: Optional[pulumi.ResourceOptions] = None,
+            agent_status: pulumi.Input[Optional[_builtins.str]] = None,
+            cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
+            poll_interval: pulumi.Input[Optional[_builtins.str]] = None,
+            status: pulumi.Input[Optional[_builtins.str]] = None,
+            timeout: pulumi.Input[Optional[_builtins.str]] = None,
+            wait_for: pulumi.Input[Optional[_builtins.str]] = None) -> 'ClusterReadiness':
+        """
+        Get an existing ClusterReadiness resource's state with the given name, id, and optional extra
+        properties used to qualify the lookup.
+
+        :param str resource_name: The unique name of the resulting resource.
+        :param pulumi.Input[str] id: The unique provider ID of the resource to lookup.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        :param pulumi.Input[_builtins.str] agent_status: Agent status, e.g. "waiting-connection" or "online".
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID to wait for.
+        :param pulumi.Input[_builtins.str] poll_interval: Wait between two status checks, e.g. "30s".
+        :param pulumi.Input[_builtins.str] status: Cluster status, e.g. "connecting" or "ready".
+        :param pulumi.Input[_builtins.str] timeout: Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+        :param pulumi.Input[_builtins.str] wait_for: Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+        """
+        opts = pulumi.ResourceOptions.merge(opts, pulumi.ResourceOptions(id=id))
+
+        __props__ = _ClusterReadinessState.__new__(_ClusterReadinessState)
+
+        __props__.__dict__["agent_status"] = agent_status
+        __props__.__dict__["cluster_id"] = cluster_id
+        __props__.__dict__["poll_interval"] = poll_interval
+        __props__.__dict__["status"] = status
+        __props__.__dict__["timeout"] = timeout
+        __props__.__dict__["wait_for"] = wait_for
+        return ClusterReadiness(resource_name, opts=opts, __props__=__props__)
+
+    @_builtins.property
+    @pulumi.getter(name="agentStatus")
+    def agent_status(self) -> pulumi.Output[_builtins.str]:
+        """
+        Agent status, e.g. "waiting-connection" or "online".
+        """
+        return pulumi.get(self, "agent_status")
+
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Output[_builtins.str]:
+        """
+        CAST AI cluster ID to wait for.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @_builtins.property
+    @pulumi.getter(name="pollInterval")
+    def poll_interval(self) -> pulumi.Output[Optional[_builtins.str]]:
+        """
+        Wait between two status checks, e.g. "30s".
+        """
+        return pulumi.get(self, "poll_interval")
+
+    @_builtins.property
+    @pulumi.getter
+    def status(self) -> pulumi.Output[_builtins.str]:
+        """
+        Cluster status, e.g. "connecting" or "ready".
+        """
+        return pulumi.get(self, "status")
+
+    @_builtins.property
+    @pulumi.getter
+    def timeout(self) -> pulumi.Output[Optional[_builtins.str]]:
+        """
+        Maximum time to wait, e.g. "10m". "0" waits until the update is cancelled.
+        """
+        return pulumi.get(self, "timeout")
+
+    @_builtins.property
+    @pulumi.getter(name="waitFor")
+    def wait_for(self) -> pulumi.Output[Optional[_builtins.str]]:
+        """
+        Condition to wait for: "connected" once the agent reports online, "ready" once CAST AI has also finished onboarding the cluster.
+        """
+        return pulumi.get(self, "wait_for")
+
@@ -24,8 +24,33 @@ apiUrl: str
 CAST.AI API url.
 """
 
+defaultClusterId: Optional[str]
+"""
+Default CAST AI cluster ID for resources that do not set one.
+"""
+
+maxConcurrentRequests: int
+"""
+Maximum number of CAST AI API requests in flight. `0` means no limit.
+"""
+
+maxRetries: int
+"""
+Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+"""
+
 organizationId: Optional[str]
 """
 CAST AI organization ID. Required when the API token has access to multiple organizations.
 """
 
+requestTimeout: str
+"""
+Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+"""
+
+retryBackoff: str
+"""
+Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+"""
+
@@ -21,9 +21,9 @@ __all__ = ['NodeConfigurationArgs', 'NodeConfiguration']
 @pulumi.input_type
 class NodeConfigurationArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
                  subnets: pulumi.Input[Sequence[pulumi.Input[_builtins.str]]],
                  aks: pulumi.Input[Optional['NodeConfigurationAksArgs']] = None,
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
                  container_runtime: pulumi.Input[Optional[_builtins.str]] = None,
                  disk_cpu_ratio: pulumi.Input[Optional[_builtins.int]] = None,
                  docker_config: pulumi.Input[Optional[_builtins.str]] = None,
@@ -41,8 +41,8 @@ class NodeConfigurationArgs:
         """
         The set of arguments for constructing a NodeConfiguration resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         :param pulumi.Input[Sequence[pulumi.Input[_builtins.str]]] subnets: Subnet ids to be used for provisioned nodes
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         :param pulumi.Input[_builtins.str] container_runtime: Optional container runtime to be used by kubelet. Applicable for EKS only.  Supported values include: `dockerd`, `containerd`
         :param pulumi.Input[_builtins.int] disk_cpu_ratio: Disk to CPU ratio. Sets the number of GiBs to be added for every CPU on the node. Defaults to 0
         :param pulumi.Input[_builtins.str] docker_config: Optional docker daemon configuration properties in JSON format. Provide only properties that you want to override. Applicable for EKS only. [Available values](https://docs.docker.com/engine/reference/commandline/dockerd/#daemon-configuration-file)
@@ -55,10 +55,11 @@ class NodeConfigurationArgs:
         :param pulumi.Input[_builtins.str] ssh_public_key: SSH public key to be used for provisioned nodes
         :param pulumi.Input[Mapping[str, pulumi.Input[_builtins.str]]] tags: Tags to be added on cloud instances for provisioned nodes
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "subnets", subnets)
         if aks is not None:
             pulumi.set(__self__, "aks", aks)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
         if container_runtime is not None:
             pulumi.set(__self__, "container_runtime", container_runtime)
         if disk_cpu_ratio is not None:
@@ -88,18 +89,6 @@ class NodeConfigurationArgs:
         if tags is not None:
             pulumi.set(__self__, "tags", tags)
 
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster id
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
-
     @_builtins.property
     @pulumi.getter
     def subnets(self) -> pulumi.Input[Sequence[pulumi.Input[_builtins.str]]]:
@@ -121,6 +110,18 @@ class NodeConfigurationArgs:
     def aks(self, value: pulumi.Input[Optional['NodeConfigurationAksArgs']]):
         pulumi.set(self, "aks", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster id
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
     @_builtins.property
     @pulumi.getter(name="containerRuntime")
     def container_runtime(self) -> pulumi.Input[Optional[_builtins.str]]:
@@ -640,8 +641,6 @@ class NodeConfiguration(pulumi.CustomResource):
             __props__ = NodeConfigurationArgs.__new__(NodeConfigurationArgs)
 
             __props__.__dict__["aks"] = aks
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             __props__.__dict__["container_runtime"] = container_runtime
             __props__.__dict__["disk_cpu_ratio"] = disk_cpu_ratio
@@ -19,28 +19,17 @@ __all__ = ['NodeConfigurationDefaultArgs', 'NodeConfigurationDefault']
 @pulumi.input_type
 class NodeConfigurationDefaultArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
-                 configuration_id: pulumi.Input[_builtins.str]):
+                 configuration_id: pulumi.Input[_builtins.str],
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a NodeConfigurationDefault resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         :param pulumi.Input[_builtins.str] configuration_id: Id of the node configuration
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "configuration_id", configuration_id)
-
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster id
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
 
     @_builtins.property
     @pulumi.getter(name="configurationId")
@@ -54,6 +43,18 @@ class NodeConfigurationDefaultArgs:
     def configuration_id(self, value: pulumi.Input[_builtins.str]):
         pulumi.set(self, "configuration_id", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster id
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
 
 @pulumi.input_type
 class _NodeConfigurationDefaultState:
@@ -148,8 +149,6 @@ class NodeConfigurationDefault(pulumi.CustomResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = NodeConfigurationDefaultArgs.__new__(NodeConfigurationDefaultArgs)
 
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             if configuration_id is None and not opts.urn:
                 raise TypeError("Missing required property 'configuration_id'")
@@ -34,10 +34,45 @@ class _ExportableConfig(types.ModuleType):
         """
         return __config__.get('apiUrl') or (_utilities.get_env('CASTAI_API_URL') or 'https://api.cast.ai')
 
+    @_builtins.property
+    def default_cluster_id(self) -> Optional[str]:
+        """
+        Default CAST AI cluster ID for resources that do not set one.
+        """
+        return __config__.get('defaultClusterId') or _utilities.get_env('CASTAI_DEFAULT_CLUSTER_ID')
+
+    @_builtins.property
+    def max_concurrent_requests(self) -> int:
+        """
+        Maximum number of CAST AI API requests in flight. `0` means no limit.
+        """
+        return __config__.get_int('maxConcurrentRequests') or (_utilities.get_env_int('CASTAI_MAX_CONCURRENT_REQUESTS') or 0)
+
+    @_builtins.property
+    def max_retries(self) -> int:
+        """
+        Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+        """
+        return __config__.get_int('maxRetries') or (_utilities.get_env_int('CASTAI_MAX_RETRIES') or 3)
+
     @_builtins.property
     def organization_id(self) -> Optional[str]:
         """
         CAST AI organization ID. Required when the API token has access to multiple organizations.
         """
-        return __config__.get('organizationId')
+        return __config__.get('organizationId') or _utilities.get_env('CASTAI_ORGANIZATION_ID')
+
+    @_builtins.property
+    def request_timeout(self) -> str:
+        """
+        Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+        """
+        return __config__.get('requestTimeout') or (_utilities.get_env('CASTAI_REQUEST_TIMEOUT') or '1m')
+
+    @_builtins.property
+    def retry_backoff(self) -> str:
+        """
+        Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+        """
+        return __config__.get('retryBackoff') or (_utilities.get_env('CASTAI_RETRY_BACKOFF') or '1s')
 
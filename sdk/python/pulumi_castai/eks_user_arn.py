@@ -19,19 +19,20 @@ __all__ = ['EksUserArnArgs', 'EksUserArn']
 @pulumi.input_type
 class EksUserArnArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str]):
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a EksUserArn resource.
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
 
     @_builtins.property
     @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
         return pulumi.get(self, "cluster_id")
 
     @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "cluster_id", value)
 
 
@@ -85,7 +86,7 @@ class EksUserArn(pulumi.CustomResource):
     @overload
     def __init__(__self__,
                  resource_name: str,
-                 args: EksUserArnArgs,
+                 args: Optional[EksUserArnArgs] = None,
                  opts: Optional[pulumi.ResourceOptions] = None):
         """
         Create a EksUserArn resource with the given unique name, props, and options.
@@ -115,8 +116,6 @@ class EksUserArn(pulumi.CustomResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = EksUserArnArgs.__new__(EksUserArnArgs)
 
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             __props__.__dict__["arn"] = None
         super(EksUserArn, __self__).__init__(
@@ -21,29 +21,30 @@ __all__ = ['EnterpriseGroupArgs', 'EnterpriseGroup']
 class EnterpriseGroupArgs:
     def __init__(__self__, *,
                  enterprise_id: pulumi.Input[_builtins.str],
-                 organization_id: pulumi.Input[_builtins.str],
                  description: pulumi.Input[Optional[_builtins.str]] = None,
                  members: pulumi.Input[Optional[Sequence[pulumi.Input['_organization.EnterpriseGroupMemberArgs']]]] = None,
                  name: pulumi.Input[Optional[_builtins.str]] = None,
+                 organization_id: pulumi.Input[Optional[_builtins.str]] = None,
                  role_bindings: pulumi.Input[Optional[Sequence[pulumi.Input['_organization.EnterpriseGroupRoleBindingArgs']]]] = None):
         """
         The set of arguments for constructing a EnterpriseGroup resource.
 
         :param pulumi.Input[_builtins.str] enterprise_id: Enterprise organization ID.
-        :param pulumi.Input[_builtins.str] organization_id: Target organization ID for the group.
         :param pulumi.Input[_builtins.str] description: Description of the group.
         :param pulumi.Input[Sequence[pulumi.Input['_organization.EnterpriseGroupMemberArgs']]] members: List of group members.
         :param pulumi.Input[_builtins.str] name: Name of the group.
+        :param pulumi.Input[_builtins.str] organization_id: Target organization ID for the group.
         :param pulumi.Input[Sequence[pulumi.Input['_organization.EnterpriseGroupRoleBindingArgs']]] role_bindings: List of role bindings for the group.
         """
         pulumi.set(__self__, "enterprise_id", enterprise_id)
-        pulumi.set(__self__, "organization_id", organization_id)
         if description is not None:
             pulumi.set(__self__, "description", description)
         if members is not None:
             pulumi.set(__self__, "members", members)
         if name is not None:
             pulumi.set(__self__, "name", name)
+        if organization_id is not None:
+            pulumi.set(__self__, "organization_id", organization_id)
         if role_bindings is not None:
             pulumi.set(__self__, "role_bindings", role_bindings)
 
@@ -59,18 +60,6 @@ class EnterpriseGroupArgs:
     def enterprise_id(self, value: pulumi.Input[_builtins.str]):
         pulumi.set(self, "enterprise_id", value)
 
-    @_builtins.property
-    @pulumi.getter(name="organizationId")
-    def organization_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        Target organization ID for the group.
-        """
-        return pulumi.get(self, "organization_id")
-
-    @organization_id.setter
-    def organization_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "organization_id", value)
-
     @_builtins.property
     @pulumi.getter
     def description(self) -> pulumi.Input[Optional[_builtins.str]]:
@@ -107,6 +96,18 @@ class EnterpriseGroupArgs:
     def name(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "name", value)
 
+    @_builtins.property
+    @pulumi.getter(name="organizationId")
+    def organization_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Target organization ID for the group.
+        """
+        return pulumi.get(self, "organization_id")
+
+    @organization_id.setter
+    def organization_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "organization_id", value)
+
     @_builtins.property
     @pulumi.getter(name="roleBindings")
     def role_bindings(self) -> pulumi.Input[Optional[Sequence[pulumi.Input['_organization.EnterpriseGroupRoleBindingArgs']]]]:
@@ -295,8 +296,6 @@ class EnterpriseGroup(pulumi.CustomResource):
             __props__.__dict__["enterprise_id"] = enterprise_id
             __props__.__dict__["members"] = members
             __props__.__dict__["name"] = name
-            if organization_id is None and not opts.urn:
-                raise TypeError("Missing required property 'organization_id'")
             __props__.__dict__["organization_id"] = organization_id
             __props__.__dict__["role_bindings"] = role_bindings
         super(EnterpriseGroup, __self__).__init__(
@@ -21,25 +21,24 @@ __all__ = ['EnterpriseRoleBindingArgs', 'EnterpriseRoleBinding']
 class EnterpriseRoleBindingArgs:
     def __init__(__self__, *,
                  enterprise_id: pulumi.Input[_builtins.str],
-                 organization_id: pulumi.Input[_builtins.str],
                  role_id: pulumi.Input[_builtins.str],
                  scopes: pulumi.Input['_iam.EnterpriseRoleBindingScopesArgs'],
                  subjects: pulumi.Input['_iam.EnterpriseRoleBindingSubjectsArgs'],
                  description: pulumi.Input[Optional[_builtins.str]] = None,
-                 name: pulumi.Input[Optional[_builtins.str]] = None):
+                 name: pulumi.Input[Optional[_builtins.str]] = None,
+                 organization_id: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a EnterpriseRoleBinding resource.
 
         :param pulumi.Input[_builtins.str] enterprise_id: Enterprise organization ID.
-        :param pulumi.Input[_builtins.str] organization_id: Organization ID (either enterprise or it's child) where the role binding is created.
         :param pulumi.Input[_builtins.str] role_id: Role UUID to bind.
         :param pulumi.Input['_iam.EnterpriseRoleBindingScopesArgs'] scopes: Scopes (organization or cluster) for this role binding.
         :param pulumi.Input['_iam.EnterpriseRoleBindingSubjectsArgs'] subjects: Subjects (users, service accounts, groups) for this role binding.
         :param pulumi.Input[_builtins.str] description: Description of the role binding.
         :param pulumi.Input[_builtins.str] name: Name of the role binding.
+        :param pulumi.Input[_builtins.str] organization_id: Organization ID (either enterprise or it's child) where the role binding is created.
         """
         pulumi.set(__self__, "enterprise_id", enterprise_id)
-        pulumi.set(__self__, "organization_id", organization_id)
         pulumi.set(__self__, "role_id", role_id)
         pulumi.set(__self__, "scopes", scopes)
         pulumi.set(__self__, "subjects", subjects)
@@ -47,6 +46,8 @@ class EnterpriseRoleBindingArgs:
             pulumi.set(__self__, "description", description)
         if name is not None:
             pulumi.set(__self__, "name", name)
+        if organization_id is not None:
+            pulumi.set(__self__, "organization_id", organization_id)
 
     @_builtins.property
     @pulumi.getter(name="enterpriseId")
@@ -60,18 +61,6 @@ class EnterpriseRoleBindingArgs:
     def enterprise_id(self, value: pulumi.Input[_builtins.str]):
         pulumi.set(self, "enterprise_id", value)
 
-    @_builtins.property
-    @pulumi.getter(name="organizationId")
-    def organization_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        Organization ID (either enterprise or it's child) where the role binding is created.
-        """
-        return pulumi.get(self, "organization_id")
-
-    @organization_id.setter
-    def organization_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "organization_id", value)
-
     @_builtins.property
     @pulumi.getter(name="roleId")
     def role_id(self) -> pulumi.Input[_builtins.str]:
@@ -132,6 +121,18 @@ class EnterpriseRoleBindingArgs:
     def name(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "name", value)
 
+    @_builtins.property
+    @pulumi.getter(name="organizationId")
+    def organization_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Organization ID (either enterprise or it's child) where the role binding is created.
+        """
+        return pulumi.get(self, "organization_id")
+
+    @organization_id.setter
+    def organization_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "organization_id", value)
+
 
 @pulumi.input_type
 class _EnterpriseRoleBindingState:
@@ -326,8 +327,6 @@ class EnterpriseRoleBinding(pulumi.CustomResource):
                 raise TypeError("Missing required property 'enterprise_id'")
             __props__.__dict__["enterprise_id"] = enterprise_id
             __props__.__dict__["name"] = name
-            if organization_id is None and not opts.urn:
-                raise TypeError("Missing required property 'organization_id'")
             __props__.__dict__["organization_id"] = organization_id
             if role_id is None and not opts.urn:
                 raise TypeError("Missing required property 'role_id'")
@@ -20,28 +20,17 @@ __all__ = ['EvictorAdvancedConfigArgs', 'EvictorAdvancedConfig']
 @pulumi.input_type
 class EvictorAdvancedConfigArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
-                 evictor_advanced_configs: pulumi.Input[Sequence[pulumi.Input['_autoscaling.EvictorAdvancedConfigEvictorAdvancedConfigArgs']]]):
+                 evictor_advanced_configs: pulumi.Input[Sequence[pulumi.Input['_autoscaling.EvictorAdvancedConfigEvictorAdvancedConfigArgs']]],
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a EvictorAdvancedConfig resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id.
         :param pulumi.Input[Sequence[pulumi.Input['_autoscaling.EvictorAdvancedConfigEvictorAdvancedConfigArgs']]] evictor_advanced_configs: evictor advanced configuration to target specific node/pod
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id.
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "evictor_advanced_configs", evictor_advanced_configs)
-
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster id.
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
 
     @_builtins.property
     @pulumi.getter(name="evictorAdvancedConfigs")
@@ -55,6 +44,18 @@ class EvictorAdvancedConfigArgs:
     def evictor_advanced_configs(self, value: pulumi.Input[Sequence[pulumi.Input['_autoscaling.EvictorAdvancedConfigEvictorAdvancedConfigArgs']]]):
         pulumi.set(self, "evictor_advanced_configs", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster id.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
 
 @pulumi.input_type
 class _EvictorAdvancedConfigState:
@@ -149,8 +150,6 @@ class EvictorAdvancedConfig(pulumi.CustomResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = EvictorAdvancedConfigArgs.__new__(EvictorAdvancedConfigArgs)
 
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             if evictor_advanced_configs is None and not opts.urn:
                 raise TypeError("Missing required property 'evictor_advanced_configs'")
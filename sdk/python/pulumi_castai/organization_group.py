@@ -20,36 +20,25 @@ __all__ = ['OrganizationGroupArgs', 'OrganizationGroup']
 @pulumi.input_type
 class OrganizationGroupArgs:
     def __init__(__self__, *,
-                 organization_id: pulumi.Input[_builtins.str],
                  description: pulumi.Input[Optional[_builtins.str]] = None,
                  members: pulumi.Input[Optional[Sequence[pulumi.Input['_organization.OrganizationGroupMemberArgs']]]] = None,
-                 name: pulumi.Input[Optional[_builtins.str]] = None):
+                 name: pulumi.Input[Optional[_builtins.str]] = None,
+                 organization_id: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a OrganizationGroup resource.
 
-        :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID.
         :param pulumi.Input[_builtins.str] description: Description of the group.
         :param pulumi.Input[_builtins.str] name: Name of the group.
+        :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID.
         """
-        pulumi.set(__self__, "organization_id", organization_id)
         if description is not None:
             pulumi.set(__self__, "description", description)
         if members is not None:
             pulumi.set(__self__, "members", members)
         if name is not None:
             pulumi.set(__self__, "name", name)
-
-    @_builtins.property
-    @pulumi.getter(name="organizationId")
-    def organization_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI organization ID.
-        """
-        return pulumi.get(self, "organization_id")
-
-    @organization_id.setter
-    def organization_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "organization_id", value)
+        if organization_id is not None:
+            pulumi.set(__self__, "organization_id", organization_id)
 
     @_builtins.property
     @pulumi.getter
@@ -84,6 +73,18 @@ class OrganizationGroupArgs:
     def name(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "name", value)
 
+    @_builtins.property
+    @pulumi.getter(name="organizationId")
+    def organization_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI organization ID.
+        """
+        return pulumi.get(self, "organization_id")
+
+    @organization_id.setter
+    def organization_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "organization_id", value)
+
 
 @pulumi.input_type
 class _OrganizationGroupState:
@@ -178,7 +179,7 @@ class OrganizationGroup(pulumi.CustomResource):
     @overload
     def __init__(__self__,
                  resource_name: str,
-                 args: OrganizationGroupArgs,
+                 args: Optional[OrganizationGroupArgs] = None,
                  opts: Optional[pulumi.ResourceOptions] = None):
         """
         Create a OrganizationGroup resource with the given unique name, props, and options.
@@ -214,8 +215,6 @@ class OrganizationGroup(pulumi.CustomResource):
             __props__.__dict__["description"] = description
             __props__.__dict__["members"] = members
             __props__.__dict__["name"] = name
-            if organization_id is None and not opts.urn:
-                raise TypeError("Missing required property 'organization_id'")
             __props__.__dict__["organization_id"] = organization_id
         super(OrganizationGroup, __self__).__init__(
             'castai:organization:OrganizationGroup',
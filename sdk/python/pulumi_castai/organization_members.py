@@ -19,24 +19,25 @@ __all__ = ['OrganizationMembersArgs', 'OrganizationMembers']
 @pulumi.input_type
 class OrganizationMembersArgs:
     def __init__(__self__, *,
-                 organization_id: pulumi.Input[_builtins.str],
                  members: pulumi.Input[Optional[Sequence[pulumi.Input[_builtins.str]]]] = None,
+                 organization_id: pulumi.Input[Optional[_builtins.str]] = None,
                  owners: pulumi.Input[Optional[Sequence[pulumi.Input[_builtins.str]]]] = None,
                  viewers: pulumi.Input[Optional[Sequence[pulumi.Input[_builtins.str]]]] = None):
         """
         The set of arguments for constructing a OrganizationMembers resource.
 
-        :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID.
         :param pulumi.Input[Sequence[pulumi.Input[_builtins.str]]] members: A list of email addresses corresponding to users who should be given member access to the organization.
+        :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID.
         :param pulumi.Input[Sequence[pulumi.Input[_builtins.str]]] owners: A list of email addresses corresponding to users who should be given owner access to the organization.
         :param pulumi.Input[Sequence[pulumi.Input[_builtins.str]]] viewers: A list of email addresses corresponding to users who should be given viewer access to the organization.
         """
-        pulumi.set(__self__, "organization_id", organization_id)
         if members is not None:
             warnings.warn("""The 'members' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.""", DeprecationWarning)
             pulumi.log.warn("""members is deprecated: The 'members' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.""")
         if members is not None:
             pulumi.set(__self__, "members", members)
+        if organization_id is not None:
+            pulumi.set(__self__, "organization_id", organization_id)
         if owners is not None:
             warnings.warn("""The 'owners' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.""", DeprecationWarning)
             pulumi.log.warn("""owners is deprecated: The 'owners' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.""")
@@ -48,18 +49,6 @@ class OrganizationMembersArgs:
         if viewers is not None:
             pulumi.set(__self__, "viewers", viewers)
 
-    @_builtins.property
-    @pulumi.getter(name="organizationId")
-    def organization_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI organization ID.
-        """
-        return pulumi.get(self, "organization_id")
-
-    @organization_id.setter
-    def organization_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "organization_id", value)
-
     @_builtins.property
     @pulumi.getter
     @_utilities.deprecated("""The 'members' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.""")
@@ -73,6 +62,18 @@ class OrganizationMembersArgs:
     def members(self, value: pulumi.Input[Optional[Sequence[pulumi.Input[_builtins.str]]]]):
         pulumi.set(self, "members", value)
 
+    @_builtins.property
+    @pulumi.getter(name="organizationId")
+    def organization_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI organization ID.
+        """
+        return pulumi.get(self, "organization_id")
+
+    @organization_id.setter
+    def organization_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "organization_id", value)
+
     @_builtins.property
     @pulumi.getter
     @_utilities.deprecated("""The 'owners' field is deprecated. Use 'castai_role_bindings' resource instead for more granular role management. This field will be removed in a future version.""")
@@ -210,7 +211,7 @@ class OrganizationMembers(pulumi.CustomResource):
     @overload
     def __init__(__self__,
                  resource_name: str,
-                 args: OrganizationMembersArgs,
+                 args: Optional[OrganizationMembersArgs] = None,
                  opts: Optional[pulumi.ResourceOptions] = None):
         """
         Create a OrganizationMembers resource with the given unique name, props, and options.
@@ -244,8 +245,6 @@ class OrganizationMembers(pulumi.CustomResource):
             __props__ = OrganizationMembersArgs.__new__(OrganizationMembersArgs)
 
             __props__.__dict__["members"] = members
-            if organization_id is None and not opts.urn:
-                raise TypeError("Missing required property 'organization_id'")
             __props__.__dict__["organization_id"] = organization_id
             __props__.__dict__["owners"] = owners
             __props__.__dict__["viewers"] = viewers
@@ -21,11 +21,11 @@ __all__ = ['PodMutationArgs', 'PodMutation']
 @pulumi.input_type
 class PodMutationArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
                  enabled: pulumi.Input[_builtins.bool],
                  filter_v2: pulumi.Input['PodMutationFilterV2Args'],
                  affinity: pulumi.Input[Optional['PodMutationAffinityArgs']] = None,
                  annotations: pulumi.Input[Optional[Mapping[str, pulumi.Input[_builtins.str]]]] = None,
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
                  distribution_groups: pulumi.Input[Optional[Sequence[pulumi.Input['PodMutationDistributionGroupArgs']]]] = None,
                  labels: pulumi.Input[Optional[Mapping[str, pulumi.Input[_builtins.str]]]] = None,
                  name: pulumi.Input[Optional[_builtins.str]] = None,
@@ -39,11 +39,11 @@ class PodMutationArgs:
         """
         The set of arguments for constructing a PodMutation resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: ID of the cluster.
         :param pulumi.Input[_builtins.bool] enabled: Whether the pod mutation is enabled.
         :param pulumi.Input['PodMutationFilterV2Args'] filter_v2: Advanced object filter with support for exact and regex matching.
         :param pulumi.Input['PodMutationAffinityArgs'] affinity: Affinity to apply to the pods.
         :param pulumi.Input[Mapping[str, pulumi.Input[_builtins.str]]] annotations: Annotations to add to the pods.
+        :param pulumi.Input[_builtins.str] cluster_id: ID of the cluster.
         :param pulumi.Input[Sequence[pulumi.Input['PodMutationDistributionGroupArgs']]] distribution_groups: Distribution groups for percentage-based pod distribution.
         :param pulumi.Input[Mapping[str, pulumi.Input[_builtins.str]]] labels: Labels to add to the pods.
         :param pulumi.Input[_builtins.str] name: Name of the pod mutation.
@@ -55,13 +55,14 @@ class PodMutationArgs:
         :param pulumi.Input['PodMutationSpotConfigArgs'] spot_config: Spot configuration for the mutation.
         :param pulumi.Input[Sequence[pulumi.Input['PodMutationTolerationArgs']]] tolerations: Tolerations to apply to the pods.
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "enabled", enabled)
         pulumi.set(__self__, "filter_v2", filter_v2)
         if affinity is not None:
             pulumi.set(__self__, "affinity", affinity)
         if annotations is not None:
             pulumi.set(__self__, "annotations", annotations)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
         if distribution_groups is not None:
             pulumi.set(__self__, "distribution_groups", distribution_groups)
         if labels is not None:
@@ -83,18 +84,6 @@ class PodMutationArgs:
         if tolerations is not None:
             pulumi.set(__self__, "tolerations", tolerations)
 
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        ID of the cluster.
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
-
     @_builtins.property
     @pulumi.getter
     def enabled(self) -> pulumi.Input[_builtins.bool]:
@@ -143,6 +132,18 @@ class PodMutationArgs:
     def annotations(self, value: pulumi.Input[Optional[Mapping[str, pulumi.Input[_builtins.str]]]]):
         pulumi.set(self, "annotations", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        ID of the cluster.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
     @_builtins.property
     @pulumi.getter(name="distributionGroups")
     def distribution_groups(self) -> pulumi.Input[Optional[Sequence[pulumi.Input['PodMutationDistributionGroupArgs']]]]:
@@ -622,8 +623,6 @@ class PodMutation(pulumi.CustomResource):
 
             __props__.__dict__["affinity"] = affinity
             __props__.__dict__["annotations"] = annotations
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             __props__.__dict__["distribution_groups"] = distribution_groups
             if enabled is None and not opts.urn:
@@ -21,13 +21,21 @@ class ProviderArgs:
     def __init__(__self__, *,
                  api_token: pulumi.Input[Optional[_builtins.str]] = None,
                  api_url: pulumi.Input[Optional[_builtins.str]] = None,
-                 organization_id: pulumi.Input[Optional[_builtins.str]] = None):
+                 max_concurrent_requests: pulumi.Input[Optional[_builtins.int]] = None,
+                 max_retries: pulumi.Input[Optional[_builtins.int]] = None,
+                 organization_id: pulumi.Input[Optional[_builtins.str]] = None,
+                 request_timeout: pulumi.Input[Optional[_builtins.str]] = None,
+                 retry_backoff: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a Provider resource.
 
         :param pulumi.Input[_builtins.str] api_token: The token used to connect to CAST AI API.
         :param pulumi.Input[_builtins.str] api_url: CAST.AI API url.
+        :param pulumi.Input[_builtins.int] max_concurrent_requests: Maximum number of CAST AI API requests in flight. `0` means no limit.
+        :param pulumi.Input[_builtins.int] max_retries: Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
         :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID. Required when the API token has access to multiple organizations.
+        :param pulumi.Input[_builtins.str] request_timeout: Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+        :param pulumi.Input[_builtins.str] retry_backoff: Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
         """
         if api_token is None:
             api_token = _utilities.get_env('CASTAI_API_TOKEN')
@@ -37,8 +45,26 @@ class ProviderArgs:
             api_url = (_utilities.get_env('CASTAI_API_URL') or 'https://api.cast.ai')
         if api_url is not None:
             pulumi.set(__self__, "api_url", api_url)
+        if max_concurrent_requests is None:
+            max_concurrent_requests = (_utilities.get_env_int('CASTAI_MAX_CONCURRENT_REQUESTS') or 0)
+        if max_concurrent_requests is not None:
+            pulumi.set(__self__, "max_concurrent_requests", max_concurrent_requests)
+        if max_retries is None:
+            max_retries = (_utilities.get_env_int('CASTAI_MAX_RETRIES') or 3)
+        if max_retries is not None:
+            pulumi.set(__self__, "max_retries", max_retries)
+        if organization_id is None:
+            organization_id = _utilities.get_env('CASTAI_ORGANIZATION_ID')
         if organization_id is not None:
             pulumi.set(__self__, "organization_id", organization_id)
+        if request_timeout is None:
+            request_timeout = (_utilities.get_env('CASTAI_REQUEST_TIMEOUT') or '1m')
+        if request_timeout is not None:
+            pulumi.set(__self__, "request_timeout", request_timeout)
+        if retry_backoff is None:
+            retry_backoff = (_utilities.get_env('CASTAI_RETRY_BACKOFF') or '1s')
+        if retry_backoff is not None:
+            pulumi.set(__self__, "retry_backoff", retry_backoff)
 
     @_builtins.property
     @pulumi.getter(name="apiToken")
@@ -64,6 +90,30 @@ class ProviderArgs:
     def api_url(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "api_url", value)
 
+    @_builtins.property
+    @pulumi.getter(name="maxConcurrentRequests")
+    def max_concurrent_requests(self) -> pulumi.Input[Optional[_builtins.int]]:
+        """
+        Maximum number of CAST AI API requests in flight. `0` means no limit.
+        """
+        return pulumi.get(self, "max_concurrent_requests")
+
+    @max_concurrent_requests.setter
+    def max_concurrent_requests(self, value: pulumi.Input[Optional[_builtins.int]]):
+        pulumi.set(self, "max_concurrent_requests", value)
+
+    @_builtins.property
+    @pulumi.getter(name="maxRetries")
+    def max_retries(self) -> pulumi.Input[Optional[_builtins.int]]:
+        """
+        Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
+        """
+        return pulumi.get(self, "max_retries")
+
+    @max_retries.setter
+    def max_retries(self, value: pulumi.Input[Optional[_builtins.int]]):
+        pulumi.set(self, "max_retries", value)
+
     @_builtins.property
     @pulumi.getter(name="organizationId")
     def organization_id(self) -> pulumi.Input[Optional[_builtins.str]]:
@@ -76,6 +126,30 @@ class ProviderArgs:
     def organization_id(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "organization_id", value)
 
+    @_builtins.property
+    @pulumi.getter(name="requestTimeout")
+    def request_timeout(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+        """
+        return pulumi.get(self, "request_timeout")
+
+    @request_timeout.setter
+    def request_timeout(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "request_timeout", value)
+
+    @_builtins.property
+    @pulumi.getter(name="retryBackoff")
+    def retry_backoff(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+        """
+        return pulumi.get(self, "retry_backoff")
+
+    @retry_backoff.setter
+    def retry_backoff(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "retry_backoff", value)
+
 
 @pulumi.type_token("pulumi:providers:castai")
 class Provider(pulumi.ProviderResource):
@@ -85,7 +159,11 @@ class Provider(pulumi.ProviderResource):
                  opts: Optional[pulumi.ResourceOptions] = None,
                  api_token: pulumi.Input[Optional[_builtins.str]] = None,
                  api_url: pulumi.Input[Optional[_builtins.str]] = None,
+                 max_concurrent_requests: pulumi.Input[Optional[_builtins.int]] = None,
+                 max_retries: pulumi.Input[Optional[_builtins.int]] = None,
                  organization_id: pulumi.Input[Optional[_builtins.str]] = None,
+                 request_timeout: pulumi.Input[Optional[_builtins.str]] = None,
+                 retry_backoff: pulumi.Input[Optional[_builtins.str]] = None,
                  __props__=None):
         """
         The provider type for the castai package. By default, resources use package-wide configuration
@@ -98,7 +176,11 @@ class Provider(pulumi.ProviderResource):
         :param pulumi.ResourceOptions opts: Options for the resource.
         :param pulumi.Input[_builtins.str] api_token: The token used to connect to CAST AI API.
         :param pulumi.Input[_builtins.str] api_url: CAST.AI API url.
+        :param pulumi.Input[_builtins.int] max_concurrent_requests: Maximum number of CAST AI API requests in flight. `0` means no limit.
+        :param pulumi.Input[_builtins.int] max_retries: Number of times a throttled (429) or failed (5xx) CAST AI API request is retried.
         :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID. Required when the API token has access to multiple organizations.
+        :param pulumi.Input[_builtins.str] request_timeout: Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+        :param pulumi.Input[_builtins.str] retry_backoff: Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
         """
         ...
     @overload
@@ -130,7 +212,11 @@ class Provider(pulumi.ProviderResource):
                  opts: Optional[pulumi.ResourceOptions] = None,
                  api_token: pulumi.Input[Optional[_builtins.str]] = None,
                  api_url: pulumi.Input[Optional[_builtins.str]] = None,
+                 max_concurrent_requests: pulumi.Input[Optional[_builtins.int]] = None,
+                 max_retries: pulumi.Input[Optional[_builtins.int]] = None,
                  organization_id: pulumi.Input[Optional[_builtins.str]] = None,
+                 request_timeout: pulumi.Input[Optional[_builtins.str]] = None,
+                 retry_backoff: pulumi.Input[Optional[_builtins.str]] = None,
                  __props__=None):
         opts = pulumi.ResourceOptions.merge(_utilities.get_resource_opts_defaults(), opts)
         if not isinstance(opts, pulumi.ResourceOptions):
@@ -146,7 +232,21 @@ class Provider(pulumi.ProviderResource):
             if api_url is None:
                 api_url = (_utilities.get_env('CASTAI_API_URL') or 'https://api.cast.ai')
             __props__.__dict__["api_url"] = api_url
+            if max_concurrent_requests is None:
+                max_concurrent_requests = (_utilities.get_env_int('CASTAI_MAX_CONCURRENT_REQUESTS') or 0)
+            __props__.__dict__["max_concurrent_requests"] = pulumi.Output.from_input(max_concurrent_requests).apply(pulumi.runtime.to_json) if max_concurrent_requests is not None else None
+            if max_retries is None:
+                max_retries = (_utilities.get_env_int('CASTAI_MAX_RETRIES') or 3)
+            __props__.__dict__["max_retries"] = pulumi.Output.from_input(max_retries).apply(pulumi.runtime.to_json) if max_retries is not None else None
+            if organization_id is None:
+                organization_id = _utilities.get_env('CASTAI_ORGANIZATION_ID')
             __props__.__dict__["organization_id"] = organization_id
+            if request_timeout is None:
+                request_timeout = (_utilities.get_env('CASTAI_REQUEST_TIMEOUT') or '1m')
+            __props__.__dict__["request_timeout"] = request_timeout
+            if retry_backoff is None:
+                retry_backoff = (_utilities.get_env('CASTAI_RETRY_BACKOFF') or '1s')
+            __props__.__dict__["retry_backoff"] = retry_backoff
         secret_opts = pulumi.ResourceOptions(additional_secret_outputs=["apiToken"])
         opts = pulumi.ResourceOptions.merge(opts, secret_opts)
         super(Provider, __self__).__init__(
@@ -179,6 +279,22 @@ class Provider(pulumi.ProviderResource):
         """
         return pulumi.get(self, "organization_id")
 
+    @_builtins.property
+    @pulumi.getter(name="requestTimeout")
+    def request_timeout(self) -> pulumi.Output[Optional[_builtins.str]]:
+        """
+        Timeout of a single CAST AI API request attempt, e.g. `30s`. `0` disables the timeout.
+        """
+        return pulumi.get(self, "request_timeout")
+
+    @_builtins.property
+    @pulumi.getter(name="retryBackoff")
+    def retry_backoff(self) -> pulumi.Output[Optional[_builtins.str]]:
+        """
+        Wait before the first retry, e.g. `500ms` or `2s`. It doubles with every further retry, up to 30s. A longer `Retry-After` from the API takes precedence.
+        """
+        return pulumi.get(self, "retry_backoff")
+
     @pulumi.output_type
     class TerraformConfigResult:
         def __init__(__self__, result=None):
@@ -19,33 +19,22 @@ __all__ = ['RebalancingJobArgs', 'RebalancingJob']
 @pulumi.input_type
 class RebalancingJobArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
                  rebalancing_schedule_id: pulumi.Input[_builtins.str],
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
                  enabled: pulumi.Input[Optional[_builtins.bool]] = None):
         """
         The set of arguments for constructing a RebalancingJob resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id.
         :param pulumi.Input[_builtins.str] rebalancing_schedule_id: Rebalancing schedule of this job.
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id.
         :param pulumi.Input[_builtins.bool] enabled: The job will only be executed if it's enabled.
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "rebalancing_schedule_id", rebalancing_schedule_id)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
         if enabled is not None:
             pulumi.set(__self__, "enabled", enabled)
 
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster id.
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
-
     @_builtins.property
     @pulumi.getter(name="rebalancingScheduleId")
     def rebalancing_schedule_id(self) -> pulumi.Input[_builtins.str]:
@@ -58,6 +47,18 @@ class RebalancingJobArgs:
     def rebalancing_schedule_id(self, value: pulumi.Input[_builtins.str]):
         pulumi.set(self, "rebalancing_schedule_id", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster id.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
     @_builtins.property
     @pulumi.getter
     def enabled(self) -> pulumi.Input[Optional[_builtins.bool]]:
@@ -183,8 +184,6 @@ class RebalancingJob(pulumi.CustomResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = RebalancingJobArgs.__new__(RebalancingJobArgs)
 
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             __props__.__dict__["enabled"] = enabled
             if rebalancing_schedule_id is None and not opts.urn:
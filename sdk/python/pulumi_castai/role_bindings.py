@@ -20,43 +20,32 @@ __all__ = ['RoleBindingsArgs', 'RoleBindings']
 @pulumi.input_type
 class RoleBindingsArgs:
     def __init__(__self__, *,
-                 organization_id: pulumi.Input[_builtins.str],
                  role_id: pulumi.Input[_builtins.str],
                  subjects: pulumi.Input[Sequence[pulumi.Input['_iam.RoleBindingsSubjectArgs']]],
                  description: pulumi.Input[Optional[_builtins.str]] = None,
                  name: pulumi.Input[Optional[_builtins.str]] = None,
+                 organization_id: pulumi.Input[Optional[_builtins.str]] = None,
                  scopes: pulumi.Input[Optional[Sequence[pulumi.Input['_iam.RoleBindingsScopeArgs']]]] = None):
         """
         The set of arguments for constructing a RoleBindings resource.
 
-        :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID.
         :param pulumi.Input[_builtins.str] role_id: ID of role from role binding.
         :param pulumi.Input[_builtins.str] description: Description of the role binding.
         :param pulumi.Input[_builtins.str] name: Name of role binding.
+        :param pulumi.Input[_builtins.str] organization_id: CAST AI organization ID.
         :param pulumi.Input[Sequence[pulumi.Input['_iam.RoleBindingsScopeArgs']]] scopes: Scopes of the role binding.
         """
-        pulumi.set(__self__, "organization_id", organization_id)
         pulumi.set(__self__, "role_id", role_id)
         pulumi.set(__self__, "subjects", subjects)
         if description is not None:
             pulumi.set(__self__, "description", description)
         if name is not None:
             pulumi.set(__self__, "name", name)
+        if organization_id is not None:
+            pulumi.set(__self__, "organization_id", organization_id)
         if scopes is not None:
             pulumi.set(__self__, "scopes", scopes)
 
-    @_builtins.property
-    @pulumi.getter(name="organizationId")
-    def organization_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI organization ID.
-        """
-        return pulumi.get(self, "organization_id")
-
-    @organization_id.setter
-    def organization_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "organization_id", value)
-
     @_builtins.property
     @pulumi.getter(name="roleId")
     def role_id(self) -> pulumi.Input[_builtins.str]:
@@ -102,6 +91,18 @@ class RoleBindingsArgs:
     def name(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "name", value)
 
+    @_builtins.property
+    @pulumi.getter(name="organizationId")
+    def organization_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI organization ID.
+        """
+        return pulumi.get(self, "organization_id")
+
+    @organization_id.setter
+    def organization_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "organization_id", value)
+
     @_builtins.property
     @pulumi.getter
     def scopes(self) -> pulumi.Input[Optional[Sequence[pulumi.Input['_iam.RoleBindingsScopeArgs']]]]:
@@ -281,8 +282,6 @@ class RoleBindings(pulumi.CustomResource):
 
             __props__.__dict__["description"] = description
             __props__.__dict__["name"] = name
-            if organization_id is None and not opts.urn:
-                raise TypeError("Missing required property 'organization_id'")
             __props__.__dict__["organization_id"] = organization_id
             if role_id is None and not opts.urn:
                 raise TypeError("Missing required property 'role_id'")
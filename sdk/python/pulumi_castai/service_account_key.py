@@ -19,21 +19,20 @@ __all__ = ['ServiceAccountKeyArgs', 'ServiceAccountKey']
 @pulumi.input_type
 class ServiceAccountKeyArgs:
     def __init__(__self__, *,
-                 organization_id: pulumi.Input[_builtins.str],
                  service_account_id: pulumi.Input[_builtins.str],
                  active: pulumi.Input[Optional[_builtins.bool]] = None,
                  expires_at: pulumi.Input[Optional[_builtins.str]] = None,
-                 name: pulumi.Input[Optional[_builtins.str]] = None):
+                 name: pulumi.Input[Optional[_builtins.str]] = None,
+                 organization_id: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a ServiceAccountKey resource.
 
-        :param pulumi.Input[_builtins.str] organization_id: ID of the organization.
         :param pulumi.Input[_builtins.str] service_account_id: ID of the service account.
         :param pulumi.Input[_builtins.bool] active: Whether the service account key is active. Defaults to true.
         :param pulumi.Input[_builtins.str] expires_at: The expiration time of the service account key in RFC3339 format. Defaults to an empty string.
         :param pulumi.Input[_builtins.str] name: Name of the service account key.
+        :param pulumi.Input[_builtins.str] organization_id: ID of the organization.
         """
-        pulumi.set(__self__, "organization_id", organization_id)
         pulumi.set(__self__, "service_account_id", service_account_id)
         if active is not None:
             pulumi.set(__self__, "active", active)
@@ -41,18 +40,8 @@ class ServiceAccountKeyArgs:
             pulumi.set(__self__, "expires_at", expires_at)
         if name is not None:
             pulumi.set(__self__, "name", name)
-
-    @_builtins.property
-    @pulumi.getter(name="organizationId")
-    def organization_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        ID of the organization.
-        """
-        return pulumi.get(self, "organization_id")
-
-    @organization_id.setter
-    def organization_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "organization_id", value)
+        if organization_id is not None:
+            pulumi.set(__self__, "organization_id", organization_id)
 
     @_builtins.property
     @pulumi.getter(name="serviceAccountId")
@@ -102,6 +91,18 @@ class ServiceAccountKeyArgs:
     def name(self, value: pulumi.Input[Optional[_builtins.str]]):
         pulumi.set(self, "name", value)
 
+    @_builtins.property
+    @pulumi.getter(name="organizationId")
+    def organization_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        ID of the organization.
+        """
+        return pulumi.get(self, "organization_id")
+
+    @organization_id.setter
+    def organization_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "organization_id", value)
+
 
 @pulumi.input_type
 class _ServiceAccountKeyState:
@@ -304,8 +305,6 @@ class ServiceAccountKey(pulumi.CustomResource):
             __props__.__dict__["active"] = active
             __props__.__dict__["expires_at"] = expires_at
             __props__.__dict__["name"] = name
-            if organization_id is None and not opts.urn:
-                raise TypeError("Missing required property 'organization_id'")
             __props__.__dict__["organization_id"] = organization_id
             if service_account_id is None and not opts.urn:
                 raise TypeError("Missing required property 'service_account_id'")
@@ -313,6 +312,8 @@ class ServiceAccountKey(pulumi.CustomResource):
             __props__.__dict__["last_used_at"] = None
             __props__.__dict__["prefix"] = None
             __props__.__dict__["token"] = None
+        secret_opts = pulumi.ResourceOptions(additional_secret_outputs=["token"])
+        opts = pulumi.ResourceOptions.merge(opts, secret_opts)
         super(ServiceAccountKey, __self__).__init__(
             'castai:organization:ServiceAccountKey',
             resource_name,
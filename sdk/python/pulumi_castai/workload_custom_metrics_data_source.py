@@ -20,33 +20,22 @@ __all__ = ['WorkloadCustomMetricsDataSourceArgs', 'WorkloadCustomMetricsDataSour
 @pulumi.input_type
 class WorkloadCustomMetricsDataSourceArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
                  prometheus: pulumi.Input['_workload.WorkloadCustomMetricsDataSourcePrometheusArgs'],
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
                  name: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a WorkloadCustomMetricsDataSource resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID.
         :param pulumi.Input['_workload.WorkloadCustomMetricsDataSourcePrometheusArgs'] prometheus: Prometheus data source configuration.
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster ID.
         :param pulumi.Input[_builtins.str] name: Name of the custom metrics data source (1-63 characters).
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "prometheus", prometheus)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
         if name is not None:
             pulumi.set(__self__, "name", name)
 
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster ID.
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
-
     @_builtins.property
     @pulumi.getter
     def prometheus(self) -> pulumi.Input['_workload.WorkloadCustomMetricsDataSourcePrometheusArgs']:
@@ -59,6 +48,18 @@ class WorkloadCustomMetricsDataSourceArgs:
     def prometheus(self, value: pulumi.Input['_workload.WorkloadCustomMetricsDataSourcePrometheusArgs']):
         pulumi.set(self, "prometheus", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster ID.
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
     @_builtins.property
     @pulumi.getter
     def name(self) -> pulumi.Input[Optional[_builtins.str]]:
@@ -232,8 +233,6 @@ class WorkloadCustomMetricsDataSource(pulumi.CustomResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = WorkloadCustomMetricsDataSourceArgs.__new__(WorkloadCustomMetricsDataSourceArgs)
 
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             __props__.__dict__["name"] = name
             if prometheus is None and not opts.urn:
@@ -21,13 +21,13 @@ __all__ = ['WorkloadScalingPolicyArgs', 'WorkloadScalingPolicy']
 class WorkloadScalingPolicyArgs:
     def __init__(__self__, *,
                  apply_type: pulumi.Input[_builtins.str],
-                 cluster_id: pulumi.Input[_builtins.str],
                  cpu: pulumi.Input['_workload.WorkloadScalingPolicyCpuArgs'],
                  management_option: pulumi.Input[_builtins.str],
                  memory: pulumi.Input['_workload.WorkloadScalingPolicyMemoryArgs'],
                  anomaly_detection: pulumi.Input[Optional['_workload.WorkloadScalingPolicyAnomalyDetectionArgs']] = None,
                  anti_affinity: pulumi.Input[Optional['_workload.WorkloadScalingPolicyAntiAffinityArgs']] = None,
                  assignment_rules: pulumi.Input[Optional[Sequence[pulumi.Input['_workload.WorkloadScalingPolicyAssignmentRuleArgs']]]] = None,
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None,
                  confidence: pulumi.Input[Optional['_workload.WorkloadScalingPolicyConfidenceArgs']] = None,
                  downscaling: pulumi.Input[Optional['_workload.WorkloadScalingPolicyDownscalingArgs']] = None,
                  excluded_containers: pulumi.Input[Optional[Sequence[pulumi.Input[_builtins.str]]]] = None,
@@ -44,12 +44,12 @@ class WorkloadScalingPolicyArgs:
         :param pulumi.Input[_builtins.str] apply_type: Recommendation apply type.
                	- IMMEDIATE - pods are restarted immediately when new recommendation is generated.
                	- DEFERRED - pods are not restarted and recommendation values are applied during natural restarts only (new deployment, etc.)
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         :param pulumi.Input[_builtins.str] management_option: Defines possible options for workload management.
                	- READ_ONLY - workload watched (metrics collected), but no actions performed by CAST AI.
                	- MANAGED - workload watched (metrics collected), CAST AI may perform actions on the workload.
         :param pulumi.Input['_workload.WorkloadScalingPolicyAnomalyDetectionArgs'] anomaly_detection: Defines anomaly detection settings for the scaling policy.
         :param pulumi.Input[Sequence[pulumi.Input['_workload.WorkloadScalingPolicyAssignmentRuleArgs']]] assignment_rules: Allows defining conditions for automatically assigning workloads to this scaling policy.
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         :param pulumi.Input['_workload.WorkloadScalingPolicyConfidenceArgs'] confidence: Defines the confidence settings for applying recommendations.
         :param pulumi.Input[Sequence[pulumi.Input[_builtins.str]]] excluded_containers: Defines containers to be excluded from receiving recommendations. The containers are matched by exact name.
         :param pulumi.Input[Sequence[pulumi.Input['_workload.WorkloadScalingPolicyHpaConverterArgs']]] hpa_converters: Configuration for converting existing HPAs when VPA is the sole optimization. If HPA management is enabled, it takes precedence over this setting.
@@ -63,7 +63,6 @@ class WorkloadScalingPolicyArgs:
                	- Cluster has workload-autoscaler component version v0.35.3 or higher.
         """
         pulumi.set(__self__, "apply_type", apply_type)
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "cpu", cpu)
         pulumi.set(__self__, "management_option", management_option)
         pulumi.set(__self__, "memory", memory)
@@ -73,6 +72,8 @@ class WorkloadScalingPolicyArgs:
             pulumi.set(__self__, "anti_affinity", anti_affinity)
         if assignment_rules is not None:
             pulumi.set(__self__, "assignment_rules", assignment_rules)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
         if confidence is not None:
             pulumi.set(__self__, "confidence", confidence)
         if downscaling is not None:
@@ -108,18 +109,6 @@ class WorkloadScalingPolicyArgs:
     def apply_type(self, value: pulumi.Input[_builtins.str]):
         pulumi.set(self, "apply_type", value)
 
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster id
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
-
     @_builtins.property
     @pulumi.getter
     def cpu(self) -> pulumi.Input['_workload.WorkloadScalingPolicyCpuArgs']:
@@ -185,6 +174,18 @@ class WorkloadScalingPolicyArgs:
     def assignment_rules(self, value: pulumi.Input[Optional[Sequence[pulumi.Input['_workload.WorkloadScalingPolicyAssignmentRuleArgs']]]]):
         pulumi.set(self, "assignment_rules", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster id
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
     @_builtins.property
     @pulumi.getter
     def confidence(self) -> pulumi.Input[Optional['_workload.WorkloadScalingPolicyConfidenceArgs']]:
@@ -694,8 +695,6 @@ class WorkloadScalingPolicy(pulumi.CustomResource):
                 raise TypeError("Missing required property 'apply_type'")
             __props__.__dict__["apply_type"] = apply_type
             __props__.__dict__["assignment_rules"] = assignment_rules
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             __props__.__dict__["confidence"] = confidence
             if cpu is None and not opts.urn:
@@ -19,28 +19,17 @@ __all__ = ['WorkloadScalingPolicyOrderArgs', 'WorkloadScalingPolicyOrder']
 @pulumi.input_type
 class WorkloadScalingPolicyOrderArgs:
     def __init__(__self__, *,
-                 cluster_id: pulumi.Input[_builtins.str],
-                 policy_ids: pulumi.Input[Sequence[pulumi.Input[_builtins.str]]]):
+                 policy_ids: pulumi.Input[Sequence[pulumi.Input[_builtins.str]]],
+                 cluster_id: pulumi.Input[Optional[_builtins.str]] = None):
         """
         The set of arguments for constructing a WorkloadScalingPolicyOrder resource.
 
-        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         :param pulumi.Input[Sequence[pulumi.Input[_builtins.str]]] policy_ids: List of scaling policy IDs in the order they should be applied.
+        :param pulumi.Input[_builtins.str] cluster_id: CAST AI cluster id
         """
-        pulumi.set(__self__, "cluster_id", cluster_id)
         pulumi.set(__self__, "policy_ids", policy_ids)
-
-    @_builtins.property
-    @pulumi.getter(name="clusterId")
-    def cluster_id(self) -> pulumi.Input[_builtins.str]:
-        """
-        CAST AI cluster id
-        """
-        return pulumi.get(self, "cluster_id")
-
-    @cluster_id.setter
-    def cluster_id(self, value: pulumi.Input[_builtins.str]):
-        pulumi.set(self, "cluster_id", value)
+        if cluster_id is not None:
+            pulumi.set(__self__, "cluster_id", cluster_id)
 
     @_builtins.property
     @pulumi.getter(name="policyIds")
@@ -54,6 +43,18 @@ class WorkloadScalingPolicyOrderArgs:
     def policy_ids(self, value: pulumi.Input[Sequence[pulumi.Input[_builtins.str]]]):
         pulumi.set(self, "policy_ids", value)
 
+    @_builtins.property
+    @pulumi.getter(name="clusterId")
+    def cluster_id(self) -> pulumi.Input[Optional[_builtins.str]]:
+        """
+        CAST AI cluster id
+        """
+        return pulumi.get(self, "cluster_id")
+
+    @cluster_id.setter
+    def cluster_id(self, value: pulumi.Input[Optional[_builtins.str]]):
+        pulumi.set(self, "cluster_id", value)
+
 
 @pulumi.input_type
 class _WorkloadScalingPolicyOrderState:
@@ -148,8 +149,6 @@ class WorkloadScalingPolicyOrder(pulumi.CustomResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = WorkloadScalingPolicyOrderArgs.__new__(WorkloadScalingPolicyOrderArgs)
 
-            if cluster_id is None and not opts.urn:
-                raise TypeError("Missing required property 'cluster_id'")
             __props__.__dict__["cluster_id"] = cluster_id
             if policy_ids is None and not opts.urn:
                 raise TypeError("Missing required property 'policy_ids'")
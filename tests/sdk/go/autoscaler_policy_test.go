@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscalerpolicy"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscaling"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func productionPolicy() *autoscalerpolicy.Policy {
+	return &autoscalerpolicy.Policy{
+		Enabled:                             autoscalerpolicy.Bool(true),
+		IsScopedMode:                        autoscalerpolicy.Bool(false),
+		NodeTemplatesPartialMatchingEnabled: autoscalerpolicy.Bool(false),
+		UnschedulablePods: &autoscalerpolicy.UnschedulablePods{
+			Enabled: autoscalerpolicy.Bool(true),
+			Headroom: &autoscalerpolicy.Headroom{
+				Enabled:          autoscalerpolicy.Bool(true),
+				CpuPercentage:    autoscalerpolicy.Int(10),
+				MemoryPercentage: autoscalerpolicy.Int(10),
+			},
+		},
+		SpotInstances: &autoscalerpolicy.SpotInstances{
+			Enabled: autoscalerpolicy.Bool(true),
+			SpotBackups: &autoscalerpolicy.SpotBackups{
+				Enabled:                      autoscalerpolicy.Bool(true),
+				SpotBackupRestoreRateSeconds: autoscalerpolicy.Int(1800),
+			},
+		},
+		ClusterLimits: &autoscalerpolicy.ClusterLimits{
+			Enabled: autoscalerpolicy.Bool(true),
+			Cpu: &autoscalerpolicy.CpuLimit{
+				MinCores: autoscalerpolicy.Int(10),
+				MaxCores: autoscalerpolicy.Int(200),
+			},
+		},
+		NodeDownscaler: &autoscalerpolicy.NodeDownscaler{
+			Enabled: autoscalerpolicy.Bool(true),
+			EmptyNodes: &autoscalerpolicy.EmptyNodes{
+				Enabled: autoscalerpolicy.Bool(true),
+			},
+			Evictor: &autoscalerpolicy.Evictor{
+				Enabled:                autoscalerpolicy.Bool(true),
+				AggressiveMode:         autoscalerpolicy.Bool(false),
+				CycleInterval:          autoscalerpolicy.String("10m"),
+				NodeGracePeriodMinutes: autoscalerpolicy.Int(15),
+			},
+		},
+	}
+}
+
+func TestAutoscalerPolicyJSONIsDeterministic(t *testing.T) {
+	first, err := productionPolicy().JSON()
+	require.NoError(t, err)
+	second, err := productionPolicy().JSON()
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t,
+		`{"enabled":true,"isScopedMode":false,"nodeTemplatesPartialMatchingEnabled":false,`+
+			`"unschedulablePods":{"enabled":true,"headroom":{"enabled":true,"cpuPercentage":10,"memoryPercentage":10}},`+
+			`"spotInstances":{"enabled":true,"spotBackups":{"enabled":true,"spotBackupRestoreRateSeconds":1800}},`+
+			`"clusterLimits":{"enabled":true,"cpu":{"minCores":10,"maxCores":200}},`+
+			`"nodeDownscaler":{"enabled":true,"emptyNodes":{"enabled":true},`+
+			`"evictor":{"enabled":true,"aggressiveMode":false,"cycleInterval":"10m","nodeGracePeriodMinutes":15}}}`,
+		first)
+}
+
+func TestAutoscalerPolicyParseRoundTrip(t *testing.T) {
+	rendered, err := productionPolicy().JSON()
+	require.NoError(t, err)
+
+	parsed, err := autoscalerpolicy.Parse([]byte(rendered))
+	require.NoError(t, err)
+	assert.Equal(t, productionPolicy(), parsed)
+}
+
+func TestAutoscalerPolicyParseRejectsMisplacedField(t *testing.T) {
+	// spotBackups belongs under spotInstances, not clusterLimits.
+	_, err := autoscalerpolicy.Parse([]byte(`{
+		"enabled": true,
+		"clusterLimits": {
+			"enabled": true,
+			"spotBackups": {"enabled": true, "spotBackupRestoreRateSeconds": 1800}
+		}
+	}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spotBackups")
+}
+
+func TestAutoscalerPolicyValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *autoscalerpolicy.Policy
+		wantErr []string
+	}{
+		{
+			name:   "valid production policy",
+			policy: productionPolicy(),
+		},
+		{
+			name: "cluster limits min above max",
+			policy: &autoscalerpolicy.Policy{
+				ClusterLimits: &autoscalerpolicy.ClusterLimits{
+					Cpu: &autoscalerpolicy.CpuLimit{MinCores: autoscalerpolicy.Int(50), MaxCores: autoscalerpolicy.Int(5)},
+				},
+			},
+			wantErr: []string{"clusterLimits.cpu.minCores"},
+		},
+		{
+			name: "headroom percentage out of range",
+			policy: &autoscalerpolicy.Policy{
+				UnschedulablePods: &autoscalerpolicy.UnschedulablePods{
+					HeadroomSpot: &autoscalerpolicy.Headroom{CpuPercentage: autoscalerpolicy.Int(150)},
+				},
+			},
+			wantErr: []string{"unschedulablePods.headroomSpot.cpuPercentage"},
+		},
+		{
+			name: "invalid evictor durations",
+			policy: &autoscalerpolicy.Policy{
+				NodeDownscaler: &autoscalerpolicy.NodeDownscaler{
+					Evictor: &autoscalerpolicy.Evictor{
+						CycleInterval:                     autoscalerpolicy.String("five minutes"),
+						PodEvictionFailureBackOffInterval: autoscalerpolicy.String("0s"),
+					},
+				},
+			},
+			wantErr: []string{"nodeDownscaler.evictor.cycleInterval", "nodeDownscaler.evictor.podEvictionFailureBackOffInterval"},
+		},
+		{
+			name: "negative spot backup restore rate",
+			policy: &autoscalerpolicy.Policy{
+				SpotInstances: &autoscalerpolicy.SpotInstances{
+					SpotBackups: &autoscalerpolicy.SpotBackups{SpotBackupRestoreRateSeconds: autoscalerpolicy.Int(-1)},
+				},
+			},
+			wantErr: []string{"spotInstances.spotBackups.spotBackupRestoreRateSeconds"},
+		},
+		{
+			name: "unknown interruption prediction type",
+			policy: &autoscalerpolicy.Policy{
+				SpotInstances: &autoscalerpolicy.SpotInstances{
+					SpotInterruptionPredictions: &autoscalerpolicy.SpotInterruptionPredictions{Type: autoscalerpolicy.String("Magic")},
+				},
+			},
+			wantErr: []string{"spotInstances.spotInterruptionPredictions.type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestAutoscalerPolicySettingsArgsRoundTrip(t *testing.T) {
+	policy := productionPolicy()
+
+	args := policy.ToSettingsArgs()
+	require.NotNil(t, args)
+
+	back, err := autoscalerpolicy.FromSettingsArgs(args)
+	require.NoError(t, err)
+	assert.Equal(t, policy, back)
+}
+
+func TestAutoscalerPolicyFromHandWrittenSettingsArgs(t *testing.T) {
+	args := &autoscaling.AutoscalerAutoscalerSettingsArgs{
+		Enabled: pulumi.Bool(true),
+		ClusterLimits: autoscaling.AutoscalerAutoscalerSettingsClusterLimitsPtr(&autoscaling.AutoscalerAutoscalerSettingsClusterLimitsArgs{
+			Enabled: pulumi.BoolPtr(true),
+			Cpu: autoscaling.AutoscalerAutoscalerSettingsClusterLimitsCpuArgs{
+				MaxCores: pulumi.Int(40),
+			},
+		}),
+		NodeDownscaler: &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerArgs{
+			Evictor: &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerEvictorArgs{
+				CycleInterval: pulumi.StringPtr("5m10s"),
+			},
+		},
+	}
+
+	policy, err := autoscalerpolicy.FromSettingsArgs(args)
+	require.NoError(t, err)
+
+	assert.Equal(t, &autoscalerpolicy.Policy{
+		Enabled: autoscalerpolicy.Bool(true),
+		ClusterLimits: &autoscalerpolicy.ClusterLimits{
+			Enabled: autoscalerpolicy.Bool(true),
+			Cpu:     &autoscalerpolicy.CpuLimit{MaxCores: autoscalerpolicy.Int(40)},
+		},
+		NodeDownscaler: &autoscalerpolicy.NodeDownscaler{
+			Evictor: &autoscalerpolicy.Evictor{CycleInterval: autoscalerpolicy.String("5m10s")},
+		},
+	}, policy)
+}
+
+func TestAutoscalerPolicyFromSettingsArgsRejectsOutputs(t *testing.T) {
+	args := &autoscaling.AutoscalerAutoscalerSettingsArgs{
+		Enabled: pulumi.Bool(true).ToBoolPtrOutput(),
+	}
+
+	_, err := autoscalerpolicy.FromSettingsArgs(args)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "enabled")
+}
+
+func TestAutoscalerWithTypedPolicy(t *testing.T) {
+	policy := productionPolicy()
+	require.NoError(t, policy.Validate())
+
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		autoscaler, err := castai.NewAutoscaler(ctx, "typed-autoscaler", &castai.AutoscalerArgs{
+			ClusterId:          pulumi.String("typed-cluster-123"),
+			AutoscalerSettings: policy.ToSettingsArgs(),
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, autoscaler)
+		return nil
+	}, pulumi.WithMocks("project", "stack", &AutoscalerMocks{}))
+
+	assert.NoError(t, err)
+}
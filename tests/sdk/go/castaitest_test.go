@@ -0,0 +1,417 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/castaitest"
+	"github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/castai/pulumi-castai/sdk/go/castai/workload"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCastAIMocksCoverSchema tests that the mocks know every token in the provider schema
+func TestCastAIMocksCoverSchema(t *testing.T) {
+	b, err := os.ReadFile("../../../provider/cmd/pulumi-resource-castai/schema.json")
+	require.NoError(t, err)
+
+	var schema struct {
+		Resources map[string]json.RawMessage `json:"resources"`
+		Functions map[string]json.RawMessage `json:"functions"`
+	}
+	require.NoError(t, json.Unmarshal(b, &schema))
+
+	keys := func(m map[string]json.RawMessage) []string {
+		var out []string
+		for k := range m {
+			if strings.HasPrefix(k, "castai:") {
+				out = append(out, k)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	sorted := func(in []string) []string {
+		out := append([]string{}, in...)
+		sort.Strings(out)
+		return out
+	}
+	assert.Equal(t, keys(schema.Resources), sorted(castaitest.ResourceTypes))
+	assert.Equal(t, keys(schema.Functions), sorted(castaitest.FunctionTokens))
+}
+
+// TestCastAIMocksEksClusterComputedOutputs tests the computed outputs of an EKS cluster
+func TestCastAIMocksEksClusterComputedOutputs(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		_, err := castai.NewEksCluster(ctx, "eks", &castai.EksClusterArgs{
+			AccountId: pulumi.String("123456789012"),
+			Region:    pulumi.String("us-west-2"),
+			Name:      pulumi.String("my-eks-cluster"),
+		})
+		return err
+	})
+
+	reg, ok := mocks.Find("castai:aws:EksCluster", "eks")
+	require.True(t, ok)
+	assert.Len(t, reg.ID, 36, "cluster ids are UUIDs")
+	assert.Len(t, reg.Outputs["clusterToken"].StringValue(), 64)
+	assert.Len(t, reg.Outputs["credentialsId"].StringValue(), 36)
+	assert.Equal(t, castaitest.DefaultOrganizationID, reg.Outputs["organizationId"].StringValue())
+	assert.Equal(t, "my-eks-cluster", reg.Outputs["name"].StringValue())
+	assert.NotContains(t, reg.Inputs, resource.PropertyKey("clusterToken"), "inputs are recorded as passed")
+}
+
+// TestCastAIMocksRunHelpers tests Await on outputs and Strings on recorded
+// list inputs of a program run with castaitest.Run
+func TestCastAIMocksRunHelpers(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		cluster, err := castai.NewEksCluster(ctx, "eks", &castai.EksClusterArgs{
+			AccountId: pulumi.String("123456789012"),
+			Region:    pulumi.String("us-west-2"),
+			Name:      pulumi.String("my-eks-cluster"),
+		})
+		if err != nil {
+			return err
+		}
+		token := castaitest.Await(t, cluster.ClusterToken)
+		assert.True(t, token.Known)
+		assert.Len(t, token.Value, 64)
+
+		_, err = config.NewNodeConfiguration(ctx, "default", &config.NodeConfigurationArgs{
+			ClusterId: cluster.ID(),
+			Subnets:   pulumi.ToStringArray([]string{"subnet-1", "subnet-2"}),
+		})
+		return err
+	})
+
+	nodeConfig, ok := mocks.Find("castai:config/node:NodeConfiguration", "default")
+	require.True(t, ok)
+	assert.Equal(t, []string{"subnet-1", "subnet-2"}, castaitest.Strings(nodeConfig.Inputs["subnets"]))
+}
+
+// TestCastAIMocksDeterministicIDs tests that the same program gets the same ids on every run
+func TestCastAIMocksDeterministicIDs(t *testing.T) {
+	program := func(ctx *pulumi.Context) error {
+		_, err := castai.NewGkeCluster(ctx, "gke", &castai.GkeClusterArgs{
+			ProjectId: pulumi.String("my-project"),
+			Location:  pulumi.String("us-central1"),
+		})
+		return err
+	}
+
+	first, _ := castaitest.Run(t, program).Find("castai:gcp:GkeCluster", "gke")
+	second, _ := castaitest.Run(t, program).Find("castai:gcp:GkeCluster", "gke")
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, first.Outputs["clusterToken"], second.Outputs["clusterToken"])
+	assert.Equal(t, "gke", first.Outputs["name"].StringValue(), "name defaults to the resource name")
+}
+
+// TestCastAIMocksGkeClusterIdServiceAccount tests that castServiceAccount is computed unless set
+func TestCastAIMocksGkeClusterIdServiceAccount(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		if _, err := castai.NewGkeClusterId(ctx, "computed", &castai.GkeClusterIdArgs{
+			ProjectId: pulumi.String("my-project"),
+			Location:  pulumi.String("us-central1"),
+		}); err != nil {
+			return err
+		}
+		_, err := castai.NewGkeClusterId(ctx, "explicit", &castai.GkeClusterIdArgs{
+			ProjectId:          pulumi.String("my-project"),
+			Location:           pulumi.String("us-central1"),
+			CastServiceAccount: pulumi.String("castai@my-project.iam.gserviceaccount.com"),
+		})
+		return err
+	})
+
+	computed, _ := mocks.Find("castai:gcp:GkeClusterId", "computed")
+	assert.Regexp(t, `^castai-gke-[0-9a-f]{8}@my-project\.iam\.gserviceaccount\.com$`, computed.Outputs["castServiceAccount"].StringValue())
+	assert.NotEmpty(t, computed.Outputs["clusterToken"].StringValue())
+
+	explicit, _ := mocks.Find("castai:gcp:GkeClusterId", "explicit")
+	assert.Equal(t, "castai@my-project.iam.gserviceaccount.com", explicit.Outputs["castServiceAccount"].StringValue())
+}
+
+// TestCastAIMocksNodeTemplateDefaults tests node template ids and the isDefault default
+func TestCastAIMocksNodeTemplateDefaults(t *testing.T) {
+	mocks := castaitest.NewMocks()
+	mocks.OrganizationID = "11111111-2222-4333-8444-555555555555"
+
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		template, err := config.NewNodeTemplate(ctx, "spot", &config.NodeTemplateArgs{
+			ClusterId: pulumi.String("cluster-1"),
+			Name:      pulumi.String("spot-pool"),
+		})
+		if err != nil {
+			return err
+		}
+		template.IsDefault.ApplyT(func(isDefault bool) error {
+			assert.False(t, isDefault)
+			return nil
+		})
+		_, err = castai.NewAksCluster(ctx, "aks", &castai.AksClusterArgs{
+			SubscriptionId:    pulumi.String("sub"),
+			TenantId:          pulumi.String("tenant"),
+			ClientId:          pulumi.String("client"),
+			ClientSecret:      pulumi.String("secret"),
+			Region:            pulumi.String("eastus"),
+			NodeResourceGroup: pulumi.String("MC_rg"),
+		})
+		return err
+	}, pulumi.WithMocks("project", "stack", mocks))
+	require.NoError(t, err)
+
+	template, ok := mocks.Find("castai:config/node:NodeTemplate", "spot")
+	require.True(t, ok)
+	assert.Equal(t, "spot-pool", template.ID, "node templates are identified by name")
+	assert.True(t, template.Outputs["isEnabled"].BoolValue())
+
+	aks, _ := mocks.Find("castai:azure:AksCluster", "aks")
+	assert.Equal(t, mocks.OrganizationID, aks.Outputs["organizationId"].StringValue())
+}
+
+// TestCastAIMocksServiceAccountKey tests the computed service account outputs
+func TestCastAIMocksServiceAccountKey(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		sa, err := castai.NewServiceAccount(ctx, "ci", &castai.ServiceAccountArgs{
+			OrganizationId: pulumi.String(castaitest.DefaultOrganizationID),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = castai.NewServiceAccountKey(ctx, "ci-key", &castai.ServiceAccountKeyArgs{
+			OrganizationId:   pulumi.String(castaitest.DefaultOrganizationID),
+			ServiceAccountId: sa.ID(),
+		})
+		return err
+	})
+
+	sa, _ := mocks.Find("castai:organization:ServiceAccount", "ci")
+	assert.Contains(t, sa.Outputs["email"].StringValue(), "@"+castaitest.DefaultOrganizationID)
+
+	key, _ := mocks.Find("castai:organization:ServiceAccountKey", "ci-key")
+	token := key.Outputs["token"].StringValue()
+	assert.Len(t, token, 64)
+	assert.Equal(t, token[:6], key.Outputs["prefix"].StringValue())
+	assert.Equal(t, sa.ID, key.Inputs["serviceAccountId"].StringValue())
+}
+
+// TestCastAIMocksAutoscalerID tests that the autoscaler is identified by its cluster
+func TestCastAIMocksAutoscalerID(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		_, err := castai.NewAutoscaler(ctx, "autoscaler", &castai.AutoscalerArgs{
+			ClusterId:              pulumi.String("cluster-1"),
+			AutoscalerPoliciesJson: pulumi.String(`{"enabled":true}`),
+		})
+		return err
+	})
+
+	reg, ok := mocks.Find("castai:autoscaling:Autoscaler", "autoscaler")
+	require.True(t, ok)
+	assert.Equal(t, "cluster-1", reg.ID)
+	assert.Equal(t, `{"enabled":true}`, reg.Outputs["autoscalerPolicies"].StringValue())
+}
+
+// TestCastAIMocksClusterReadiness tests that the readiness wait is identified by
+// its cluster and can gate the resources configuring the cluster
+func TestCastAIMocksClusterReadiness(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		cluster, err := castai.NewEksCluster(ctx, "eks", &castai.EksClusterArgs{
+			AccountId: pulumi.String("123456789012"),
+			Region:    pulumi.String("us-west-2"),
+			Name:      pulumi.String("my-eks-cluster"),
+		})
+		if err != nil {
+			return err
+		}
+		ready, err := castai.NewClusterReadiness(ctx, "eks-ready", &castai.ClusterReadinessArgs{
+			ClusterId: cluster.ID(),
+			Timeout:   pulumi.String("20m"),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = config.NewNodeConfiguration(ctx, "default", &config.NodeConfigurationArgs{
+			ClusterId: ready.ClusterId,
+			Name:      pulumi.String("default"),
+			Subnets:   pulumi.StringArray{pulumi.String("subnet-1")},
+		}, pulumi.DependsOn([]pulumi.Resource{ready}))
+		return err
+	})
+
+	cluster, _ := mocks.Find("castai:aws:EksCluster", "eks")
+	ready, ok := mocks.Find("castai:index:ClusterReadiness", "eks-ready")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, ready.ID)
+	assert.Equal(t, "connected", ready.Outputs["waitFor"].StringValue())
+	assert.Equal(t, "20m", ready.Outputs["timeout"].StringValue())
+	assert.Equal(t, "online", ready.Outputs["agentStatus"].StringValue())
+
+	nodeConfig, ok := mocks.Find("castai:config/node:NodeConfiguration", "default")
+	require.True(t, ok)
+	assert.Equal(t, cluster.ID, nodeConfig.Inputs["clusterId"].StringValue())
+	require.Len(t, nodeConfig.Dependencies, 1)
+	assert.Equal(t, "eks-ready", resource.URN(nodeConfig.Dependencies[0]).Name())
+}
+
+// TestCastAIMocksDataSources tests the answers to data source calls
+func TestCastAIMocksDataSources(t *testing.T) {
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		eks, err := castai.GetEksSettings(ctx, &castai.GetEksSettingsArgs{
+			AccountId: "123456789012",
+			Region:    "us-west-2",
+			Vpc:       "vpc-123",
+			Cluster:   "my-eks-cluster",
+		})
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, castaitest.EksManagedPolicies, eks.IamManagedPolicies)
+		var policy map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(eks.IamPolicyJson), &policy))
+		assert.Equal(t, "2012-10-17", policy["Version"])
+
+		gke, err := castai.GetGkePolicies(ctx, &castai.GetGkePoliciesArgs{
+			Features: map[string]bool{"loadBalancersNetworkEndpointGroup": true},
+		})
+		if err != nil {
+			return err
+		}
+		assert.Contains(t, gke.Policies, "compute.instances.create")
+		assert.Contains(t, gke.Policies, "compute.networkEndpointGroups.attachNetworkEndpoints")
+
+		org, err := castai.GetOrganization(ctx, &castai.GetOrganizationArgs{Name: "My Org"})
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, castaitest.DefaultOrganizationID, org.Id)
+		assert.Equal(t, "My Org", org.Name)
+		return nil
+	})
+
+	calls := mocks.Invocations()
+	require.Len(t, calls, 3)
+	assert.Equal(t, "castai:aws:getEksSettings", calls[0].Token)
+	assert.Equal(t, "us-west-2", calls[0].Args["region"].StringValue())
+	assert.Len(t, mocks.InvocationsOf("castai:organization:getOrganization"), 1)
+}
+
+// TestCastAIMocksWorkloadScalingPolicies tests that the policy data sources see registered policies
+func TestCastAIMocksWorkloadScalingPolicies(t *testing.T) {
+	castaitest.Run(t, func(ctx *pulumi.Context) error {
+		policy, err := castai.NewWorkloadScalingPolicy(ctx, "prod", &castai.WorkloadScalingPolicyArgs{
+			ClusterId:        pulumi.String("cluster-1"),
+			Name:             pulumi.String("prod"),
+			ApplyType:        pulumi.String("IMMEDIATE"),
+			ManagementOption: pulumi.String("READ_ONLY"),
+			Cpu: workload.WorkloadScalingPolicyCpuArgs{
+				Function: pulumi.String("QUANTILE"),
+			},
+			Memory: workload.WorkloadScalingPolicyMemoryArgs{
+				Function: pulumi.String("MAX"),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		policy.ID().ApplyT(func(id pulumi.ID) error {
+			order, err := castai.LookupWorkloadScalingPolicyOrder(ctx, &castai.LookupWorkloadScalingPolicyOrderArgs{ClusterId: "cluster-1"})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{string(id)}, order.PolicyIds)
+
+			policies, err := castai.GetWorkloadScalingPolicies(ctx, &castai.GetWorkloadScalingPoliciesArgs{ClusterId: "cluster-1"})
+			assert.NoError(t, err)
+			assert.Equal(t, string(id), policies.PoliciesByName["prod"])
+			return nil
+		})
+		return nil
+	})
+}
+
+// TestCastAIMocksUnknownToken tests that misspelled CAST AI tokens fail instead of being echoed
+func TestCastAIMocksUnknownToken(t *testing.T) {
+	mocks := castaitest.NewMocks()
+	_, _, err := mocks.NewResource(pulumi.MockResourceArgs{TypeToken: "castai:aws:EKSCluster", Name: "typo", Custom: true})
+	assert.Error(t, err)
+
+	_, err = mocks.Call(pulumi.MockCallArgs{Token: "castai:aws:getEKSSettings"})
+	assert.Error(t, err)
+
+	id, outputs, err := mocks.NewResource(pulumi.MockResourceArgs{
+		TypeToken: "aws:iam/role:Role",
+		Custom:    true,
+		Name:      "role",
+		Inputs:    resource.PropertyMap{"name": resource.NewStringProperty("castai")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "role-id", id)
+	assert.Equal(t, "castai", outputs["name"].StringValue())
+	assert.Len(t, mocks.Registrations(), 1, "failed registrations are not recorded")
+}
+
+// TestProviderIDsAreOptional tests that organization and cluster IDs can be left
+// to the provider defaults (organizationId and defaultClusterId)
+func TestProviderIDsAreOptional(t *testing.T) {
+	t.Setenv("CASTAI_ORGANIZATION_ID", "env-org")
+	t.Setenv("CASTAI_DEFAULT_CLUSTER_ID", "env-cluster")
+
+	mocks := castaitest.Run(t, func(ctx *pulumi.Context) error {
+		assert.Equal(t, "env-org", config.GetOrganizationId(ctx))
+		assert.Equal(t, "env-cluster", config.GetDefaultClusterId(ctx))
+
+		sa, err := castai.NewServiceAccount(ctx, "ci", &castai.ServiceAccountArgs{})
+		if err != nil {
+			return err
+		}
+		_, err = castai.NewServiceAccountKey(ctx, "ci-key", &castai.ServiceAccountKeyArgs{
+			ServiceAccountId: sa.ID(),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = castai.NewRebalancingJob(ctx, "job", &castai.RebalancingJobArgs{
+			RebalancingScheduleId: pulumi.String("schedule-1"),
+		})
+		return err
+	})
+
+	for _, reg := range []struct{ typ, name, field string }{
+		{"castai:organization:ServiceAccount", "ci", "organizationId"},
+		{"castai:organization:ServiceAccountKey", "ci-key", "organizationId"},
+		{"castai:rebalancing:RebalancingJob", "job", "clusterId"},
+	} {
+		res, ok := mocks.Find(reg.typ, reg.name)
+		require.True(t, ok, "%s should be registered", reg.typ)
+		_, set := res.Inputs[resource.PropertyKey(reg.field)]
+		assert.False(t, set, "%s.%s should be left to the provider", reg.typ, reg.field)
+	}
+}
+
+// TestProviderHTTPSettings tests the defaults and env vars of the HTTP client settings
+func TestProviderHTTPSettings(t *testing.T) {
+	castaitest.Run(t, func(ctx *pulumi.Context) error {
+		assert.Equal(t, 3, config.GetMaxRetries(ctx))
+		assert.Equal(t, "1s", config.GetRetryBackoff(ctx))
+		assert.Equal(t, "1m", config.GetRequestTimeout(ctx))
+		assert.Equal(t, 0, config.GetMaxConcurrentRequests(ctx))
+		return nil
+	})
+
+	t.Setenv("CASTAI_MAX_RETRIES", "5")
+	t.Setenv("CASTAI_RETRY_BACKOFF", "250ms")
+	t.Setenv("CASTAI_REQUEST_TIMEOUT", "30s")
+	t.Setenv("CASTAI_MAX_CONCURRENT_REQUESTS", "4")
+	castaitest.Run(t, func(ctx *pulumi.Context) error {
+		assert.Equal(t, 5, config.GetMaxRetries(ctx))
+		assert.Equal(t, "250ms", config.GetRetryBackoff(ctx))
+		assert.Equal(t, "30s", config.GetRequestTimeout(ctx))
+		assert.Equal(t, 4, config.GetMaxConcurrentRequests(ctx))
+		return nil
+	})
+}
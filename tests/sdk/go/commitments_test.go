@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai/commitments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readCommitmentsFile(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "commitments", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestCommitmentsGCPRoundTrip(t *testing.T) {
+	cuds, err := commitments.ParseGCPCommitments(readCommitmentsFile(t, "gcloud_commitments.json"))
+	require.NoError(t, err)
+	require.Len(t, cuds, 2)
+
+	assert.Equal(t, "shop-prod", cuds[0].Project)
+	assert.Equal(t, "us-east4", cuds[0].Region)
+	assert.Equal(t, int64(16), cuds[0].VCPUs)
+	assert.Equal(t, int64(65536), cuds[0].MemoryMB)
+	assert.Equal(t, time.Date(2024, 1, 26, 8, 0, 0, 0, time.UTC), cuds[0].Start.UTC())
+	assert.Len(t, cuds[1].Accelerators, 1)
+	assert.Equal(t, int64(2), cuds[1].Accelerators[0].Count)
+
+	// The rendered input drops the fields gcloud adds for display.
+	out, err := cuds.JSON()
+	require.NoError(t, err)
+	assert.Equal(t, string(readCommitmentsFile(t, "gcp_cuds.json")), out)
+
+	again, err := commitments.ParseGCPCommitments([]byte(out))
+	require.NoError(t, err)
+	againOut, err := again.JSON()
+	require.NoError(t, err)
+	assert.Equal(t, out, againOut)
+}
+
+func TestCommitmentsGCPValidation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cuds := commitments.GCPCommitments{
+		{
+			ID: "1", Name: "Bad_Name", Region: "us-east4", Plan: "ONE_MONTH", Type: "GENERAL_PURPOSE_E2",
+			Status: commitments.GCPStatusActive, Start: start, End: start, VCPUs: 4,
+		},
+		{
+			ID: "1", Name: "dup", Region: "us-east4", Plan: commitments.GCPPlanTwelveMonth, Type: "GENERAL_PURPOSE_N2",
+			Status: "RUNNING", Start: start, End: start.AddDate(1, 0, 0), VCPUs: 4, MemoryMB: 16384,
+		},
+	}
+
+	_, err := cuds.JSON()
+	require.Error(t, err)
+	for _, want := range []string{
+		`[0] Bad_Name: name "Bad_Name" is not a valid GCP resource name`,
+		`[0] Bad_Name: plan: unsupported value "ONE_MONTH"`,
+		"[0] Bad_Name: machine commitments must cover vCPUs and memory, got 4 vCPUs and 0 MB",
+		"[0] Bad_Name: end 2024-01-01T00:00:00Z is not after start 2024-01-01T00:00:00Z",
+		"[1] dup: id 1 is also used by [0]",
+		`[1] dup: status: unsupported value "RUNNING"`,
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+
+	_, err = commitments.ParseGCPCommitments([]byte(`[{"name": "x", "resources": [{"type": "LICENSE", "amount": "1"}]}]`))
+	assert.ErrorContains(t, err, `[0].resources[0].type: unsupported value "LICENSE"`)
+	_, err = commitments.ParseGCPCommitments([]byte(`[{"name": "x", "resources": [{"type": "VCPU", "amount": "four"}]}]`))
+	assert.ErrorContains(t, err, `invalid amount "four"`)
+}
+
+func TestCommitmentsAzureRoundTrip(t *testing.T) {
+	rs, err := commitments.ParseAzureReservations(readCommitmentsFile(t, "azure_reservations_export.csv"))
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	assert.Equal(t, "Standard_D4s_v3", rs[0].InstanceType)
+	assert.Equal(t, commitments.AzureTermOneYear, rs[0].Term)
+	assert.Equal(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), rs[0].End)
+	assert.Equal(t, "batch, westeurope", rs[1].Name)
+	assert.Equal(t, "batch-rg", rs[1].ScopeResourceGroup)
+	assert.Equal(t, 10, rs[1].Quantity)
+
+	// Dates are rendered as RFC 3339 and the utilization columns are left empty.
+	out, err := rs.CSV()
+	require.NoError(t, err)
+	assert.Equal(t, string(readCommitmentsFile(t, "azure_reservations.csv")), out)
+
+	again, err := commitments.ParseAzureReservations([]byte(out))
+	require.NoError(t, err)
+	assert.Equal(t, rs, again)
+}
+
+func TestCommitmentsAzureValidation(t *testing.T) {
+	_, err := commitments.ParseAzureReservations([]byte("Name,Status\nri,Succeeded\n"))
+	assert.ErrorContains(t, err, `missing columns "Reservation Id", "Expiry date"`)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rs := commitments.AzureReservations{{
+		Name: "ri", ReservationID: "a", Status: "Succeeded", Term: "P2Y", Scope: commitments.AzureScopeSingleResourceGroup,
+		ScopeSubscription: "sub", Type: "SqlDatabases", InstanceType: "Standard_D4s_v3", Region: "eastus",
+		Start: start, End: start.AddDate(1, 0, 0),
+	}}
+	err = rs.Validate()
+	require.Error(t, err)
+	for _, want := range []string{
+		`[0] ri: term: unsupported value "P2Y"`,
+		"[0] ri: scope resource group: must be set",
+		`[0] ri: type: only VirtualMachines reservations can be imported, got "SqlDatabases"`,
+		"[0] ri: quantity: must be positive, got 0",
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestCommitmentsReservationsRoundTrip(t *testing.T) {
+	data := readCommitmentsFile(t, "reservations.csv")
+	rs, err := commitments.ParseReservations(data)
+	require.NoError(t, err)
+	require.Len(t, rs, 3)
+	assert.Equal(t, 0.108, rs[0].Price)
+	assert.True(t, rs[1].End.IsZero())
+	assert.Equal(t, "1", rs[2].ZoneName)
+
+	out, err := rs.CSV()
+	require.NoError(t, err)
+	assert.Equal(t, string(data), out)
+
+	rs = append(rs, rs[0])
+	rs[3].ZoneName = "us-west-2a"
+	err = rs.Validate()
+	assert.ErrorContains(t, err, "[3] web-c5: name is also used by [0] for c5.xlarge in us-east-1")
+	assert.ErrorContains(t, err, "[3] web-c5: zone us-west-2a is not in region us-east-1")
+}
+
+func TestCommitmentsAWSReservedInstances(t *testing.T) {
+	rs, err := commitments.ParseAWSReservedInstances(readCommitmentsFile(t, "aws_reserved_instances.json"), "us-east-1")
+	require.NoError(t, err)
+
+	// The retired reservation is skipped and the upfront price of the
+	// regional one is spread over its three years.
+	require.Len(t, rs, 2)
+	assert.Equal(t, "us-east-1a", rs[0].ZoneName)
+	assert.Equal(t, 0.1, rs[1].Price)
+
+	out, err := rs.CSV()
+	require.NoError(t, err)
+	assert.Equal(t, string(readCommitmentsFile(t, "aws_reservations.csv")), out)
+}
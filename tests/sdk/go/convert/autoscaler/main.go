@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/autoscaling"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		cfg := config.New(ctx, "")
+		clusterId := cfg.Require("clusterId")
+		_, err := castai.NewAutoscaler(ctx, "this", &castai.AutoscalerArgs{
+			ClusterId: pulumi.String(pulumi.String(clusterId)),
+			AutoscalerSettings: &autoscaling.AutoscalerAutoscalerSettingsArgs{
+				Enabled:                             pulumi.Bool(true),
+				IsScopedMode:                        pulumi.Bool(false),
+				NodeTemplatesPartialMatchingEnabled: pulumi.Bool(false),
+				UnschedulablePods: &autoscaling.AutoscalerAutoscalerSettingsUnschedulablePodsArgs{
+					Enabled: pulumi.Bool(true),
+				},
+				ClusterLimits: &autoscaling.AutoscalerAutoscalerSettingsClusterLimitsArgs{
+					Enabled: pulumi.Bool(true),
+					Cpu: &autoscaling.AutoscalerAutoscalerSettingsClusterLimitsCpuArgs{
+						MinCores: pulumi.Int(2),
+						MaxCores: pulumi.Int(200),
+					},
+				},
+				NodeDownscaler: &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerArgs{
+					Enabled: pulumi.Bool(true),
+					EmptyNodes: &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerEmptyNodesArgs{
+						Enabled:      pulumi.Bool(true),
+						DelaySeconds: pulumi.Int(300),
+					},
+					Evictor: &autoscaling.AutoscalerAutoscalerSettingsNodeDownscalerEvictorArgs{
+						Enabled:                pulumi.Bool(true),
+						AggressiveMode:         pulumi.Bool(false),
+						CycleInterval:          pulumi.String("5m10s"),
+						DryRun:                 pulumi.Bool(false),
+						NodeGracePeriodMinutes: pulumi.Int(10),
+						ScopedMode:             pulumi.Bool(false),
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
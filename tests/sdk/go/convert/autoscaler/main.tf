@@ -0,0 +1,44 @@
+variable "cluster_id" {
+  type = string
+}
+
+resource "castai_autoscaler" "this" {
+  cluster_id = var.cluster_id
+
+  autoscaler_settings {
+    enabled                                 = true
+    is_scoped_mode                          = false
+    node_templates_partial_matching_enabled = false
+
+    unschedulable_pods {
+      enabled = true
+    }
+
+    cluster_limits {
+      enabled = true
+
+      cpu {
+        min_cores = 2
+        max_cores = 200
+      }
+    }
+
+    node_downscaler {
+      enabled = true
+
+      empty_nodes {
+        enabled       = true
+        delay_seconds = 300
+      }
+
+      evictor {
+        enabled                   = true
+        aggressive_mode           = false
+        cycle_interval            = "5m10s"
+        dry_run                   = false
+        node_grace_period_minutes = 10
+        scoped_mode               = false
+      }
+    }
+  }
+}
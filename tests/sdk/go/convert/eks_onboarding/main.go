@@ -0,0 +1,74 @@
+package main
+
+import (
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	castaiconfig "github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		cfg := config.New(ctx, "")
+		awsAccountId := cfg.Require("awsAccountId")
+		awsClusterRegion := cfg.Require("awsClusterRegion")
+		awsClusterName := cfg.Require("awsClusterName")
+		awsAssumeRoleArn := cfg.Require("awsAssumeRoleArn")
+		instanceProfileArn := cfg.Require("instanceProfileArn")
+		clusterId, err := castai.NewEksClusterId(ctx, "clusterId", &castai.EksClusterIdArgs{
+			AccountId:   pulumi.String(pulumi.String(awsAccountId)),
+			Region:      pulumi.String(pulumi.String(awsClusterRegion)),
+			ClusterName: pulumi.String(pulumi.String(awsClusterName)),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = castai.NewEksUserArn(ctx, "castaiUserArn", &castai.EksUserArnArgs{
+			ClusterId: clusterId.ID(),
+		})
+		if err != nil {
+			return err
+		}
+		thisEksCluster, err := castai.NewEksCluster(ctx, "thisEksCluster", &castai.EksClusterArgs{
+			AccountId:               pulumi.String(pulumi.String(awsAccountId)),
+			Region:                  pulumi.String(pulumi.String(awsClusterRegion)),
+			Name:                    pulumi.String(pulumi.String(awsClusterName)),
+			AssumeRoleArn:           pulumi.String(pulumi.String(awsAssumeRoleArn)),
+			DeleteNodesOnDisconnect: pulumi.Bool(false),
+		})
+		if err != nil {
+			return err
+		}
+		_default, err := castaiconfig.NewNodeConfiguration(ctx, "default", &castaiconfig.NodeConfigurationArgs{
+			ClusterId:    thisEksCluster.ID(),
+			Name:         pulumi.String("default"),
+			DiskCpuRatio: pulumi.Int(0),
+			MinDiskSize:  pulumi.Int(100),
+			Subnets: pulumi.StringArray{
+				pulumi.String("subnet-0a1b2c3d"),
+				pulumi.String("subnet-4e5f6a7b"),
+			},
+			Tags: pulumi.StringMap{
+				"team": pulumi.String("platform"),
+			},
+			Eks: &castaiconfig.NodeConfigurationEksArgs{
+				InstanceProfileArn: pulumi.String(pulumi.String(instanceProfileArn)),
+				SecurityGroups: pulumi.StringArray{
+					pulumi.String("sg-0123456789abcdef0"),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = castaiconfig.NewNodeConfigurationDefault(ctx, "thisNodeConfigurationDefault", &castaiconfig.NodeConfigurationDefaultArgs{
+			ClusterId:       thisEksCluster.ID(),
+			ConfigurationId: _default.ID(),
+		})
+		if err != nil {
+			return err
+		}
+		ctx.Export("clusterToken", thisEksCluster.ClusterToken)
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+variable "aws_account_id" {
+  type = string
+}
+
+variable "aws_cluster_region" {
+  type = string
+}
+
+variable "aws_cluster_name" {
+  type = string
+}
+
+variable "aws_assume_role_arn" {
+  type = string
+}
+
+variable "instance_profile_arn" {
+  type = string
+}
+
+resource "castai_eks_clusterid" "cluster_id" {
+  account_id   = var.aws_account_id
+  region       = var.aws_cluster_region
+  cluster_name = var.aws_cluster_name
+}
+
+resource "castai_eks_user_arn" "castai_user_arn" {
+  cluster_id = castai_eks_clusterid.cluster_id.id
+}
+
+resource "castai_eks_cluster" "this" {
+  account_id                 = var.aws_account_id
+  region                     = var.aws_cluster_region
+  name                       = var.aws_cluster_name
+  assume_role_arn            = var.aws_assume_role_arn
+  delete_nodes_on_disconnect = false
+}
+
+resource "castai_node_configuration" "default" {
+  cluster_id     = castai_eks_cluster.this.id
+  name           = "default"
+  disk_cpu_ratio = 0
+  min_disk_size  = 100
+  subnets        = ["subnet-0a1b2c3d", "subnet-4e5f6a7b"]
+  tags = {
+    team = "platform"
+  }
+
+  eks {
+    instance_profile_arn = var.instance_profile_arn
+    security_groups      = ["sg-0123456789abcdef0"]
+  }
+}
+
+resource "castai_node_configuration_default" "this" {
+  cluster_id       = castai_eks_cluster.this.id
+  configuration_id = castai_node_configuration.default.id
+}
+
+output "cluster_token" {
+  value     = castai_eks_cluster.this.cluster_token
+  sensitive = true
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/castai/pulumi-castai/sdk/go/castai"
+	"github.com/castai/pulumi-castai/sdk/go/castai/rebalancing"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		cfg := config.New(ctx, "")
+		clusterId := cfg.Require("clusterId")
+		_, err := castai.NewHibernationSchedule(ctx, "weeknights", &castai.HibernationScheduleArgs{
+			Name:    pulumi.String("weeknights"),
+			Enabled: pulumi.Bool(true),
+			PauseConfig: &rebalancing.HibernationSchedulePauseConfigArgs{
+				Enabled: pulumi.Bool(true),
+				Schedule: &rebalancing.HibernationSchedulePauseConfigScheduleArgs{
+					CronExpression: pulumi.String("0 22 * * 1-5"),
+				},
+			},
+			ResumeConfig: &rebalancing.HibernationScheduleResumeConfigArgs{
+				Enabled: pulumi.Bool(true),
+				Schedule: &rebalancing.HibernationScheduleResumeConfigScheduleArgs{
+					CronExpression: pulumi.String("0 7 * * 1-5"),
+				},
+				JobConfig: &rebalancing.HibernationScheduleResumeConfigJobConfigArgs{
+					NodeConfig: &rebalancing.HibernationScheduleResumeConfigJobConfigNodeConfigArgs{
+						InstanceType: pulumi.String("m5.large"),
+					},
+				},
+			},
+			ClusterAssignments: &rebalancing.HibernationScheduleClusterAssignmentsArgs{
+				Assignments: rebalancing.HibernationScheduleClusterAssignmentsAssignmentArray{
+					&rebalancing.HibernationScheduleClusterAssignmentsAssignmentArgs{
+						ClusterId: pulumi.String(pulumi.String(clusterId)),
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
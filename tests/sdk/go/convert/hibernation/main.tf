@@ -0,0 +1,36 @@
+variable "cluster_id" {
+  type = string
+}
+
+resource "castai_hibernation_schedule" "weeknights" {
+  name    = "weeknights"
+  enabled = true
+
+  pause_config {
+    enabled = true
+
+    schedule {
+      cron_expression = "0 22 * * 1-5"
+    }
+  }
+
+  resume_config {
+    enabled = true
+
+    schedule {
+      cron_expression = "0 7 * * 1-5"
+    }
+
+    job_config {
+      node_config {
+        instance_type = "m5.large"
+      }
+    }
+  }
+
+  cluster_assignments {
+    assignment {
+      cluster_id = var.cluster_id
+    }
+  }
+}
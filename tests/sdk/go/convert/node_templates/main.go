@@ -0,0 +1,67 @@
+package main
+
+import (
+	castaiconfig "github.com/castai/pulumi-castai/sdk/go/castai/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		cfg := config.New(ctx, "")
+		clusterId := cfg.Require("clusterId")
+		configurationId := cfg.Require("configurationId")
+		_, err := castaiconfig.NewNodeTemplate(ctx, "defaultByCastai", &castaiconfig.NodeTemplateArgs{
+			ClusterId:       pulumi.String(pulumi.String(clusterId)),
+			Name:            pulumi.String("default-by-castai"),
+			ConfigurationId: pulumi.String(pulumi.String(configurationId)),
+			IsDefault:       pulumi.Bool(true),
+			IsEnabled:       pulumi.Bool(true),
+			ShouldTaint:     pulumi.Bool(false),
+			Constraints: &castaiconfig.NodeTemplateConstraintsArgs{
+				OnDemand: pulumi.Bool(true),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = castaiconfig.NewNodeTemplate(ctx, "spot", &castaiconfig.NodeTemplateArgs{
+			ClusterId:       pulumi.String(pulumi.String(clusterId)),
+			Name:            pulumi.String("spot"),
+			ConfigurationId: pulumi.String(pulumi.String(configurationId)),
+			IsEnabled:       pulumi.Bool(true),
+			ShouldTaint:     pulumi.Bool(true),
+			CustomLabels: pulumi.StringMap{
+				"workload-type": pulumi.String("batch"),
+			},
+			CustomTaints: castaiconfig.NodeTemplateCustomTaintArray{
+				&castaiconfig.NodeTemplateCustomTaintArgs{
+					Key:    pulumi.String("dedicated"),
+					Value:  pulumi.String("spot"),
+					Effect: pulumi.String("NoSchedule"),
+				},
+			},
+			Constraints: &castaiconfig.NodeTemplateConstraintsArgs{
+				Spot:                       pulumi.Bool(true),
+				UseSpotFallbacks:           pulumi.Bool(true),
+				FallbackRestoreRateSeconds: pulumi.Int(1800),
+				MinCpu:                     pulumi.Int(2),
+				MaxCpu:                     pulumi.Int(32),
+				Architectures: pulumi.StringArray{
+					pulumi.String("amd64"),
+					pulumi.String("arm64"),
+				},
+				InstanceFamilies: &castaiconfig.NodeTemplateConstraintsInstanceFamiliesArgs{
+					Excludes: pulumi.StringArray{
+						pulumi.String("p4d"),
+						pulumi.String("p5"),
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
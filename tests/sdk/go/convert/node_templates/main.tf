@@ -0,0 +1,51 @@
+variable "cluster_id" {
+  type = string
+}
+
+variable "configuration_id" {
+  type = string
+}
+
+resource "castai_node_template" "default_by_castai" {
+  cluster_id       = var.cluster_id
+  name             = "default-by-castai"
+  configuration_id = var.configuration_id
+  is_default       = true
+  is_enabled       = true
+  should_taint     = false
+
+  constraints {
+    on_demand = true
+  }
+}
+
+resource "castai_node_template" "spot" {
+  cluster_id       = var.cluster_id
+  name             = "spot"
+  configuration_id = var.configuration_id
+  is_enabled       = true
+  should_taint     = true
+
+  custom_labels = {
+    "workload-type" = "batch"
+  }
+
+  custom_taints {
+    key    = "dedicated"
+    value  = "spot"
+    effect = "NoSchedule"
+  }
+
+  constraints {
+    spot                          = true
+    use_spot_fallbacks            = true
+    fallback_restore_rate_seconds = 1800
+    min_cpu                       = 2
+    max_cpu                       = 32
+    architectures                 = ["amd64", "arm64"]
+
+    instance_families {
+      exclude = ["p4d", "p5"]
+    }
+  }
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai/castaitest"
+	"github.com/castai/pulumi-castai/sdk/go/castai/gitops"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitOpsLoadDir(t *testing.T) {
+	set, err := gitops.LoadDir("testdata/gitops/valid")
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, doc := range set.Documents {
+		kinds = append(kinds, doc.Kind+"/"+doc.Name)
+	}
+	// Files are read in lexical order, including subdirectories.
+	assert.Equal(t, []string{
+		"EvictorAdvancedConfig/evictor",
+		"PodMutation/spot-batch",
+		"HibernationSchedule/weekends",
+		"NodeConfiguration/default",
+		"NodeTemplate/gpu",
+		"WorkloadScalingPolicy/api",
+	}, kinds)
+
+	gpu := set.Find(gitops.KindNodeTemplate, "gpu")
+	require.NotNil(t, gpu)
+	assert.Equal(t, gitops.Pos{File: "testdata/gitops/valid/nodes.yaml", Line: 16, Column: 1}, gpu.Pos)
+	constraints := gpu.Spec["constraints"].(map[string]interface{})
+	assert.Equal(t, 32, constraints["maxCpu"])
+	assert.Equal(t, false, constraints["spot"])
+	assert.Equal(t, []interface{}{"NVIDIA"}, constraints["gpu"].(map[string]interface{})["manufacturers"])
+
+	// Numbers given for string inputs keep their text.
+	api := set.Find(gitops.KindWorkloadScalingPolicy, "api")
+	assert.Equal(t, "0.9", api.Spec["cpu"].(map[string]interface{})["args"])
+}
+
+func TestGitOpsValidationErrors(t *testing.T) {
+	_, err := gitops.LoadDir("testdata/gitops/invalid")
+	require.Error(t, err)
+
+	var errs gitops.Errors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, []string{
+		"testdata/gitops/invalid/specs.yaml:4:3: spec.clusterId: is set by Register",
+		"testdata/gitops/invalid/specs.yaml:6:5: spec.constraints.minCPU: unknown input, did you mean minCpu?",
+		`testdata/gitops/invalid/specs.yaml:7:13: spec.constraints.maxCpu: expected an integer, got "many"`,
+		"testdata/gitops/invalid/specs.yaml:9:1: name: NodeTemplate gpu is also defined at testdata/gitops/invalid/specs.yaml:1:1",
+		"testdata/gitops/invalid/specs.yaml:15:3: spec: missing required inputs managementOption, memory",
+		`testdata/gitops/invalid/specs.yaml:18:1: kind: unknown kind "Autoscaler", expected one of EvictorAdvancedConfig, HibernationSchedule, NodeConfiguration, NodeTemplate, PodMutation, WorkloadScalingPolicy`,
+		"testdata/gitops/invalid/specs.yaml:24:1: kind: a cluster has a single EvictorAdvancedConfig, first is also defined at testdata/gitops/invalid/specs.yaml:21:1",
+	}, strings.Split(err.Error(), "\n"))
+}
+
+func TestGitOpsLoadSyntaxError(t *testing.T) {
+	_, err := gitops.Load("broken.yaml", strings.NewReader("kind: NodeTemplate\nname: gpu\nspec:\n  constraints: [\n"))
+	require.Error(t, err)
+	assert.Regexp(t, `^broken.yaml:\d+:0: `, err.Error())
+
+	_, err = gitops.Load("list.yaml", strings.NewReader("- kind: NodeTemplate\n"))
+	assert.EqualError(t, err, "list.yaml:1:1: expected a mapping with kind, name and spec")
+}
+
+func TestGitOpsRegister(t *testing.T) {
+	set, err := gitops.LoadDir("testdata/gitops/valid")
+	require.NoError(t, err)
+
+	mocks := castaitest.NewMocks()
+	err = pulumi.RunErr(func(ctx *pulumi.Context) error {
+		res, err := gitops.Register(ctx, pulumi.String("cluster-1"), set)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, res.NodeTemplates, 1)
+		assert.Len(t, res.HibernationSchedules, 1)
+		return nil
+	}, pulumi.WithMocks("project", "stack", mocks))
+	require.NoError(t, err)
+	assert.Len(t, mocks.Registrations(), 6)
+
+	nc, ok := mocks.Find("castai:config/node:NodeConfiguration", "default")
+	require.True(t, ok)
+	assert.Equal(t, "cluster-1", nc.Inputs["clusterId"].StringValue())
+	assert.Equal(t, "default", nc.Inputs["name"].StringValue())
+	assert.Equal(t, float64(100), nc.Inputs["minDiskSize"].NumberValue())
+
+	// The node template refers to the node configuration of the set by name.
+	tmpl, ok := mocks.Find("castai:config/node:NodeTemplate", "gpu")
+	require.True(t, ok)
+	assert.Equal(t, nc.ID, tmpl.Inputs["configurationId"].StringValue())
+	gpu := tmpl.Inputs["constraints"].ObjectValue()["gpu"].ObjectValue()
+	assert.Equal(t, []resource.PropertyValue{resource.NewStringProperty("NVIDIA")}, gpu["manufacturers"].ArrayValue())
+
+	hs, ok := mocks.Find("castai:rebalancing:HibernationSchedule", "weekends")
+	require.True(t, ok)
+	assert.NotContains(t, hs.Inputs, resource.PropertyKey("clusterId"))
+	assignments := hs.Inputs["clusterAssignments"].ObjectValue()["assignments"].ArrayValue()
+	require.Len(t, assignments, 1)
+	assert.Equal(t, "cluster-1", assignments[0].ObjectValue()["clusterId"].StringValue())
+
+	evictor, ok := mocks.Find("castai:autoscaling:EvictorAdvancedConfig", "evictor")
+	require.True(t, ok)
+	assert.NotContains(t, evictor.Inputs, resource.PropertyKey("name"))
+}
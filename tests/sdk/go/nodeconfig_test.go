@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai/nodeconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var eksDockerd = nodeconfig.Target{
+	Cloud:            nodeconfig.CloudEKS,
+	ContainerRuntime: nodeconfig.ContainerRuntimeDockerd,
+	ImageFamily:      nodeconfig.ImageFamilyAL2,
+}
+
+func TestNodeConfigKubeletJSON(t *testing.T) {
+	c := &nodeconfig.KubeletConfig{
+		MaxPods:             nodeconfig.Int(110),
+		RegistryPullQPS:     nodeconfig.Int(10),
+		RegistryBurst:       nodeconfig.Int(20),
+		SerializeImagePulls: nodeconfig.Bool(false),
+		EvictionHard:        map[string]string{"nodefs.available": "10%", "memory.available": "200Mi"},
+		KubeReserved:        map[string]string{"memory": "1Gi", "cpu": "100m"},
+		CpuManagerPolicy:    nodeconfig.String(nodeconfig.CpuManagerPolicyStatic),
+	}
+	out, err := c.JSON(nodeconfig.Target{Cloud: nodeconfig.CloudEKS})
+	require.NoError(t, err)
+	assert.Equal(t, `{"cpuManagerPolicy":"static","evictionHard":{"memory.available":"200Mi","nodefs.available":"10%"},`+
+		`"kubeReserved":{"cpu":"100m","memory":"1Gi"},"maxPods":110,"registryBurst":20,"registryPullQPS":10,"serializeImagePulls":false}`, out)
+
+	parsed, err := nodeconfig.ParseKubeletConfig([]byte(out))
+	require.NoError(t, err)
+	assert.Equal(t, c, parsed)
+
+	// Key order and whitespace do not change the canonical form.
+	canonical, err := nodeconfig.Canonical([]byte(`{
+		"serializeImagePulls": false, "registryPullQPS": 10, "registryBurst": 20, "maxPods": 110,
+		"kubeReserved": {"memory": "1Gi", "cpu": "100m"},
+		"evictionHard": {"nodefs.available": "10%", "memory.available": "200Mi"},
+		"cpuManagerPolicy": "static"
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, out, canonical)
+}
+
+func TestNodeConfigKubeletParseErrors(t *testing.T) {
+	_, err := nodeconfig.ParseKubeletConfig([]byte(`{"maxpods": 110}`))
+	assert.EqualError(t, err, `decoding kubelet config: unknown field "maxpods", did you mean "maxPods"?`)
+
+	_, err = nodeconfig.ParseKubeletConfig([]byte(`{"evictionHard": {"memory.available": 100}}`))
+	assert.ErrorContains(t, err, "decoding kubelet config")
+}
+
+func TestNodeConfigKubeletValidation(t *testing.T) {
+	c := &nodeconfig.KubeletConfig{
+		MaxPods:                         nodeconfig.Int(0),
+		PodPidsLimit:                    nodeconfig.Int(-2),
+		MaxParallelImagePulls:           nodeconfig.Int(5),
+		ImageGCHighThresholdPercent:     nodeconfig.Int(70),
+		ImageGCLowThresholdPercent:      nodeconfig.Int(80),
+		CpuCFSQuotaPeriod:               nodeconfig.String("2s"),
+		TopologyManagerPolicy:           nodeconfig.String("strict"),
+		EvictionHard:                    map[string]string{"memory.free": "100Mi", "nodefs.available": "110%"},
+		EvictionSoft:                    map[string]string{"memory.available": "500Mi"},
+		KubeReserved:                    map[string]string{"gpu": "1"},
+		SystemReserved:                  map[string]string{"memory": "1 GB"},
+		ContainerLogMaxFiles:            nodeconfig.Int(1),
+		ShutdownGracePeriod:             nodeconfig.String("30s"),
+		ShutdownGracePeriodCriticalPods: nodeconfig.String("1m"),
+	}
+	err := c.Validate(nodeconfig.Target{Cloud: nodeconfig.CloudEKS})
+	require.Error(t, err)
+	for _, want := range []string{
+		"maxPods: must be positive, got 0",
+		"podPidsLimit: must be positive or -1, got -2",
+		"maxParallelImagePulls: requires serializeImagePulls to be false",
+		"imageGCLowThresholdPercent: 80 must be less than imageGCHighThresholdPercent 70",
+		`cpuCFSQuotaPeriod: must be between 1ms and 1s, got "2s"`,
+		`topologyManagerPolicy: unsupported value "strict"`,
+		"evictionHard.memory.free: unknown eviction signal",
+		`evictionHard.nodefs.available: "110%" is not a percentage between 0% and 100%`,
+		"evictionSoft.memory.available: needs a grace period in evictionSoftGracePeriod",
+		"kubeReserved.gpu: unknown resource",
+		`systemReserved.memory: "1 GB" is not a valid quantity`,
+		"containerLogMaxFiles: must be at least 2, got 1",
+		"shutdownGracePeriodCriticalPods: 1m0s must not exceed shutdownGracePeriod 30s",
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+
+	err = (&nodeconfig.KubeletConfig{}).Validate(nodeconfig.Target{Cloud: nodeconfig.CloudGKE})
+	assert.EqualError(t, err, "invalid kubelet config: kubeletConfig is only applied to EKS nodes, not GKE")
+}
+
+func TestNodeConfigDocker(t *testing.T) {
+	c, err := nodeconfig.ParseDockerConfig([]byte(`{
+		"max-concurrent-downloads": 10,
+		"registry-mirrors": ["https://mirror.gcr.io"],
+		"log-driver": "json-file",
+		"log-opts": {"max-size": "10m", "max-file": "3"},
+		"default-ulimits": {"nofile": {"Name": "nofile", "Soft": 65536, "Hard": 65536}}
+	}`))
+	require.NoError(t, err)
+	out, err := c.JSON(eksDockerd)
+	require.NoError(t, err)
+	assert.Equal(t, `{"default-ulimits":{"nofile":{"Hard":65536,"Name":"nofile","Soft":65536}},"log-driver":"json-file",`+
+		`"log-opts":{"max-file":"3","max-size":"10m"},"max-concurrent-downloads":10,"registry-mirrors":["https://mirror.gcr.io"]}`, out)
+
+	// dockerd rejects numbers in log-opts.
+	_, err = nodeconfig.ParseDockerConfig([]byte(`{"log-opts": {"max-file": 3}}`))
+	assert.ErrorContains(t, err, "decoding docker config")
+
+	bad := &nodeconfig.DockerConfig{
+		RegistryMirrors: []string{"mirror.gcr.io"},
+		DefaultUlimits:  map[string]nodeconfig.Ulimit{"nofile": {Name: "nproc", Soft: 10, Hard: 5}},
+		DNS:             []string{"dns.google"},
+		MTU:             nodeconfig.Int(20),
+	}
+	err = bad.Validate(eksDockerd)
+	require.Error(t, err)
+	for _, want := range []string{
+		`registry-mirrors[0]: "mirror.gcr.io" is not an http or https URL`,
+		`default-ulimits.nofile: Name must be "nofile", got "nproc"`,
+		"default-ulimits.nofile: Soft 10 must be between 0 and Hard 5",
+		`dns[0]: "dns.google" is not an IP address`,
+		"mtu: must be between 68 and 65535, got 20",
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestNodeConfigDockerTarget(t *testing.T) {
+	c := &nodeconfig.DockerConfig{LiveRestore: nodeconfig.Bool(true)}
+
+	err := c.Validate(nodeconfig.Target{Cloud: nodeconfig.CloudEKS, ContainerRuntime: nodeconfig.ContainerRuntimeContainerd})
+	assert.ErrorContains(t, err, `dockerConfig: configures dockerd and requires containerRuntime "dockerd", got "containerd"`)
+
+	err = c.Validate(nodeconfig.Target{Cloud: nodeconfig.CloudEKS, ContainerRuntime: nodeconfig.ContainerRuntimeDockerd, ImageFamily: nodeconfig.ImageFamilyAL2023})
+	assert.ErrorContains(t, err, "dockerConfig: al2023 images do not ship dockerd")
+
+	err = c.Validate(nodeconfig.Target{Cloud: "eks-anywhere"})
+	assert.ErrorContains(t, err, `cloud: unsupported value "eks-anywhere"`)
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fri 2027-03-19 12:00 UTC, nine days before Europe moves to summer time.
+var scheduleStart = time.Date(2027, 3, 19, 12, 0, 0, 0, time.UTC)
+
+func TestScheduleParse(t *testing.T) {
+	for _, expr := range []string{
+		"0 7 * * 1-5",
+		"CRON_TZ=America/New_York 0 12 * * ?",
+		"TZ=Europe/Berlin */15 8-18 * JAN-MAR MON,WED,FRI",
+		"0 0 1,15 * *",
+		"@daily",
+	} {
+		_, err := schedule.Parse(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	for expr, want := range map[string]string{
+		"0 7 * *":                        "expected 5 fields, got 4",
+		"60 7 * * *":                     `invalid minute "60", expected 0-59`,
+		"0 7 * * MON-SUNDAY":             `invalid day of week "SUNDAY"`,
+		"0 7 * * 5-1":                    "day of week range 5-1 is empty",
+		"0 */0 * * *":                    `invalid hour step "0"`,
+		"CRON_TZ=Mars/Olympus 0 7 * * *": `unknown time zone "Mars/Olympus"`,
+		"@every 5m":                      "unsupported descriptor @every",
+	} {
+		_, err := schedule.Parse(expr)
+		assert.ErrorContains(t, err, want, expr)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	c := schedule.MustParse("CRON_TZ=America/New_York 0 7 * * 1-5")
+	fires := c.NextN(scheduleStart, 3)
+	require.Len(t, fires, 3)
+	assert.Equal(t, "Mon 2027-03-22 07:00 EDT", fires[0].Format("Mon 2006-01-02 15:04 MST"))
+	assert.Equal(t, "Wed 2027-03-24 07:00 EDT", fires[2].Format("Mon 2006-01-02 15:04 MST"))
+	assert.Equal(t, time.Date(2027, 3, 22, 11, 0, 0, 0, time.UTC), fires[0].UTC())
+
+	// Sunday is 0 or 7, and both day fields restricted match either day.
+	sunday := schedule.MustParse("0 0 1 * 7").NextN(scheduleStart, 2)
+	assert.Equal(t, time.Date(2027, 3, 21, 0, 0, 0, 0, time.UTC), sunday[0])
+	assert.Equal(t, time.Date(2027, 3, 28, 0, 0, 0, 0, time.UTC), sunday[1])
+
+	_, ok := schedule.MustParse("0 0 30 2 *").Next(scheduleStart)
+	assert.False(t, ok, "February 30th never comes")
+}
+
+func TestScheduleSkipped(t *testing.T) {
+	c := schedule.MustParse("CRON_TZ=Europe/Berlin 30 2 * * *")
+	skipped := c.Skipped(scheduleStart, scheduleStart.Add(14*24*time.Hour))
+	assert.Equal(t, []time.Time{time.Date(2027, 3, 28, 2, 30, 0, 0, time.UTC)}, skipped)
+
+	fires := c.Between(time.Date(2027, 3, 27, 0, 0, 0, 0, time.UTC), time.Date(2027, 3, 30, 0, 0, 0, 0, time.UTC))
+	require.Len(t, fires, 2, "2:30 does not exist on March 28th")
+	assert.Equal(t, 27, fires[0].Day())
+	assert.Equal(t, 29, fires[1].Day())
+}
+
+func TestSchedulePreview(t *testing.T) {
+	plan := &schedule.Plan{
+		Hibernations: []schedule.Hibernation{{
+			Name:     "weekends",
+			Pause:    "CRON_TZ=Europe/Berlin 0 20 * * 5",
+			Resume:   "CRON_TZ=Europe/Berlin 0 6 * * 1",
+			Clusters: []string{"c1"},
+		}},
+		Rebalancings: []schedule.Rebalancing{
+			{Name: "nightly", Cron: "CRON_TZ=Europe/Berlin 0 3 * * *", Clusters: []string{"c1", "c2"}},
+			{Name: "unassigned", Cron: "0 4 * * *"},
+		},
+	}
+	report := schedule.Preview(plan, scheduleStart, 2)
+
+	require.Len(t, report.Clusters, 2)
+	assert.Equal(t, "c1", report.Clusters[0].Cluster)
+	var events []string
+	for _, e := range report.Clusters[0].Events {
+		events = append(events, e.Time.Format("Mon 15:04 ")+string(e.Kind)+" "+e.Schedule)
+	}
+	assert.Equal(t, []string{
+		"Fri 20:00 pause weekends",
+		"Sat 03:00 rebalance nightly",
+		"Sun 03:00 rebalance nightly",
+		"Mon 06:00 resume weekends",
+		"Fri 20:00 pause weekends",
+		"Mon 06:00 resume weekends",
+	}, events)
+	assert.Len(t, report.Clusters[1].Events, 2)
+
+	require.Len(t, report.Findings, 1)
+	f := report.Findings[0]
+	assert.Equal(t, schedule.Overlap, f.Kind)
+	assert.Equal(t, "nightly", f.Schedule)
+	assert.Equal(t, "c1", f.Cluster)
+	// Saturday, Sunday and Monday 03:00 fall into every weekend.
+	assert.Equal(t, "fires at Sat 2027-03-20 03:00 CET while the cluster is paused by weekends (158 times)", f.Message)
+}
+
+func TestSchedulePreviewFindings(t *testing.T) {
+	plan := &schedule.Plan{
+		Hibernations: []schedule.Hibernation{
+			{Name: "friday-only", Pause: "0 20 * * 5", Resume: "0 7 * * 1-5"},
+			{Name: "forever", Pause: "0 20 * * 5"},
+			{Name: "together", Pause: "0 20 * * *", Resume: "0 20 * * *"},
+			{Name: "early-monday", Pause: "CRON_TZ=Europe/London 0 20 * * 5", Resume: "CRON_TZ=Europe/London 30 1 * * 0"},
+		},
+		Rebalancings: []schedule.Rebalancing{
+			{Name: "broken", Cron: "0 25 * * *"},
+			{Name: "leap-day", Cron: "0 0 30 2 *"},
+		},
+	}
+	report := schedule.Preview(plan, scheduleStart, 1)
+
+	var got []string
+	for _, f := range report.Findings {
+		got = append(got, string(f.Kind)+" "+f.Schedule)
+	}
+	assert.Equal(t, []string{
+		"resume-before-pause friday-only",
+		"no-resume forever",
+		"resume-before-pause together",
+		"dst-gap early-monday",
+		"invalid broken",
+		"invalid leap-day",
+	}, got)
+	assert.Contains(t, report.Findings[0].Message, "resume fires at Tue 2027-03-23 07:00 UTC without a pause since the previous resume")
+	assert.Contains(t, report.Findings[1].Message, "the resume config is disabled")
+	assert.Contains(t, report.Findings[3].Message,
+		`resume cron "CRON_TZ=Europe/London 30 1 * * 0" does not fire on 2027-03-28: 01:30 does not exist in Europe/London; the cluster stays paused`)
+	assert.Empty(t, report.Clusters, "no schedule is assigned to a cluster")
+}
+
+func TestScheduleLoadStackExport(t *testing.T) {
+	export := `{
+  "version": 3,
+  "deployment": {
+    "resources": [
+      {"urn": "urn:pulumi:dev::p::castai:rebalancing:RebalancingJob::j1", "type": "castai:rebalancing:RebalancingJob", "id": "j1",
+       "inputs": {"clusterId": "c1", "rebalancingScheduleId": "rs-1"}},
+      {"urn": "urn:pulumi:dev::p::castai:rebalancing:RebalancingJob::j2", "type": "castai:rebalancing:RebalancingJob", "id": "j2",
+       "inputs": {"clusterId": "c2", "rebalancingScheduleId": "rs-1", "enabled": false}},
+      {"urn": "urn:pulumi:dev::p::castai:rebalancing:RebalancingSchedule::nightly", "type": "castai:rebalancing:RebalancingSchedule", "id": "rs-1",
+       "inputs": {"name": "nightly", "schedule": {"cron": "0 3 * * *"}}},
+      {"urn": "urn:pulumi:dev::p::castai:rebalancing:HibernationSchedule::weekends", "type": "castai:rebalancing:HibernationSchedule", "id": "hs-1",
+       "inputs": {"name": "weekends", "enabled": true, "organizationId": "org",
+                  "pauseConfig": {"enabled": true, "schedule": {"cronExpression": "0 20 * * 5"}},
+                  "resumeConfig": {"enabled": false, "schedule": {"cronExpression": "0 6 * * 1"}},
+                  "clusterAssignments": {"assignments": [{"clusterId": "c1"}, {"clusterId": "c3"}]}}},
+      {"urn": "urn:pulumi:dev::p::castai:rebalancing:HibernationSchedule::off", "type": "castai:rebalancing:HibernationSchedule", "id": "hs-2",
+       "inputs": {"name": "off", "enabled": false, "pauseConfig": {"enabled": true, "schedule": {"cronExpression": "0 20 * * *"}}}}
+    ]
+  }
+}`
+	plan, err := schedule.LoadStackExport(strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, &schedule.Plan{
+		Hibernations: []schedule.Hibernation{{Name: "weekends", Pause: "0 20 * * 5", Clusters: []string{"c1", "c3"}}},
+		Rebalancings: []schedule.Rebalancing{{Name: "nightly", Cron: "0 3 * * *", Clusters: []string{"c1"}}},
+	}, plan)
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castai/pulumi-castai/sdk/go/castai/sqltemplate"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSQLTemplateGolden tests that the queries in testdata/sqltemplate/<protocol>
+// normalize to the .golden files next to them. Run with
+// PULUMI_ACCEPT=1 to update the golden files.
+func TestSQLTemplateGolden(t *testing.T) {
+	for _, p := range sqltemplate.Protocols {
+		files, err := filepath.Glob(filepath.Join("testdata/sqltemplate", strings.ToLower(string(p)), "*.sql"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+
+		for _, file := range files {
+			t.Run(filepath.Base(file), func(t *testing.T) {
+				sql, err := os.ReadFile(file)
+				require.NoError(t, err)
+				template, err := sqltemplate.Normalize(p, string(sql))
+				require.NoError(t, err)
+				actual := template + "\n"
+
+				golden := strings.TrimSuffix(file, ".sql") + ".golden"
+				if cmdutil.IsTruthy(os.Getenv("PULUMI_ACCEPT")) {
+					require.NoError(t, os.WriteFile(golden, []byte(actual), 0o600))
+				}
+				expected, err := os.ReadFile(golden)
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), actual)
+			})
+		}
+	}
+}
+
+// TestSQLTemplateEquivalent tests that spellings of the same query share a
+// template.
+func TestSQLTemplateEquivalent(t *testing.T) {
+	cases := []struct {
+		protocol sqltemplate.Protocol
+		queries  []string
+	}{
+		{sqltemplate.MySQL, []string{
+			"SELECT * FROM users WHERE id = 1",
+			"select *\n  from users\n where id = ?;",
+			"SELECT * FROM `users` WHERE `id` = -42 -- by id",
+			"/* api */ SELECT * FROM users WHERE id = 'abc'",
+		}},
+		{sqltemplate.MySQL, []string{
+			"SELECT name FROM users WHERE id IN (1)",
+			"SELECT name FROM users WHERE id IN (1, 2, 3)",
+			"SELECT name FROM users WHERE id in (?, ?)",
+		}},
+		{sqltemplate.MySQL, []string{
+			"INSERT INTO t (a) VALUES (1)",
+			"INSERT INTO t (a) VALUES (1), (2), (3)",
+		}},
+		{sqltemplate.PostgreSQL, []string{
+			"SELECT * FROM Users WHERE id = $1",
+			`SELECT * FROM "users" WHERE "id" = 7`,
+			"select * from USERS where ID = E'it\\'s'",
+			"SELECT * FROM users WHERE id = $$x$$",
+		}},
+	}
+	for _, c := range cases {
+		want, err := sqltemplate.Normalize(c.protocol, c.queries[0])
+		require.NoError(t, err)
+		for _, q := range c.queries[1:] {
+			got, err := sqltemplate.Normalize(c.protocol, q)
+			require.NoError(t, err)
+			assert.Equal(t, want, got, "%s: %q", c.protocol, q)
+		}
+	}
+}
+
+// TestSQLTemplateDifferent tests that queries differing in more than their
+// literals get different templates.
+func TestSQLTemplateDifferent(t *testing.T) {
+	cases := []struct {
+		protocol sqltemplate.Protocol
+		a, b     string
+	}{
+		// MySQL table names are case sensitive.
+		{sqltemplate.MySQL, "SELECT * FROM users", "SELECT * FROM Users"},
+		// PostgreSQL quoted identifiers keep their case.
+		{sqltemplate.PostgreSQL, `SELECT * FROM "Users"`, "SELECT * FROM Users"},
+		{sqltemplate.PostgreSQL, "SELECT * FROM users WHERE id = $1", "SELECT * FROM accounts WHERE id = $1"},
+		{sqltemplate.PostgreSQL, "SELECT a FROM t", "SELECT a, b FROM t"},
+		// A column in an IN list is not a literal.
+		{sqltemplate.MySQL, "SELECT * FROM t WHERE a IN (1, 2)", "SELECT * FROM t WHERE a IN (1, b)"},
+		// MySQL -- needs a space to start a comment.
+		{sqltemplate.MySQL, "SELECT 1--1", "SELECT 1"},
+	}
+	for _, c := range cases {
+		a, err := sqltemplate.Normalize(c.protocol, c.a)
+		require.NoError(t, err)
+		b, err := sqltemplate.Normalize(c.protocol, c.b)
+		require.NoError(t, err)
+		assert.NotEqual(t, a, b, "%s: %q and %q", c.protocol, c.a, c.b)
+	}
+}
+
+// TestSQLTemplateErrors tests the errors for SQL text that is not a query.
+func TestSQLTemplateErrors(t *testing.T) {
+	cases := []struct {
+		protocol sqltemplate.Protocol
+		sql      string
+		err      string
+	}{
+		{sqltemplate.MySQL, "SELECT 'abc", "unterminated string at offset 7"},
+		{sqltemplate.PostgreSQL, "SELECT 'it\\'s'", "unterminated string at offset 13"},
+		{sqltemplate.PostgreSQL, "SELECT 1 /* a /* b */", "unterminated comment at offset 9"},
+		{sqltemplate.MySQL, "SELECT `a", "unterminated quoted identifier at offset 7"},
+		{sqltemplate.PostgreSQL, "SELECT $x$abc", "unterminated dollar-quoted string at offset 7"},
+		{sqltemplate.MySQL, " ;\n-- nothing\n", "no query in SQL text"},
+		{"Redis", "GET key", `unsupported protocol "Redis", must be "MySQL" or "PostgreSQL"`},
+	}
+	for _, c := range cases {
+		_, err := sqltemplate.Normalize(c.protocol, c.sql)
+		assert.EqualError(t, err, c.err, "%s: %q", c.protocol, c.sql)
+	}
+}
@@ -0,0 +1,2 @@
+insert into t(a, b) values (1, 'x'), (2, 'y'), (3, NULL)
+on duplicate key update b = values(b)
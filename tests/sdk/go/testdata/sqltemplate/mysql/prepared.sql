@@ -0,0 +1,2 @@
+SELECT * FROM `order items` WHERE order_id = ? # prepared
+  AND sku LIKE _utf8mb4'AB%' AND flags & 0x0F <> 0
@@ -0,0 +1,7 @@
+-- Orders of active users in a few cities.
+SELECT u.id, `Name`, count(*)
+  FROM users u /* aliased */
+ WHERE u.age > 21 AND u.city in ('Vilnius', 'Kaunas', "Berlin")
+   AND u.balance = -10.5e3
+ GROUP BY 1
+ LIMIT 10;
@@ -0,0 +1 @@
+SELECT coalesce(sum(amount), 0) FROM payments WHERE note = $tag$it's paid$tag$ AND id IN (1, 2, 3)
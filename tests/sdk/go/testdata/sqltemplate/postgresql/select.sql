@@ -0,0 +1,9 @@
+select "Users".id, Name, "email"
+  from "Users"
+ where id = $1
+   and tags @> ARRAY['a', 'b']
+   and created_at > now() - interval '1 day'
+   and x::int = 1_000
+   /* outer /* nested */ comment */
+ order by created_at desc nulls last
+ limit $2;
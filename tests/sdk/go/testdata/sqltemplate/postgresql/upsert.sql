@@ -0,0 +1,3 @@
+INSERT INTO counters (name, value) VALUES ($1, 1), ($2, 1)
+ON CONFLICT (name) DO UPDATE SET value = counters.value + E'\x01'::int
+RETURNING value